@@ -13,6 +13,7 @@ app.use(
 
 const LOGS_DIR = path.join(__dirname, "../logs");
 const CONFIG_FILE = path.join(__dirname, "../config.json");
+const ALARMS_FILE = path.join(LOGS_DIR, "alarms.json");
 
 app.get("/api/logs", (req, res) => {
   fs.readdir(LOGS_DIR, (err, files) => {
@@ -29,6 +30,14 @@ app.get("/api/logs/:log", (req, res) => {
   });
 });
 
+app.get("/api/alarms", (req, res) => {
+  fs.readFile(ALARMS_FILE, "utf-8", (err, content) => {
+    if (err && err.code === "ENOENT") return res.json({ active: [], events: [] });
+    if (err) return res.status(500).send(err.message);
+    res.json(JSON.parse(content));
+  });
+});
+
 app.get("/api/config", (req, res) => {
   const configPath = path.join(CONFIG_FILE);
   fs.readFile(configPath, "utf-8", (err, content) => {
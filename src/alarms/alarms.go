@@ -0,0 +1,275 @@
+package alarms
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"contor-system/src/utils"
+)
+
+// Alarme pentru suprasarcina termica, tensiune in afara benzii si factor de putere scazut
+
+type Kind string
+type Severity string
+type State string
+type EventType string
+
+const (
+	KindOverload       Kind = "overload"
+	KindUndervoltage   Kind = "undervoltage"
+	KindOvervoltage    Kind = "overvoltage"
+	KindLowPowerFactor Kind = "lowPowerFactor"
+)
+
+const (
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// Starile unei alarme: pending (prag depasit, se asteapta RaiseDelay), active, clearing (revenit in banda, se asteapta ClearDelay)
+const (
+	StatePending  State = "pending"
+	StateActive   State = "active"
+	StateClearing State = "clearing"
+)
+
+const (
+	EventRaised  EventType = "raised"
+	EventCleared EventType = "cleared"
+)
+
+type Alarm struct {
+	ElementID string    `json:"elementId"`
+	Kind      Kind      `json:"kind"`
+	Severity  Severity  `json:"severity"`
+	State     State     `json:"state"`
+	Value     float64   `json:"value"`
+	Limit     float64   `json:"limit"`
+	Since     time.Time `json:"since"` // momentul intrarii in starea curenta
+	RaisedAt  time.Time `json:"raisedAt,omitempty"`
+	// Unevaluated marcheaza o alarma a unui element din configuratie care lipseste din ultimul rezultat;
+	// Value este cea din ultima evaluare, iar starea se reia la urmatorul rezultat care contine elementul
+	Unevaluated bool `json:"unevaluated,omitempty"`
+}
+
+type Event struct {
+	Type  EventType `json:"type"`
+	Time  time.Time `json:"time"`
+	Alarm Alarm     `json:"alarm"`
+}
+
+// Manager pastreaza starea alarmelor intre pasii de calcul.
+type Manager struct {
+	alarms map[string]*Alarm
+}
+
+func NewManager() *Manager {
+	return &Manager{alarms: map[string]*Alarm{}}
+}
+
+// check descrie o conditie de alarma evaluata pentru un element.
+type check struct {
+	kind     Kind
+	severity Severity
+	value    float64
+	limit    float64
+	violated bool // pragul de ridicare este depasit
+	normal   bool // valoarea a revenit in banda, dincolo de histerezis
+}
+
+func checks(element *utils.ElementResult, limits utils.Limits, hysteresis float64) []check {
+	var result []check
+	band := hysteresis / 100
+
+	if limits.MaxLoading > 0 {
+		result = append(result, check{
+			kind:     KindOverload,
+			severity: SeverityCritical,
+			value:    element.Loading,
+			limit:    limits.MaxLoading,
+			violated: element.Loading > limits.MaxLoading,
+			normal:   element.Loading <= limits.MaxLoading*(1-band),
+		})
+	}
+
+	// Tensiunea si factorul de putere nu au sens pentru un element fara tensiune; alarmele lor raman in starea
+	// in care erau, vezi Evaluate
+	if !element.Energized {
+		return result
+	}
+
+	if limits.MinVoltage > 0 && element.VoltagePU > 0 {
+		result = append(result, check{
+			kind:     KindUndervoltage,
+			severity: SeverityCritical,
+			value:    element.VoltagePU,
+			limit:    limits.MinVoltage,
+			violated: element.VoltagePU < limits.MinVoltage,
+			normal:   element.VoltagePU >= limits.MinVoltage*(1+band),
+		})
+	}
+	if limits.MaxVoltage > 0 && element.VoltagePU > 0 {
+		result = append(result, check{
+			kind:     KindOvervoltage,
+			severity: SeverityCritical,
+			value:    element.VoltagePU,
+			limit:    limits.MaxVoltage,
+			violated: element.VoltagePU > limits.MaxVoltage,
+			normal:   element.VoltagePU <= limits.MaxVoltage*(1-band),
+		})
+	}
+	if limits.MinPowerFactor > 0 && element.PowerFactor > 0 {
+		result = append(result, check{
+			kind:     KindLowPowerFactor,
+			severity: SeverityWarning,
+			value:    element.PowerFactor,
+			limit:    limits.MinPowerFactor,
+			violated: element.PowerFactor < limits.MinPowerFactor,
+			normal:   element.PowerFactor >= limits.MinPowerFactor*(1+band) || element.PowerFactor >= 1,
+		})
+	}
+
+	return result
+}
+
+func alarmKey(elementID string, kind Kind) string {
+	return elementID + "/" + string(kind)
+}
+
+// Evaluate actualizeaza masina de stari a alarmelor pe baza rezultatului unui calcul si intoarce alarmele ridicate sau sterse.
+func (m *Manager) Evaluate(system utils.System, result utils.SystemResult, now time.Time) []Event {
+	var events []Event
+	settings := system.Alarms
+	raiseDelay := time.Duration(settings.RaiseDelay * float64(time.Second))
+	clearDelay := time.Duration(settings.ClearDelay * float64(time.Second))
+	limits := utils.ElementLimits(system)
+	seen := map[string]bool{}
+
+	for _, id := range result.Order {
+		element := result.Elements[id]
+		elementLimits, exists := limits[id]
+		if !exists {
+			continue
+		}
+
+		// Un element scos de sub tensiune (declansare, retragere) nu isi sterge alarmele de tensiune si factor de putere
+		if !element.Energized {
+			for _, kind := range []Kind{KindUndervoltage, KindOvervoltage, KindLowPowerFactor} {
+				seen[alarmKey(id, kind)] = true
+			}
+		}
+
+		for _, c := range checks(element, elementLimits, settings.Hysteresis) {
+			key := alarmKey(id, c.kind)
+			seen[key] = true
+			alarm, exists := m.alarms[key]
+
+			if !exists {
+				if !c.violated {
+					continue
+				}
+				alarm = &Alarm{ElementID: id, Kind: c.kind, Severity: c.severity, State: StatePending, Since: now}
+				m.alarms[key] = alarm
+			}
+			alarm.Value = c.value
+			alarm.Limit = c.limit
+			alarm.Unevaluated = false
+
+			switch alarm.State {
+			case StatePending:
+				if !c.violated {
+					delete(m.alarms, key)
+				} else if now.Sub(alarm.Since) >= raiseDelay {
+					alarm.State = StateActive
+					alarm.Since = now
+					alarm.RaisedAt = now
+					events = append(events, Event{Type: EventRaised, Time: now, Alarm: *alarm})
+				}
+			case StateActive:
+				if c.normal {
+					alarm.State = StateClearing
+					alarm.Since = now
+				}
+			case StateClearing:
+				if !c.normal {
+					alarm.State = StateActive
+					alarm.Since = now
+				}
+			}
+
+			if alarm.State == StateClearing && now.Sub(alarm.Since) >= clearDelay {
+				delete(m.alarms, key)
+				events = append(events, Event{Type: EventCleared, Time: now, Alarm: *alarm})
+			}
+		}
+	}
+
+	// Elementele scoase din configuratie sau ale caror limite au fost eliminate nu mai pot fi evaluate, alarmele lor se sterg.
+	// Un element care exista in configuratie, dar lipseste din rezultat, isi pastreaza alarmele ridicate marcate ca neevaluate,
+	// iar cele in asteptare se renunta, pentru ca intarzierea de ridicare nu mai poate fi urmarita
+	for key, alarm := range m.alarms {
+		if seen[key] {
+			continue
+		}
+		if elementLimits, exists := limits[alarm.ElementID]; exists && limitSet(elementLimits, alarm.Kind) {
+			if alarm.State == StatePending {
+				delete(m.alarms, key)
+			} else {
+				alarm.Unevaluated = true
+			}
+			continue
+		}
+		delete(m.alarms, key)
+		if alarm.State != StatePending {
+			events = append(events, Event{Type: EventCleared, Time: now, Alarm: *alarm})
+		}
+	}
+
+	return events
+}
+
+// limitSet spune daca limita verificata de un tip de alarma este configurata.
+func limitSet(limits utils.Limits, kind Kind) bool {
+	switch kind {
+	case KindOverload:
+		return limits.MaxLoading > 0
+	case KindUndervoltage:
+		return limits.MinVoltage > 0
+	case KindOvervoltage:
+		return limits.MaxVoltage > 0
+	case KindLowPowerFactor:
+		return limits.MinPowerFactor > 0
+	}
+	return false
+}
+
+// Active intoarce alarmele ridicate (inclusiv cele in curs de stergere), sortate dupa element.
+func (m *Manager) Active() []Alarm {
+	active := []Alarm{}
+	for _, alarm := range m.alarms {
+		if alarm.State != StatePending {
+			active = append(active, *alarm)
+		}
+	}
+	sort.Slice(active, func(i, j int) bool {
+		return alarmKey(active[i].ElementID, active[i].Kind) < alarmKey(active[j].ElementID, active[j].Kind)
+	})
+	return active
+}
+
+// LogEntry transforma evenimentul intr-o intrare de log.
+func (e Event) LogEntry() utils.LogEntry {
+	var message string
+	if e.Type == EventRaised {
+		message = fmt.Sprintf("ALARM RAISED [%s] %s on %s: value %.3f, limit %.3f\n", e.Alarm.Severity, e.Alarm.Kind, e.Alarm.ElementID, e.Alarm.Value, e.Alarm.Limit)
+	} else {
+		message = fmt.Sprintf("ALARM CLEARED [%s] %s on %s: value %.3f, limit %.3f\n", e.Alarm.Severity, e.Alarm.Kind, e.Alarm.ElementID, e.Alarm.Value, e.Alarm.Limit)
+	}
+
+	return utils.LogEntry{
+		Timestamp:   e.Time.Format("2006/01/02-15:04:05"),
+		ComponentID: e.Alarm.ElementID,
+		Message:     message,
+	}
+}
@@ -0,0 +1,187 @@
+package alarms
+
+import (
+	"testing"
+	"time"
+
+	"contor-system/src/utils"
+)
+
+func testSystem() utils.System {
+	return utils.System{
+		Lines: []utils.Line{{ID: "line1"}},
+		Alarms: utils.AlarmSettings{
+			DefaultLimits: utils.Limits{MaxLoading: 100, MinVoltage: 0.9},
+			Hysteresis:    5,
+			RaiseDelay:    2,
+			ClearDelay:    3,
+		},
+	}
+}
+
+func testResult(loading float64, voltagePU float64, energized bool) utils.SystemResult {
+	return utils.SystemResult{
+		Order: []string{"line1"},
+		Elements: map[string]*utils.ElementResult{
+			"line1": {ID: "line1", Kind: "line", Loading: loading, VoltagePU: voltagePU, Energized: energized},
+		},
+	}
+}
+
+func eventTypes(events []Event) []string {
+	var types []string
+	for _, event := range events {
+		types = append(types, string(event.Type)+" "+string(event.Alarm.Kind))
+	}
+	return types
+}
+
+func expectEvents(t *testing.T, step string, events []Event, expected ...string) {
+	t.Helper()
+	got := eventTypes(events)
+	if len(got) != len(expected) {
+		t.Fatalf("%s: events %v, expected %v", step, got, expected)
+	}
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Fatalf("%s: events %v, expected %v", step, got, expected)
+		}
+	}
+}
+
+func TestRaiseDelay(t *testing.T) {
+	m := NewManager()
+	system := testSystem()
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	expectEvents(t, "first violation", m.Evaluate(system, testResult(120, 1, true), start))
+	if len(m.Active()) != 0 {
+		t.Fatalf("a pending alarm is not active: %v", m.Active())
+	}
+	expectEvents(t, "before raiseDelay", m.Evaluate(system, testResult(120, 1, true), start.Add(time.Second)))
+	expectEvents(t, "after raiseDelay", m.Evaluate(system, testResult(120, 1, true), start.Add(2*time.Second)), "raised overload")
+	if active := m.Active(); len(active) != 1 || active[0].State != StateActive {
+		t.Fatalf("active alarms %v", active)
+	}
+}
+
+func TestPendingAlarmDropsWithoutEvent(t *testing.T) {
+	m := NewManager()
+	system := testSystem()
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	m.Evaluate(system, testResult(120, 1, true), start)
+	expectEvents(t, "back below the limit", m.Evaluate(system, testResult(90, 1, true), start.Add(time.Second)))
+	expectEvents(t, "violated again", m.Evaluate(system, testResult(120, 1, true), start.Add(2*time.Second)))
+}
+
+func TestHysteresisAndClearDelay(t *testing.T) {
+	m := NewManager()
+	system := testSystem()
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+
+	m.Evaluate(system, testResult(120, 1, true), at(0))
+	expectEvents(t, "raise", m.Evaluate(system, testResult(120, 1, true), at(2)), "raised overload")
+
+	// 97 % este sub prag, dar in banda de histerezis de 5 %: alarma ramane activa
+	expectEvents(t, "inside the hysteresis band", m.Evaluate(system, testResult(97, 1, true), at(10)))
+	if active := m.Active(); len(active) != 1 || active[0].State != StateActive {
+		t.Fatalf("alarm inside the hysteresis band: %v", active)
+	}
+
+	// Sub 95 % incepe stergerea, care asteapta clearDelay
+	expectEvents(t, "below the band", m.Evaluate(system, testResult(90, 1, true), at(11)))
+	if active := m.Active(); len(active) != 1 || active[0].State != StateClearing {
+		t.Fatalf("alarm below the band: %v", active)
+	}
+	expectEvents(t, "before clearDelay", m.Evaluate(system, testResult(90, 1, true), at(13)))
+
+	// O revenire peste banda repune alarma in starea activa si reporneste clearDelay
+	expectEvents(t, "back above the band", m.Evaluate(system, testResult(99, 1, true), at(14)))
+	if active := m.Active(); len(active) != 1 || active[0].State != StateActive {
+		t.Fatalf("alarm back above the band: %v", active)
+	}
+	expectEvents(t, "below the band again", m.Evaluate(system, testResult(90, 1, true), at(15)))
+	expectEvents(t, "clearDelay restarted", m.Evaluate(system, testResult(90, 1, true), at(17)))
+	expectEvents(t, "after clearDelay", m.Evaluate(system, testResult(90, 1, true), at(18)), "cleared overload")
+	if active := m.Active(); len(active) != 0 {
+		t.Fatalf("alarms after clearing: %v", active)
+	}
+}
+
+func TestDeenergizedElementKeepsAlarms(t *testing.T) {
+	m := NewManager()
+	system := testSystem()
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+
+	m.Evaluate(system, testResult(120, 0.85, true), at(0))
+	expectEvents(t, "raise", m.Evaluate(system, testResult(120, 0.85, true), at(2)), "raised overload", "raised undervoltage")
+
+	// Declansarea elementului nu sterge alarmele pe loc: tensiunea ramane neevaluata, suprasarcina trece prin clearDelay
+	expectEvents(t, "trip", m.Evaluate(system, testResult(0, 0, false), at(3)))
+	if active := m.Active(); len(active) != 2 {
+		t.Fatalf("alarms after the trip: %v", active)
+	}
+	expectEvents(t, "after clearDelay", m.Evaluate(system, testResult(0, 0, false), at(6)), "cleared overload")
+	if active := m.Active(); len(active) != 1 || active[0].Kind != KindUndervoltage {
+		t.Fatalf("alarms while de-energized: %v", active)
+	}
+}
+
+func TestRemovedElementClearsAlarms(t *testing.T) {
+	m := NewManager()
+	system := testSystem()
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	m.Evaluate(system, testResult(120, 1, true), start)
+	m.Evaluate(system, testResult(120, 1, true), start.Add(2*time.Second))
+
+	system.Lines = nil
+	expectEvents(t, "element removed", m.Evaluate(system, utils.SystemResult{Elements: map[string]*utils.ElementResult{}}, start.Add(3*time.Second)), "cleared overload")
+}
+
+func TestMissingElementMarksAlarms(t *testing.T) {
+	m := NewManager()
+	system := testSystem()
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+	missing := utils.SystemResult{Elements: map[string]*utils.ElementResult{}}
+
+	m.Evaluate(system, testResult(120, 0.85, true), at(0))
+	m.Evaluate(system, testResult(120, 0.85, true), at(2))
+
+	// Elementul ramane in configuratie, dar lipseste din rezultat: alarmele raman, marcate ca neevaluate
+	expectEvents(t, "missing", m.Evaluate(system, missing, at(3)))
+	active := m.Active()
+	if len(active) != 2 {
+		t.Fatalf("alarms while missing: %v", active)
+	}
+	for _, alarm := range active {
+		if !alarm.Unevaluated || alarm.State != StateActive {
+			t.Fatalf("alarm not marked as unevaluated: %+v", alarm)
+		}
+	}
+
+	// Cand elementul revine in rezultat, marcajul dispare si alarmele se sterg normal
+	m.Evaluate(system, testResult(50, 1, true), at(4))
+	for _, alarm := range m.Active() {
+		if alarm.Unevaluated {
+			t.Fatalf("alarm still unevaluated: %+v", alarm)
+		}
+	}
+	expectEvents(t, "back in band", m.Evaluate(system, testResult(50, 1, true), at(7)), "cleared overload", "cleared undervoltage")
+}
+
+func TestMissingElementDropsPendingAlarms(t *testing.T) {
+	m := NewManager()
+	system := testSystem()
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	m.Evaluate(system, testResult(120, 1, true), start)
+	expectEvents(t, "missing", m.Evaluate(system, utils.SystemResult{Elements: map[string]*utils.ElementResult{}}, start.Add(time.Second)))
+	// Intarzierea de ridicare reincepe cand elementul revine
+	expectEvents(t, "back", m.Evaluate(system, testResult(120, 1, true), start.Add(3*time.Second)))
+	expectEvents(t, "after raiseDelay", m.Evaluate(system, testResult(120, 1, true), start.Add(5*time.Second)), "raised overload")
+}
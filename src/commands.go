@@ -0,0 +1,474 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"contor-system/src/computing"
+	"contor-system/src/diagram"
+	"contor-system/src/export"
+	"contor-system/src/model"
+	"contor-system/src/report"
+	"contor-system/src/switching"
+	"contor-system/src/utils"
+)
+
+// command este o subcomanda CLI: contor <name> [flags] [arguments].
+type command struct {
+	usage string
+	run   func(args []string) error
+}
+
+var subcommands map[string]command
+
+func init() {
+	subcommands = map[string]command{
+		"run":         {"run the monitoring daemon for one network or a directory of networks", runCommand},
+		"solve":       {"compute the power flow once and print it as a table, JSON or CSV", solveCommand},
+		"validate":    {"check configurations for missing references and invalid values", validateCommand},
+		"diff":        {"compare two configurations element by element", diffCommand},
+		"diagram":     {"draw the single-line diagram of a configuration as SVG or Graphviz DOT", diagramCommand},
+		"contingency": {"run the N-1 contingency analysis", contingencyCommand},
+		"export":      {"compute the power flow and write the element results as CSV, JSON or Parquet", exportCommand},
+		"report":      {"compute the power flow and write a report as text tables, Markdown or HTML", reportCommand},
+	}
+}
+
+// printUsage listeaza subcomenzile.
+func printUsage() {
+	fmt.Fprintf(os.Stderr, "Usage: contor <command> [flags] [arguments]\n\nCommands:\n")
+	names := make([]string, 0, len(subcommands))
+	for name := range subcommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", name, subcommands[name].usage)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun contor <command> -h for the flags of a command. Without a command, contor runs the daemon with the legacy flags.\n")
+}
+
+// runSubcommand ruleaza subcomanda cu numele dat; intoarce false cand name nu este o subcomanda.
+func runSubcommand(name string, args []string) (bool, error) {
+	if name == "help" {
+		printUsage()
+		return true, nil
+	}
+	c, exists := subcommands[name]
+	if !exists {
+		return false, nil
+	}
+	return true, c.run(args)
+}
+
+// newFlagSet intoarce flagurile unei subcomenzi, cu descrierea afisata de -h.
+func newFlagSet(name string, arguments string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: contor %s [flags] %s\n\n%s\n\nFlags:\n", name, arguments, subcommands[name].usage)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// parseArgs citeste flagurile unei subcomenzi, inclusiv pe cele puse dupa argumente, si intoarce argumentele.
+// Tot ce urmeaza dupa "--" este argument.
+func parseArgs(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := flags.Args()
+		if consumed := args[:len(args)-len(rest)]; len(consumed) > 0 && consumed[len(consumed)-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// configArgument intoarce calea configuratiei data ca unic argument, sau valoarea flagului -config.
+func configArgument(positional []string, config string) (string, error) {
+	switch len(positional) {
+	case 0:
+		return config, nil
+	case 1:
+		return positional[0], nil
+	}
+	return "", fmt.Errorf("unexpected arguments %s: give a single configuration file", strings.Join(positional[1:], " "))
+}
+
+// parseConfigArgs citeste flagurile unei subcomenzi care primeste optional fisierul de configuratie ca argument.
+func parseConfigArgs(flags *flag.FlagSet, args []string, config *string) (string, error) {
+	positional, err := parseArgs(flags, args)
+	if err != nil {
+		return "", err
+	}
+	return configArgument(positional, *config)
+}
+
+func runCommand(args []string) error {
+	flags := newFlagSet("run", "")
+	config := flags.String("config", "./config.json", "network configuration file")
+	configDir := flags.String("config-dir", "", "run every *.json network configuration of the directory concurrently")
+	logDir := flags.String("log-dir", "logs", "log directory; with -config-dir each network logs to a subdirectory named after its file")
+	tick := flags.Duration("tick", time.Second, "calculation interval of the networks without daemon.tick")
+	switchingPlan := flags.String("switching", "", "execute a switching sequence file against the running model")
+	positional, err := parseArgs(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return fmt.Errorf("unexpected arguments %s: use -config or -config-dir", strings.Join(positional, " "))
+	}
+	if *tick <= 0 {
+		return errors.New("-tick must be positive")
+	}
+
+	var switchingRunner *switching.Runner
+	if *switchingPlan != "" {
+		if *configDir != "" {
+			return errors.New("-switching runs against a single network and cannot be used with -config-dir")
+		}
+		sequence, err := switching.Load(*switchingPlan)
+		if err != nil {
+			return fmt.Errorf("error loading switching sequence: %v", err)
+		}
+		switchingRunner = switching.NewRunner(sequence, time.Now())
+	}
+	if *configDir == "" {
+		if _, err := loadConfig(*config); err != nil {
+			return fmt.Errorf("failed to load initial config: %v", err)
+		}
+	}
+
+	runDaemon(*config, *configDir, *logDir, *tick, switchingRunner)
+	return nil
+}
+
+func solveCommand(args []string) error {
+	flags := newFlagSet("solve", "[config.json]")
+	config := flags.String("config", "./config.json", "network configuration file")
+	format := flags.String("format", string(export.FormatTable), "output format: table, json or csv")
+	engine := flags.String("engine", "", "override solver.engine: traversal or sweep")
+	path, err := parseConfigArgs(flags, args, config)
+	if err != nil {
+		return err
+	}
+	switch export.Format(*format) {
+	case export.FormatTable, export.FormatJSON, export.FormatCSV:
+	default:
+		return fmt.Errorf("unknown format %q: use table, json or csv", *format)
+	}
+	switch utils.EngineType(*engine) {
+	case "", utils.EngineTraversal, utils.EngineSweep:
+	default:
+		return fmt.Errorf("unknown engine %q: use %s or %s", *engine, utils.EngineTraversal, utils.EngineSweep)
+	}
+
+	system, err := loadConfig(path)
+	if err != nil {
+		return err
+	}
+	if *engine != "" {
+		system.Solver.Engine = utils.EngineType(*engine)
+	}
+	result := solveQuiet(system)
+
+	switch export.Format(*format) {
+	case export.FormatTable:
+		fmt.Print(export.FormatResultTable(result))
+		return nil
+	case export.FormatJSON:
+		return export.WriteResultJSON(os.Stdout, result)
+	default:
+		return export.WriteCSV(os.Stdout, export.Rows(result))
+	}
+}
+
+func validateCommand(args []string) error {
+	flags := newFlagSet("validate", "[config.json ...]")
+	config := flags.String("config", "./config.json", "network configuration file, when no files are given")
+	format := flags.String("format", "text", "output format: text or json")
+	paths, err := parseArgs(flags, args)
+	if err != nil {
+		return err
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("unknown format %q: use text or json", *format)
+	}
+
+	if len(paths) == 0 {
+		paths = []string{*config}
+	}
+	reports := map[string][]model.Issue{}
+	invalid := 0
+	for _, path := range paths {
+		system, err := loadConfig(path)
+		if err != nil {
+			reports[path] = []model.Issue{{Severity: model.SeverityError, Message: err.Error()}}
+		} else {
+			reports[path] = model.Validate(system)
+		}
+		if model.HasErrors(reports[path]) {
+			invalid++
+		}
+		if *format == "text" {
+			if len(paths) > 1 {
+				fmt.Printf("%s:\n", path)
+			}
+			fmt.Print(model.FormatIssues(reports[path]))
+		}
+	}
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(reports); err != nil {
+			return err
+		}
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d configurations have errors", invalid, len(paths))
+	}
+	return nil
+}
+
+func diffCommand(args []string) error {
+	flags := newFlagSet("diff", "a.json b.json")
+	format := flags.String("format", "text", "output format: text or json")
+	paths, err := parseArgs(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(paths) != 2 {
+		flags.Usage()
+		return errors.New("diff needs two configuration files")
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("unknown format %q: use text or json", *format)
+	}
+
+	a, err := loadConfig(paths[0])
+	if err != nil {
+		return err
+	}
+	b, err := loadConfig(paths[1])
+	if err != nil {
+		return err
+	}
+	changes, err := model.Diff(a, b)
+	if err != nil {
+		return err
+	}
+
+	if *format == "text" {
+		fmt.Print(model.FormatDiff(changes))
+		return nil
+	}
+	if changes == nil {
+		changes = []model.Change{}
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(changes)
+}
+
+func contingencyCommand(args []string) error {
+	flags := newFlagSet("contingency", "[config.json]")
+	config := flags.String("config", "./config.json", "network configuration file")
+	sources := flags.Bool("sources", false, "include source outages")
+	format := flags.String("format", "text", "output format: text or json")
+	path, err := parseConfigArgs(flags, args, config)
+	if err != nil {
+		return err
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("unknown format %q: use text or json", *format)
+	}
+
+	system, err := loadConfig(path)
+	if err != nil {
+		return err
+	}
+	report := computing.RunContingencies(system, computing.ContingencyOptions{IncludeSources: *sources})
+
+	if *format == "text" {
+		fmt.Print(computing.FormatContingencyReport(report))
+		return nil
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+func exportCommand(args []string) error {
+	flags := newFlagSet("export", "[config.json]")
+	config := flags.String("config", "./config.json", "network configuration file")
+	format := flags.String("format", string(export.FormatCSV), "output format: csv, json or parquet")
+	output := flags.String("o", "", "output file; standard output when empty (required for parquet)")
+	path, err := parseConfigArgs(flags, args, config)
+	if err != nil {
+		return err
+	}
+	var write func(w io.Writer, rows []export.Row) error
+	switch export.Format(*format) {
+	case export.FormatCSV:
+		write = export.WriteCSV
+	case export.FormatJSON:
+		write = export.WriteJSON
+	case export.FormatParquet:
+		if *output == "" {
+			return errors.New("parquet export needs an output file (-o)")
+		}
+	default:
+		return fmt.Errorf("unknown format %q: use %s", *format, strings.Join([]string{string(export.FormatCSV), string(export.FormatJSON), string(export.FormatParquet)}, ", "))
+	}
+
+	system, err := loadConfig(path)
+	if err != nil {
+		return err
+	}
+	rows := export.Rows(solveQuiet(system))
+
+	if write == nil {
+		return export.WriteParquet(*output, rows)
+	}
+	if *output == "" {
+		return write(os.Stdout, rows)
+	}
+	file, err := os.Create(*output)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", *output, err)
+	}
+	if err := write(file, rows); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %v", *output, err)
+	}
+	return nil
+}
+
+func reportCommand(args []string) error {
+	flags := newFlagSet("report", "[config.json]")
+	config := flags.String("config", "./config.json", "network configuration file")
+	format := flags.String("format", "text", "output format: text, markdown or html")
+	output := flags.String("o", "", "output file; standard output when empty")
+	title := flags.String("title", "", "report title; the configuration file name when empty")
+	path, err := parseConfigArgs(flags, args, config)
+	if err != nil {
+		return err
+	}
+	if *format != "text" && *format != "markdown" && *format != "html" {
+		return fmt.Errorf("unknown format %q: use text, markdown or html", *format)
+	}
+
+	system, err := loadConfig(path)
+	if err != nil {
+		return err
+	}
+	if *title == "" {
+		*title = "Power flow report: " + filepath.Base(path)
+	}
+	content, err := formatReport(report.Build(*title, system, solveQuiet(system), time.Now()), *format)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		fmt.Print(content)
+		return nil
+	}
+	if err := os.WriteFile(*output, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", *output, err)
+	}
+	return nil
+}
+
+// solveQuiet ruleaza motorul ales, dupa ce semnaleaza datele din configuratie pe care acesta nu le foloseste.
+func solveQuiet(system utils.System) utils.SystemResult {
+	for _, warning := range computing.EngineWarnings(system) {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+	}
+	return computing.SolveQuiet(system)
+}
+
+// formatReport reda raportul in unul dintre formatele text, markdown sau html.
+func formatReport(r report.Report, format string) (string, error) {
+	switch format {
+	case "text":
+		return report.FormatText(r), nil
+	case "markdown":
+		return report.FormatMarkdown(r), nil
+	case "html":
+		var b strings.Builder
+		if err := report.WriteHTML(&b, r); err != nil {
+			return "", err
+		}
+		return b.String(), nil
+	}
+	return "", fmt.Errorf("unknown format %q: use text, markdown or html", format)
+}
+
+func diagramCommand(args []string) error {
+	flags := newFlagSet("diagram", "[config.json]")
+	config := flags.String("config", "./config.json", "network configuration file")
+	format := flags.String("format", "svg", "output format: svg or dot")
+	output := flags.String("o", "", "output file; standard output when empty")
+	flows := flags.Bool("flows", true, "compute the power flow and colour the diagram by loading and voltage")
+	path, err := parseConfigArgs(flags, args, config)
+	if err != nil {
+		return err
+	}
+	if *format != "svg" && *format != "dot" {
+		return fmt.Errorf("unknown format %q: use svg or dot", *format)
+	}
+
+	system, err := loadConfig(path)
+	if err != nil {
+		return err
+	}
+	var result *utils.SystemResult
+	if *flows {
+		solved := solveQuiet(system)
+		result = &solved
+	}
+	content, err := formatDiagram(diagram.Build(system, result), *format)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		fmt.Print(content)
+		return nil
+	}
+	if err := os.WriteFile(*output, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", *output, err)
+	}
+	return nil
+}
+
+// formatDiagram reda schema in unul dintre formatele svg sau dot.
+func formatDiagram(d diagram.Diagram, format string) (string, error) {
+	switch format {
+	case "svg":
+		return diagram.FormatSVG(d), nil
+	case "dot":
+		return diagram.FormatDOT(d), nil
+	}
+	return "", fmt.Errorf("unknown format %q: use svg or dot", format)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseArgsAfterPositional(t *testing.T) {
+	flags := newFlagSet("solve", "[config.json]")
+	format := flags.String("format", "table", "")
+	positional, err := parseArgs(flags, []string{"config.json", "-format", "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *format != "json" {
+		t.Fatalf("format %q, expected json", *format)
+	}
+	if len(positional) != 1 || positional[0] != "config.json" {
+		t.Fatalf("arguments %v", positional)
+	}
+}
+
+func TestParseArgsTerminator(t *testing.T) {
+	flags := newFlagSet("validate", "[config.json ...]")
+	format := flags.String("format", "text", "")
+	positional, err := parseArgs(flags, []string{"a.json", "--", "-format", "b.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *format != "text" {
+		t.Fatalf("a flag after -- was parsed: format %q", *format)
+	}
+	if strings.Join(positional, " ") != "a.json -format b.json" {
+		t.Fatalf("arguments %v", positional)
+	}
+}
+
+func TestConfigArgument(t *testing.T) {
+	if path, err := configArgument(nil, "default.json"); err != nil || path != "default.json" {
+		t.Fatalf("no argument: %q, %v", path, err)
+	}
+	if path, err := configArgument([]string{"a.json"}, "default.json"); err != nil || path != "a.json" {
+		t.Fatalf("one argument: %q, %v", path, err)
+	}
+	if _, err := configArgument([]string{"a.json", "b.json"}, "default.json"); err == nil {
+		t.Fatal("trailing arguments accepted")
+	}
+}
+
+func TestSolveRejectsInvalidFlags(t *testing.T) {
+	for _, args := range [][]string{
+		{"-engine", "bogus", "../config.json"},
+		{"../config.json", "-format", "xml"},
+		{"../config.json", "extra.json"},
+	} {
+		if err := solveCommand(args); err == nil {
+			t.Fatalf("solve %v accepted", args)
+		}
+	}
+}
+
+func TestRunRejectsArguments(t *testing.T) {
+	if err := runCommand([]string{"config.json"}); err == nil {
+		t.Fatal("run accepted a positional argument")
+	}
+}
+
+func TestExportUnknownFormatKeepsOutput(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "rows.xml")
+	if err := os.WriteFile(output, []byte("previous"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := exportCommand([]string{"-format", "xml", "-o", output, "../config.json"}); err == nil {
+		t.Fatal("unknown format accepted")
+	}
+	if content, _ := os.ReadFile(output); string(content) != "previous" {
+		t.Fatalf("the output file was overwritten: %q", content)
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "rows.csv")
+	if err := exportCommand([]string{"../config.json", "-o", output}); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) < 2 || !strings.Contains(lines[0], "id") {
+		t.Fatalf("unexpected CSV:\n%s", content)
+	}
+}
+
+// captureStdout intoarce ce scrie run la iesirea standard.
+func captureStdout(t *testing.T, run func() error) string {
+	t.Helper()
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	output := make(chan string)
+	go func() {
+		content, _ := io.ReadAll(reader)
+		output <- string(content)
+	}()
+	runErr := run()
+	writer.Close()
+	os.Stdout = stdout
+	if runErr != nil {
+		t.Fatal(runErr)
+	}
+	return <-output
+}
+
+func TestSolveUnpoweredConsumer(t *testing.T) {
+	// Sursa de 5 MW nu acopera consumatorul de 8 MW: lipsesc 3 MW
+	system := map[string]interface{}{
+		"source":    map[string]interface{}{"id": "source1", "power": 5, "voltage": 20, "connectedTo": "consumer1"},
+		"consumers": []interface{}{map[string]interface{}{"id": "consumer1", "powerNeeded": 8, "voltage": 20}},
+	}
+	content, err := json.Marshal(system)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(config, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	output := captureStdout(t, func() error { return solveCommand([]string{config}) })
+	if !strings.Contains(output, "Consumer consumer1 is missing 3.00 MW") {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+}
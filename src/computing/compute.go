@@ -2,15 +2,15 @@ package computing
 
 import (
 	"fmt"
+	"io"
 	"math"
+	"os"
 	"reflect"
 	"time"
 
 	"contor-system/src/utils"
 )
 
-var logs []LogEntry
-
 type LogEntry = utils.LogEntry
 
 // Funcția de conversie volți în kilovolți
@@ -150,7 +150,7 @@ func transformerLossesBasedOnEfficency(powerInput float64, efficency float64) fl
 Functie pentru distanta medie geometrica
 */
 func geometricDistance(Drs float64, Dst float64, Drt float64) float64 {
-	var Dm = math.Pow(Drs*Dst*Drt, 1/3)
+	var Dm = math.Pow(Drs*Dst*Drt, 1.0/3)
 	return Dm
 }
 
@@ -158,7 +158,7 @@ func geometricDistance(Drs float64, Dst float64, Drt float64) float64 {
 Functie pentru raza echivalenta
 */
 func equivalentRadius(r float64) float64 {
-	var re = math.Pow(math.E, -(1/4)) * r
+	var re = math.Pow(math.E, -0.25) * r
 	return re
 }
 
@@ -291,26 +291,85 @@ func isActive(nID string, config *utils.System) bool {
 	return isActive
 }
 
-func calculatePowerFlow(id string, inputPower float64, config *utils.System, nodes map[string]interface{}, visited map[string]bool, consumersWithoutPower *[]utils.ConsumerPowerDetails) {
+// flowState retine starea unei parcurgeri a retelei.
+type flowState struct {
+	nodes                 map[string]interface{}
+	visited               map[string]bool
+	consumersWithoutPower []utils.ConsumerPowerDetails
+	result                utils.SystemResult
+	voltagePU             float64 // tensiunea (u.r.) in punctul curent al parcurgerii
+	logs                  []LogEntry
+	out                   io.Writer // destinatia mesajelor de urmarire a parcurgerii
+}
+
+func newFlowState(out io.Writer) *flowState {
+	return &flowState{
+		out:     out,
+		visited: map[string]bool{},
+		result: utils.SystemResult{
+			Elements: map[string]*utils.ElementResult{},
+		},
+		voltagePU: 1,
+	}
+}
+
+// record salveaza rezultatul unui element, pastrand ordinea parcurgerii.
+func (s *flowState) record(r utils.ElementResult) {
+	// Un element fara tensiune (puterea de intrare <= 0) nu transporta putere si nu are pierderi; puterea negativa
+	// ramasa din parcurgere este doar deficitul consumatorilor din amonte
+	if !r.Energized {
+		r.ActivePower, r.ReactivePower, r.Current, r.Loading, r.PowerFactor = 0, 0, 0, 0, 0
+		r.ActivePowerLosses, r.ReactivePowerLosses = 0, 0
+	}
+	if _, exists := s.result.Elements[r.ID]; !exists {
+		s.result.Order = append(s.result.Order, r.ID)
+	}
+	s.result.Elements[r.ID] = &r
+}
+
+/*
+Functie pentru caderea de tensiune pe linie in u.r.: dU = (P*R + Q*X) / U^2
+- p: MW, q: MVAr
+- r, x: ohm
+- u: kV
+*/
+func voltageDropPU(p float64, q float64, r float64, x float64, u float64) float64 {
+	if u == 0 {
+		return 0
+	}
+	return (p*r + q*x) / math.Pow(u, 2)
+}
+
+/*
+Functie pentru gradul de incarcare: valoare / valoare nominala * 100
+*/
+func loadingPercent(value float64, rated float64) float64 {
+	if rated == 0 {
+		return 0
+	}
+	return math.Abs(value) / rated * 100
+}
+
+func calculatePowerFlow(id string, inputPower float64, config *utils.System, state *flowState) {
 	const LowVoltage = 20
 
 	// Check if the node has already been visited
-	if visited[id] {
+	if state.visited[id] {
 		return
 	}
-	visited[id] = true
+	state.visited[id] = true
 
-	node, exists := nodes[id]
+	node, exists := state.nodes[id]
 	if !exists {
-		fmt.Printf("Node %s not found\n", id)
+		fmt.Fprintf(state.out, "Node %s not found\n", id)
 		return
 	}
 
-	fmt.Printf("Traversing node: %s\n", id)
+	fmt.Fprintf(state.out, "Traversing node: %s\n", id)
 
 	switch n := node.(type) {
 	case utils.Source:
-		fmt.Printf("Source %s receiving power: %.2f\n", n.ID, inputPower)
+		fmt.Fprintf(state.out, "Source %s receiving power: %.2f\n", n.ID, inputPower)
 		// Update the source's power in the config
 		if n.ID == config.Source.ID {
 			config.Source.Power = inputPower
@@ -322,13 +381,24 @@ func calculatePowerFlow(id string, inputPower float64, config *utils.System, nod
 				}
 			}
 		}
-		calculatePowerFlow(n.ConnectedTo, inputPower, config, nodes, visited, consumersWithoutPower)
+		state.record(utils.ElementResult{
+			ID:            n.ID,
+			Kind:          "source",
+			ActivePower:   inputPower,
+			ReactivePower: n.ReactivePower,
+			Voltage:       n.Voltage * state.voltagePU,
+			VoltagePU:     state.voltagePU,
+			PowerFactor:   powerFactor(inputPower, n.ReactivePower),
+			Energized:     inputPower > 0,
+		})
+		calculatePowerFlow(n.ConnectedTo, inputPower, config, state)
 
 	case utils.Transformer:
 		var isActive = isActive(n.ID, config)
 
 		if !isActive {
-			calculatePowerFlow(n.ConnectedTo, 0, config, nodes, visited, consumersWithoutPower)
+			state.record(utils.ElementResult{ID: n.ID, Kind: "transformer"})
+			calculatePowerFlow(n.ConnectedTo, 0, config, state)
 			return
 		}
 
@@ -345,7 +415,7 @@ func calculatePowerFlow(id string, inputPower float64, config *utils.System, nod
 			Message:     transformerMessage,
 		}
 
-		logs = append(logs, transformerInfoLog)
+		state.logs = append(state.logs, transformerInfoLog)
 
 		if n.InputVoltage == LowVoltage {
 			outputPower = inputPower - totalCooperAndSteelLosses
@@ -353,7 +423,7 @@ func calculatePowerFlow(id string, inputPower float64, config *utils.System, nod
 			outputPower = inputPower
 		}
 
-		// fmt.Printf("Transformer %s transferring power: %.2f -> %.2f\n", n.ID, inputPower, outputPower)
+		// fmt.Fprintf(state.out, "Transformer %s transferring power: %.2f -> %.2f\n", n.ID, inputPower, outputPower)
 		// Update the transformer's power in the config
 		for i, transformer := range config.Transformers {
 			if transformer.ID == n.ID {
@@ -361,18 +431,33 @@ func calculatePowerFlow(id string, inputPower float64, config *utils.System, nod
 				break
 			}
 		}
-		calculatePowerFlow(n.ConnectedTo, outputPower, config, nodes, visited, consumersWithoutPower)
+
+		state.record(utils.ElementResult{
+			ID:                n.ID,
+			Kind:              "transformer",
+			ActivePower:       outputPower,
+			ReactivePower:     n.ReactivePowerTransfered,
+			Current:           currentFromPower(outputPower, n.OutputVoltage),
+			Voltage:           n.OutputVoltage * state.voltagePU,
+			VoltagePU:         state.voltagePU,
+			Loading:           loadingPercent(apparentPower(outputPower, n.ReactivePowerTransfered), n.ApparentPower),
+			PowerFactor:       powerFactor(outputPower, n.ReactivePowerTransfered),
+			ActivePowerLosses: inputPower - outputPower,
+			Energized:         inputPower > 0,
+		})
+		calculatePowerFlow(n.ConnectedTo, outputPower, config, state)
 
 	case utils.Line:
 		var isActive = isActive(n.ID, config)
 
 		if !isActive {
-			calculatePowerFlow(n.ConnectedTo, 0, config, nodes, visited, consumersWithoutPower)
+			state.record(utils.ElementResult{ID: n.ID, Kind: "line"})
+			calculatePowerFlow(n.ConnectedTo, 0, config, state)
 			return
 		}
 
 		// Print the power being transferred through the line
-		fmt.Printf("Line %s transferring power: %.2f\n", n.ID, inputPower)
+		fmt.Fprintf(state.out, "Line %s transferring power: %.2f\n", n.ID, inputPower)
 
 		// Update the line's power in the config
 		for i, line := range config.Lines {
@@ -396,7 +481,7 @@ func calculatePowerFlow(id string, inputPower float64, config *utils.System, nod
 				var sinFi = sinfi(cosFi)
 				var Q = (line.Voltage * 1000) * lineCurrent * sinFi
 
-				fmt.Printf("Reactive power on line: %.2f \n", Q)
+				fmt.Fprintf(state.out, "Reactive power on line: %.2f \n", Q)
 
 				var lineInfoMessage = fmt.Sprintf("Line %s (%d km) has voltage %.2f kV , Active power losses per line %.3f, Reactive power losses per line %.3f \n", line.ID, line.Length, line.Voltage, activePowerLoseesPerLine, reactivePowerLoseesPerLine)
 
@@ -406,18 +491,44 @@ func calculatePowerFlow(id string, inputPower float64, config *utils.System, nod
 					Message:     lineInfoMessage,
 				}
 
-				logs = append(logs, lineInfoLog)
+				state.logs = append(state.logs, lineInfoLog)
 
 				config.Lines[i].PowerTransfered = inputPower
+				config.Lines[i].Currnet = lineCurrent
+				config.Lines[i].ActivePowerLosses = activePowerLoseesPerLine
+				config.Lines[i].ReactivePowerLosses = reactivePowerLoseesPerLine
+
+				if apparentPower == 0 {
+					cosFi, Q = 1, 0
+				}
+				if inputPower > 0 {
+					state.voltagePU -= voltageDropPU(inputPower, wattToMegawatt(Q), lineResistence, XL, line.Voltage)
+				}
+
+				state.record(utils.ElementResult{
+					ID:                  line.ID,
+					Kind:                "line",
+					ActivePower:         inputPower,
+					ReactivePower:       wattToMegawatt(Q),
+					Current:             math.Abs(lineCurrent),
+					Voltage:             line.Voltage * state.voltagePU,
+					VoltagePU:           state.voltagePU,
+					Loading:             loadingPercent(lineCurrent, line.RatedCurrent),
+					PowerFactor:         cosFi,
+					ActivePowerLosses:   activePowerLoseesPerLine,
+					ReactivePowerLosses: reactivePowerLoseesPerLine,
+					Energized:           inputPower > 0,
+				})
 				break
 			}
 		}
-		calculatePowerFlow(n.ConnectedTo, inputPower, config, nodes, visited, consumersWithoutPower)
+		calculatePowerFlow(n.ConnectedTo, inputPower, config, state)
 
 	case utils.Separator:
 		if n.State == utils.StateOpen {
-			fmt.Printf("Separator %s is open, stopping power flow.\n", n.ID)
-			calculatePowerFlow(n.ConnectedTo, 0, config, nodes, visited, consumersWithoutPower)
+			fmt.Fprintf(state.out, "Separator %s is open, stopping power flow.\n", n.ID)
+			state.record(utils.ElementResult{ID: n.ID, Kind: "separator"})
+			calculatePowerFlow(n.ConnectedTo, 0, config, state)
 			return
 		}
 		for i, separator := range config.Separators {
@@ -430,37 +541,49 @@ func calculatePowerFlow(id string, inputPower float64, config *utils.System, nod
 					Message:     separatorMessage,
 				}
 
-				logs = append(logs, separatorLog)
+				state.logs = append(state.logs, separatorLog)
 
 				config.Separators[i].State = n.State
 				break
 			}
 		}
-		calculatePowerFlow(n.ConnectedTo, inputPower, config, nodes, visited, consumersWithoutPower)
+		state.record(utils.ElementResult{
+			ID:          n.ID,
+			Kind:        "separator",
+			ActivePower: inputPower,
+			VoltagePU:   state.voltagePU,
+			PowerFactor: 1,
+			Energized:   inputPower > 0,
+		})
+		calculatePowerFlow(n.ConnectedTo, inputPower, config, state)
 
 	case utils.Consumer:
 		var isActive = isActive(n.ID, config)
+		// Puterea ceruta depinde de tensiunea din punctul de racord
+		demand := consumerLoadAt(n, state.voltagePU)
+		powerNeeded := real(demand)
 
-		remainingPower := inputPower - n.PowerNeeded
+		remainingPower := inputPower - powerNeeded
 
-		if inputPower < n.PowerNeeded {
-			*consumersWithoutPower = append(*consumersWithoutPower, utils.ConsumerPowerDetails{
+		if inputPower < powerNeeded {
+			state.consumersWithoutPower = append(state.consumersWithoutPower, utils.ConsumerPowerDetails{
 				ID:                   n.ID,
 				RemainingPowerNeeded: remainingPower,
 			})
 		}
 
 		if !isActive {
-			calculatePowerFlow(n.ConnectedTo, 0, config, nodes, visited, consumersWithoutPower)
+			state.record(utils.ElementResult{ID: n.ID, Kind: "consumer"})
+			calculatePowerFlow(n.ConnectedTo, 0, config, state)
 			return
 		}
 
-		fmt.Printf("Consumer %s received power: %.2f, remaining: %.2f\n", n.ID, inputPower, remainingPower)
+		fmt.Fprintf(state.out, "Consumer %s received power: %.2f, remaining: %.2f\n", n.ID, inputPower, remainingPower)
 		// Update the consumer's remaining power in the config
 		for i, consumer := range config.Consumers {
 			if consumer.ID == n.ID {
 				config.Consumers[i].RemainingPower = remainingPower
-				var consumerMessage = fmt.Sprintf("Consumer %s draws %.2f MW at %.2f kV\n", consumer.ID, consumer.PowerNeeded, consumer.Voltage)
+				var consumerMessage = fmt.Sprintf("Consumer %s draws %.2f MW at %.2f kV\n", consumer.ID, powerNeeded, consumer.Voltage)
 
 				consumerLog := LogEntry{
 					Timestamp:   time.Now().Format("2006/01/02-15:04:05"),
@@ -468,21 +591,68 @@ func calculatePowerFlow(id string, inputPower float64, config *utils.System, nod
 					Message:     consumerMessage,
 				}
 
-				logs = append(logs, consumerLog)
+				state.logs = append(state.logs, consumerLog)
 
 				break
 			}
 		}
-		calculatePowerFlow(n.ConnectedTo, remainingPower, config, nodes, visited, consumersWithoutPower)
+		state.record(utils.ElementResult{
+			ID:            n.ID,
+			Kind:          "consumer",
+			ActivePower:   math.Min(powerNeeded, math.Max(inputPower, 0)),
+			ReactivePower: imag(demand),
+			Current:       currentFromPower(apparentPower(powerNeeded, imag(demand)), n.Voltage*state.voltagePU),
+			Voltage:       n.Voltage * state.voltagePU,
+			VoltagePU:     state.voltagePU,
+			PowerFactor:   powerFactor(powerNeeded, imag(demand)),
+			Energized:     inputPower > 0,
+		})
+		calculatePowerFlow(n.ConnectedTo, remainingPower, config, state)
 
 	default:
-		fmt.Printf("Unhandled node type for ID: %s\n", id)
+		fmt.Fprintf(state.out, "Unhandled node type for ID: %s\n", id)
+	}
+}
+
+/*
+Functie pentru curentul absorbit la o putere si tensiune date: I = P / U
+- p: MW
+- u: kV
+- I: A
+*/
+func currentFromPower(p float64, u float64) float64 {
+	if u == 0 {
+		return 0
 	}
+	return math.Abs(p) * 1000 / u
+}
+
+/*
+Functie pentru factorul de putere; un element fara putere reactiva are cosfi = 1
+*/
+func powerFactor(p float64, q float64) float64 {
+	if q == 0 {
+		return 1
+	}
+	return math.Abs(cosfi(p, q))
 }
 
 // Funcția principală pentru calcul
 func ComputeSystem(system utils.System) []LogEntry {
-	fmt.Println("Calculating power flow for the system...")
+	_, entries := ComputeSystemResult(system)
+	return entries
+}
+
+// ComputeSystemResult calculeaza circulatia de puteri si intoarce, pe langa loguri, marimile calculate pentru fiecare element.
+func ComputeSystemResult(system utils.System) (utils.SystemResult, []LogEntry) {
+	return computeSystem(system, os.Stdout)
+}
+
+// computeSystem ruleaza calculul scriind mesajele de urmarire in out (io.Discard pentru calcule repetate, ex. analiza N-1).
+func computeSystem(system utils.System, out io.Writer) (utils.SystemResult, []LogEntry) {
+	state := newFlowState(out)
+
+	fmt.Fprintln(state.out, "Calculating power flow for the system...")
 
 	// Verifică sursa inițială
 	sourcePower := system.Source.Power
@@ -490,7 +660,7 @@ func ComputeSystem(system utils.System) []LogEntry {
 
 	var sourceMessage = fmt.Sprintf("Source %s supplying %.2f MW at %.2f kV\n", system.Source.ID, sourcePower, sourceVoltage)
 
-	// fmt.Println(sourceMessage)
+	// fmt.Fprintln(state.out, sourceMessage)
 
 	sourceLog := LogEntry{
 		Timestamp:   time.Now().Format("2006/01/02-15:04:05"),
@@ -498,11 +668,10 @@ func ComputeSystem(system utils.System) []LogEntry {
 		Message:     sourceMessage,
 	}
 
-	logs = append(logs, sourceLog)
+	state.logs = append(state.logs, sourceLog)
 
 	// Map nodes for quick lookup
 	nodes := map[string]interface{}{}
-	visited := map[string]bool{} // Track visited nodes
 
 	// Populate nodes
 	nodes[system.Source.ID] = system.Source
@@ -523,10 +692,10 @@ func ComputeSystem(system utils.System) []LogEntry {
 		nodes[c.ID] = c
 	}
 
-	consumersWithoutPower := []utils.ConsumerPowerDetails{}
+	state.nodes = nodes
 
 	// Start traversal from the source
-	calculatePowerFlow(system.Source.ID, system.Source.Power, &system, nodes, visited, &consumersWithoutPower)
+	calculatePowerFlow(system.Source.ID, system.Source.Power, &system, state)
 
 	// Reverse calculation from additional sources
 	for _, source := range system.AdditionalSources {
@@ -537,12 +706,14 @@ func ComputeSystem(system utils.System) []LogEntry {
 			}
 		}
 		if isSeparatorClose {
-			calculatePowerFlow(source.ID, source.Power, &system, nodes, visited, &consumersWithoutPower)
+			state.voltagePU = 1
+			calculatePowerFlow(source.ID, source.Power, &system, state)
 		}
 	}
 
+	consumersWithoutPower := state.consumersWithoutPower
 	if len(consumersWithoutPower) > 0 {
-		fmt.Println("Consumers without power:")
+		fmt.Fprintln(state.out, "Consumers without power:")
 		for _, consumerID := range consumersWithoutPower {
 			var emptyConsumerMessage = fmt.Sprintf("Consumer %s needs more power: %.2f MW\n", consumerID.ID, math.Abs(consumerID.RemainingPowerNeeded))
 
@@ -552,13 +723,22 @@ func ComputeSystem(system utils.System) []LogEntry {
 				Message:     emptyConsumerMessage,
 			}
 
-			logs = append(logs, emptyConsumerMessageLog)
-			fmt.Printf("- Consumer ID: %sn", consumerID.ID)
-			fmt.Printf("- Consumer Power Needed: %fn", consumerID.RemainingPowerNeeded)
+			state.logs = append(state.logs, emptyConsumerMessageLog)
+			fmt.Fprintf(state.out, "- Consumer ID: %sn", consumerID.ID)
+			fmt.Fprintf(state.out, "- Consumer Power Needed: %fn", consumerID.RemainingPowerNeeded)
 		}
 	} else {
-		fmt.Println("All consumers are powered.")
+		fmt.Fprintln(state.out, "All consumers are powered.")
+	}
+
+	result := state.result
+	result.ConsumersWithoutPower = consumersWithoutPower
+	result.Engine = utils.EngineTraversal
+	result.Converged = true
+	for _, element := range result.Elements {
+		result.ActivePowerLosses += element.ActivePowerLosses
+		result.ReactivePowerLosses += element.ReactivePowerLosses
 	}
 
-	return logs
+	return result, state.logs
 }
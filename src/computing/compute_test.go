@@ -0,0 +1,38 @@
+package computing
+
+import (
+	"io"
+	"math"
+	"testing"
+
+	"contor-system/src/utils"
+)
+
+func TestTraversalDeenergizedElements(t *testing.T) {
+	// Sursa de 10 MW nu acopera consumer1 (60 MW): line2 si consumer2 raman fara tensiune
+	system := opfSystem(2000)
+	system.Source.Power = 10
+	system.Consumers[0].ConnectedTo = "line2"
+	system.Lines = append(system.Lines, utils.Line{
+		ID: "line2", Voltage: 20, Length: 5, ConnectedTo: "consumer2", Area: 240, Ro: 0.0295,
+		Drs: 1, Dst: 1, Drt: 1, ConductorDiameter: 2, R: 0.01,
+	})
+	system.Consumers = append(system.Consumers, utils.Consumer{ID: "consumer2", PowerNeeded: 10, ReactivePowerAbsorbed: 2, Voltage: 20})
+	system.Separators = nil
+	system.AdditionalSources = nil
+
+	result, _ := computeSystem(system, io.Discard)
+	for _, id := range []string{"line2", "consumer2"} {
+		element := result.Elements[id]
+		if element.Energized || element.ActivePower != 0 || element.ReactivePower != 0 || element.Current != 0 || element.ActivePowerLosses != 0 {
+			t.Fatalf("de-energized %s recorded %+v", id, element)
+		}
+	}
+	line1 := result.Elements["line1"]
+	if line1.Current < 0 {
+		t.Fatalf("line1 current %.2f A", line1.Current)
+	}
+	if math.Abs(result.ActivePowerLosses-line1.ActivePowerLosses) > 1e-12 {
+		t.Fatalf("system losses %.6f MW, expected the line1 losses %.6f MW", result.ActivePowerLosses, line1.ActivePowerLosses)
+	}
+}
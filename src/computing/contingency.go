@@ -0,0 +1,237 @@
+package computing
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"contor-system/src/utils"
+)
+
+// Analiza N-1: fiecare linie / transformator (si optional fiecare sursa) este scos pe rand din retea si se reface calculul.
+
+type ViolationKind string
+
+const (
+	ViolationOverload     ViolationKind = "overload"
+	ViolationUndervoltage ViolationKind = "undervoltage"
+	ViolationOvervoltage  ViolationKind = "overvoltage"
+)
+
+type Violation struct {
+	ElementID string        `json:"elementId"`
+	Kind      ViolationKind `json:"kind"`
+	Value     float64       `json:"value"`
+	Limit     float64       `json:"limit"`
+}
+
+type UnservedConsumer struct {
+	ID            string  `json:"id"`
+	UnservedPower float64 `json:"unservedPower"` // MW
+}
+
+type ContingencyCase struct {
+	Outage            string             `json:"outage"` // elementul scos din retea, gol pentru cazul de baza
+	OutageKind        string             `json:"outageKind"`
+	Overloads         []Violation        `json:"overloads"`
+	VoltageViolations []Violation        `json:"voltageViolations"`
+	UnservedConsumers []UnservedConsumer `json:"unservedConsumers"`
+	UnservedPower     float64            `json:"unservedPower"`     // MW
+	UnservedIncrease  float64            `json:"unservedIncrease"`  // MW, fata de cazul de baza
+	ActivePowerLosses float64            `json:"activePowerLosses"` // MW
+}
+
+type ContingencyReport struct {
+	Base  ContingencyCase   `json:"base"`
+	Cases []ContingencyCase `json:"cases"` // ordonate de la cel mai sever caz
+}
+
+type ContingencyOptions struct {
+	IncludeSources bool // scoate pe rand si sursele, pe langa linii si transformatoare
+}
+
+// cloneSystem copiaza listele sistemului, calculul modificand elementele pe loc.
+func cloneSystem(system utils.System) utils.System {
+	clone := system
+	clone.Transformers = append([]utils.Transformer(nil), system.Transformers...)
+	clone.Lines = append([]utils.Line(nil), system.Lines...)
+	clone.Consumers = append([]utils.Consumer(nil), system.Consumers...)
+	clone.Separators = append([]utils.Separator(nil), system.Separators...)
+	clone.AdditionalSources = append([]utils.Source(nil), system.AdditionalSources...)
+	clone.Batteries = append([]utils.Battery(nil), system.Batteries...)
+	clone.Shunts = append([]utils.Shunt(nil), system.Shunts...)
+	return clone
+}
+
+// WithOutage intoarce o copie a sistemului fara elementul id. Sursa principala nu poate lipsi, ea ramane fara putere.
+func WithOutage(system utils.System, id string) utils.System {
+	outage := cloneSystem(system)
+
+	if outage.Source.ID == id {
+		outage.Source.Power = 0
+		return outage
+	}
+
+	outage.Transformers = outage.Transformers[:0]
+	for _, transformer := range system.Transformers {
+		if transformer.ID != id {
+			outage.Transformers = append(outage.Transformers, transformer)
+		}
+	}
+	outage.Lines = outage.Lines[:0]
+	for _, line := range system.Lines {
+		if line.ID != id {
+			outage.Lines = append(outage.Lines, line)
+		}
+	}
+	outage.AdditionalSources = outage.AdditionalSources[:0]
+	for _, source := range system.AdditionalSources {
+		if source.ID != id {
+			outage.AdditionalSources = append(outage.AdditionalSources, source)
+		}
+	}
+
+	return outage
+}
+
+// evaluateCase verifica rezultatul unui calcul fata de limitele elementelor.
+func evaluateCase(system utils.System, result utils.SystemResult) ContingencyCase {
+	c := ContingencyCase{
+		Overloads:         []Violation{},
+		VoltageViolations: []Violation{},
+		UnservedConsumers: []UnservedConsumer{},
+		ActivePowerLosses: result.ActivePowerLosses,
+	}
+	limits := utils.ElementLimits(system)
+
+	for _, id := range result.Order {
+		element := result.Elements[id]
+		elementLimits := limits[id]
+
+		if elementLimits.MaxLoading > 0 && element.Loading > elementLimits.MaxLoading {
+			c.Overloads = append(c.Overloads, Violation{ElementID: id, Kind: ViolationOverload, Value: element.Loading, Limit: elementLimits.MaxLoading})
+		}
+		if !element.Energized {
+			continue
+		}
+		if elementLimits.MinVoltage > 0 && element.VoltagePU < elementLimits.MinVoltage {
+			c.VoltageViolations = append(c.VoltageViolations, Violation{ElementID: id, Kind: ViolationUndervoltage, Value: element.VoltagePU, Limit: elementLimits.MinVoltage})
+		}
+		if elementLimits.MaxVoltage > 0 && element.VoltagePU > elementLimits.MaxVoltage {
+			c.VoltageViolations = append(c.VoltageViolations, Violation{ElementID: id, Kind: ViolationOvervoltage, Value: element.VoltagePU, Limit: elementLimits.MaxVoltage})
+		}
+	}
+
+	unserved := UnservedPower(system, result)
+	for _, consumer := range system.Consumers {
+		if unserved[consumer.ID] > 0 {
+			c.UnservedConsumers = append(c.UnservedConsumers, UnservedConsumer{ID: consumer.ID, UnservedPower: unserved[consumer.ID]})
+			c.UnservedPower += unserved[consumer.ID]
+		}
+	}
+
+	return c
+}
+
+// UnservedPower intoarce puterea nealimentata a fiecarui consumator: toata puterea ceruta daca nu este alimentat,
+// altfel puterea lipsa raportata de calcul - MW.
+func UnservedPower(system utils.System, result utils.SystemResult) map[string]float64 {
+	shortfall := map[string]float64{}
+	for _, consumer := range result.ConsumersWithoutPower {
+		shortfall[consumer.ID] = math.Abs(consumer.RemainingPowerNeeded)
+	}
+	unserved := map[string]float64{}
+	for _, consumer := range system.Consumers {
+		unserved[consumer.ID] = consumer.PowerNeeded
+		if element, exists := result.Elements[consumer.ID]; exists && element.Energized {
+			unserved[consumer.ID] = math.Min(shortfall[consumer.ID], consumer.PowerNeeded)
+		}
+	}
+	return unserved
+}
+
+// RunContingencies ruleaza analiza N-1 si ordoneaza cazurile dupa puterea nealimentata in plus fata de cazul de baza,
+// numarul de violari si incarcarea maxima.
+func RunContingencies(system utils.System, options ContingencyOptions) ContingencyReport {
+	baseResult, _ := solve(cloneSystem(system), io.Discard)
+	report := ContingencyReport{Base: evaluateCase(system, baseResult)}
+
+	type outage struct{ id, kind string }
+	var outages []outage
+	for _, line := range system.Lines {
+		outages = append(outages, outage{line.ID, "line"})
+	}
+	for _, transformer := range system.Transformers {
+		// Transformatoarele de masura nu transfera putere, iesirea lor nu schimba calculul
+		if transformer.Type == utils.TransformerTypeMeasure {
+			continue
+		}
+		outages = append(outages, outage{transformer.ID, "transformer"})
+	}
+	if options.IncludeSources {
+		outages = append(outages, outage{system.Source.ID, "source"})
+		for _, source := range system.AdditionalSources {
+			outages = append(outages, outage{source.ID, "source"})
+		}
+	}
+
+	for _, o := range outages {
+		outageSystem := WithOutage(system, o.id)
+		result, _ := solve(outageSystem, io.Discard)
+		c := evaluateCase(outageSystem, result)
+		c.Outage = o.id
+		c.OutageKind = o.kind
+		c.UnservedIncrease = c.UnservedPower - report.Base.UnservedPower
+		report.Cases = append(report.Cases, c)
+	}
+
+	sort.SliceStable(report.Cases, func(i, j int) bool {
+		a, b := report.Cases[i], report.Cases[j]
+		if a.UnservedIncrease != b.UnservedIncrease {
+			return a.UnservedIncrease > b.UnservedIncrease
+		}
+		if va, vb := len(a.Overloads)+len(a.VoltageViolations), len(b.Overloads)+len(b.VoltageViolations); va != vb {
+			return va > vb
+		}
+		return maxLoading(a) > maxLoading(b)
+	})
+
+	return report
+}
+
+func maxLoading(c ContingencyCase) float64 {
+	var max float64
+	for _, overload := range c.Overloads {
+		max = math.Max(max, overload.Value)
+	}
+	return max
+}
+
+// FormatContingencyReport scrie raportul N-1 ca text, cate un rand pe caz.
+func FormatContingencyReport(report ContingencyReport) string {
+	var b strings.Builder
+
+	describe := func(c ContingencyCase) string {
+		var parts []string
+		for _, v := range append(append([]Violation{}, c.Overloads...), c.VoltageViolations...) {
+			parts = append(parts, fmt.Sprintf("%s %s %.2f (limit %.2f)", v.ElementID, v.Kind, v.Value, v.Limit))
+		}
+		for _, consumer := range c.UnservedConsumers {
+			parts = append(parts, fmt.Sprintf("%s unserved %.2f MW", consumer.ID, consumer.UnservedPower))
+		}
+		if len(parts) == 0 {
+			return "no violations"
+		}
+		return strings.Join(parts, "; ")
+	}
+
+	fmt.Fprintf(&b, "N-1 contingency analysis (%d outages)\n", len(report.Cases))
+	fmt.Fprintf(&b, "Base case: unserved %.2f MW, losses %.3f MW: %s\n", report.Base.UnservedPower, report.Base.ActivePowerLosses, describe(report.Base))
+	for i, c := range report.Cases {
+		fmt.Fprintf(&b, "%2d. %s %s: unserved %.2f MW (%+.2f MW vs base), losses %.3f MW: %s\n", i+1, c.OutageKind, c.Outage, c.UnservedPower, c.UnservedIncrease, c.ActivePowerLosses, describe(c))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,31 @@
+package computing
+
+import (
+	"math"
+	"testing"
+
+	"contor-system/src/utils"
+)
+
+func TestContingenciesAgainstBaseCase(t *testing.T) {
+	// Sursa principala de 10 MW nu acopera consumul nici in cazul de baza; transformatorul de masura nu este scos
+	system := opfSystem(2000)
+	system.Source.Power = 10
+	system.Transformers = []utils.Transformer{{ID: "transformer1", Type: utils.TransformerTypeMeasure, ConnectedTo: "consumer1"}}
+
+	report := RunContingencies(system, ContingencyOptions{})
+	if report.Base.UnservedPower <= 0 {
+		t.Fatalf("the base case supplies everything: %+v", report.Base)
+	}
+	for _, c := range report.Cases {
+		if c.Outage == "transformer1" {
+			t.Fatal("the measure transformer was taken out")
+		}
+		if math.Abs(c.UnservedIncrease-(c.UnservedPower-report.Base.UnservedPower)) > 1e-9 {
+			t.Fatalf("%s: increase %.4f MW, unserved %.4f MW, base %.4f MW", c.Outage, c.UnservedIncrease, c.UnservedPower, report.Base.UnservedPower)
+		}
+	}
+	if len(report.Cases) != 1 || report.Cases[0].Outage != "line1" {
+		t.Fatalf("cases %+v", report.Cases)
+	}
+}
@@ -0,0 +1,327 @@
+package computing
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"contor-system/src/utils"
+)
+
+// Defecte nesimetrice (monofazat, bifazat, bifazat cu pamantul) calculate cu retelele de secventa.
+
+type FaultType string
+
+const (
+	FaultSingleLineToGround FaultType = "slg"
+	FaultLineToLine         FaultType = "ll"
+	FaultDoubleLineToGround FaultType = "dlg"
+)
+
+type FaultOptions struct {
+	Type           FaultType
+	Case           ShortCircuitCase
+	C              float64 // factor de tensiune impus; 0 = dupa nivelul de tensiune si Case
+	FaultImpedance complex128
+}
+
+type SequenceImpedance struct {
+	R float64 `json:"r"` // ohm
+	X float64 `json:"x"` // ohm
+}
+
+// BusVoltage este tensiunea dupa defect intr-un nod; pentru transformatoare, la infasurarea de intrare.
+type BusVoltage struct {
+	Node string  `json:"node"`
+	Va   float64 `json:"va"` // u.r.
+	Vb   float64 `json:"vb"` // u.r.
+	Vc   float64 `json:"vc"` // u.r.
+}
+
+type FaultResult struct {
+	Node          string             `json:"node"`
+	Type          FaultType          `json:"type"`
+	Voltage       float64            `json:"voltage"` // kV
+	Z1            SequenceImpedance  `json:"z1"`
+	Z2            SequenceImpedance  `json:"z2"`
+	Z0            *SequenceImpedance `json:"z0"`            // nil daca nodul nu are cale pentru secventa zero
+	CurrentA      float64            `json:"currentA"`      // kA
+	CurrentB      float64            `json:"currentB"`      // kA
+	CurrentC      float64            `json:"currentC"`      // kA
+	GroundCurrent float64            `json:"groundCurrent"` // kA, 3*I0
+	Voltages      []BusVoltage       `json:"voltages"`      // tensiunile dupa defect pe caile de alimentare
+}
+
+// winding descrie o infasurare din grupa de conexiuni: Y, D sau Z, cu neutrul legat la pamant sau nu.
+type winding struct {
+	kind     byte
+	grounded bool
+}
+
+var vectorGroupPattern = regexp.MustCompile(`^(YN|Y|D|ZN|Z)(yn|y|d|zn|z)(\d{1,2})$`)
+
+// parseVectorGroup intoarce infasurarea de inalta tensiune, cea de joasa tensiune si indicele orar.
+func parseVectorGroup(vectorGroup string) (winding, winding, int, error) {
+	if vectorGroup == "" {
+		vectorGroup = "YNyn0"
+	}
+	match := vectorGroupPattern.FindStringSubmatch(vectorGroup)
+	if match == nil {
+		return winding{}, winding{}, 0, fmt.Errorf("invalid vector group %q", vectorGroup)
+	}
+	clock, _ := strconv.Atoi(match[3])
+	if clock > 11 {
+		return winding{}, winding{}, 0, fmt.Errorf("invalid clock number in vector group %q", vectorGroup)
+	}
+
+	parse := func(s string) winding {
+		s = strings.ToUpper(s)
+		return winding{kind: s[0], grounded: strings.HasSuffix(s, "N")}
+	}
+	return parse(match[1]), parse(match[2]), clock, nil
+}
+
+// throughTransformer trece impedantele de secventa prin transformator, raportandu-le la tensiunea de iesire.
+func throughTransformer(current partialShortCircuit, transformer utils.Transformer) partialShortCircuit {
+	ratio := complex(transformer.InputVoltage/transformer.OutputVoltage, 0)
+	zt := transformerImpedance(transformer)
+	current.z = current.z/(ratio*ratio) + zt
+	current.z2 = current.z2/(ratio*ratio) + zt
+
+	hv, lv, clock, err := parseVectorGroup(transformer.VectorGroup)
+	if err != nil {
+		hv, lv, clock, _ = parseVectorGroup("")
+	}
+	input, output := hv, lv
+	// Indicele orar: joasa tensiune este defazata in urma inaltei tensiuni cu clock * 30°
+	shift := -float64(clock) * math.Pi / 6
+	if transformer.InputVoltage < transformer.OutputVoltage {
+		input, output = lv, hv
+		shift = -shift
+	}
+	current.shift += shift
+
+	z0Ratio := transformer.Z0Z1Ratio
+	if z0Ratio == 0 {
+		z0Ratio = 1
+	}
+	z0t := zt*complex(z0Ratio, 0) + 3*complex(transformer.NeutralResistance, transformer.NeutralReactance)
+
+	switch {
+	case input.grounded && output.grounded && output.kind != 'Z':
+		// YNyn: secventa zero trece prin transformator
+		if !current.z0Open {
+			current.z0 = current.z0/(ratio*ratio) + z0t
+		}
+	case output.grounded && (input.kind == 'D' || output.kind == 'Z'):
+		// Triunghiul (sau zig-zagul) inchide curentul de secventa zero, transformatorul devine originea unui nou segment
+		current.segment++
+		current.z0 = z0t
+		current.z0Open = false
+	default:
+		current.segment++
+		current.z0 = 0
+		current.z0Open = true
+	}
+
+	current.voltage = transformer.OutputVoltage
+	return current
+}
+
+/*
+Impedanta de secventa zero a liniei: R0 = r0 * l, X0 = x0 * l; implicit R0 = R1, X0 = 3 * X1
+*/
+func lineZeroSequenceImpedance(line utils.Line, r1 float64, x1 float64) (float64, float64) {
+	r0, x0 := r1, 3*x1
+	if line.R0 > 0 {
+		r0 = line.R0 * float64(line.Length)
+	}
+	if line.X0 > 0 {
+		x0 = line.X0 * float64(line.Length)
+	}
+	return r0, x0
+}
+
+// operatorul de rotatie a = 1∠120°
+var rotation = cmplx.Rect(1, 2*math.Pi/3)
+
+// phases transforma componentele simetrice in marimi de faza.
+func phases(v0 complex128, v1 complex128, v2 complex128) (complex128, complex128, complex128) {
+	a, a2 := rotation, rotation*rotation
+	return v0 + v1 + v2, v0 + a2*v1 + a*v2, v0 + a*v1 + a2*v2
+}
+
+func parallel(impedances []complex128) complex128 {
+	var admittance complex128
+	for _, z := range impedances {
+		admittance += 1 / z
+	}
+	if admittance == 0 {
+		return 0
+	}
+	return 1 / admittance
+}
+
+// ComputeFaults calculeaza curentii de defect nesimetric si tensiunile dupa defect pentru fiecare nod.
+func ComputeFaults(system utils.System, options FaultOptions) ([]FaultResult, error) {
+	transformers := map[string]bool{}
+	for _, transformer := range system.Transformers {
+		if _, _, _, err := parseVectorGroup(transformer.VectorGroup); err != nil {
+			return nil, fmt.Errorf("transformer %s: %v", transformer.ID, err)
+		}
+		transformers[transformer.ID] = true
+	}
+	switch options.Type {
+	case FaultSingleLineToGround, FaultLineToLine, FaultDoubleLineToGround:
+	default:
+		return nil, fmt.Errorf("unknown fault type %q", options.Type)
+	}
+
+	order, byNode, paths := shortCircuitPartials(system, options.Case)
+	zf := options.FaultImpedance
+
+	var results []FaultResult
+	for _, node := range order {
+		var feeding []partialShortCircuit
+		var z1s, z2s, z0s []complex128
+		for _, partial := range byNode[node] {
+			// Retea de putere infinita chiar la borne: curentul nu poate fi determinat
+			if partial.z == 0 {
+				continue
+			}
+			feeding = append(feeding, partial)
+			z1s = append(z1s, partial.z)
+			z2s = append(z2s, partial.z2)
+			if !partial.z0Open && partial.z0 != 0 {
+				z0s = append(z0s, partial.z0)
+			}
+		}
+		if len(feeding) == 0 {
+			continue
+		}
+
+		un := feeding[0].voltage
+		c := options.C
+		if c == 0 {
+			c = voltageFactor(un, options.Case)
+		}
+		e := complex(c*un/math.Sqrt(3), 0) // kV, tensiunea de faza inainte de defect
+		z1, z2 := parallel(z1s), parallel(z2s)
+		z0 := parallel(z0s)
+		grounded := len(z0s) > 0
+
+		var i0, i1, i2, v0, v1, v2 complex128
+		switch {
+		case options.Type == FaultSingleLineToGround && grounded:
+			i1 = e / (z1 + z2 + z0 + 3*zf)
+			i2, i0 = i1, i1
+			v1, v2, v0 = e-z1*i1, -z2*i2, -z0*i0
+		case options.Type == FaultSingleLineToGround:
+			// Retea izolata: nu circula curent, neutrul se deplaseaza cu tensiunea de faza
+			v1, v0 = e, -e
+		case options.Type == FaultDoubleLineToGround && grounded:
+			zg := z0 + 3*zf
+			i1 = e / (z1 + z2*zg/(z2+zg))
+			i2 = -i1 * zg / (z2 + zg)
+			i0 = -i1 * z2 / (z2 + zg)
+			v1, v2, v0 = e-z1*i1, -z2*i2, -z0*i0
+		default:
+			// Bifazat (sau bifazat cu pamantul intr-o retea izolata)
+			if options.Type == FaultLineToLine {
+				i1 = e / (z1 + z2 + zf)
+			} else {
+				i1 = e / (z1 + z2)
+			}
+			i2 = -i1
+			v1, v2 = e-z1*i1, -z2*i2
+		}
+
+		currentA, currentB, currentC := phases(i0, i1, i2)
+		result := FaultResult{
+			Node:          node,
+			Type:          options.Type,
+			Voltage:       un,
+			Z1:            SequenceImpedance{R: real(z1), X: imag(z1)},
+			Z2:            SequenceImpedance{R: real(z2), X: imag(z2)},
+			CurrentA:      cmplx.Abs(currentA),
+			CurrentB:      cmplx.Abs(currentB),
+			CurrentC:      cmplx.Abs(currentC),
+			GroundCurrent: 3 * cmplx.Abs(i0),
+		}
+		if grounded {
+			result.Z0 = &SequenceImpedance{R: real(z0), X: imag(z0)}
+		}
+
+		va, vb, vc := phases(v0, v1, v2)
+		result.Voltages = append(result.Voltages, BusVoltage{Node: node, Va: cmplx.Abs(va / e), Vb: cmplx.Abs(vb / e), Vc: cmplx.Abs(vc / e)})
+
+		// Tensiunile in amonte, pe calea fiecarei surse: curentii se impart invers proportional cu impedantele.
+		// Un nod apare o singura data, chiar daca se afla pe caile mai multor surse.
+		seen := map[string]bool{node: true}
+		for _, fault := range feeding {
+			i1s, i2s := i1*z1/fault.z, i2*z2/fault.z2
+			var i0s complex128
+			if grounded && !fault.z0Open {
+				i0s = i0 * z0 / fault.z0
+			}
+
+			path := paths[fault.source.ID]
+			for k, bus := range path {
+				if bus.node == node {
+					break
+				}
+				// Transformatorul se raporteaza la infasurarea de intrare, inaintea impedantei lui; infasurarea de
+				// iesire este nodul urmator
+				if transformers[bus.node] && k > 0 {
+					bus = path[k-1]
+					bus.node = path[k].node
+				}
+				// Nodurile legate de defect fara nicio impedanta au chiar tensiunile din locul defectului
+				if seen[bus.node] || (bus.z == fault.z && bus.shift == fault.shift && bus.segment == fault.segment) {
+					continue
+				}
+				seen[bus.node] = true
+
+				refer := complex(math.Pow(un/bus.voltage, 2), 0)
+				delta := fault.shift - bus.shift
+				bv1 := (e - bus.z*refer*i1s) / e * cmplx.Rect(1, -delta)
+				bv2 := (-bus.z2 * refer * i2s) / e * cmplx.Rect(1, delta)
+				var bv0 complex128
+				if !bus.z0Open && !fault.z0Open && bus.segment == fault.segment {
+					bv0 = (-bus.z0 * refer * i0s) / e
+				}
+				bva, bvb, bvc := phases(bv0, bv1, bv2)
+				result.Voltages = append(result.Voltages, BusVoltage{Node: bus.node, Va: cmplx.Abs(bva), Vb: cmplx.Abs(bvb), Vc: cmplx.Abs(bvc)})
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// FormatFaultReport scrie curentii de defect ca tabel text, urmat de tensiunile dupa defect.
+func FormatFaultReport(results []FaultResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-16s %4s %8s %9s %9s %9s %9s %9s %9s %10s\n", "Node", "Type", "Un[kV]", "|Z1|", "|Z2|", "|Z0|", "Ia[kA]", "Ib[kA]", "Ic[kA]", "3I0[kA]")
+	for _, r := range results {
+		z0 := "open"
+		if r.Z0 != nil {
+			z0 = fmt.Sprintf("%.4f", math.Hypot(r.Z0.R, r.Z0.X))
+		}
+		fmt.Fprintf(&b, "%-16s %4s %8.2f %9.4f %9.4f %9s %9.3f %9.3f %9.3f %10.3f\n", r.Node, r.Type, r.Voltage, math.Hypot(r.Z1.R, r.Z1.X), math.Hypot(r.Z2.R, r.Z2.X), z0, r.CurrentA, r.CurrentB, r.CurrentC, r.GroundCurrent)
+	}
+
+	for _, r := range results {
+		fmt.Fprintf(&b, "\nPost-fault voltages for a %s fault at %s [p.u., transformers at the input winding]:\n", r.Type, r.Node)
+		for _, v := range r.Voltages {
+			fmt.Fprintf(&b, "  %-16s Va %.3f  Vb %.3f  Vc %.3f\n", v.Node, v.Va, v.Vb, v.Vc)
+		}
+	}
+
+	return b.String()
+}
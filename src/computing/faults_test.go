@@ -0,0 +1,95 @@
+package computing
+
+import (
+	"math"
+	"testing"
+
+	"contor-system/src/utils"
+)
+
+// faultSystem este o retea de 20 kV alimentata de un sistem cu puterea de scurtcircuit de 500 MVA, R/X = 0.1,
+// urmata de o linie de 10 km cu R = 30·10/100 = 3 ohm, X = 0.4·10 = 4 ohm si, implicit, R0 = 3 ohm, X0 = 12 ohm.
+func faultSystem() utils.System {
+	return utils.System{
+		Source: utils.Source{ID: "source1", Voltage: 20, ShortCircuitPower: 500, RXRatio: 0.1, ConnectedTo: "line1"},
+		Lines: []utils.Line{{
+			ID: "line1", Voltage: 20, Length: 10, Area: 100, Ro: 30, X: 0.4, ConnectedTo: "consumer1",
+		}},
+		Consumers: []utils.Consumer{{ID: "consumer1", PowerNeeded: 1, Voltage: 20}},
+	}
+}
+
+func fault(t *testing.T, results []FaultResult, node string) FaultResult {
+	t.Helper()
+	for _, r := range results {
+		if r.Node == node {
+			return r
+		}
+	}
+	t.Fatalf("no fault result for %s", node)
+	return FaultResult{}
+}
+
+func TestSingleLineToGroundIEC60909(t *testing.T) {
+	results, err := ComputeFaults(faultSystem(), FaultOptions{Type: FaultSingleLineToGround})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// La borne: ZQ = 1.1·20²/500 = 0.88 ohm, Z0 = Z2 = Z1, deci I''k1 = √3·c·Un / |3·ZQ| = 14.434 kA
+	expected := math.Sqrt(3) * 1.1 * 20 / (3 * 0.88)
+	if r := fault(t, results, "source1"); math.Abs(r.CurrentA-expected) > 1e-3 || r.CurrentB > 1e-9 || r.CurrentC > 1e-9 {
+		t.Fatalf("source1: Ia %.4f Ib %.4f Ic %.4f kA, expected Ia %.4f", r.CurrentA, r.CurrentB, r.CurrentC, expected)
+	}
+
+	// La capatul liniei: XQ = 0.88/√1.01, RQ = 0.1·XQ
+	// Z1 = Z2 = (RQ + 3) + j(XQ + 4), Z0 = (RQ + 3) + j(XQ + 12), I''k1 = √3·c·Un / |Z1 + Z2 + Z0| = 1.5585 kA
+	xq := 0.88 / math.Sqrt(1.01)
+	rq := 0.1 * xq
+	expected = math.Sqrt(3) * 1.1 * 20 / math.Hypot(3*(rq+3), 3*xq+4+4+12)
+	r := fault(t, results, "line1")
+	if math.Abs(r.CurrentA-expected) > 1e-3 || math.Abs(r.GroundCurrent-expected) > 1e-3 {
+		t.Fatalf("line1: Ia %.4f kA, 3I0 %.4f kA, expected %.4f", r.CurrentA, r.GroundCurrent, expected)
+	}
+	if r.Z0 == nil || math.Abs(r.Z0.X-(xq+12)) > 1e-6 {
+		t.Fatalf("line1: Z0 %+v", r.Z0)
+	}
+}
+
+func TestLineToLineIEC60909(t *testing.T) {
+	results, err := ComputeFaults(faultSystem(), FaultOptions{Type: FaultLineToLine})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// I''k2 = c·Un / |Z1 + Z2| = 1.9061 kA pe fazele b si c, fara curent la pamant
+	xq := 0.88 / math.Sqrt(1.01)
+	rq := 0.1 * xq
+	expected := 1.1 * 20 / math.Hypot(2*(rq+3), 2*(xq+4))
+	r := fault(t, results, "line1")
+	if math.Abs(r.CurrentB-expected) > 1e-3 || math.Abs(r.CurrentC-expected) > 1e-3 || r.CurrentA > 1e-9 || r.GroundCurrent > 1e-9 {
+		t.Fatalf("line1: Ia %.4f Ib %.4f Ic %.4f 3I0 %.4f kA, expected Ib = Ic = %.4f", r.CurrentA, r.CurrentB, r.CurrentC, r.GroundCurrent, expected)
+	}
+}
+
+func TestFaultVoltagesListEachNodeOnce(t *testing.T) {
+	// Inelul source1 → line1 → consumer1 → separator2 ← source2: consumer1 se afla pe caile ambelor surse
+	system := faultSystem()
+	system.Consumers[0].ConnectedTo = "separator2"
+	system.Separators = []utils.Separator{{ID: "separator2", ConnectsFrom: "_", State: utils.StateClose, ConnectedTo: "source2"}}
+	system.AdditionalSources = []utils.Source{{ID: "source2", Voltage: 20, ShortCircuitPower: 300, ConnectedTo: "consumer1"}}
+
+	results, err := ComputeFaults(system, FaultOptions{Type: FaultSingleLineToGround})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		seen := map[string]bool{}
+		for _, v := range r.Voltages {
+			if seen[v.Node] {
+				t.Fatalf("%s listed twice for a fault at %s: %+v", v.Node, r.Node, r.Voltages)
+			}
+			seen[v.Node] = true
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package computing
+
+import (
+	"math"
+
+	"contor-system/src/utils"
+)
+
+// Arborele radial folosit de metodele de tip backward/forward sweep, construit din lantul connectedTo al sursei principale.
+
+// baseApparentPower este puterea de baza a sistemului in unitati relative: MVA
+const baseApparentPower = 100.0
+
+// feederBus este borna de iesire a unui element alimentat din sursa principala.
+type feederBus struct {
+	id       string
+	kind     string
+	element  interface{}
+	parent   int // -1 pentru sursa
+	children []int
+	voltage  float64    // kV, tensiunea nominala (intre faze) a nodului
+	z        complex128 // u.r., impedanta laturii dintre parinte si nod
+	ratio    float64    // raportul suplimentar dat de plotul transformatorului, 1 pentru celelalte elemente
+}
+
+// injection este o sursa aditionala sau o baterie conectata intr-un nod al arborelui.
+type injection struct {
+	bus    int
+	kind   string // source, battery
+	source utils.Source
+}
+
+type feederTree struct {
+	buses      []feederBus // in ordinea parcurgerii; parintele apare inaintea copiilor
+	index      map[string]int
+	injections []injection
+	loop       string // elementul al carui connectedTo inchide o bucla, gol pentru o retea radiala
+}
+
+/*
+Impedanta de baza: Zb = Ub^2 / Sb
+- ub: kV
+- Zb: ohm
+*/
+func baseImpedance(ub float64) float64 {
+	return math.Pow(ub, 2) / baseApparentPower
+}
+
+// busCurrent intoarce curentul de linie pentru puterea aparenta s: MVA, la tensiunea v: u.r. a unui nod cu tensiunea
+// nominala ub: kV; foloseste acelasi curent de baza ca laturile, Ib = Sb / (sqrt(3) * Ub): A.
+func busCurrent(s float64, v float64, ub float64) float64 {
+	if v == 0 || ub == 0 {
+		return 0
+	}
+	baseCurrent := baseApparentPower / (math.Sqrt(3) * ub) * 1000
+	return math.Abs(s) / baseApparentPower / v * baseCurrent
+}
+
+// tapRatio intoarce raportul dintre tensiunea de iesire pe plotul curent si tensiunea de iesire nominala.
+func tapRatio(transformer utils.Transformer) float64 {
+	if transformer.Tap == nil {
+		return 1
+	}
+	return 1 + float64(transformer.Tap.Position)*transformer.Tap.StepPercent/100
+}
+
+// buildFeederTree parcurge lantul sursei principale pana la un separator deschis, un element inactiv, o alta sursa sau un nod deja vizitat;
+// in ultimul caz reteaua este buclata si elementul care inchide bucla se retine in loop.
+func buildFeederTree(system utils.System) feederTree {
+	tree := feederTree{index: map[string]int{}}
+
+	nodes := map[string]interface{}{}
+	for _, t := range system.Transformers {
+		nodes[t.ID] = t
+	}
+	for _, l := range system.Lines {
+		nodes[l.ID] = l
+	}
+	for _, s := range system.Separators {
+		nodes[s.ID] = s
+	}
+	for _, c := range system.Consumers {
+		nodes[c.ID] = c
+	}
+
+	add := func(bus feederBus) int {
+		tree.buses = append(tree.buses, bus)
+		i := len(tree.buses) - 1
+		tree.index[bus.id] = i
+		if bus.parent >= 0 {
+			tree.buses[bus.parent].children = append(tree.buses[bus.parent].children, i)
+		}
+		return i
+	}
+
+	parent := add(feederBus{id: system.Source.ID, kind: "source", element: system.Source, parent: -1, voltage: system.Source.Voltage, ratio: 1})
+
+	for id := system.Source.ConnectedTo; ; {
+		if _, visited := tree.index[id]; visited {
+			tree.loop = tree.buses[parent].id
+			break
+		}
+		node, exists := nodes[id]
+		if !exists {
+			break
+		}
+
+		level := tree.buses[parent].voltage
+		bus := feederBus{id: id, element: node, parent: parent, voltage: level, ratio: 1}
+		var next string
+
+		switch n := node.(type) {
+		case utils.Separator:
+			if n.State == utils.StateOpen {
+				return tree.withInjections(system)
+			}
+			bus.kind = "separator"
+			next = n.ConnectedTo
+		case utils.Transformer:
+			if !isActive(n.ID, &system) {
+				return tree.withInjections(system)
+			}
+			bus.kind = "transformer"
+			if n.Type != utils.TransformerTypeMeasure && n.OutputVoltage > 0 {
+				bus.voltage = n.OutputVoltage
+				bus.z = transformerImpedance(n) / complex(baseImpedance(n.OutputVoltage), 0)
+				bus.ratio = tapRatio(n)
+			}
+			next = n.ConnectedTo
+		case utils.Line:
+			if !isActive(n.ID, &system) {
+				return tree.withInjections(system)
+			}
+			bus.kind = "line"
+			bus.voltage = n.Voltage
+			r, x := lineImpedance(n)
+			bus.z = complex(r, x) / complex(baseImpedance(n.Voltage), 0)
+			next = n.ConnectedTo
+		case utils.Consumer:
+			if !isActive(n.ID, &system) {
+				return tree.withInjections(system)
+			}
+			bus.kind = "consumer"
+			next = n.ConnectedTo
+		}
+
+		parent = add(bus)
+		id = next
+	}
+
+	return tree.withInjections(system)
+}
+
+// withInjections ataseaza nodului in care sunt conectate sursele aditionale cu separatorul inchis si bateriile.
+func (tree feederTree) withInjections(system utils.System) feederTree {
+	for _, source := range system.AdditionalSources {
+		var isSeparatorClose = false
+		for _, separator := range system.Separators {
+			if separator.ConnectedTo == source.ID && separator.State == utils.StateClose {
+				isSeparatorClose = true
+			}
+		}
+		if bus, exists := tree.index[source.ConnectedTo]; exists && isSeparatorClose {
+			tree.injections = append(tree.injections, injection{bus: bus, kind: "source", source: source})
+		}
+	}
+	for _, battery := range system.Batteries {
+		if bus, exists := tree.index[battery.ConnectedTo]; exists {
+			// Bateria este o injectie de putere activa la tensiunea nodului
+			source := utils.Source{ID: battery.ID, Power: battery.Power, Voltage: tree.buses[bus].voltage, ConnectedTo: battery.ConnectedTo}
+			tree.injections = append(tree.injections, injection{bus: bus, kind: "battery", source: source})
+		}
+	}
+	return tree
+}
@@ -0,0 +1,148 @@
+package computing
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"contor-system/src/utils"
+)
+
+// Generare distribuita: centrale fotovoltaice si parcuri eoliene cu puterea disponibila data de profile,
+// cu reglajul puterii reactive (cosfi fix, Q(U)) si limitarea puterii active P(U) facute de invertor.
+
+// interpolate intoarce valoarea curbei in x, constanta in afara capetelor.
+func interpolate(points []utils.CurvePoint, x float64) float64 {
+	if len(points) == 0 {
+		return 0
+	}
+	sorted := append([]utils.CurvePoint(nil), points...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].X < sorted[j].X })
+
+	if x <= sorted[0].X {
+		return sorted[0].Y
+	}
+	for i := 1; i < len(sorted); i++ {
+		if x <= sorted[i].X {
+			a, b := sorted[i-1], sorted[i]
+			return a.Y + (x-a.X)/(b.X-a.X)*(b.Y-a.Y)
+		}
+	}
+	return sorted[len(sorted)-1].Y
+}
+
+// availablePower intoarce puterea activa disponibila a sursei la momentul t: MW.
+func availablePower(source utils.Source, t time.Time) float64 {
+	switch {
+	case source.Photovoltaic != nil:
+		pv := source.Photovoltaic
+		ratio := pv.PerformanceRatio
+		if ratio <= 0 {
+			ratio = 0.85
+		}
+		p := pv.PeakPower * math.Max(pv.Irradiance.ValueAt(t), 0) / 1000 * ratio
+		if pv.InverterRating > 0 {
+			p = math.Min(p, pv.InverterRating)
+		}
+		return p
+	case source.Wind != nil:
+		turbines := source.Wind.Turbines
+		if turbines <= 0 {
+			turbines = 1
+		}
+		p := interpolate(source.Wind.PowerCurve, source.Wind.WindSpeed.ValueAt(t)) * float64(turbines)
+		if source.Wind.Rating > 0 {
+			p = math.Min(p, source.Wind.Rating)
+		}
+		return p
+	default:
+		return source.Power
+	}
+}
+
+// generationAt intoarce sursa cu puterea activa disponibila la momentul t.
+func generationAt(source utils.Source, t time.Time) utils.Source {
+	source.Power = availablePower(source, t)
+	return source
+}
+
+// ratedApparentPower intoarce puterea aparenta nominala a invertorului: MVA, 0 daca nu este data.
+func ratedApparentPower(source utils.Source) float64 {
+	switch {
+	case source.Photovoltaic != nil:
+		return source.Photovoltaic.InverterRating
+	case source.Wind != nil:
+		return source.Wind.Rating
+	default:
+		return 0
+	}
+}
+
+// dgUnit este o sursa cu invertor a carei injectie depinde de tensiunea nodului.
+type dgUnit struct {
+	bus       int
+	source    utils.Source
+	available float64 // u.r., puterea activa disponibila
+	rating    float64 // u.r., 0 = nelimitat
+	p         float64 // u.r., puterea activa injectata
+	q         float64 // u.r., puterea reactiva injectata
+}
+
+// hasInverterControl verifica daca injectia sursei depinde de tensiune.
+func hasInverterControl(source utils.Source) bool {
+	return source.Inverter != nil && (source.Inverter.Mode != "" || len(source.Inverter.PU) > 0)
+}
+
+func newDGUnit(inj injection) *dgUnit {
+	unit := &dgUnit{
+		bus:       inj.bus,
+		source:    inj.source,
+		available: inj.source.Power / baseApparentPower,
+		rating:    ratedApparentPower(inj.source) / baseApparentPower,
+	}
+	unit.p, unit.q = unit.target(1)
+	return unit
+}
+
+// target intoarce injectia ceruta de reglajele invertorului la tensiunea v (u.r.). Puterea activa are prioritate
+// fata de cea reactiva in limita puterii nominale a invertorului.
+func (u *dgUnit) target(v float64) (float64, float64) {
+	control := u.source.Inverter
+
+	p := u.available
+	if len(control.PU) > 0 {
+		p *= math.Max(0, math.Min(1, interpolate(control.PU, v)))
+	}
+
+	q := u.source.ReactivePower / baseApparentPower
+	switch control.Mode {
+	case utils.ReactiveControlFixedPowerFactor:
+		q = 0
+		if pf := math.Abs(control.PowerFactor); pf > 0 && pf < 1 {
+			q = math.Copysign(reactivePower(p, math.Tan(math.Acos(pf))), control.PowerFactor)
+		}
+	case utils.ReactiveControlQU:
+		rating := u.rating
+		if rating == 0 {
+			rating = u.available
+		}
+		q = interpolate(control.QU, v) * rating
+	}
+
+	if u.rating > 0 {
+		limit := math.Sqrt(math.Max(u.rating*u.rating-p*p, 0))
+		q = math.Max(-limit, math.Min(limit, q))
+	}
+	return p, q
+}
+
+// update aduce injectia spre valoarea ceruta la tensiunea v, cu relaxare pentru a evita oscilatiile curbelor abrupte,
+// si intoarce cea mai mare variatie.
+func (u *dgUnit) update(v float64) float64 {
+	const relaxation = 0.5
+	p, q := u.target(v)
+	dp, dq := relaxation*(p-u.p), relaxation*(q-u.q)
+	u.p += dp
+	u.q += dq
+	return math.Max(math.Abs(dp), math.Abs(dq))
+}
@@ -0,0 +1,78 @@
+package computing
+
+import (
+	"io"
+	"math"
+	"testing"
+
+	"contor-system/src/utils"
+)
+
+// qu absoarbe reactiv peste 1.02 u.r. si produce sub 0.98 u.r., pana la 44 % din puterea nominala
+var qu = []utils.CurvePoint{{X: 0.95, Y: 0.44}, {X: 0.98, Y: 0}, {X: 1.02, Y: 0}, {X: 1.05, Y: -0.44}}
+
+// pu reduce puterea activa liniar intre 1.05 si 1.10 u.r.
+var pu = []utils.CurvePoint{{X: 1.05, Y: 1}, {X: 1.1, Y: 0}}
+
+func inverterUnit(available float64, rating float64) *dgUnit {
+	source := utils.Source{
+		Power:        available,
+		Photovoltaic: &utils.Photovoltaic{InverterRating: rating},
+		Inverter:     &utils.InverterControl{Mode: utils.ReactiveControlQU, QU: qu, PU: pu},
+	}
+	return newDGUnit(injection{source: source})
+}
+
+func TestQUAndPUCurves(t *testing.T) {
+	u := inverterUnit(5, 6)
+	for _, c := range []struct {
+		v    float64
+		p, q float64 // MW, MVAr
+	}{
+		{1, 5, 0},
+		{0.965, 5, 0.22 * 6},
+		{0.9, 5, 0.44 * 6},    // constanta sub primul punct
+		{1.035, 5, -0.22 * 6}, // jumatatea pantei de absorbtie
+		{1.075, 2.5, -0.44 * 6},
+		{1.2, 0, -0.44 * 6},
+	} {
+		p, q := u.target(c.v)
+		if math.Abs(p*baseApparentPower-c.p) > 1e-9 || math.Abs(q*baseApparentPower-c.q) > 1e-9 {
+			t.Fatalf("at %.3f pu: %.4f MW, %.4f MVAr, expected %.4f MW, %.4f MVAr", c.v, p*baseApparentPower, q*baseApparentPower, c.p, c.q)
+		}
+	}
+}
+
+func TestQULimitedByRating(t *testing.T) {
+	// La 5.5 MW dintr-un invertor de 6 MVA raman √(6² - 5.5²) = 2.398 MVAr, mai putin decat cei 2.64 ceruti
+	u := inverterUnit(5.5, 6)
+	p, q := u.target(0.9)
+	if limit := math.Sqrt(36 - 5.5*5.5); math.Abs(p*baseApparentPower-5.5) > 1e-9 || math.Abs(q*baseApparentPower-limit) > 1e-9 {
+		t.Fatalf("%.4f MW, %.4f MVAr, expected 5.5 MW, %.4f MVAr", p*baseApparentPower, q*baseApparentPower, limit)
+	}
+}
+
+func TestSweepFollowsQUCurve(t *testing.T) {
+	// Centrala de 5 MW de la capatul liniei ridica tensiunea in panta de absorbtie a curbei Q(U)
+	// Invertorul de 6 MVA tine panta dQ/dU sub inversul reactantei liniei (x = 1 u.r.), altfel iteratiile oscileaza
+	system := twoBusSystem(1, 0)
+	system.Separators = []utils.Separator{{ID: "separator2", ConnectsFrom: "_", State: utils.StateClose, ConnectedTo: "source2"}}
+	system.AdditionalSources = []utils.Source{{
+		ID: "source2", Voltage: 20, ConnectedTo: "consumer1",
+		Photovoltaic: &utils.Photovoltaic{PeakPower: 5, InverterRating: 6, PerformanceRatio: 1, Irradiance: utils.Profile{Values: []float64{1000}}},
+		Inverter:     &utils.InverterControl{Mode: utils.ReactiveControlQU, QU: qu},
+	}}
+
+	result, _ := computeSweep(system, SweepOptions{}, io.Discard)
+	if !result.Converged {
+		t.Fatal("did not converge")
+	}
+	v := result.Elements["consumer1"].VoltagePU
+	if v <= 1.02 {
+		t.Fatalf("consumer1 at %.4f pu, expected above the Q(U) deadband", v)
+	}
+	source := result.Elements["source2"]
+	if expected := interpolate(qu, v) * 6; math.Abs(source.ActivePower-5) > 1e-9 || math.Abs(source.ReactivePower-expected) > 1e-4 {
+		t.Fatalf("source2 injects %.4f MW, %.4f MVAr at %.4f pu, the curve gives %.4f MVAr", source.ActivePower, source.ReactivePower, v, expected)
+	}
+}
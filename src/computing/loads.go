@@ -0,0 +1,49 @@
+package computing
+
+import (
+	"math"
+
+	"contor-system/src/utils"
+)
+
+// Sarcini dependente de tensiune: puterea consumata se calculeaza la tensiunea rezultata din calcul.
+
+// consumerDemand intoarce puterea nominala ceruta de consumator: MW + j MVAr.
+// Daca reactivePowerAbsorbed lipseste, Q se obtine din factorul de putere: Q = P * tan(arccos(cosfi)).
+func consumerDemand(consumer utils.Consumer) complex128 {
+	q := consumer.ReactivePowerAbsorbed
+	if q == 0 && consumer.PowerFactor > 0 && consumer.PowerFactor < 1 {
+		q = reactivePower(consumer.PowerNeeded, math.Tan(math.Acos(consumer.PowerFactor)))
+	}
+	return complex(consumer.PowerNeeded, q)
+}
+
+// zipFactor intoarce z*V^2 + i*V + p, cu coeficientii normati la suma lor; fara coeficienti sarcina este de putere constanta.
+func zipFactor(z float64, i float64, p float64, v float64) float64 {
+	sum := z + i + p
+	if sum == 0 {
+		return 1
+	}
+	return (z*v*v + i*v + p) / sum
+}
+
+// applyLoadModel intoarce puterea absorbita la tensiunea v (u.r.) de o sarcina cu puterea nominala s0.
+func applyLoadModel(model *utils.LoadModel, s0 complex128, v float64) complex128 {
+	if model == nil {
+		return s0
+	}
+
+	switch model.Type {
+	case utils.LoadModelZIP:
+		return complex(real(s0)*zipFactor(model.Zp, model.Ip, model.Pp, v), imag(s0)*zipFactor(model.Zq, model.Iq, model.Pq, v))
+	case utils.LoadModelExponential:
+		return complex(real(s0)*math.Pow(v, model.Np), imag(s0)*math.Pow(v, model.Nq))
+	default:
+		return s0
+	}
+}
+
+// consumerLoadAt intoarce puterea absorbita de consumator la tensiunea v (u.r.): MW + j MVAr.
+func consumerLoadAt(consumer utils.Consumer, v float64) complex128 {
+	return applyLoadModel(consumer.LoadModel, consumerDemand(consumer), v)
+}
@@ -0,0 +1,44 @@
+package computing
+
+import (
+	"io"
+	"math"
+	"testing"
+
+	"contor-system/src/utils"
+)
+
+func TestZIPLoadAtReducedVoltage(t *testing.T) {
+	// P: 50 % impedanta, 30 % curent, 20 % putere constanta; Q: impedanta constanta. Coeficientii se normeaza la suma lor.
+	model := &utils.LoadModel{Type: utils.LoadModelZIP, Zp: 5, Ip: 3, Pp: 2, Zq: 1}
+	s := applyLoadModel(model, complex(10, 4), 0.95)
+
+	// P = 10·(0.5·0.95² + 0.3·0.95 + 0.2) = 9.3625 MW, Q = 4·0.95² = 3.61 MVAr
+	if math.Abs(real(s)-9.3625) > 1e-9 || math.Abs(imag(s)-3.61) > 1e-9 {
+		t.Fatalf("%.4f MW, %.4f MVAr at 0.95 pu, expected 9.3625 MW, 3.61 MVAr", real(s), imag(s))
+	}
+	if s := applyLoadModel(model, complex(10, 4), 1); s != complex(10, 4) {
+		t.Fatalf("%v at 1 pu, expected the rated power", s)
+	}
+
+	exponential := &utils.LoadModel{Type: utils.LoadModelExponential, Np: 1.5, Nq: 3}
+	if s := applyLoadModel(exponential, complex(10, 4), 0.95); math.Abs(real(s)-10*math.Pow(0.95, 1.5)) > 1e-9 || math.Abs(imag(s)-4*math.Pow(0.95, 3)) > 1e-9 {
+		t.Fatalf("exponential load %v at 0.95 pu", s)
+	}
+}
+
+func TestSweepZIPLoad(t *testing.T) {
+	// Consumatorul legat direct la sursa principala tinuta la 0.95 u.r.
+	system := utils.System{
+		Source: utils.Source{ID: "source1", Voltage: 20, Power: 50, VoltageSetpoint: 0.95, ConnectedTo: "consumer1"},
+		Consumers: []utils.Consumer{{
+			ID: "consumer1", PowerNeeded: 10, ReactivePowerAbsorbed: 4, Voltage: 20,
+			LoadModel: &utils.LoadModel{Type: utils.LoadModelZIP, Zp: 0.5, Ip: 0.3, Pp: 0.2, Zq: 1},
+		}},
+	}
+	result, _ := computeSweep(system, SweepOptions{}, io.Discard)
+	consumer := result.Elements["consumer1"]
+	if math.Abs(consumer.VoltagePU-0.95) > 1e-9 || math.Abs(consumer.ActivePower-9.3625) > 1e-6 || math.Abs(consumer.ReactivePower-3.61) > 1e-6 {
+		t.Fatalf("consumer1 %+v", consumer)
+	}
+}
@@ -0,0 +1,210 @@
+package computing
+
+import (
+	"errors"
+	"math"
+)
+
+// Rezolvarea problemelor de programare liniara prin metoda simplex in doua faze, cu regula lui Bland impotriva ciclarii.
+
+type lpKind int
+
+const (
+	lpLessEqual lpKind = iota
+	lpGreaterEqual
+	lpEqual
+)
+
+type lpConstraint struct {
+	coefficients []float64
+	kind         lpKind
+	rhs          float64
+}
+
+// linearProgram este problema: min c*x, cu restrictiile date si x >= 0.
+type linearProgram struct {
+	objective   []float64
+	constraints []lpConstraint
+}
+
+var (
+	errLPInfeasible = errors.New("linear program is infeasible")
+	errLPUnbounded  = errors.New("linear program is unbounded")
+)
+
+const lpEpsilon = 1e-9
+
+func (lp *linearProgram) addConstraint(coefficients []float64, kind lpKind, rhs float64) {
+	lp.constraints = append(lp.constraints, lpConstraint{coefficients: coefficients, kind: kind, rhs: rhs})
+}
+
+// solve intoarce solutia optima si valoarea functiei obiectiv.
+func (lp *linearProgram) solve() ([]float64, float64, error) {
+	n := len(lp.objective)
+	m := len(lp.constraints)
+
+	// Termenii liberi devin pozitivi; fiecare restrictie primeste variabila de abatere si, daca e nevoie, o variabila artificiala
+	constraints := make([]lpConstraint, m)
+	var slacks, artificials int
+	for i, c := range lp.constraints {
+		if c.rhs < 0 {
+			flipped := make([]float64, len(c.coefficients))
+			for j, a := range c.coefficients {
+				flipped[j] = -a
+			}
+			c = lpConstraint{coefficients: flipped, kind: c.kind, rhs: -c.rhs}
+			switch c.kind {
+			case lpLessEqual:
+				c.kind = lpGreaterEqual
+			case lpGreaterEqual:
+				c.kind = lpLessEqual
+			}
+		}
+		constraints[i] = c
+		if c.kind != lpEqual {
+			slacks++
+		}
+		if c.kind != lpLessEqual {
+			artificials++
+		}
+	}
+
+	columns := n + slacks + artificials
+	rhs := columns
+	tableau := make([][]float64, m)
+	basis := make([]int, m)
+	slack, artificial := n, n+slacks
+	for i, c := range constraints {
+		row := make([]float64, columns+1)
+		copy(row, c.coefficients)
+		row[rhs] = c.rhs
+		switch c.kind {
+		case lpLessEqual:
+			row[slack] = 1
+			basis[i] = slack
+			slack++
+		case lpGreaterEqual:
+			row[slack] = -1
+			slack++
+			row[artificial] = 1
+			basis[i] = artificial
+			artificial++
+		case lpEqual:
+			row[artificial] = 1
+			basis[i] = artificial
+			artificial++
+		}
+		tableau[i] = row
+	}
+
+	// Faza 1: minimizarea sumei variabilelor artificiale
+	if artificials > 0 {
+		cost := make([]float64, columns)
+		for j := n + slacks; j < columns; j++ {
+			cost[j] = 1
+		}
+		if err := simplex(tableau, basis, cost, columns); err != nil {
+			return nil, 0, err
+		}
+		var infeasibility float64
+		for i, b := range basis {
+			infeasibility += cost[b] * tableau[i][rhs]
+		}
+		if infeasibility > 1e-7 {
+			return nil, 0, errLPInfeasible
+		}
+		// Variabilele artificiale ramase in baza (cu valoare 0) sunt inlocuite cu o variabila reala, daca exista
+		for i, b := range basis {
+			if b < n+slacks {
+				continue
+			}
+			for j := 0; j < n+slacks; j++ {
+				if math.Abs(tableau[i][j]) > lpEpsilon {
+					pivot(tableau, basis, i, j)
+					break
+				}
+			}
+		}
+	}
+
+	// Faza 2: functia obiectiv initiala, fara variabilele artificiale
+	cost := make([]float64, columns)
+	copy(cost, lp.objective)
+	if err := simplex(tableau, basis, cost, n+slacks); err != nil {
+		return nil, 0, err
+	}
+
+	x := make([]float64, n)
+	for i, b := range basis {
+		if b < n {
+			x[b] = tableau[i][rhs]
+		}
+	}
+	var value float64
+	for j, c := range lp.objective {
+		value += c * x[j]
+	}
+	return x, value, nil
+}
+
+// simplex optimizeaza tabelul pentru costurile date, folosind doar primele usable coloane ca variabile de intrare.
+func simplex(tableau [][]float64, basis []int, cost []float64, usable int) error {
+	if len(tableau) == 0 {
+		return nil
+	}
+	rhs := len(tableau[0]) - 1
+
+	for iteration := 0; iteration < 50000; iteration++ {
+		// Prima coloana cu cost redus negativ (Bland)
+		entering := -1
+		for j := 0; j < usable; j++ {
+			reduced := cost[j]
+			for i, b := range basis {
+				reduced -= cost[b] * tableau[i][j]
+			}
+			if reduced < -lpEpsilon {
+				entering = j
+				break
+			}
+		}
+		if entering < 0 {
+			return nil
+		}
+
+		leaving := -1
+		var best float64
+		for i := range tableau {
+			if tableau[i][entering] <= lpEpsilon {
+				continue
+			}
+			ratio := tableau[i][rhs] / tableau[i][entering]
+			if leaving < 0 || ratio < best-lpEpsilon || (math.Abs(ratio-best) <= lpEpsilon && basis[i] < basis[leaving]) {
+				leaving = i
+				best = ratio
+			}
+		}
+		if leaving < 0 {
+			return errLPUnbounded
+		}
+		pivot(tableau, basis, leaving, entering)
+	}
+
+	return errors.New("simplex iteration limit reached")
+}
+
+func pivot(tableau [][]float64, basis []int, row int, column int) {
+	factor := tableau[row][column]
+	for j := range tableau[row] {
+		tableau[row][j] /= factor
+	}
+	for i := range tableau {
+		if i == row || tableau[i][column] == 0 {
+			continue
+		}
+		f := tableau[i][column]
+		for j := range tableau[i] {
+			tableau[i][j] -= f * tableau[row][j]
+		}
+	}
+	basis[row] = column
+}
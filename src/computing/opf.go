@@ -0,0 +1,638 @@
+package computing
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"contor-system/src/utils"
+)
+
+/*
+Circulatia optima de puteri (OPF): dispecerizarea surselor (costul sau pierderile minime) cu respectarea limitelor de tensiune si incarcare.
+- dc: model de curent continuu pe arborele radial (fluxul unei laturi este suma puterilor din aval), fara pierderi si fara tensiuni,
+  rezolvat prin programare liniara cu costul patratic liniarizat pe segmente; variabile: puterea surselor.
+- ac: metoda punctului interior cu bariera logaritmica pe calculul backward/forward sweep; variabile continue: puterea activa si reactiva
+  a surselor aditionale, variabile discrete: plotul transformatoarelor si treapta bateriilor de compensare (cautare pe coordonate).
+*/
+
+type OPFMode string
+type OPFObjective string
+
+const (
+	OPFModeDC OPFMode = "dc"
+	OPFModeAC OPFMode = "ac"
+)
+
+const (
+	OPFObjectiveCost   OPFObjective = "cost"
+	OPFObjectiveLosses OPFObjective = "losses"
+)
+
+type OPFOptions struct {
+	Mode      OPFMode
+	Objective OPFObjective // implicit cost
+	Segments  int          // dc: numarul de segmente ale costului patratic, implicit 8
+	Sweep     SweepOptions
+}
+
+type GeneratorDispatch struct {
+	ID            string  `json:"id"`
+	ActivePower   float64 `json:"activePower"`   // MW
+	ReactivePower float64 `json:"reactivePower"` // MVAr
+	Cost          float64 `json:"cost"`          // pe ora
+}
+
+// DiscreteSetting este plotul unui transformator sau treapta unei baterii de compensare.
+type DiscreteSetting struct {
+	ID    string `json:"id"`
+	Value int    `json:"value"`
+}
+
+type OPFResult struct {
+	Mode       OPFMode             `json:"mode"`
+	Objective  OPFObjective        `json:"objective"`
+	Generators []GeneratorDispatch `json:"generators"`
+	Taps       []DiscreteSetting   `json:"taps"`
+	ShuntSteps []DiscreteSetting   `json:"shuntSteps"`
+	Cost       float64             `json:"cost"`       // pe ora
+	Losses     float64             `json:"losses"`     // MW
+	BaseCost   float64             `json:"baseCost"`   // configuratia curenta
+	BaseLosses float64             `json:"baseLosses"` // MW, configuratia curenta
+	Violations []Violation         `json:"violations"` // limitele incalcate in solutia finala
+	Converged  bool                `json:"converged"`
+	System     utils.System        `json:"-"` // sistemul cu valorile optime
+}
+
+func (o OPFOptions) withDefaults() OPFOptions {
+	if o.Mode == "" {
+		o.Mode = OPFModeAC
+	}
+	if o.Objective == "" {
+		o.Objective = OPFObjectiveCost
+	}
+	if o.Segments <= 0 {
+		o.Segments = 8
+	}
+	o.Sweep = o.Sweep.withDefaults()
+	return o
+}
+
+// validate respinge modurile si obiectivele necunoscute si combinatiile nesuportate: modelul dc nu are pierderi,
+// deci nu poate minimiza pierderile.
+func (o OPFOptions) validate() error {
+	switch o.Mode {
+	case OPFModeDC, OPFModeAC:
+	default:
+		return fmt.Errorf("unknown OPF mode %q (expected %s or %s)", o.Mode, OPFModeDC, OPFModeAC)
+	}
+	switch o.Objective {
+	case OPFObjectiveCost, OPFObjectiveLosses:
+	default:
+		return fmt.Errorf("unknown OPF objective %q (expected %s or %s)", o.Objective, OPFObjectiveCost, OPFObjectiveLosses)
+	}
+	if o.Mode == OPFModeDC && o.Objective != OPFObjectiveCost {
+		return fmt.Errorf("the %s OPF is lossless and only supports the %s objective", OPFModeDC, OPFObjectiveCost)
+	}
+	return nil
+}
+
+// sourceCost intoarce costul orar al sursei la puterea p: MW.
+func sourceCost(source utils.Source, p float64) float64 {
+	if source.Cost == nil {
+		return 0
+	}
+	return source.Cost.A + source.Cost.B*p + source.Cost.C*p*p
+}
+
+// dispatchable verifica daca puterea sursei aditionale poate fi aleasa de OPF; generarea din profile ramane fixa.
+func dispatchable(source utils.Source) bool {
+	return source.MaxPower > source.MinPower && source.Photovoltaic == nil && source.Wind == nil
+}
+
+// systemCost intoarce costul orar al surselor pentru puterile din rezultat.
+func systemCost(system utils.System, result utils.SystemResult) float64 {
+	var cost float64
+	if element, exists := result.Elements[system.Source.ID]; exists {
+		cost += sourceCost(system.Source, element.ActivePower)
+	}
+	for _, source := range system.AdditionalSources {
+		if element, exists := result.Elements[source.ID]; exists && element.Energized {
+			cost += sourceCost(source, element.ActivePower)
+		}
+	}
+	return cost
+}
+
+// ratedFlow intoarce puterea admisibila a laturii care alimenteaza nodul: MW, 0 daca nu are valoare nominala.
+func ratedFlow(bus feederBus, limits utils.Limits) float64 {
+	maxLoading := limits.MaxLoading
+	if maxLoading <= 0 {
+		maxLoading = 100
+	}
+	switch e := bus.element.(type) {
+	case utils.Line:
+		// P = sqrt(3) * U * I: MW
+		return math.Sqrt(3) * e.Voltage * e.RatedCurrent / 1000 * maxLoading / 100
+	case utils.Transformer:
+		return e.ApparentPower * maxLoading / 100
+	}
+	return 0
+}
+
+// ComputeOPF calculeaza dispecerizarea optima si verifica solutia cu calculul backward/forward sweep.
+func ComputeOPF(system utils.System, options OPFOptions) (OPFResult, error) {
+	options = options.withDefaults()
+	if err := options.validate(); err != nil {
+		return OPFResult{Mode: options.Mode, Objective: options.Objective}, err
+	}
+
+	base, _ := computeSweep(system, options.Sweep, io.Discard)
+	result := OPFResult{
+		Mode:       options.Mode,
+		Objective:  options.Objective,
+		BaseCost:   systemCost(system, base),
+		BaseLosses: base.ActivePowerLosses,
+	}
+
+	var optimal utils.System
+	var err error
+	switch options.Mode {
+	case OPFModeDC:
+		optimal, err = dcOPF(system, options)
+	case OPFModeAC:
+		optimal = newACProblem(system, options).optimise()
+	}
+	if err != nil {
+		return result, err
+	}
+
+	solved, _ := computeSweep(optimal, options.Sweep, io.Discard)
+	result.System = optimal
+	result.Converged = solved.Converged
+	result.Cost = systemCost(optimal, solved)
+	result.Losses = solved.ActivePowerLosses
+
+	sources := append([]utils.Source{optimal.Source}, optimal.AdditionalSources...)
+	for _, source := range sources {
+		element, exists := solved.Elements[source.ID]
+		if !exists || !element.Energized {
+			continue
+		}
+		result.Generators = append(result.Generators, GeneratorDispatch{
+			ID:            source.ID,
+			ActivePower:   element.ActivePower,
+			ReactivePower: element.ReactivePower,
+			Cost:          sourceCost(source, element.ActivePower),
+		})
+	}
+	for _, transformer := range optimal.Transformers {
+		if transformer.Tap != nil {
+			result.Taps = append(result.Taps, DiscreteSetting{ID: transformer.ID, Value: transformer.Tap.Position})
+		}
+	}
+	for _, shunt := range optimal.Shunts {
+		result.ShuntSteps = append(result.ShuntSteps, DiscreteSetting{ID: shunt.ID, Value: shunt.Step})
+	}
+
+	c := evaluateCase(optimal, solved)
+	result.Violations = append(c.Overloads, c.VoltageViolations...)
+
+	return result, nil
+}
+
+// dcGenerator este o sursa dispecerizabila in modelul de curent continuu.
+type dcGenerator struct {
+	bus       int
+	source    int // -1 pentru sursa principala, altfel indexul in AdditionalSources
+	min       float64
+	max       float64 // +Inf daca nu este limitata
+	variables []int
+}
+
+// dcOPF rezolva dispecerizarea in modelul de curent continuu si intoarce sistemul cu puterile surselor aditionale.
+func dcOPF(system utils.System, options OPFOptions) (utils.System, error) {
+	system = cloneSystem(system)
+	tree := buildFeederTree(system)
+	n := len(tree.buses)
+	limits := utils.ElementLimits(system)
+
+	// Puterea neta absorbita in fiecare nod de sarcini si de injectiile fixe: MW
+	netLoad := make([]float64, n)
+	for i, bus := range tree.buses {
+		switch e := bus.element.(type) {
+		case utils.Consumer:
+			netLoad[i] = real(consumerDemand(e))
+		case utils.Transformer:
+			netLoad[i] = e.SteelLosses / 1000
+		}
+	}
+
+	sourceIndex := map[string]int{}
+	for i, source := range system.AdditionalSources {
+		sourceIndex[source.ID] = i
+	}
+
+	maxPower := system.Source.MaxPower
+	if maxPower <= system.Source.MinPower {
+		maxPower = math.Inf(1)
+	}
+	generators := []*dcGenerator{{bus: 0, source: -1, min: system.Source.MinPower, max: maxPower}}
+	for _, inj := range tree.injections {
+		source := generationAt(inj.source, options.Sweep.Time)
+		if index, exists := sourceIndex[source.ID]; exists && inj.kind == "source" && dispatchable(source) {
+			generators = append(generators, &dcGenerator{bus: inj.bus, source: index, min: source.MinPower, max: source.MaxPower})
+			continue
+		}
+		netLoad[inj.bus] -= source.Power
+	}
+
+	// Costul patratic se inlocuieste cu segmente de pante crescatoare; o sursa nelimitata are un singur segment cu panta B
+	lp := linearProgram{}
+	for _, g := range generators {
+		source := system.Source
+		if g.source >= 0 {
+			source = system.AdditionalSources[g.source]
+		}
+		if math.IsInf(g.max, 1) {
+			g.variables = append(g.variables, len(lp.objective))
+			slope := 0.0
+			if source.Cost != nil {
+				slope = source.Cost.B + 2*source.Cost.C*g.min
+			}
+			lp.objective = append(lp.objective, slope)
+			continue
+		}
+		width := (g.max - g.min) / float64(options.Segments)
+		for k := 0; k < options.Segments; k++ {
+			from := g.min + float64(k)*width
+			g.variables = append(g.variables, len(lp.objective))
+			lp.objective = append(lp.objective, (sourceCost(source, from+width)-sourceCost(source, from))/width)
+		}
+	}
+	variables := len(lp.objective)
+	for _, g := range generators {
+		if math.IsInf(g.max, 1) {
+			continue
+		}
+		width := (g.max - g.min) / float64(options.Segments)
+		for _, v := range g.variables {
+			row := make([]float64, variables)
+			row[v] = 1
+			lp.addConstraint(row, lpLessEqual, width)
+		}
+	}
+
+	inSubtree := func(bus int, root int) bool {
+		for i := bus; i >= 0; i = tree.buses[i].parent {
+			if i == root {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Bilantul puterilor: suma surselor acopera sarcina totala
+	balance := make([]float64, variables)
+	total := 0.0
+	for _, load := range netLoad {
+		total += load
+	}
+	for _, g := range generators {
+		total -= g.min
+		for _, v := range g.variables {
+			balance[v] = 1
+		}
+	}
+	lp.addConstraint(balance, lpEqual, total)
+
+	// Fluxul fiecarei laturi: F = sarcina din aval - sursele din aval, -Fmax <= F <= Fmax
+	for i := 1; i < n; i++ {
+		rating := ratedFlow(tree.buses[i], limits[tree.buses[i].id])
+		if rating <= 0 {
+			continue
+		}
+		var downstream float64
+		for k := range tree.buses {
+			if inSubtree(k, i) {
+				downstream += netLoad[k]
+			}
+		}
+		row := make([]float64, variables)
+		for _, g := range generators {
+			if !inSubtree(g.bus, i) {
+				continue
+			}
+			downstream -= g.min
+			for _, v := range g.variables {
+				row[v] = -1
+			}
+		}
+		lp.addConstraint(row, lpLessEqual, rating-downstream)
+		lp.addConstraint(row, lpGreaterEqual, -rating-downstream)
+	}
+
+	x, _, err := lp.solve()
+	if err != nil {
+		return system, fmt.Errorf("DC OPF: %w", err)
+	}
+
+	// Sursa principala ramane nodul de echilibru si acopera si pierderile
+	for _, g := range generators {
+		if g.source < 0 {
+			continue
+		}
+		p := g.min
+		for _, v := range g.variables {
+			p += x[v]
+		}
+		system.AdditionalSources[g.source].Power = p
+	}
+	return system, nil
+}
+
+// acVariable este o variabila continua a problemei AC: puterea activa sau reactiva a unei surse aditionale.
+type acVariable struct {
+	source   int
+	reactive bool
+	lo, hi   float64 // MW sau MVAr
+}
+
+type acProblem struct {
+	system    utils.System
+	options   OPFOptions
+	variables []acVariable
+	taps      []int // indexii transformatoarelor cu comutator de ploturi
+	shunts    []int // indexii bateriilor de compensare
+	scale     float64
+	penalized map[string]bool // restrictii incalcate in punctul de plecare, tratate prin penalizare
+}
+
+func newACProblem(system utils.System, options OPFOptions) *acProblem {
+	p := &acProblem{system: cloneSystem(system), options: options, scale: 1, penalized: map[string]bool{}}
+
+	for i, source := range system.AdditionalSources {
+		if dispatchable(source) {
+			p.variables = append(p.variables, acVariable{source: i, lo: source.MinPower, hi: source.MaxPower})
+		}
+		if source.VoltageSetpoint > 0 || hasInverterControl(source) {
+			continue
+		}
+		qMin, qMax := reactiveLimits(source, source.Power)
+		if !math.IsInf(qMin, 0) && !math.IsInf(qMax, 0) && qMax > qMin {
+			p.variables = append(p.variables, acVariable{source: i, reactive: true, lo: qMin, hi: qMax})
+		}
+	}
+	for i, transformer := range system.Transformers {
+		if transformer.Tap != nil && transformer.Tap.MaxPosition > transformer.Tap.MinPosition {
+			p.taps = append(p.taps, i)
+		}
+	}
+	for i, shunt := range system.Shunts {
+		if shunt.Steps > 0 {
+			p.shunts = append(p.shunts, i)
+		}
+	}
+	return p
+}
+
+// apply intoarce sistemul cu valorile date ale variabilelor (u in [0, 1]) si pozitiile discrete.
+func (p *acProblem) apply(u []float64, positions []int) utils.System {
+	system := cloneSystem(p.system)
+	for k, v := range p.variables {
+		value := v.lo + u[k]*(v.hi-v.lo)
+		if v.reactive {
+			system.AdditionalSources[v.source].ReactivePower = value
+		} else {
+			system.AdditionalSources[v.source].Power = value
+		}
+	}
+	for k, i := range p.taps {
+		tap := *system.Transformers[i].Tap
+		tap.Position = positions[k]
+		system.Transformers[i].Tap = &tap
+	}
+	for k, i := range p.shunts {
+		system.Shunts[i].Step = positions[len(p.taps)+k]
+		system.Shunts[i].Control = ""
+	}
+	return system
+}
+
+// evaluate intoarce functia obiectiv si rezervele restrictiilor (pozitive cand limita este respectata).
+func (p *acProblem) evaluate(system utils.System) (float64, map[string]float64) {
+	result, _ := computeSweep(system, p.options.Sweep, io.Discard)
+	margins := map[string]float64{}
+	if !result.Converged {
+		margins["convergence"] = -1
+	}
+
+	limits := utils.ElementLimits(system)
+	for _, id := range result.Order {
+		element := result.Elements[id]
+		if !element.Energized {
+			continue
+		}
+		l := limits[id]
+		if l.MinVoltage > 0 {
+			margins[id+"/minVoltage"] = element.VoltagePU - l.MinVoltage
+		}
+		if l.MaxVoltage > 0 {
+			margins[id+"/maxVoltage"] = l.MaxVoltage - element.VoltagePU
+		}
+		if l.MaxLoading > 0 && element.Loading > 0 {
+			margins[id+"/maxLoading"] = (l.MaxLoading - element.Loading) / 100
+		}
+	}
+	if element, exists := result.Elements[system.Source.ID]; exists {
+		margins[system.Source.ID+"/minPower"] = (element.ActivePower - system.Source.MinPower) / baseApparentPower
+		if system.Source.MaxPower > system.Source.MinPower {
+			margins[system.Source.ID+"/maxPower"] = (system.Source.MaxPower - element.ActivePower) / baseApparentPower
+		}
+	}
+
+	if p.options.Objective == OPFObjectiveLosses {
+		return result.ActivePowerLosses, margins
+	}
+	return systemCost(system, result), margins
+}
+
+// violation intoarce suma restrictiilor incalcate.
+func violation(margins map[string]float64) float64 {
+	var total float64
+	for _, m := range margins {
+		total += math.Max(0, -m)
+	}
+	return total
+}
+
+// merit este functia bariera: f / scala - mu * sum(ln(rezerve)), cu penalizarea patratica (ponderea 1 / mu) a restrictiilor incalcate la plecare.
+func (p *acProblem) merit(u []float64, positions []int, mu float64) float64 {
+	f, margins := p.evaluate(p.apply(u, positions))
+	value := f / p.scale
+
+	for _, x := range u {
+		if x <= 0 || x >= 1 {
+			return math.Inf(1)
+		}
+		value -= mu * (math.Log(x) + math.Log(1-x))
+	}
+	for key, m := range margins {
+		if p.penalized[key] {
+			value += math.Pow(math.Max(0, -m), 2) / mu
+			continue
+		}
+		if m <= 0 {
+			return math.Inf(1)
+		}
+		value -= mu * math.Log(m)
+	}
+	return value
+}
+
+// descend minimizeaza functia bariera pentru mu dat prin gradient (diferente finite) si cautare liniara Armijo.
+func (p *acProblem) descend(u []float64, positions []int, mu float64) []float64 {
+	const h = 1e-4
+	current := p.merit(u, positions, mu)
+
+	for iteration := 0; iteration < 30; iteration++ {
+		gradient := make([]float64, len(u))
+		var norm float64
+		for k := range u {
+			forward := append([]float64(nil), u...)
+			backward := append([]float64(nil), u...)
+			forward[k] = math.Min(u[k]+h, 1-h/2)
+			backward[k] = math.Max(u[k]-h, h/2)
+			gradient[k] = (p.merit(forward, positions, mu) - p.merit(backward, positions, mu)) / (forward[k] - backward[k])
+			if math.IsInf(gradient[k], 0) || math.IsNaN(gradient[k]) {
+				gradient[k] = 0
+			}
+			norm = math.Max(norm, math.Abs(gradient[k]))
+		}
+		if norm < 1e-9 {
+			break
+		}
+
+		step := 0.25 / norm
+		improved := false
+		for attempt := 0; attempt < 30; attempt++ {
+			candidate := make([]float64, len(u))
+			for k := range u {
+				candidate[k] = u[k] - step*gradient[k]
+			}
+			value := p.merit(candidate, positions, mu)
+			if value < current-1e-4*step*norm*norm {
+				u, current, improved = candidate, value, true
+				break
+			}
+			step /= 2
+		}
+		if !improved || step*norm < 1e-8 {
+			break
+		}
+	}
+	return u
+}
+
+// interiorPoint reduce parametrul barierei pana aproape de zero, pornind din u.
+func (p *acProblem) interiorPoint(u []float64, positions []int, mu float64) []float64 {
+	if len(u) == 0 {
+		return u
+	}
+	for ; mu > 1e-7; mu *= 0.2 {
+		u = p.descend(u, positions, mu)
+	}
+	return u
+}
+
+// optimise rezolva problema AC si intoarce sistemul cu valorile optime.
+func (p *acProblem) optimise() utils.System {
+	// Punctul de plecare: valorile din configuratie, aduse in interiorul limitelor
+	u := make([]float64, len(p.variables))
+	for k, v := range p.variables {
+		value := p.system.AdditionalSources[v.source].Power
+		if v.reactive {
+			value = p.system.AdditionalSources[v.source].ReactivePower
+		}
+		u[k] = math.Max(0.01, math.Min(0.99, (value-v.lo)/(v.hi-v.lo)))
+	}
+	positions := make([]int, len(p.taps)+len(p.shunts))
+	for k, i := range p.taps {
+		positions[k] = p.system.Transformers[i].Tap.Position
+	}
+	for k, i := range p.shunts {
+		positions[len(p.taps)+k] = p.system.Shunts[i].Step
+	}
+
+	f, margins := p.evaluate(p.apply(u, positions))
+	p.scale = math.Max(math.Abs(f), 1e-3)
+	for key, m := range margins {
+		if m <= 0 {
+			p.penalized[key] = true
+		}
+	}
+
+	u = p.interiorPoint(u, positions, 0.1)
+
+	// Cautare pe coordonate pentru ploturi si trepte, acceptand o pozitie vecina daca reduce incalcarile sau, la incalcari egale, obiectivul
+	bounds := func(k int) (int, int) {
+		if k < len(p.taps) {
+			tap := p.system.Transformers[p.taps[k]].Tap
+			return tap.MinPosition, tap.MaxPosition
+		}
+		return 0, p.system.Shunts[p.shunts[k-len(p.taps)]].Steps
+	}
+	f, margins = p.evaluate(p.apply(u, positions))
+	bestViolation := violation(margins)
+	for pass := 0; pass < 50; pass++ {
+		improved := false
+		for k := range positions {
+			lo, hi := bounds(k)
+			for _, delta := range []int{-1, 1} {
+				candidate := append([]int(nil), positions...)
+				candidate[k] += delta
+				if candidate[k] < lo || candidate[k] > hi {
+					continue
+				}
+				value, m := p.evaluate(p.apply(u, candidate))
+				v := violation(m)
+				if v < bestViolation-1e-9 || (math.Abs(v-bestViolation) <= 1e-9 && value < f-1e-9) {
+					positions, f, bestViolation, improved = candidate, value, v, true
+				}
+			}
+		}
+		if !improved {
+			break
+		}
+	}
+
+	u = p.interiorPoint(u, positions, 1e-3)
+	return p.apply(u, positions)
+}
+
+// FormatOPFReport scrie dispecerizarea optima ca text.
+func FormatOPFReport(result OPFResult) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Optimal power flow (%s, minimise %s), AC check converged %t\n", strings.ToUpper(string(result.Mode)), result.Objective, result.Converged)
+	fmt.Fprintf(&b, "Cost: %.2f /h (current %.2f /h), losses: %.3f MW (current %.3f MW)\n", result.Cost, result.BaseCost, result.Losses, result.BaseLosses)
+	fmt.Fprintf(&b, "%-16s %10s %10s %10s\n", "Source", "P[MW]", "Q[MVAr]", "Cost[/h]")
+	for _, g := range result.Generators {
+		fmt.Fprintf(&b, "%-16s %10.2f %10.2f %10.2f\n", g.ID, g.ActivePower, g.ReactivePower, g.Cost)
+	}
+	for _, tap := range result.Taps {
+		fmt.Fprintf(&b, "Transformer %s: tap %d\n", tap.ID, tap.Value)
+	}
+	for _, step := range result.ShuntSteps {
+		fmt.Fprintf(&b, "Shunt %s: step %d\n", step.ID, step.Value)
+	}
+	if len(result.Violations) == 0 {
+		b.WriteString("All voltage and loading limits are respected\n")
+	}
+	for _, v := range result.Violations {
+		fmt.Fprintf(&b, "Violation: %s %s %.3f (limit %.3f)\n", v.ElementID, v.Kind, v.Value, v.Limit)
+	}
+
+	return b.String()
+}
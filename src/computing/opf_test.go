@@ -0,0 +1,98 @@
+package computing
+
+import (
+	"math"
+	"testing"
+
+	"contor-system/src/utils"
+)
+
+// opfSystem este o retea radiala de 20 kV: sursa principala, o linie si un consumator de 60 MW, cu o sursa
+// aditionala dispecerizabila in nodul consumatorului, racordata prin separatorul separator2.
+func opfSystem(ratedCurrent float64) utils.System {
+	return utils.System{
+		Source: utils.Source{ID: "source1", Voltage: 20, ConnectedTo: "line1", Cost: &utils.CostCurve{B: 30}},
+		Lines: []utils.Line{{
+			ID: "line1", Voltage: 20, Length: 5, ConnectedTo: "consumer1", Area: 240, Ro: 0.0295,
+			Drs: 1, Dst: 1, Drt: 1, ConductorDiameter: 2, R: 0.01, RatedCurrent: ratedCurrent,
+		}},
+		Consumers:  []utils.Consumer{{ID: "consumer1", PowerNeeded: 60, Voltage: 20, ConnectedTo: "separator2"}},
+		Separators: []utils.Separator{{ID: "separator2", ConnectsFrom: "_", State: utils.StateClose, ConnectedTo: "source2"}},
+		AdditionalSources: []utils.Source{{
+			ID: "source2", Voltage: 20, ConnectedTo: "consumer1",
+			MinPower: 0, MaxPower: 100, Cost: &utils.CostCurve{B: 10, C: 0.2},
+		}},
+	}
+}
+
+func generator(t *testing.T, result OPFResult, id string) GeneratorDispatch {
+	t.Helper()
+	for _, g := range result.Generators {
+		if g.ID == id {
+			return g
+		}
+	}
+	t.Fatalf("no dispatch for %s in %+v", id, result.Generators)
+	return GeneratorDispatch{}
+}
+
+func TestDCOPFEqualIncrementalCost(t *testing.T) {
+	// Costul marginal al sursei aditionale 10 + 0.4·P egaleaza costul sursei principale (30) la P = 50 MW;
+	// cu 8 segmente de 12.5 MW limita cade exact la capatul celui de-al patrulea segment
+	result, err := ComputeOPF(opfSystem(2000), OPFOptions{Mode: OPFModeDC})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := generator(t, result, "source2").ActivePower; math.Abs(p-50) > 1e-6 {
+		t.Fatalf("source2 dispatched at %.6f MW, expected 50", p)
+	}
+	if result.Cost >= result.BaseCost && result.BaseCost > 0 {
+		t.Fatalf("cost %.2f is not below the current %.2f", result.Cost, result.BaseCost)
+	}
+}
+
+func TestDCOPFLineRating(t *testing.T) {
+	// Linia de 150 A transporta cel mult √3·20·150/1000 = 5.196 MW, deci sursa aditionala acopera restul
+	result, err := ComputeOPF(opfSystem(150), OPFOptions{Mode: OPFModeDC})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := 60 - math.Sqrt(3)*20*150/1000
+	if p := generator(t, result, "source2").ActivePower; math.Abs(p-expected) > 1e-6 {
+		t.Fatalf("source2 dispatched at %.6f MW, expected %.6f", p, expected)
+	}
+}
+
+func TestACOPFObjectives(t *testing.T) {
+	cost, err := ComputeOPF(opfSystem(2000), OPFOptions{Mode: OPFModeAC, Objective: OPFObjectiveCost})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cost.Converged || cost.Cost > cost.BaseCost+1e-6 {
+		t.Fatalf("cost objective: converged %t, cost %.3f, current %.3f", cost.Converged, cost.Cost, cost.BaseCost)
+	}
+
+	losses, err := ComputeOPF(opfSystem(2000), OPFOptions{Mode: OPFModeAC, Objective: OPFObjectiveLosses})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !losses.Converged || losses.Losses > losses.BaseLosses+1e-6 {
+		t.Fatalf("losses objective: converged %t, losses %.4f, current %.4f", losses.Converged, losses.Losses, losses.BaseLosses)
+	}
+	// Sarcina este in nodul sursei aditionale: pierderile minime se obtin cand ea acopera aproape tot consumul
+	if p := generator(t, losses, "source2").ActivePower; p < 55 {
+		t.Fatalf("losses objective dispatched source2 at %.3f MW", p)
+	}
+}
+
+func TestOPFOptionsValidation(t *testing.T) {
+	for _, options := range []OPFOptions{
+		{Mode: OPFModeDC, Objective: OPFObjectiveLosses},
+		{Mode: OPFModeAC, Objective: "bogus"},
+		{Mode: "bogus"},
+	} {
+		if _, err := ComputeOPF(opfSystem(2000), options); err == nil {
+			t.Fatalf("options %+v accepted", options)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package computing
+
+import (
+	"math"
+	"sort"
+
+	"contor-system/src/utils"
+)
+
+// Surse aditionale modelate ca noduri PV: puterea reactiva se ajusteaza pentru a mentine tensiunea la valoarea impusa,
+// in limitele de putere reactiva; la atingerea unei limite nodul devine PQ cu Q la limita.
+
+// pvGenerator este o sursa cu reglaj de tensiune conectata intr-un nod al arborelui.
+type pvGenerator struct {
+	bus     int
+	source  utils.Source
+	q       float64 // u.r., puterea reactiva injectata
+	qMin    float64 // u.r.
+	qMax    float64 // u.r.
+	x       float64 // u.r., reactanta caii dintre sursa principala si nod, sensibilitatea dV/dQ
+	limited bool    // nod trecut pe PQ
+}
+
+// reactiveLimits intoarce limitele de putere reactiva ale sursei la puterea activa p: MVAr.
+func reactiveLimits(source utils.Source, p float64) (float64, float64) {
+	if len(source.Capability) > 0 {
+		points := append([]utils.CapabilityPoint(nil), source.Capability...)
+		sort.Slice(points, func(i, j int) bool { return points[i].P < points[j].P })
+
+		if p <= points[0].P {
+			return points[0].QMin, points[0].QMax
+		}
+		for i := 1; i < len(points); i++ {
+			if p <= points[i].P {
+				a, b := points[i-1], points[i]
+				t := (p - a.P) / (b.P - a.P)
+				return a.QMin + t*(b.QMin-a.QMin), a.QMax + t*(b.QMax-a.QMax)
+			}
+		}
+		last := points[len(points)-1]
+		return last.QMin, last.QMax
+	}
+
+	if source.MinReactivePower == 0 && source.MaxReactivePower == 0 {
+		return math.Inf(-1), math.Inf(1)
+	}
+	return source.MinReactivePower, source.MaxReactivePower
+}
+
+// pathReactance intoarce suma reactantelor laturilor de la nod pana la sursa principala: u.r.
+func (tree feederTree) pathReactance(bus int) float64 {
+	var x float64
+	for i := bus; i > 0; i = tree.buses[i].parent {
+		x += imag(tree.buses[i].z)
+	}
+	return x
+}
+
+func newPVGenerator(tree feederTree, inj injection) *pvGenerator {
+	qMin, qMax := reactiveLimits(inj.source, inj.source.Power)
+	return &pvGenerator{
+		bus:    inj.bus,
+		source: inj.source,
+		q:      math.Max(qMin, math.Min(qMax, inj.source.ReactivePower)) / baseApparentPower,
+		qMin:   qMin / baseApparentPower,
+		qMax:   qMax / baseApparentPower,
+		x:      tree.pathReactance(inj.bus),
+	}
+}
+
+// regulate corecteaza puterea reactiva pentru tensiunea v (u.r.) a nodului si intoarce abaterea de la tensiunea impusa.
+// Un nod PQ la limita revine PV cand tensiunea trece de partea cealalta a valorii impuse.
+func (g *pvGenerator) regulate(v float64) float64 {
+	deviation := g.source.VoltageSetpoint - v
+
+	if g.limited {
+		if (g.q >= g.qMax && deviation < 0) || (g.q <= g.qMin && deviation > 0) {
+			g.limited = false
+		} else {
+			return 0
+		}
+	}
+	if g.x <= 0 {
+		return 0
+	}
+
+	// dQ = dV / X
+	g.q += deviation / g.x
+	if g.q > g.qMax {
+		g.q = g.qMax
+		g.limited = true
+	} else if g.q < g.qMin {
+		g.q = g.qMin
+		g.limited = true
+	}
+
+	return math.Abs(deviation)
+}
@@ -0,0 +1,88 @@
+package computing
+
+import (
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"contor-system/src/utils"
+)
+
+// pvSystem este sistemul cu doua noduri din sweep_test.go, cu sursa source2 tinand 1 u.r. la consumer1.
+func pvSystem(qMax float64) utils.System {
+	system := twoBusSystem(10, 5)
+	system.Separators = []utils.Separator{{ID: "separator2", ConnectsFrom: "_", State: utils.StateClose, ConnectedTo: "source2"}}
+	system.AdditionalSources = []utils.Source{{
+		ID: "source2", Voltage: 20, Power: 2, VoltageSetpoint: 1, MinReactivePower: -qMax, MaxReactivePower: qMax, ConnectedTo: "consumer1",
+	}}
+	return system
+}
+
+func sourceLog(logs []LogEntry, id string) string {
+	for _, entry := range logs {
+		if entry.ComponentID == id {
+			return entry.Message
+		}
+	}
+	return ""
+}
+
+func TestPVHoldsVoltage(t *testing.T) {
+	result, logs := computeSweep(pvSystem(20), SweepOptions{}, io.Discard)
+	if !result.Converged {
+		t.Fatal("did not converge")
+	}
+	if v := result.Elements["consumer1"].VoltagePU; math.Abs(v-1) > 1e-5 {
+		t.Fatalf("consumer1 at %.6f pu, expected the setpoint 1", v)
+	}
+	if q := result.Elements["source2"].ReactivePower; q <= 0 || q >= 20 {
+		t.Fatalf("source2 injects %.4f MVAr, expected within 0..20", q)
+	}
+	if message := sourceLog(logs, "source2"); !strings.Contains(message, "(PV)") {
+		t.Fatalf("source2: %s", message)
+	}
+}
+
+func TestPVSwitchesToPQAtQmax(t *testing.T) {
+	result, logs := computeSweep(pvSystem(1), SweepOptions{}, io.Discard)
+	if !result.Converged {
+		t.Fatal("did not converge")
+	}
+	// Cu Q limitat la 1 MVAr tensiunea ramane sub valoarea impusa
+	if q := result.Elements["source2"].ReactivePower; math.Abs(q-1) > 1e-9 {
+		t.Fatalf("source2 injects %.4f MVAr, expected Qmax = 1", q)
+	}
+	if v := result.Elements["consumer1"].VoltagePU; v >= 1 {
+		t.Fatalf("consumer1 at %.6f pu with Q at its limit", v)
+	}
+	if message := sourceLog(logs, "source2"); !strings.Contains(message, "(PQ, Q at limit)") {
+		t.Fatalf("source2: %s", message)
+	}
+}
+
+func TestPQReturnsToPV(t *testing.T) {
+	g := &pvGenerator{source: utils.Source{VoltageSetpoint: 1}, q: 0.01, qMin: -0.01, qMax: 0.01, x: 2, limited: true}
+
+	// La Qmax si tensiune sub valoarea impusa nodul ramane PQ
+	if g.regulate(0.98); !g.limited || g.q != 0.01 {
+		t.Fatalf("limited %t, q %.4f", g.limited, g.q)
+	}
+	// Tensiunea trece peste valoarea impusa: nodul revine PV si reduce Q
+	if deviation := g.regulate(1.01); g.limited || math.Abs(deviation-0.01) > 1e-12 || math.Abs(g.q-(0.01-0.01/2)) > 1e-12 {
+		t.Fatalf("limited %t, q %.4f, deviation %.4f", g.limited, g.q, deviation)
+	}
+}
+
+func TestCapabilityLimits(t *testing.T) {
+	source := utils.Source{Capability: []utils.CapabilityPoint{{P: 10, QMin: -3, QMax: 5}, {P: 0, QMin: -6, QMax: 8}}}
+	if qMin, qMax := reactiveLimits(source, 5); qMin != -4.5 || qMax != 6.5 {
+		t.Fatalf("limits at 5 MW: %.2f..%.2f, expected -4.5..6.5", qMin, qMax)
+	}
+	if qMin, qMax := reactiveLimits(source, 12); qMin != -3 || qMax != 5 {
+		t.Fatalf("limits beyond the curve: %.2f..%.2f", qMin, qMax)
+	}
+	if qMin, qMax := reactiveLimits(utils.Source{}, 5); !math.IsInf(qMin, -1) || !math.IsInf(qMax, 1) {
+		t.Fatalf("unlimited source: %.2f..%.2f", qMin, qMax)
+	}
+}
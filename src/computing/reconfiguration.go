@@ -0,0 +1,280 @@
+package computing
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"contor-system/src/utils"
+)
+
+// Reconfigurarea retelei: alegerea starii separatoarelor care minimizeaza pierderile sau incarcarea maxima,
+// pastrand reteaua radiala si fara a lasa nealimentata mai multa putere decat configuratia curenta.
+
+type ReconfigurationObjective string
+
+const (
+	ReconfigurationLosses  ReconfigurationObjective = "losses"  // pierderile active totale
+	ReconfigurationLoading ReconfigurationObjective = "loading" // incarcarea maxima a liniilor si transformatoarelor
+)
+
+type ReconfigurationOptions struct {
+	Objective     ReconfigurationObjective // implicit losses
+	MaxExhaustive int                      // numarul maxim de separatoare pentru cautarea exhaustiva, implicit 12
+}
+
+// SwitchingAction este o manevra recomandata.
+type SwitchingAction struct {
+	SeparatorID string          `json:"separatorId"`
+	State       utils.StateType `json:"state"`
+}
+
+// NetworkState descrie o configuratie evaluata.
+type NetworkState struct {
+	Losses     float64 `json:"losses"`     // MW
+	MaxLoading float64 `json:"maxLoading"` // %
+	Unserved   float64 `json:"unserved"`   // MW, puterea consumatorilor nealimentati
+	Radial     bool    `json:"radial"`
+	Feasible   bool    `json:"feasible"` // radiala si fara mai multa putere nealimentata decat configuratia curenta
+}
+
+type ReconfigurationResult struct {
+	Objective     ReconfigurationObjective `json:"objective"`
+	Current       NetworkState             `json:"current"`
+	Recommended   NetworkState             `json:"recommended"`
+	Plan          []SwitchingAction        `json:"plan"`          // intai inchiderile, apoi deschiderile
+	LossSavings   float64                  `json:"lossSavings"`   // MW
+	EnergySavings float64                  `json:"energySavings"` // MWh pe an, estimare: LossSavings la sarcina constanta 8760 h
+	Evaluated     int                      `json:"evaluated"`     // configuratii calculate
+	Separators    []utils.Separator        `json:"-"`             // starea recomandata a separatoarelor
+}
+
+func (o ReconfigurationOptions) withDefaults() ReconfigurationOptions {
+	if o.Objective == "" {
+		o.Objective = ReconfigurationLosses
+	}
+	if o.MaxExhaustive <= 0 {
+		o.MaxExhaustive = 12
+	}
+	return o
+}
+
+func (o ReconfigurationOptions) validate() error {
+	switch o.Objective {
+	case ReconfigurationLosses, ReconfigurationLoading:
+		return nil
+	}
+	return fmt.Errorf("unknown reconfiguration objective %q (expected %s or %s)", o.Objective, ReconfigurationLosses, ReconfigurationLoading)
+}
+
+// isRadial verifica ca fiecare element alimentat din sursa principala are un singur element alimentat in amonte.
+func isRadial(system utils.System, tree feederTree) bool {
+	feeders := map[string]int{}
+	count := func(id string, connectedTo string) {
+		if _, energized := tree.index[id]; energized {
+			if _, target := tree.index[connectedTo]; target {
+				feeders[connectedTo]++
+			}
+		}
+	}
+
+	count(system.Source.ID, system.Source.ConnectedTo)
+	for _, t := range system.Transformers {
+		count(t.ID, t.ConnectedTo)
+	}
+	for _, l := range system.Lines {
+		count(l.ID, l.ConnectedTo)
+	}
+	for _, s := range system.Separators {
+		if s.State == utils.StateClose {
+			count(s.ID, s.ConnectedTo)
+		}
+	}
+	for _, c := range system.Consumers {
+		count(c.ID, c.ConnectedTo)
+	}
+
+	for _, n := range feeders {
+		if n > 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// evaluateState calculeaza circulatia de puteri pentru starea data a separatoarelor. Fezabilitatea se stabileste
+// fata de configuratia curenta, in Reconfigure: motorul traversal poate lasa putere nealimentata in orice configuratie.
+func evaluateState(system utils.System) NetworkState {
+	result, _ := solve(system, io.Discard)
+	c := evaluateCase(system, result)
+
+	state := NetworkState{
+		Losses:   result.ActivePowerLosses,
+		Unserved: c.UnservedPower,
+		Radial:   isRadial(system, buildFeederTree(system)),
+	}
+	for _, id := range result.Order {
+		element := result.Elements[id]
+		if element.Kind == "line" || element.Kind == "transformer" {
+			state.MaxLoading = math.Max(state.MaxLoading, element.Loading)
+		}
+	}
+	return state
+}
+
+func (o ReconfigurationOptions) value(state NetworkState) float64 {
+	if o.Objective == ReconfigurationLoading {
+		return state.MaxLoading
+	}
+	return state.Losses
+}
+
+// better compara doua configuratii: intai fezabilitatea, apoi puterea nealimentata, apoi obiectivul.
+func (o ReconfigurationOptions) better(a NetworkState, b NetworkState) bool {
+	if a.Feasible != b.Feasible {
+		return a.Feasible
+	}
+	if math.Abs(a.Unserved-b.Unserved) > 1e-6 {
+		return a.Unserved < b.Unserved
+	}
+	return o.value(a) < o.value(b)-1e-9
+}
+
+func withSeparatorStates(system utils.System, states []utils.StateType) utils.System {
+	configured := cloneSystem(system)
+	for i := range configured.Separators {
+		configured.Separators[i].State = states[i]
+	}
+	return configured
+}
+
+func toggle(state utils.StateType) utils.StateType {
+	if state == utils.StateOpen {
+		return utils.StateClose
+	}
+	return utils.StateOpen
+}
+
+// Reconfigure cauta starea separatoarelor: exhaustiv pana la MaxExhaustive separatoare, altfel prin schimburi de ramuri
+// (comutarea unui separator sau a unei perechi inchis/deschis) pornind din configuratia curenta.
+func Reconfigure(system utils.System, options ReconfigurationOptions) (ReconfigurationResult, error) {
+	options = options.withDefaults()
+	if err := options.validate(); err != nil {
+		return ReconfigurationResult{Objective: options.Objective}, err
+	}
+
+	current := make([]utils.StateType, len(system.Separators))
+	for i, separator := range system.Separators {
+		current[i] = separator.State
+	}
+
+	result := ReconfigurationResult{Objective: options.Objective}
+	result.Current = evaluateState(system)
+	result.Current.Feasible = result.Current.Radial
+	result.Evaluated = 1
+
+	best := append([]utils.StateType(nil), current...)
+	bestState := result.Current
+
+	try := func(states []utils.StateType) bool {
+		state := evaluateState(withSeparatorStates(system, states))
+		state.Feasible = state.Radial && state.Unserved <= result.Current.Unserved+1e-6
+		result.Evaluated++
+		if options.better(state, bestState) {
+			best = append([]utils.StateType(nil), states...)
+			bestState = state
+			return true
+		}
+		return false
+	}
+
+	n := len(system.Separators)
+	if n <= options.MaxExhaustive {
+		for mask := 0; mask < 1<<n; mask++ {
+			states := make([]utils.StateType, n)
+			changed := false
+			for i := range states {
+				states[i] = utils.StateOpen
+				if mask&(1<<i) != 0 {
+					states[i] = utils.StateClose
+				}
+				changed = changed || states[i] != current[i]
+			}
+			if changed {
+				try(states)
+			}
+		}
+	} else {
+		for improved := true; improved; {
+			improved = false
+			base := append([]utils.StateType(nil), best...)
+			for i := range base {
+				states := append([]utils.StateType(nil), base...)
+				states[i] = toggle(states[i])
+				improved = try(states) || improved
+			}
+			for i := range base {
+				for j := range base {
+					if base[i] != utils.StateOpen || base[j] != utils.StateClose {
+						continue
+					}
+					states := append([]utils.StateType(nil), base...)
+					states[i], states[j] = utils.StateClose, utils.StateOpen
+					improved = try(states) || improved
+				}
+			}
+		}
+	}
+
+	result.Recommended = bestState
+	result.Separators = withSeparatorStates(system, best).Separators
+
+	// Inchiderile inaintea deschiderilor, pentru a nu intrerupe consumatorii in timpul manevrelor
+	for _, wanted := range []utils.StateType{utils.StateClose, utils.StateOpen} {
+		for i, separator := range system.Separators {
+			if best[i] != current[i] && best[i] == wanted {
+				result.Plan = append(result.Plan, SwitchingAction{SeparatorID: separator.ID, State: wanted})
+			}
+		}
+	}
+
+	result.LossSavings = result.Current.Losses - result.Recommended.Losses
+	result.EnergySavings = result.LossSavings * 8760
+	return result, nil
+}
+
+// FormatReconfigurationReport scrie planul de manevre si economiile estimate.
+func FormatReconfigurationReport(result ReconfigurationResult) string {
+	var b strings.Builder
+
+	describe := func(state NetworkState) string {
+		var feasible string
+		switch {
+		case !state.Radial:
+			feasible = fmt.Sprintf("not radial, unserved %.2f MW", state.Unserved)
+		case !state.Feasible:
+			feasible = fmt.Sprintf("not feasible, unserved %.2f MW", state.Unserved)
+		case state.Unserved > 1e-6:
+			feasible = fmt.Sprintf("radial, unserved %.2f MW", state.Unserved)
+		default:
+			feasible = "radial, all consumers supplied"
+		}
+		return fmt.Sprintf("losses %.3f MW, max loading %.1f %% (%s)", state.Losses, state.MaxLoading, feasible)
+	}
+
+	fmt.Fprintf(&b, "Network reconfiguration (minimise %s, %d configurations evaluated)\n", result.Objective, result.Evaluated)
+	fmt.Fprintf(&b, "Current:     %s\n", describe(result.Current))
+	fmt.Fprintf(&b, "Recommended: %s\n", describe(result.Recommended))
+	if len(result.Plan) == 0 {
+		b.WriteString("The current configuration is already the best one\n")
+		return b.String()
+	}
+	b.WriteString("Switching plan:\n")
+	for i, action := range result.Plan {
+		fmt.Fprintf(&b, "%2d. %s separator %s\n", i+1, action.State, action.SeparatorID)
+	}
+	fmt.Fprintf(&b, "Expected savings: %.3f MW of losses (estimated %.0f MWh per year at constant load)\n", result.LossSavings, result.EnergySavings)
+
+	return b.String()
+}
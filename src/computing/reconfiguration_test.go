@@ -0,0 +1,51 @@
+package computing
+
+import (
+	"testing"
+
+	"contor-system/src/utils"
+)
+
+func TestReconfigureClosesLocalSource(t *testing.T) {
+	// Cu separator2 deschis consumul vine integral prin linie; inchiderea lui aduce sursa locala si reduce pierderile
+	system := opfSystem(2000)
+	system.Solver.Engine = utils.EngineSweep
+	system.AdditionalSources[0].Power = 50
+	system.Separators[0].State = utils.StateOpen
+
+	result, err := Reconfigure(system, ReconfigurationOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Plan) != 1 || result.Plan[0].SeparatorID != "separator2" || result.Plan[0].State != utils.StateClose {
+		t.Fatalf("plan %+v", result.Plan)
+	}
+	if !result.Recommended.Feasible || result.LossSavings <= 0 {
+		t.Fatalf("recommended %+v, savings %.4f MW", result.Recommended, result.LossSavings)
+	}
+}
+
+func TestReconfigureKeepsBaselineUnserved(t *testing.T) {
+	// Motorul traversal limiteaza alimentarea la puterea surselor: configuratiile care nu lasa mai multa putere
+	// nealimentata decat cea curenta raman fezabile
+	system := opfSystem(2000)
+	system.Source.Power = 10
+	system.Separators[0].State = utils.StateOpen
+
+	result, err := Reconfigure(system, ReconfigurationOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Current.Unserved <= 0 {
+		t.Fatalf("the base case supplies everything: %+v", result.Current)
+	}
+	if !result.Current.Feasible || !result.Recommended.Feasible {
+		t.Fatalf("current %+v, recommended %+v", result.Current, result.Recommended)
+	}
+}
+
+func TestReconfigureRejectsUnknownObjective(t *testing.T) {
+	if _, err := Reconfigure(opfSystem(2000), ReconfigurationOptions{Objective: "foo"}); err == nil {
+		t.Fatal("unknown objective accepted")
+	}
+}
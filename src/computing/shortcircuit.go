@@ -0,0 +1,342 @@
+package computing
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"sort"
+	"strings"
+
+	"contor-system/src/utils"
+)
+
+// Calculul curentilor de scurtcircuit trifazat dupa IEC 60909.
+
+type ShortCircuitCase string
+
+const (
+	ShortCircuitMax ShortCircuitCase = "max"
+	ShortCircuitMin ShortCircuitCase = "min"
+)
+
+type ShortCircuitOptions struct {
+	Case         ShortCircuitCase // max: c = cmax, min: c = cmin
+	C            float64          // factor de tensiune impus; 0 = dupa nivelul de tensiune si Case
+	MinTimeDelay float64          // s, timpul minim de deconectare pentru curentul de rupere, implicit 0.1
+}
+
+type ShortCircuitNode struct {
+	Node    string   `json:"node"`
+	Voltage float64  `json:"voltage"` // kV, tensiunea nominala in punctul de defect
+	C       float64  `json:"c"`
+	R       float64  `json:"r"`  // ohm, rezistenta echivalenta de scurtcircuit
+	X       float64  `json:"x"`  // ohm, reactanta echivalenta de scurtcircuit
+	Ik      float64  `json:"ik"` // kA, curentul initial de scurtcircuit simetric Ik''
+	Ip      float64  `json:"ip"` // kA, curentul de soc
+	Ib      float64  `json:"ib"` // kA, curentul de rupere simetric
+	Sk      float64  `json:"sk"` // MVA, puterea de scurtcircuit initiala
+	Sources []string `json:"sources"`
+}
+
+/*
+Factorul de tensiune c dupa IEC 60909 (tabelul 1):
+- Un <= 1 kV: cmax = 1.05, cmin = 0.95
+- Un > 1 kV: cmax = 1.10, cmin = 1.00
+*/
+func voltageFactor(un float64, scCase ShortCircuitCase) float64 {
+	if un <= 1 {
+		if scCase == ShortCircuitMin {
+			return 0.95
+		}
+		return 1.05
+	}
+	if scCase == ShortCircuitMin {
+		return 1.0
+	}
+	return 1.1
+}
+
+/*
+Impedanta longitudinala a liniei: R = ro*l/A, X = w*L*l
+- r, x: ohm
+*/
+func lineImpedance(line utils.Line) (float64, float64) {
+	r := lineResistence(line.Ro, line.Length, line.Area)
+	if line.X > 0 {
+		return r, line.X * float64(line.Length)
+	}
+
+	Dm := geometricDistance(line.Drs, line.Dst, line.Drt)
+	re := equivalentRadius(line.R)
+	L := inductionOnLength(Dm, re)                                  // H/m
+	x := totalReactation(2*math.Pi*50*L*1000, float64(line.Length)) // ohm/km * km
+	return r, x
+}
+
+/*
+Impedanta retelei de alimentare: ZQ = c * UnQ^2 / SkQ (puterea de scurtcircuit initiala), RQ = (R/X) * XQ
+*/
+func feederImpedance(source utils.Source, c float64) complex128 {
+	if source.ShortCircuitPower <= 0 {
+		return 0
+	}
+	rx := source.RXRatio
+	if rx == 0 {
+		rx = 0.1
+	}
+	z := c * math.Pow(source.Voltage, 2) / source.ShortCircuitPower
+	x := z / math.Sqrt(1+rx*rx)
+	return complex(rx*x, x)
+}
+
+/*
+Impedanta transformatorului raportata la tensiunea de iesire, corectata cu KT:
+- ZT = uk/100 * Ur^2 / Sr, RT = Pcu * Ur^2 / Sr^2
+- KT = 0.95 * cmax / (1 + 0.6 * xT)
+*/
+func transformerImpedance(transformer utils.Transformer) complex128 {
+	if transformer.ApparentPower <= 0 || transformer.Uk <= 0 {
+		return 0
+	}
+	ur := transformer.OutputVoltage
+	sr := transformer.ApparentPower
+	z := transformer.Uk / 100 * ur * ur / sr
+	r := (transformer.CooperLosses / 1000) * ur * ur / (sr * sr)
+	x := math.Sqrt(math.Max(z*z-r*r, 0))
+
+	xt := x / (ur * ur / sr)
+	kt := 0.95 * voltageFactor(ur, ShortCircuitMax) / (1 + 0.6*xt)
+	return complex(r*kt, x*kt)
+}
+
+/*
+Factorul kappa pentru curentul de soc: k = 1.02 + 0.98 * e^(-3R/X)
+*/
+func peakFactor(z complex128) float64 {
+	if imag(z) == 0 {
+		return 2
+	}
+	return 1.02 + 0.98*math.Exp(-3*real(z)/imag(z))
+}
+
+/*
+Factorul mu pentru curentul de rupere al unui generator (IEC 60909, ec. 70), x = IkG / IrG (curentul initial al generatorului raportat la cel nominal)
+*/
+func breakingFactor(x float64, tmin float64) float64 {
+	if x <= 2 {
+		return 1
+	}
+	var mu float64
+	switch {
+	case tmin <= 0.02:
+		mu = 0.84 + 0.26*math.Exp(-0.26*x)
+	case tmin <= 0.05:
+		mu = 0.71 + 0.51*math.Exp(-0.30*x)
+	case tmin <= 0.10:
+		mu = 0.62 + 0.72*math.Exp(-0.32*x)
+	default:
+		mu = 0.56 + 0.94*math.Exp(-0.38*x)
+	}
+	return math.Min(mu, 1)
+}
+
+// partialShortCircuit este contributia unei surse la defectul dintr-un nod.
+type partialShortCircuit struct {
+	node    string
+	voltage float64    // kV
+	z       complex128 // ohm, impedanta de secventa pozitiva raportata la tensiunea nodului
+	z2      complex128 // ohm, impedanta de secventa negativa
+	z0      complex128 // ohm, impedanta de secventa zero de la originea segmentului
+	z0Open  bool       // nu exista cale pentru secventa zero
+	segment int        // segmentul retelei de secventa zero (se intrerupe la infasurarile in triunghi)
+	shift   float64    // rad, defazajul secventei pozitive fata de sursa
+	source  utils.Source
+}
+
+// feederPartials parcurge lantul alimentat de sursa si cumuleaza impedantele de secventa pana in fiecare nod.
+func feederPartials(system *utils.System, nodes map[string]interface{}, source utils.Source, scCase ShortCircuitCase) []partialShortCircuit {
+	var partials []partialShortCircuit
+	visited := map[string]bool{source.ID: true}
+
+	level := source.Voltage
+	z := feederImpedance(source, voltageFactor(level, scCase))
+	z2Ratio, z0Ratio := source.Z2Z1Ratio, source.Z0Z1Ratio
+	if z2Ratio == 0 {
+		z2Ratio = 1
+	}
+	if z0Ratio == 0 {
+		z0Ratio = 1
+	}
+	current := partialShortCircuit{
+		node:    source.ID,
+		voltage: level,
+		z:       z,
+		z2:      z * complex(z2Ratio, 0),
+		z0:      z * complex(z0Ratio, 0),
+		z0Open:  source.Ungrounded,
+		source:  source,
+	}
+	partials = append(partials, current)
+
+	for id := source.ConnectedTo; !visited[id]; {
+		visited[id] = true
+		node, exists := nodes[id]
+		if !exists {
+			break
+		}
+
+		var next string
+		switch n := node.(type) {
+		case utils.Separator:
+			if n.State == utils.StateOpen {
+				return partials
+			}
+			next = n.ConnectedTo
+		case utils.Transformer:
+			if !isActive(n.ID, system) {
+				return partials
+			}
+			if n.Type != utils.TransformerTypeMeasure && n.OutputVoltage > 0 {
+				current = throughTransformer(current, n)
+			}
+			next = n.ConnectedTo
+		case utils.Line:
+			if !isActive(n.ID, system) {
+				return partials
+			}
+			r, x := lineImpedance(n)
+			r0, x0 := lineZeroSequenceImpedance(n, r, x)
+			current.z += complex(r, x)
+			current.z2 += complex(r, x)
+			current.z0 += complex(r0, x0)
+			current.voltage = n.Voltage
+			next = n.ConnectedTo
+		case utils.Consumer:
+			if !isActive(n.ID, system) {
+				return partials
+			}
+			next = n.ConnectedTo
+		default:
+			// O alta sursa incheie lantul
+			return partials
+		}
+
+		current.node = id
+		partials = append(partials, current)
+		id = next
+	}
+
+	return partials
+}
+
+// shortCircuitPartials parcurge lanturile tuturor surselor conectate. Intoarce nodurile in ordinea parcurgerii,
+// contributiile fiecarei surse pe noduri si calea completa a fiecarei surse.
+func shortCircuitPartials(system utils.System, scCase ShortCircuitCase) ([]string, map[string][]partialShortCircuit, map[string][]partialShortCircuit) {
+	nodes := map[string]interface{}{}
+	for _, t := range system.Transformers {
+		nodes[t.ID] = t
+	}
+	for _, l := range system.Lines {
+		nodes[l.ID] = l
+	}
+	for _, s := range system.Separators {
+		nodes[s.ID] = s
+	}
+	for _, c := range system.Consumers {
+		nodes[c.ID] = c
+	}
+	nodes[system.Source.ID] = system.Source
+	for _, as := range system.AdditionalSources {
+		nodes[as.ID] = as
+	}
+
+	sources := []utils.Source{system.Source}
+	for _, source := range system.AdditionalSources {
+		for _, separator := range system.Separators {
+			if separator.ConnectedTo == source.ID && separator.State == utils.StateClose {
+				sources = append(sources, source)
+				break
+			}
+		}
+	}
+
+	var order []string
+	byNode := map[string][]partialShortCircuit{}
+	paths := map[string][]partialShortCircuit{}
+	for _, source := range sources {
+		paths[source.ID] = feederPartials(&system, nodes, source, scCase)
+		for _, partial := range paths[source.ID] {
+			if _, exists := byNode[partial.node]; !exists {
+				order = append(order, partial.node)
+			}
+			byNode[partial.node] = append(byNode[partial.node], partial)
+		}
+	}
+
+	return order, byNode, paths
+}
+
+// ComputeShortCircuit calculeaza curentul initial Ik, ip si Ib pentru un defect trifazat in fiecare nod al retelei.
+// Contributiile surselor care alimenteaza acelasi nod pe cai diferite se insumeaza.
+func ComputeShortCircuit(system utils.System, options ShortCircuitOptions) []ShortCircuitNode {
+	if options.MinTimeDelay == 0 {
+		options.MinTimeDelay = 0.1
+	}
+
+	order, byNode, _ := shortCircuitPartials(system, options.Case)
+
+	var results []ShortCircuitNode
+	for _, node := range order {
+		partials := byNode[node]
+		un := partials[0].voltage
+		c := options.C
+		if c == 0 {
+			c = voltageFactor(un, options.Case)
+		}
+
+		result := ShortCircuitNode{Node: node, Voltage: un, C: c}
+		var admittance complex128
+		for _, partial := range partials {
+			// Retea de putere infinita chiar la borne: curentul nu poate fi determinat
+			if partial.z == 0 {
+				continue
+			}
+			ik := c * un / (math.Sqrt(3) * cmplx.Abs(partial.z))
+			result.Ik += ik
+			result.Ip += peakFactor(partial.z) * math.Sqrt2 * ik
+
+			mu := 1.0
+			if partial.source.Generator && partial.source.Voltage > 0 && partial.source.Power > 0 {
+				irg := partial.source.Power / (math.Sqrt(3) * partial.source.Voltage)
+				ikg := ik * un / partial.source.Voltage
+				mu = breakingFactor(ikg/irg, options.MinTimeDelay)
+			}
+			result.Ib += mu * ik
+
+			admittance += 1 / partial.z
+			result.Sources = append(result.Sources, partial.source.ID)
+		}
+		if admittance == 0 {
+			continue
+		}
+
+		zeq := 1 / admittance
+		result.R = real(zeq)
+		result.X = imag(zeq)
+		result.Sk = math.Sqrt(3) * un * result.Ik
+		sort.Strings(result.Sources)
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// FormatShortCircuitReport scrie rezultatele ca tabel text.
+func FormatShortCircuitReport(results []ShortCircuitNode) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-16s %8s %5s %9s %9s %9s %9s %9s %10s  %s\n", "Node", "Un[kV]", "c", "R[ohm]", "X[ohm]", "Ik''[kA]", "ip[kA]", "Ib[kA]", "Sk''[MVA]", "Sources")
+	for _, r := range results {
+		fmt.Fprintf(&b, "%-16s %8.2f %5.2f %9.4f %9.4f %9.3f %9.3f %9.3f %10.2f  %s\n", r.Node, r.Voltage, r.C, r.R, r.X, r.Ik, r.Ip, r.Ib, r.Sk, strings.Join(r.Sources, ","))
+	}
+	return b.String()
+}
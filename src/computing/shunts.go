@@ -0,0 +1,77 @@
+package computing
+
+import (
+	"math"
+	"math/cmplx"
+
+	"contor-system/src/utils"
+)
+
+// Compensarea puterii reactive cu baterii de condensatoare si bobine in trepte. Treptele sunt admitante constante
+// (Q = n * Qtreapta * V^2); regulatoarele conecteaza sau deconecteaza cate o treapta dupa fiecare calcul convergent.
+
+// shuntBank este o baterie de compensare dintr-un nod al arborelui.
+type shuntBank struct {
+	bus     int
+	monitor int // nodul in care se masoara cosfi
+	shunt   utils.Shunt
+	step    int
+}
+
+func newShuntBank(tree feederTree, shunt utils.Shunt) (*shuntBank, bool) {
+	bus, exists := tree.index[shunt.ConnectedTo]
+	if !exists {
+		return nil, false
+	}
+	monitor := bus
+	if m, exists := tree.index[shunt.MonitoredElement]; exists {
+		monitor = m
+	}
+	step := int(math.Max(0, math.Min(float64(shunt.Steps), float64(shunt.Step))))
+	return &shuntBank{bus: bus, monitor: monitor, shunt: shunt, step: step}, true
+}
+
+// injection intoarce puterea reactiva produsa la tensiunea v (u.r.): u.r., negativa pentru bobine.
+func (b *shuntBank) injection(v float64) float64 {
+	q := float64(b.step) * b.shunt.StepSize / baseApparentPower * v * v
+	if b.shunt.Type == utils.ShuntTypeReactor {
+		return -q
+	}
+	return q
+}
+
+// control muta treapta cu o pozitie daca marimea reglata este in afara benzii si intoarce true daca treapta s-a schimbat.
+func (b *shuntBank) control(s *sweepState) bool {
+	// raise: este nevoie de mai multa putere reactiva produsa in nod
+	var raise, lower bool
+
+	switch b.shunt.Control {
+	case utils.ShuntControlVoltage:
+		v := cmplx.Abs(s.voltages[b.bus])
+		raise = b.shunt.MinVoltage > 0 && v < b.shunt.MinVoltage
+		lower = b.shunt.MaxVoltage > 0 && v > b.shunt.MaxVoltage
+	case utils.ShuntControlPowerFactor:
+		flow := s.voltages[b.monitor] * cmplx.Conj(s.currents[b.monitor]) * baseApparentPower
+		p, q := real(flow), imag(flow)
+		// Banda moarta de jumatate de treapta pentru a evita pendularea in jurul lui Q = 0
+		halfStep := b.shunt.StepSize / 2
+		raise = q > halfStep && powerFactor(p, q) < b.shunt.PowerFactor
+		lower = q < -halfStep
+	default:
+		return false
+	}
+
+	// La bobine o treapta in plus absoarbe putere reactiva
+	if b.shunt.Type == utils.ShuntTypeReactor {
+		raise, lower = lower, raise
+	}
+	switch {
+	case raise && b.step < b.shunt.Steps:
+		b.step++
+		return true
+	case lower && b.step > 0:
+		b.step--
+		return true
+	}
+	return false
+}
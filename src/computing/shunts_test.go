@@ -0,0 +1,101 @@
+package computing
+
+import (
+	"io"
+	"testing"
+
+	"contor-system/src/utils"
+)
+
+// bankAt intoarce o baterie de compensare in nodul 1, cu tensiunea v (u.r.) si circulatia p + jq (MW, MVAr) in nod.
+func bankAt(shunt utils.Shunt, v float64, p float64, q float64) (*shuntBank, *sweepState) {
+	state := &sweepState{
+		voltages: []complex128{1, complex(v, 0)},
+		currents: []complex128{0, complex(p, -q) / complex(baseApparentPower*v, 0)},
+	}
+	return &shuntBank{bus: 1, monitor: 1, shunt: shunt, step: shunt.Step}, state
+}
+
+func TestShuntVoltageDeadband(t *testing.T) {
+	capacitor := utils.Shunt{Type: utils.ShuntTypeCapacitor, StepSize: 1, Steps: 4, Step: 2, Control: utils.ShuntControlVoltage, MinVoltage: 0.95, MaxVoltage: 1.05}
+	reactor := capacitor
+	reactor.Type = utils.ShuntTypeReactor
+
+	for _, c := range []struct {
+		shunt utils.Shunt
+		v     float64
+		step  int
+	}{
+		{capacitor, 0.95, 2}, // pe marginea benzii nu se comuta
+		{capacitor, 0.9499, 3},
+		{capacitor, 1.05, 2},
+		{capacitor, 1.0501, 1},
+		{reactor, 0.9499, 1}, // bobina deconecteaza o treapta cand tensiunea scade
+		{reactor, 1.0501, 3},
+	} {
+		bank, state := bankAt(c.shunt, c.v, 10, 0)
+		bank.control(state)
+		if bank.step != c.step {
+			t.Fatalf("%s at %.4f pu: step %d, expected %d", c.shunt.Type, c.v, bank.step, c.step)
+		}
+	}
+}
+
+func TestShuntPowerFactorDeadband(t *testing.T) {
+	// Banda moarta este de jumatate de treapta (0.5 MVAr) in jurul lui Q = 0
+	shunt := utils.Shunt{Type: utils.ShuntTypeCapacitor, StepSize: 1, Steps: 4, Step: 2, Control: utils.ShuntControlPowerFactor, PowerFactor: 0.99}
+	for _, c := range []struct {
+		q    float64
+		step int
+	}{
+		{0.5, 2},
+		{0.51, 3},
+		{-0.5, 2},
+		{-0.51, 1},
+	} {
+		// La 1 MW cosfi este sub 0.99 pentru orice Q din afara benzii
+		bank, state := bankAt(shunt, 1, 1, c.q)
+		bank.control(state)
+		if bank.step != c.step {
+			t.Fatalf("Q %.2f MVAr: step %d, expected %d", c.q, bank.step, c.step)
+		}
+	}
+
+	// Cu cosfi peste valoarea impusa treapta nu se schimba, chiar in afara benzii
+	bank, state := bankAt(shunt, 1, 100, 0.6)
+	if bank.control(state) {
+		t.Fatalf("switched at a power factor of %.4f", powerFactor(100, 0.6))
+	}
+}
+
+func TestShuntStepLimits(t *testing.T) {
+	shunt := utils.Shunt{Type: utils.ShuntTypeCapacitor, StepSize: 1, Steps: 4, Step: 4, Control: utils.ShuntControlVoltage, MinVoltage: 0.95, MaxVoltage: 1.05}
+	if bank, state := bankAt(shunt, 0.9, 10, 0); bank.control(state) || bank.step != 4 {
+		t.Fatalf("raised past the last step: %d", bank.step)
+	}
+	shunt.Step = 0
+	if bank, state := bankAt(shunt, 1.1, 10, 0); bank.control(state) || bank.step != 0 {
+		t.Fatalf("lowered below zero: %d", bank.step)
+	}
+}
+
+func TestSweepShuntBringsVoltageIntoBand(t *testing.T) {
+	system := twoBusSystem(6, 3)
+	system.Shunts = []utils.Shunt{{
+		ID: "shunt1", Type: utils.ShuntTypeCapacitor, ConnectedTo: "consumer1", StepSize: 0.5, Steps: 10,
+		Control: utils.ShuntControlVoltage, MinVoltage: 0.95, MaxVoltage: 1.05,
+	}}
+	without, _ := computeSweep(twoBusSystem(6, 3), SweepOptions{}, io.Discard)
+	if v := without.Elements["consumer1"].VoltagePU; v >= 0.95 {
+		t.Fatalf("consumer1 at %.4f pu without compensation, expected below the band", v)
+	}
+
+	result, _ := computeSweep(system, SweepOptions{}, io.Discard)
+	v := result.Elements["consumer1"].VoltagePU
+	if v < 0.95 || v > 1.05 {
+		t.Fatalf("consumer1 at %.4f pu with the capacitor bank", v)
+	}
+	if q := result.Elements["shunt1"].ReactivePower; q <= 0 {
+		t.Fatalf("shunt1 produces %.4f MVAr", q)
+	}
+}
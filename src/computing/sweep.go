@@ -0,0 +1,444 @@
+package computing
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/cmplx"
+	"os"
+	"time"
+
+	"contor-system/src/utils"
+)
+
+// Motor alternativ de calcul: backward/forward sweep pe arborele radial al sursei principale, in unitati relative.
+// Backward: curentul fiecarei laturi este suma curentilor consumatorilor din aval; forward: tensiunea nodului
+// este tensiunea parintelui minus caderea pe latura. Se itereaza pana cand tensiunile nu se mai modifica.
+
+// Solve ruleaza motorul de calcul ales in configuratie (solver.engine).
+func Solve(system utils.System) (utils.SystemResult, []LogEntry) {
+	return solve(system, os.Stdout)
+}
+
+// SolveTo ruleaza motorul de calcul ales in configuratie si scrie mesajele de urmarire in out.
+func SolveTo(system utils.System, out io.Writer) (utils.SystemResult, []LogEntry) {
+	return solve(system, out)
+}
+
+// SolveQuiet ruleaza motorul de calcul fara mesajele de urmarire, pentru calcule repetate.
+func SolveQuiet(system utils.System) utils.SystemResult {
+	result, _ := solve(system, io.Discard)
+	return result
+}
+
+// SolveAt ruleaza motorul de calcul fara mesaje, cu profilele generarii distribuite evaluate la momentul t.
+func SolveAt(system utils.System, t time.Time) utils.SystemResult {
+	result, _ := solveAt(system, t, io.Discard)
+	return result
+}
+
+// EngineWarnings intoarce datele din configuratie pe care motorul ales (solver.engine) le lasa in afara calculului.
+func EngineWarnings(system utils.System) []string {
+	var warnings []string
+	if len(system.Batteries) > 0 && system.Solver.Engine != utils.EngineSweep {
+		warnings = append(warnings, "batteries take part in the power flow only with the sweep engine (solver.engine: sweep)")
+	}
+	// Motoarele echilibrate nu au faze: incarcarea pe faze conteaza doar pentru calculul nesimetric (-unbalanced)
+	for _, consumer := range system.Consumers {
+		if len(consumer.PhaseLoads) == 0 {
+			continue
+		}
+		warning := fmt.Sprintf("consumer %s: phaseLoads are used only by the unbalanced power flow (-unbalanced)", consumer.ID)
+		// Modelul de sarcina se aplica puterii cerute a consumatorului, nu sumei pe faze
+		var total complex128
+		for _, load := range consumerPhaseLoads(consumer) {
+			total += load
+		}
+		if demand := consumerDemand(consumer); cmplx.Abs(total-demand) > 1e-6 {
+			warning += fmt.Sprintf("; the balanced engines apply the load model to %.2f MW, %.2f MVAr instead of the phase total %.2f MW, %.2f MVAr", real(demand), imag(demand), real(total), imag(total))
+		}
+		warnings = append(warnings, warning)
+	}
+	return warnings
+}
+
+func solve(system utils.System, out io.Writer) (utils.SystemResult, []LogEntry) {
+	return solveAt(system, time.Now(), out)
+}
+
+func solveAt(system utils.System, t time.Time, out io.Writer) (utils.SystemResult, []LogEntry) {
+	switch system.Solver.Engine {
+	case utils.EngineSweep:
+		return computeSweep(system, SweepOptions{Tolerance: system.Solver.Tolerance, MaxIterations: system.Solver.MaxIterations, Time: t}, out)
+	default:
+		// Motorul fara iteratii foloseste puterea disponibila a generarii distribuite, fara reglajele invertorului
+		sources := make([]utils.Source, len(system.AdditionalSources))
+		for i, source := range system.AdditionalSources {
+			sources[i] = generationAt(source, t)
+		}
+		system.AdditionalSources = sources
+		return computeSystem(system, out)
+	}
+}
+
+// ComputeSweep calculeaza circulatia de puteri prin backward/forward sweep.
+func ComputeSweep(system utils.System, options SweepOptions) (utils.SystemResult, []LogEntry) {
+	return computeSweep(system, options, os.Stdout)
+}
+
+func newLogEntry(componentID string, message string) LogEntry {
+	return LogEntry{
+		Timestamp:   time.Now().Format("2006/01/02-15:04:05"),
+		ComponentID: componentID,
+		Message:     message,
+	}
+}
+
+// sweepState contine marimile iterate, in unitati relative.
+type sweepState struct {
+	tree       feederTree
+	loads      []complex128      // puterea constanta absorbita in nod (injectiile surselor aditionale cu semn minus)
+	consumers  []*utils.Consumer // consumatorul din nod, a carui putere depinde de tensiune
+	voltages   []complex128
+	currents   []complex128 // curentul laturii care alimenteaza nodul
+	generators []*pvGenerator
+	units      []*dgUnit
+	shunts     []*shuntBank
+}
+
+func newSweepState(tree feederTree) *sweepState {
+	n := len(tree.buses)
+	state := &sweepState{
+		tree:      tree,
+		loads:     make([]complex128, n),
+		consumers: make([]*utils.Consumer, n),
+		voltages:  make([]complex128, n),
+		currents:  make([]complex128, n),
+	}
+	for i := range state.voltages {
+		state.voltages[i] = 1
+	}
+	return state
+}
+
+// load intoarce puterea absorbita in nodul i la tensiunea curenta: u.r.
+func (s *sweepState) load(i int) complex128 {
+	load := s.loads[i]
+	if consumer := s.consumers[i]; consumer != nil {
+		load += consumerLoadAt(*consumer, cmplx.Abs(s.voltages[i])) / baseApparentPower
+	}
+	for _, g := range s.generators {
+		if g.bus == i {
+			load -= complex(0, g.q)
+		}
+	}
+	for _, u := range s.units {
+		if u.bus == i {
+			load -= complex(u.p, u.q)
+		}
+	}
+	for _, b := range s.shunts {
+		if b.bus == i {
+			load -= complex(0, b.injection(cmplx.Abs(s.voltages[i])))
+		}
+	}
+	return load
+}
+
+// iterate face o trecere backward si una forward si intoarce cea mai mare variatie a tensiunii.
+func (s *sweepState) iterate() float64 {
+	n := len(s.tree.buses)
+
+	for i := n - 1; i >= 0; i-- {
+		s.currents[i] = 0
+		if s.voltages[i] != 0 {
+			s.currents[i] = cmplx.Conj(s.load(i) / s.voltages[i])
+		}
+		// Curentul copilului vazut din nodul parinte se inmulteste cu raportul plotului
+		for _, child := range s.tree.buses[i].children {
+			s.currents[i] += s.currents[child] * complex(s.tree.buses[child].ratio, 0)
+		}
+	}
+
+	var maxChange float64
+	for i := 1; i < n; i++ {
+		bus := s.tree.buses[i]
+		v := s.voltages[bus.parent]*complex(bus.ratio, 0) - bus.z*s.currents[i]
+		maxChange = math.Max(maxChange, cmplx.Abs(v-s.voltages[i]))
+		s.voltages[i] = v
+	}
+
+	return maxChange
+}
+
+// solve itereaza pana la convergenta, cu reglajele surselor PV si ale invertoarelor, si intoarce numarul de iteratii.
+func (s *sweepState) solve(options SweepOptions) (int, bool) {
+	for iteration := 1; iteration <= options.MaxIterations; iteration++ {
+		change := s.iterate()
+		var mismatch float64
+		for _, g := range s.generators {
+			mismatch = math.Max(mismatch, g.regulate(cmplx.Abs(s.voltages[g.bus])))
+		}
+		for _, u := range s.units {
+			mismatch = math.Max(mismatch, u.update(cmplx.Abs(s.voltages[u.bus])))
+		}
+		if change < options.Tolerance && mismatch < options.Tolerance {
+			return iteration, true
+		}
+	}
+	return options.MaxIterations, false
+}
+
+func computeSweep(system utils.System, options SweepOptions, out io.Writer) (utils.SystemResult, []LogEntry) {
+	options = options.withDefaults()
+	fmt.Fprintln(out, "Calculating power flow for the system (backward/forward sweep)...")
+
+	tree := buildFeederTree(system)
+	if tree.loop != "" {
+		// Metoda cere o retea radiala: o retea buclata nu se calculeaza si toate elementele raman fara tensiune
+		message := fmt.Sprintf("Backward/forward sweep needs a radial network: connectedTo of %s closes a loop\n", tree.loop)
+		fmt.Fprint(out, message)
+		result := utils.SystemResult{Elements: map[string]*utils.ElementResult{}, Engine: utils.EngineSweep}
+		logs := append([]LogEntry{newLogEntry(tree.loop, message)}, deenergizedElements(system, &result)...)
+		return result, logs
+	}
+	for k, inj := range tree.injections {
+		tree.injections[k].source = generationAt(inj.source, options.Time)
+	}
+	state := newSweepState(tree)
+	for i, bus := range tree.buses {
+		switch e := bus.element.(type) {
+		case utils.Consumer:
+			state.consumers[i] = &e
+		case utils.Transformer:
+			// Pierderile in fier sunt o sarcina constanta in nodul transformatorului
+			state.loads[i] = complex(e.SteelLosses/1000, 0) / baseApparentPower
+		}
+	}
+	if system.Source.VoltageSetpoint > 0 {
+		state.voltages[0] = complex(system.Source.VoltageSetpoint, 0)
+	}
+	generators := map[string]*pvGenerator{}
+	units := map[string]*dgUnit{}
+	for _, inj := range tree.injections {
+		if hasInverterControl(inj.source) {
+			u := newDGUnit(inj)
+			state.units = append(state.units, u)
+			units[inj.source.ID] = u
+			continue
+		}
+		if inj.source.VoltageSetpoint > 0 {
+			g := newPVGenerator(tree, inj)
+			state.generators = append(state.generators, g)
+			generators[inj.source.ID] = g
+			state.loads[inj.bus] -= complex(inj.source.Power, 0) / baseApparentPower
+			continue
+		}
+		state.loads[inj.bus] -= complex(inj.source.Power, inj.source.ReactivePower) / baseApparentPower
+	}
+
+	result := utils.SystemResult{
+		Elements: map[string]*utils.ElementResult{},
+		Engine:   utils.EngineSweep,
+	}
+	maxOperations := 0
+	for _, shunt := range system.Shunts {
+		if bank, exists := newShuntBank(tree, shunt); exists {
+			state.shunts = append(state.shunts, bank)
+			maxOperations += 2 * shunt.Steps
+		}
+	}
+
+	// Dupa fiecare calcul convergent regulatoarele bateriilor de compensare muta cel mult cate o treapta
+	for operations := 0; ; operations++ {
+		iterations, converged := state.solve(options)
+		result.Iterations += iterations
+		result.Converged = converged
+
+		switched := false
+		for _, bank := range state.shunts {
+			if converged && bank.control(state) {
+				switched = true
+			}
+		}
+		if !switched || operations >= maxOperations {
+			break
+		}
+	}
+
+	var logs []LogEntry
+	if !result.Converged {
+		logs = append(logs, newLogEntry(system.Source.ID, fmt.Sprintf("Backward/forward sweep did not converge after %d iterations\n", result.Iterations)))
+	}
+	fmt.Fprintf(out, "Sweep finished after %d iterations (converged: %t)\n", result.Iterations, result.Converged)
+
+	record := func(r utils.ElementResult) {
+		result.Order = append(result.Order, r.ID)
+		result.Elements[r.ID] = &r
+	}
+
+	for i, bus := range tree.buses {
+		v := state.voltages[i]
+		j := state.currents[i]
+		// Curentul de baza: Ib = Sb / (sqrt(3) * Ub): kA
+		baseCurrent := baseApparentPower / (math.Sqrt(3) * bus.voltage) * 1000
+		received := v * cmplx.Conj(j) * baseApparentPower
+		var sent complex128
+		if bus.parent >= 0 {
+			sent = state.voltages[bus.parent] * complex(bus.ratio, 0) * cmplx.Conj(j) * baseApparentPower
+		} else {
+			sent = received
+		}
+
+		element := utils.ElementResult{
+			ID:                  bus.id,
+			Kind:                bus.kind,
+			ActivePower:         real(received),
+			ReactivePower:       imag(received),
+			Current:             cmplx.Abs(j) * baseCurrent,
+			Voltage:             cmplx.Abs(v) * bus.voltage,
+			VoltagePU:           cmplx.Abs(v),
+			PowerFactor:         powerFactor(real(received), imag(received)),
+			ActivePowerLosses:   real(sent - received),
+			ReactivePowerLosses: imag(sent - received),
+			Energized:           true,
+		}
+
+		var message string
+		switch e := bus.element.(type) {
+		case utils.Source:
+			message = fmt.Sprintf("Source %s supplying %.2f MW, %.2f MVAr at %.2f kV\n", e.ID, real(received), imag(received), element.Voltage)
+		case utils.Transformer:
+			steelLosses := e.SteelLosses / 1000
+			element.ActivePower -= steelLosses
+			element.ActivePowerLosses += steelLosses
+			element.Loading = loadingPercent(cmplx.Abs(received), e.ApparentPower)
+			message = fmt.Sprintf("Transformer %s transferring power: %.2f MW, %.2f MVAr (losses: %.3f MW), loading %.1f %%\n", e.ID, real(received), imag(received), element.ActivePowerLosses, element.Loading)
+			if e.Tap != nil {
+				message = fmt.Sprintf("Transformer %s (tap %d) transferring power: %.2f MW, %.2f MVAr (losses: %.3f MW), loading %.1f %%\n", e.ID, e.Tap.Position, real(received), imag(received), element.ActivePowerLosses, element.Loading)
+			}
+		case utils.Line:
+			element.Loading = loadingPercent(element.Current, e.RatedCurrent)
+			message = fmt.Sprintf("Line %s (%d km) carries %.2f A, voltage at end %.2f kV, Active power losses per line %.3f, Reactive power losses per line %.3f \n", e.ID, e.Length, element.Current, element.Voltage, element.ActivePowerLosses, element.ReactivePowerLosses)
+		case utils.Separator:
+			message = fmt.Sprintf("Separator %s is in %s state \n", e.ID, e.State)
+		case utils.Consumer:
+			consumed := consumerLoadAt(e, element.VoltagePU)
+			element.ActivePower = real(consumed)
+			element.ReactivePower = imag(consumed)
+			element.Current = cmplx.Abs(consumed/baseApparentPower/v) * baseCurrent
+			element.PowerFactor = powerFactor(real(consumed), imag(consumed))
+			message = fmt.Sprintf("Consumer %s draws %.2f MW, %.2f MVAr at %.2f kV (voltage drop %.2f %%)\n", e.ID, real(consumed), imag(consumed), element.Voltage, (1-element.VoltagePU)*100)
+		}
+
+		record(element)
+		logs = append(logs, newLogEntry(bus.id, message))
+	}
+
+	for _, bank := range state.shunts {
+		v := cmplx.Abs(state.voltages[bank.bus])
+		q := bank.injection(v) * baseApparentPower
+		voltage := tree.buses[bank.bus].voltage * v
+		record(utils.ElementResult{
+			ID:            bank.shunt.ID,
+			Kind:          "shunt",
+			ReactivePower: q,
+			Current:       busCurrent(q, v, tree.buses[bank.bus].voltage),
+			Voltage:       voltage,
+			VoltagePU:     v,
+			Energized:     true,
+		})
+		logs = append(logs, newLogEntry(bank.shunt.ID, fmt.Sprintf("Shunt %s %s at step %d/%d: %.2f MVAr at %.2f kV (%.4f pu)\n", bank.shunt.Type, bank.shunt.ID, bank.step, bank.shunt.Steps, q, voltage, v)))
+	}
+
+	for _, inj := range tree.injections {
+		v := cmplx.Abs(state.voltages[inj.bus])
+		p, q := inj.source.Power, inj.source.ReactivePower
+		control := "PQ"
+		if g, exists := generators[inj.source.ID]; exists {
+			q = g.q * baseApparentPower
+			control = "PV"
+			if g.limited {
+				control = "PQ, Q at limit"
+			}
+		}
+		if u, exists := units[inj.source.ID]; exists {
+			p, q = u.p*baseApparentPower, u.q*baseApparentPower
+			control = "inverter"
+			if mode := inj.source.Inverter.Mode; mode != "" {
+				control += " " + string(mode)
+			}
+			if curtailed := inj.source.Power - p; curtailed > 1e-6 {
+				control += fmt.Sprintf(", curtailed %.2f MW", curtailed)
+			}
+		}
+		record(utils.ElementResult{
+			ID:            inj.source.ID,
+			Kind:          inj.kind,
+			ActivePower:   p,
+			ReactivePower: q,
+			Current:       busCurrent(apparentPower(p, q), v, inj.source.Voltage),
+			Voltage:       inj.source.Voltage * v,
+			VoltagePU:     v,
+			PowerFactor:   powerFactor(p, q),
+			Energized:     true,
+		})
+		if inj.kind == "battery" {
+			logs = append(logs, newLogEntry(inj.source.ID, fmt.Sprintf("Battery %s exchanging %.2f MW at %.2f kV (%.4f pu)\n", inj.source.ID, p, inj.source.Voltage*v, v)))
+			continue
+		}
+		logs = append(logs, newLogEntry(inj.source.ID, fmt.Sprintf("Source %s (%s) injecting %.2f MW, %.2f MVAr at %.2f kV (%.4f pu)\n", inj.source.ID, control, p, q, inj.source.Voltage*v, v)))
+	}
+
+	// Elementele la care nu ajunge sursa principala raman fara tensiune
+	logs = append(logs, deenergizedElements(system, &result)...)
+
+	for _, element := range result.Elements {
+		result.ActivePowerLosses += element.ActivePowerLosses
+		result.ReactivePowerLosses += element.ReactivePowerLosses
+	}
+
+	return result, logs
+}
+
+// deenergizedElements inregistreaza fara tensiune elementele care lipsesc din rezultat si consumatorii lor nealimentati.
+func deenergizedElements(system utils.System, result *utils.SystemResult) []LogEntry {
+	var logs []LogEntry
+	deenergized := func(id string, kind string) {
+		if _, exists := result.Elements[id]; !exists {
+			result.Order = append(result.Order, id)
+			result.Elements[id] = &utils.ElementResult{ID: id, Kind: kind}
+		}
+	}
+	deenergized(system.Source.ID, "source")
+	for _, source := range system.AdditionalSources {
+		deenergized(source.ID, "source")
+	}
+	for _, battery := range system.Batteries {
+		deenergized(battery.ID, "battery")
+	}
+	for _, shunt := range system.Shunts {
+		deenergized(shunt.ID, "shunt")
+	}
+	for _, transformer := range system.Transformers {
+		deenergized(transformer.ID, "transformer")
+	}
+	for _, line := range system.Lines {
+		deenergized(line.ID, "line")
+	}
+	for _, separator := range system.Separators {
+		deenergized(separator.ID, "separator")
+	}
+	for _, consumer := range system.Consumers {
+		if _, exists := result.Elements[consumer.ID]; exists {
+			continue
+		}
+		deenergized(consumer.ID, "consumer")
+		result.ConsumersWithoutPower = append(result.ConsumersWithoutPower, utils.ConsumerPowerDetails{
+			ID:                   consumer.ID,
+			RemainingPowerNeeded: -consumer.PowerNeeded,
+		})
+		logs = append(logs, newLogEntry(consumer.ID, fmt.Sprintf("Consumer %s needs more power: %.2f MW\n", consumer.ID, consumer.PowerNeeded)))
+	}
+	return logs
+}
@@ -0,0 +1,80 @@
+package computing
+
+import (
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"contor-system/src/utils"
+)
+
+// twoBusSystem: sursa de 20 kV, o linie cu R = 30·10/100 = 3 ohm si X = 0.4·10 = 4 ohm, un consumator de putere constanta.
+// Cu Zb = 20²/100 = 4 ohm, linia are r = 0.75 u.r. si x = 1 u.r.
+func twoBusSystem(p float64, q float64) utils.System {
+	return utils.System{
+		Source: utils.Source{ID: "source1", Voltage: 20, Power: 200, ConnectedTo: "line1"},
+		Lines: []utils.Line{{
+			ID: "line1", Voltage: 20, Length: 10, Area: 100, Ro: 30, X: 0.4, ConnectedTo: "consumer1",
+		}},
+		Consumers: []utils.Consumer{{ID: "consumer1", PowerNeeded: p, ReactivePowerAbsorbed: q, Voltage: 20}},
+		Solver:    utils.Solver{Engine: utils.EngineSweep},
+	}
+}
+
+func TestSweepTwoBus(t *testing.T) {
+	result, _ := computeSweep(twoBusSystem(2, 1), SweepOptions{Tolerance: 1e-10}, io.Discard)
+	if !result.Converged {
+		t.Fatal("did not converge")
+	}
+
+	// Tensiunea la capat pentru o sarcina de putere constanta, cu U1 = 1:
+	// U2⁴ + (2(p·r + q·x) - 1)·U2² + (p² + q²)(r² + x²) = 0, iar pierderile sunt r·(p² + q²) / U2²
+	r, x := 0.75, 1.0
+	p, q := 0.02, 0.01
+	b := 2*(p*r+q*x) - 1
+	c := (p*p + q*q) * (r*r + x*x)
+	v := math.Sqrt((-b + math.Sqrt(b*b-4*c)) / 2)
+	losses := r * (p*p + q*q) / (v * v) * baseApparentPower
+
+	if consumer := result.Elements["consumer1"]; math.Abs(consumer.VoltagePU-v) > 1e-8 {
+		t.Fatalf("consumer1 at %.8f pu, expected %.8f", consumer.VoltagePU, v)
+	}
+	if line := result.Elements["line1"]; math.Abs(line.ActivePowerLosses-losses) > 1e-8 || math.Abs(line.ReactivePowerLosses-losses*x/r) > 1e-8 {
+		t.Fatalf("line1 losses %.8f MW, %.8f MVAr, expected %.8f MW, %.8f MVAr", line.ActivePowerLosses, line.ReactivePowerLosses, losses, losses*x/r)
+	}
+	if math.Abs(result.ActivePowerLosses-losses) > 1e-8 {
+		t.Fatalf("system losses %.8f MW, expected %.8f", result.ActivePowerLosses, losses)
+	}
+}
+
+func TestSweepRejectsMeshedNetwork(t *testing.T) {
+	// consumer1 → separator1 → line1 inchide bucla source1 → line1 → consumer1
+	system := twoBusSystem(2, 1)
+	system.Consumers[0].ConnectedTo = "separator1"
+	system.Separators = []utils.Separator{{ID: "separator1", ConnectsFrom: "consumer1", State: utils.StateClose, ConnectedTo: "line1"}}
+
+	result, logs := computeSweep(system, SweepOptions{}, io.Discard)
+	if result.Converged || len(logs) == 0 || !strings.Contains(logs[0].Message, "separator1 closes a loop") {
+		t.Fatalf("converged %t, logs %+v", result.Converged, logs)
+	}
+	for _, id := range []string{"source1", "line1", "consumer1", "separator1"} {
+		if element, exists := result.Elements[id]; !exists || element.Energized {
+			t.Fatalf("%s: %+v", id, element)
+		}
+	}
+	if len(result.ConsumersWithoutPower) != 1 {
+		t.Fatalf("consumers without power %+v", result.ConsumersWithoutPower)
+	}
+}
+
+func TestSweepNonConvergence(t *testing.T) {
+	// 80 MW depasesc puterea maxima transmisibila pe linie (varful curbei P-U), deci iteratiile nu se stabilizeaza
+	result, logs := computeSweep(twoBusSystem(80, 20), SweepOptions{MaxIterations: 50}, io.Discard)
+	if result.Converged || result.Iterations != 50 {
+		t.Fatalf("converged %t after %d iterations", result.Converged, result.Iterations)
+	}
+	if len(logs) == 0 || !strings.Contains(logs[0].Message, "did not converge after 50 iterations") {
+		t.Fatalf("logs %+v", logs)
+	}
+}
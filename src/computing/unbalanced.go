@@ -0,0 +1,292 @@
+package computing
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"strings"
+	"time"
+
+	"contor-system/src/utils"
+)
+
+// Circulatie de puteri trifazata nesimetrica (abc) prin backward/forward sweep, pentru retele radiale de distributie.
+
+type SweepOptions struct {
+	Tolerance        float64   // u.r., implicit 1e-6
+	MaxIterations    int       // implicit 100
+	EarthResistivity float64   // ohm*m, rezistivitatea solului pentru ecuatiile Carson, implicit 100
+	Time             time.Time // momentul pentru profilele generarii distribuite, implicit momentul curent
+}
+
+func (o SweepOptions) withDefaults() SweepOptions {
+	if o.Tolerance <= 0 {
+		o.Tolerance = 1e-6
+	}
+	if o.MaxIterations <= 0 {
+		o.MaxIterations = 100
+	}
+	if o.EarthResistivity <= 0 {
+		o.EarthResistivity = 100
+	}
+	if o.Time.IsZero() {
+		o.Time = time.Now()
+	}
+	return o
+}
+
+type PhaseValues struct {
+	A float64 `json:"a"`
+	B float64 `json:"b"`
+	C float64 `json:"c"`
+}
+
+type ThreePhaseBus struct {
+	ID             string      `json:"id"`
+	Kind           string      `json:"kind"`
+	NominalVoltage float64     `json:"nominalVoltage"` // kV, intre faze
+	VoltagePU      PhaseValues `json:"voltagePU"`      // u.r.
+	Voltage        PhaseValues `json:"voltage"`        // kV, faza-neutru
+	VoltageAngle   PhaseValues `json:"voltageAngle"`   // grade
+	Current        PhaseValues `json:"current"`        // A, curentul laturii care alimenteaza nodul
+	NeutralCurrent float64     `json:"neutralCurrent"` // A
+	Unbalance      float64     `json:"unbalance"`      // %, |V2| / |V1|
+}
+
+type ThreePhaseResult struct {
+	Buses             []ThreePhaseBus `json:"buses"`
+	ActivePowerLosses float64         `json:"activePowerLosses"` // MW
+	Iterations        int             `json:"iterations"`
+	Converged         bool            `json:"converged"`
+}
+
+type phaseVector [3]complex128
+type phaseMatrix [3][3]complex128
+
+func (m phaseMatrix) mul(v phaseVector) phaseVector {
+	var r phaseVector
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			r[i] += m[i][j] * v[j]
+		}
+	}
+	return r
+}
+
+func diagonal(z complex128) phaseMatrix {
+	return phaseMatrix{{z, 0, 0}, {0, z, 0}, {0, 0, z}}
+}
+
+/*
+Matricea impedantelor liniei din ecuatiile Carson simplificate (cu intoarcere prin pamant), ohm:
+- Zii = r + w*u0/8 + j*w*u0/(2*pi) * ln(De/GMR)
+- Zij = w*u0/8 + j*w*u0/(2*pi) * ln(De/Dij)
+- De = 658.5 * sqrt(ro_sol / f): m
+Daca geometria lipseste, matricea se construieste echilibrat din Z1 si Z0: Zs = (Z0 + 2*Z1) / 3, Zm = (Z0 - Z1) / 3
+*/
+func lineImpedanceMatrix(line utils.Line, earthResistivity float64) phaseMatrix {
+	if line.R <= 0 || line.Drs <= 0 || line.Dst <= 0 || line.Drt <= 0 {
+		r1, x1 := lineImpedance(line)
+		r0, x0 := lineZeroSequenceImpedance(line, r1, x1)
+		z1, z0 := complex(r1, x1), complex(r0, x0)
+		zs, zm := (z0+2*z1)/3, (z0-z1)/3
+		return phaseMatrix{{zs, zm, zm}, {zm, zs, zm}, {zm, zm, zs}}
+	}
+
+	const f = 50.0
+	w := 2 * math.Pi * f
+	u0 := 4 * math.Pi * 1e-7
+	de := 658.5 * math.Sqrt(earthResistivity/f)
+	gmr := equivalentRadius(line.R)
+	length := float64(line.Length) * 1000 // m
+	r := lineResistence(line.Ro, line.Length, line.Area)
+
+	earth := w * u0 / 8 * length
+	self := complex(r+earth, w*u0/(2*math.Pi)*math.Log(de/gmr)*length)
+	mutual := func(d float64) complex128 {
+		return complex(earth, w*u0/(2*math.Pi)*math.Log(de/d)*length)
+	}
+	ab, bc, ac := mutual(line.Drs), mutual(line.Dst), mutual(line.Drt)
+
+	return phaseMatrix{{self, ab, ac}, {ab, self, bc}, {ac, bc, self}}
+}
+
+// consumerPhaseLoads intoarce puterea ceruta pe fiecare faza: MVA.
+func consumerPhaseLoads(consumer utils.Consumer) phaseVector {
+	var loads phaseVector
+	names := []string{"a", "b", "c"}
+
+	if len(consumer.PhaseLoads) > 0 {
+		for i, name := range names {
+			if load, exists := consumer.PhaseLoads[name]; exists {
+				loads[i] = complex(load.P, load.Q)
+			}
+		}
+		return loads
+	}
+
+	phases := strings.ToLower(consumer.Phases)
+	if phases == "" {
+		phases = "abc"
+	}
+	var connected []int
+	for i, name := range names {
+		if strings.Contains(phases, name) {
+			connected = append(connected, i)
+		}
+	}
+	for _, i := range connected {
+		loads[i] = consumerDemand(consumer) / complex(float64(len(connected)), 0)
+	}
+	return loads
+}
+
+// symmetricalComponents intoarce componentele de secventa pozitiva si negativa.
+func symmetricalComponents(v phaseVector) (complex128, complex128) {
+	a, a2 := rotation, rotation*rotation
+	v1 := (v[0] + a*v[1] + a2*v[2]) / 3
+	v2 := (v[0] + a2*v[1] + a*v[2]) / 3
+	return v1, v2
+}
+
+// ComputeUnbalanced rezolva circulatia de puteri pe faze. Transformatoarele sunt modelate pe faza (stea-stea legata la pamant),
+// fara defazajul grupei de conexiuni, cu raportul plotului si pierderile in fier impartite egal pe faze; sursele aditionale
+// injecteaza puterea echilibrat pe cele trei faze.
+func ComputeUnbalanced(system utils.System, options SweepOptions) ThreePhaseResult {
+	options = options.withDefaults()
+	tree := buildFeederTree(system)
+	n := len(tree.buses)
+	perPhaseBase := complex(baseApparentPower/3, 0)
+
+	impedances := make([]phaseMatrix, n)
+	loads := make([]phaseVector, n)
+	models := make([]*utils.LoadModel, n)
+	injected := make([]phaseVector, n)
+	var steelLosses float64
+	for i, bus := range tree.buses {
+		switch e := bus.element.(type) {
+		case utils.Line:
+			zabc := lineImpedanceMatrix(e, options.EarthResistivity)
+			zb := complex(baseImpedance(bus.voltage), 0)
+			for r := 0; r < 3; r++ {
+				for c := 0; c < 3; c++ {
+					impedances[i][r][c] = zabc[r][c] / zb
+				}
+			}
+		case utils.Consumer:
+			for p, s := range consumerPhaseLoads(e) {
+				loads[i][p] = s / perPhaseBase
+			}
+			models[i] = e.LoadModel
+		case utils.Transformer:
+			impedances[i] = diagonal(bus.z)
+			// Pierderile in fier sunt o sarcina constanta in nodul transformatorului
+			for p := 0; p < 3; p++ {
+				loads[i][p] = complex(e.SteelLosses/1000/3, 0) / perPhaseBase
+			}
+			steelLosses += e.SteelLosses / 1000
+		default:
+			impedances[i] = diagonal(bus.z)
+		}
+	}
+	for _, inj := range tree.injections {
+		source := generationAt(inj.source, options.Time)
+		s := complex(source.Power, source.ReactivePower) / 3 / perPhaseBase
+		for p := 0; p < 3; p++ {
+			injected[inj.bus][p] += s
+		}
+	}
+
+	balanced := phaseVector{1, cmplx.Rect(1, -2*math.Pi/3), cmplx.Rect(1, 2*math.Pi/3)}
+	voltages := make([]phaseVector, n)
+	for i := range voltages {
+		voltages[i] = balanced
+	}
+	if system.Source.VoltageSetpoint > 0 {
+		for p := 0; p < 3; p++ {
+			voltages[0][p] *= complex(system.Source.VoltageSetpoint, 0)
+		}
+	}
+	currents := make([]phaseVector, n)
+
+	result := ThreePhaseResult{}
+	for iteration := 1; iteration <= options.MaxIterations; iteration++ {
+		result.Iterations = iteration
+
+		// Backward: curentul fiecarei laturi este suma curentilor absorbiti in aval
+		for i := n - 1; i >= 0; i-- {
+			for p := 0; p < 3; p++ {
+				currents[i][p] = 0
+				if voltages[i][p] != 0 {
+					load := applyLoadModel(models[i], loads[i][p], cmplx.Abs(voltages[i][p])) - injected[i][p]
+					currents[i][p] = cmplx.Conj(load / voltages[i][p])
+				}
+			}
+			// Curentul copilului vazut din nodul parinte se inmulteste cu raportul plotului
+			for _, child := range tree.buses[i].children {
+				for p := 0; p < 3; p++ {
+					currents[i][p] += currents[child][p] * complex(tree.buses[child].ratio, 0)
+				}
+			}
+		}
+
+		// Forward: tensiunea fiecarui nod din tensiunea parintelui si caderea pe latura
+		var maxChange float64
+		for i := 1; i < n; i++ {
+			drop := impedances[i].mul(currents[i])
+			for p := 0; p < 3; p++ {
+				v := voltages[tree.buses[i].parent][p]*complex(tree.buses[i].ratio, 0) - drop[p]
+				maxChange = math.Max(maxChange, cmplx.Abs(v-voltages[i][p]))
+				voltages[i][p] = v
+			}
+		}
+
+		if maxChange < options.Tolerance {
+			result.Converged = true
+			break
+		}
+	}
+
+	result.ActivePowerLosses = steelLosses
+	for i, bus := range tree.buses {
+		drop := impedances[i].mul(currents[i])
+		for p := 0; p < 3; p++ {
+			result.ActivePowerLosses += real(drop[p]*cmplx.Conj(currents[i][p])) * baseApparentPower / 3
+		}
+
+		// Curentul de baza: Ib = Sb / (sqrt(3) * Ub): kA
+		baseCurrent := baseApparentPower / (math.Sqrt(3) * bus.voltage) * 1000
+		phaseVoltage := bus.voltage / math.Sqrt(3)
+		v := voltages[i]
+		j := currents[i]
+		v1, v2 := symmetricalComponents(v)
+
+		threePhaseBus := ThreePhaseBus{
+			ID:             bus.id,
+			Kind:           bus.kind,
+			NominalVoltage: bus.voltage,
+			VoltagePU:      PhaseValues{cmplx.Abs(v[0]), cmplx.Abs(v[1]), cmplx.Abs(v[2])},
+			Voltage:        PhaseValues{cmplx.Abs(v[0]) * phaseVoltage, cmplx.Abs(v[1]) * phaseVoltage, cmplx.Abs(v[2]) * phaseVoltage},
+			VoltageAngle:   PhaseValues{cmplx.Phase(v[0]) * 180 / math.Pi, cmplx.Phase(v[1]) * 180 / math.Pi, cmplx.Phase(v[2]) * 180 / math.Pi},
+			Current:        PhaseValues{cmplx.Abs(j[0]) * baseCurrent, cmplx.Abs(j[1]) * baseCurrent, cmplx.Abs(j[2]) * baseCurrent},
+			NeutralCurrent: cmplx.Abs(j[0]+j[1]+j[2]) * baseCurrent,
+		}
+		if cmplx.Abs(v1) > 0 {
+			threePhaseBus.Unbalance = cmplx.Abs(v2) / cmplx.Abs(v1) * 100
+		}
+		result.Buses = append(result.Buses, threePhaseBus)
+	}
+
+	return result
+}
+
+// FormatUnbalancedReport scrie tensiunile si curentii pe faze ca tabel text.
+func FormatUnbalancedReport(result ThreePhaseResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Three-phase sweep: %d iterations, converged %t, losses %.4f MW\n", result.Iterations, result.Converged, result.ActivePowerLosses)
+	fmt.Fprintf(&b, "%-16s %7s %7s %7s %7s %9s %9s %9s %9s %7s\n", "Bus", "Un[kV]", "Va[pu]", "Vb[pu]", "Vc[pu]", "Ia[A]", "Ib[A]", "Ic[A]", "In[A]", "VUF[%]")
+	for _, bus := range result.Buses {
+		fmt.Fprintf(&b, "%-16s %7.2f %7.4f %7.4f %7.4f %9.2f %9.2f %9.2f %9.2f %7.3f\n", bus.ID, bus.NominalVoltage, bus.VoltagePU.A, bus.VoltagePU.B, bus.VoltagePU.C, bus.Current.A, bus.Current.B, bus.Current.C, bus.NeutralCurrent, bus.Unbalance)
+	}
+	return b.String()
+}
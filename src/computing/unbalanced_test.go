@@ -0,0 +1,79 @@
+package computing
+
+import (
+	"io"
+	"math"
+	"testing"
+
+	"contor-system/src/utils"
+)
+
+// unbalancedSystem este o retea 110/20 kV: transformatorul are plotul +2 x 1.25 % si pierderi in fier de 30 kW,
+// linia de 20 kV este data prin R si X, fara geometrie, deci matricea ei se construieste din Z1 si Z0.
+func unbalancedSystem() utils.System {
+	return utils.System{
+		Source: utils.Source{ID: "source1", Voltage: 110, Power: 100, ConnectedTo: "transformer1"},
+		Transformers: []utils.Transformer{{
+			ID: "transformer1", InputVoltage: 110, OutputVoltage: 20, ApparentPower: 40, Uk: 10, CooperLosses: 120, SteelLosses: 30,
+			Tap: &utils.TapChanger{Position: 2, MinPosition: -8, MaxPosition: 8, StepPercent: 1.25}, ConnectedTo: "line1",
+		}},
+		Lines: []utils.Line{{
+			ID: "line1", Voltage: 20, Length: 10, Area: 100, Ro: 30, X: 0.4, ConnectedTo: "consumer1",
+		}},
+		Consumers: []utils.Consumer{{ID: "consumer1", PowerNeeded: 9, ReactivePowerAbsorbed: 3, Voltage: 20}},
+	}
+}
+
+func TestUnbalancedMatchesSweepForBalancedLoad(t *testing.T) {
+	system := unbalancedSystem()
+	balanced, _ := computeSweep(system, SweepOptions{}, io.Discard)
+	unbalanced := ComputeUnbalanced(system, SweepOptions{})
+	if !balanced.Converged || !unbalanced.Converged {
+		t.Fatalf("converged: sweep %t, unbalanced %t", balanced.Converged, unbalanced.Converged)
+	}
+
+	for _, bus := range unbalanced.Buses {
+		expected := balanced.Elements[bus.ID].VoltagePU
+		for _, v := range []float64{bus.VoltagePU.A, bus.VoltagePU.B, bus.VoltagePU.C} {
+			if math.Abs(v-expected) > 1e-6 {
+				t.Fatalf("%s: phase voltages %+v pu, sweep %.6f pu", bus.ID, bus.VoltagePU, expected)
+			}
+		}
+		if bus.NeutralCurrent > 1e-6 {
+			t.Fatalf("%s: neutral current %.6f A under a balanced load", bus.ID, bus.NeutralCurrent)
+		}
+	}
+	// Plotul ridica tensiunea la iesirea transformatorului peste cea a sursei
+	if v := balanced.Elements["transformer1"].VoltagePU; v <= 1 {
+		t.Fatalf("transformer1 at %.4f pu, expected the +2.5 %% tap to raise it above 1", v)
+	}
+	if math.Abs(unbalanced.ActivePowerLosses-balanced.ActivePowerLosses) > 1e-6 {
+		t.Fatalf("losses %.6f MW, sweep %.6f MW", unbalanced.ActivePowerLosses, balanced.ActivePowerLosses)
+	}
+}
+
+func TestUnbalancedSinglePhaseLoad(t *testing.T) {
+	system := unbalancedSystem()
+	// 1 MW pe o singura faza; 9 MW pe faza a depasesc capacitatea liniei si calculul nu mai converge
+	system.Consumers[0].PowerNeeded, system.Consumers[0].ReactivePowerAbsorbed = 1, 0.3
+	system.Consumers[0].Phases = "a"
+
+	result := ComputeUnbalanced(system, SweepOptions{})
+	if !result.Converged {
+		t.Fatal("did not converge")
+	}
+	var consumer ThreePhaseBus
+	for _, bus := range result.Buses {
+		if bus.ID == "consumer1" {
+			consumer = bus
+		}
+	}
+
+	// Tot curentul fazei a se intoarce prin neutru: In = Ia, Ib = Ic = 0
+	if consumer.Current.A <= 0 || math.Abs(consumer.NeutralCurrent-consumer.Current.A) > 1e-6 || consumer.Current.B > 1e-9 {
+		t.Fatalf("currents %+v, neutral %.4f A", consumer.Current, consumer.NeutralCurrent)
+	}
+	if consumer.VoltagePU.A >= consumer.VoltagePU.B || consumer.Unbalance <= 0 {
+		t.Fatalf("voltages %+v pu, unbalance %.4f %%", consumer.VoltagePU, consumer.Unbalance)
+	}
+}
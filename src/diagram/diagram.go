@@ -0,0 +1,285 @@
+package diagram
+
+import (
+	"fmt"
+	"math"
+
+	"contor-system/src/utils"
+)
+
+// Schema monofilara a sistemului: elementele si legaturile connectedTo, colorate dupa rezultatele ultimului calcul.
+
+const (
+	KindSource      = "source"
+	KindTransformer = "transformer"
+	KindMeasure     = "measure" // transformator de masura
+	KindLine        = "line"
+	KindConsumer    = "consumer"
+	KindSeparator   = "separator"
+	KindShunt       = "shunt"
+	KindBattery     = "battery"
+)
+
+const (
+	ColorNone        = "#212121" // fara rezultate
+	ColorNormal      = "#2e7d32"
+	ColorModerate    = "#9e9d24"
+	ColorHigh        = "#ef6c00"
+	ColorViolation   = "#c62828"
+	ColorDeenergized = "#9e9e9e"
+)
+
+type Node struct {
+	ID        string
+	Kind      string
+	Type      string // tipul separatorului sau al bateriei de compensare
+	Open      bool   // separator deschis
+	Energized bool
+	Color     string
+	Labels    []string // randurile de sub identificator: puteri, incarcare, tensiune
+}
+
+type Edge struct {
+	From      string // elementul al carui connectedTo este To
+	To        string
+	Measure   bool // legatura unui transformator de masura, fara circulatie de puteri
+	Flow      int  // 1: puterea circula de la From la To, -1: invers, 0: necunoscut sau zero
+	Energized bool
+}
+
+type Diagram struct {
+	Nodes []Node
+	Edges []Edge
+	Flows bool // nodurile si legaturile sunt colorate dupa un rezultat
+}
+
+// flowThreshold este puterea sub care nu se deseneaza sensul circulatiei - MW.
+const flowThreshold = 1e-6
+
+// loadingColor coloreaza o latura dupa incarcare, fata de limita ei (implicit 100 %).
+func loadingColor(loading float64, maxLoading float64) string {
+	if maxLoading <= 0 {
+		maxLoading = 100
+	}
+	switch {
+	case loading > maxLoading:
+		return ColorViolation
+	case loading > 0.8*maxLoading:
+		return ColorHigh
+	case loading > 0.5*maxLoading:
+		return ColorModerate
+	}
+	return ColorNormal
+}
+
+// voltageColor coloreaza un nod dupa abaterea tensiunii: in afara limitelor, peste 5 % sau normala.
+func voltageColor(voltagePU float64, limits utils.Limits) string {
+	switch {
+	case limits.MinVoltage > 0 && voltagePU < limits.MinVoltage, limits.MaxVoltage > 0 && voltagePU > limits.MaxVoltage:
+		return ColorViolation
+	case math.Abs(voltagePU-1) > 0.05:
+		return ColorHigh
+	}
+	return ColorNormal
+}
+
+func voltageLabel(element *utils.ElementResult) string {
+	return fmt.Sprintf("%.3f pu (%+.1f %%)", element.VoltagePU, (element.VoltagePU-1)*100)
+}
+
+// Build construieste schema sistemului; result poate lipsi, caz in care schema arata doar topologia.
+func Build(system utils.System, result *utils.SystemResult) Diagram {
+	d := Diagram{Flows: result != nil}
+	index := map[string]int{}
+	addNode := func(node Node) {
+		if node.ID == "" {
+			return
+		}
+		if _, exists := index[node.ID]; exists {
+			return
+		}
+		index[node.ID] = len(d.Nodes)
+		d.Nodes = append(d.Nodes, node)
+	}
+
+	addNode(Node{ID: system.Source.ID, Kind: KindSource})
+	for _, separator := range system.Separators {
+		addNode(Node{ID: separator.ID, Kind: KindSeparator, Type: string(separator.Type), Open: separator.State == utils.StateOpen})
+	}
+	for _, transformer := range system.Transformers {
+		kind := KindTransformer
+		if transformer.Type == utils.TransformerTypeMeasure {
+			kind = KindMeasure
+		}
+		addNode(Node{ID: transformer.ID, Kind: kind})
+	}
+	for _, line := range system.Lines {
+		addNode(Node{ID: line.ID, Kind: KindLine})
+	}
+	for _, consumer := range system.Consumers {
+		addNode(Node{ID: consumer.ID, Kind: KindConsumer})
+	}
+	for _, source := range system.AdditionalSources {
+		addNode(Node{ID: source.ID, Kind: KindSource})
+	}
+	for _, battery := range system.Batteries {
+		addNode(Node{ID: battery.ID, Kind: KindBattery})
+	}
+	for _, shunt := range system.Shunts {
+		addNode(Node{ID: shunt.ID, Kind: KindShunt, Type: string(shunt.Type)})
+	}
+
+	// Legaturile, o singura data pentru fiecare pereche de elemente
+	linked := map[[2]string]bool{}
+	addEdge := func(from string, to string, measure bool) *Edge {
+		if from == "" || to == "" || from == to {
+			return nil
+		}
+		if _, exists := index[to]; !exists {
+			return nil
+		}
+		if linked[[2]string{from, to}] || linked[[2]string{to, from}] {
+			return nil
+		}
+		linked[[2]string{from, to}] = true
+		d.Edges = append(d.Edges, Edge{From: from, To: to, Measure: measure})
+		return &d.Edges[len(d.Edges)-1]
+	}
+	// Pe lantul connectedTo circulatia prin legatura este puterea elementului din aval
+	chain := func(from string, to string) {
+		edge := addEdge(from, to, false)
+		if edge == nil || result == nil {
+			return
+		}
+		if element, exists := result.Elements[to]; exists {
+			edge.Flow = direction(element.ActivePower)
+		}
+	}
+	// Sursele si bateriile injecteaza puterea lor in nodul la care sunt racordate
+	injection := func(from string, to string) {
+		edge := addEdge(from, to, false)
+		if edge == nil || result == nil {
+			return
+		}
+		if element, exists := result.Elements[from]; exists {
+			edge.Flow = direction(element.ActivePower)
+		}
+	}
+
+	chain(system.Source.ID, system.Source.ConnectedTo)
+	for _, separator := range system.Separators {
+		if _, exists := index[separator.ConnectsFrom]; exists {
+			chain(separator.ConnectsFrom, separator.ID)
+		}
+		chain(separator.ID, separator.ConnectedTo)
+	}
+	for _, transformer := range system.Transformers {
+		if transformer.Type == utils.TransformerTypeMeasure {
+			addEdge(transformer.ID, transformer.ConnectedTo, true)
+			continue
+		}
+		chain(transformer.ID, transformer.ConnectedTo)
+	}
+	for _, line := range system.Lines {
+		chain(line.ID, line.ConnectedTo)
+	}
+	for _, consumer := range system.Consumers {
+		chain(consumer.ID, consumer.ConnectedTo)
+	}
+	for _, source := range system.AdditionalSources {
+		injection(source.ID, source.ConnectedTo)
+	}
+	for _, battery := range system.Batteries {
+		injection(battery.ID, battery.ConnectedTo)
+	}
+	for _, shunt := range system.Shunts {
+		addEdge(shunt.ID, shunt.ConnectedTo, false)
+	}
+
+	if result == nil {
+		for i := range d.Nodes {
+			d.Nodes[i].Energized = true
+			d.Nodes[i].Color = ColorNone
+			if d.Nodes[i].Kind == KindSeparator {
+				d.Nodes[i].Labels = []string{stateLabel(d.Nodes[i].Open)}
+			}
+		}
+		for i := range d.Edges {
+			d.Edges[i].Energized = true
+		}
+		return d
+	}
+
+	limits := utils.ElementLimits(system)
+	for i := range d.Nodes {
+		node := &d.Nodes[i]
+		element, exists := result.Elements[node.ID]
+		node.Energized = exists && element.Energized
+		node.Color = ColorDeenergized
+		if node.Kind == KindSeparator {
+			node.Labels = []string{stateLabel(node.Open)}
+		}
+		if !exists || node.Kind == KindMeasure {
+			continue
+		}
+		if !node.Energized {
+			node.Labels = append(node.Labels, "de-energized")
+			continue
+		}
+
+		switch node.Kind {
+		case KindTransformer, KindLine:
+			node.Labels = []string{fmt.Sprintf("%.2f MW, %.2f MVAr", element.ActivePower, element.ReactivePower), fmt.Sprintf("%.1f %% loaded", element.Loading)}
+			node.Color = loadingColor(element.Loading, limits[node.ID].MaxLoading)
+			if node.Kind == KindTransformer {
+				node.Labels = append(node.Labels, voltageLabel(element))
+			}
+		case KindSource, KindBattery, KindConsumer:
+			node.Labels = []string{fmt.Sprintf("%.2f MW, %.2f MVAr", element.ActivePower, element.ReactivePower), voltageLabel(element)}
+			node.Color = voltageColor(element.VoltagePU, limits[node.ID])
+		case KindShunt:
+			node.Labels = []string{fmt.Sprintf("%.2f MVAr", element.ReactivePower), voltageLabel(element)}
+			node.Color = voltageColor(element.VoltagePU, limits[node.ID])
+		default:
+			node.Color = ColorNone
+		}
+	}
+
+	energized := map[string]bool{}
+	for _, node := range d.Nodes {
+		energized[node.ID] = node.Energized
+	}
+	// Transformatoarele de masura nu apar in calcul: au tensiune cand o are elementul masurat
+	for _, edge := range d.Edges {
+		if edge.Measure && energized[edge.To] {
+			node := &d.Nodes[index[edge.From]]
+			node.Energized, node.Color = true, ColorNone
+			energized[edge.From] = true
+		}
+	}
+	for i := range d.Edges {
+		edge := &d.Edges[i]
+		edge.Energized = energized[edge.From] && energized[edge.To]
+		if !edge.Energized {
+			edge.Flow = 0
+		}
+	}
+	return d
+}
+
+func direction(power float64) int {
+	switch {
+	case power > flowThreshold:
+		return 1
+	case power < -flowThreshold:
+		return -1
+	}
+	return 0
+}
+
+func stateLabel(open bool) string {
+	if open {
+		return "open"
+	}
+	return "closed"
+}
@@ -0,0 +1,78 @@
+package diagram
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Schema in limbajul DOT, pentru Graphviz: dot -Tsvg schema.dot -o schema.svg.
+
+var dotShapes = map[string]string{
+	KindSource:      "circle",
+	KindTransformer: "doublecircle",
+	KindMeasure:     "doublecircle",
+	KindLine:        "box",
+	KindConsumer:    "invtriangle",
+	KindSeparator:   "square",
+	KindShunt:       "invtrapezium",
+	KindBattery:     "box3d",
+}
+
+func quote(text string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(text) + `"`
+}
+
+// FormatDOT scrie schema ca graf orientat, de la sursa principala in jos.
+func FormatDOT(d Diagram) string {
+	var b strings.Builder
+	b.WriteString("digraph network {\n")
+	b.WriteString("  rankdir=TB;\n")
+	b.WriteString("  node [fontname=\"Helvetica\", fontsize=10, width=0.4, height=0.4, fixedsize=false];\n")
+	b.WriteString("  edge [arrowsize=0.7];\n")
+
+	for _, node := range d.Nodes {
+		label := strings.Join(append([]string{node.ID}, node.Labels...), "\n")
+		attributes := []string{
+			"shape=" + dotShapes[node.Kind],
+			"label=" + quote(label),
+			"color=" + quote(node.Color),
+			"fontcolor=" + quote(node.Color),
+		}
+		if node.Kind == KindMeasure {
+			attributes = append(attributes, "style=dashed", "width=0.25", "height=0.25")
+		}
+		if node.Kind == KindSeparator {
+			if node.Open {
+				attributes = append(attributes, "style=dashed")
+			} else {
+				attributes = append(attributes, "style=bold")
+			}
+		}
+		if node.Kind == KindSource {
+			attributes = append(attributes, "penwidth=2")
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", quote(node.ID), strings.Join(attributes, ", "))
+	}
+
+	for _, edge := range d.Edges {
+		var attributes []string
+		switch edge.Flow {
+		case 1:
+			attributes = append(attributes, "dir=forward")
+		case -1:
+			attributes = append(attributes, "dir=back")
+		default:
+			attributes = append(attributes, "dir=none")
+		}
+		switch {
+		case edge.Measure:
+			attributes = append(attributes, "style=dotted")
+		case !edge.Energized:
+			attributes = append(attributes, "style=dashed", "color="+quote(ColorDeenergized))
+		}
+		fmt.Fprintf(&b, "  %s -> %s [%s];\n", quote(edge.From), quote(edge.To), strings.Join(attributes, ", "))
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
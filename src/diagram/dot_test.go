@@ -0,0 +1,116 @@
+package diagram
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"contor-system/src/computing"
+	"contor-system/src/utils"
+)
+
+// feederSystem este o plecare cu separatorul de la capat deschis: line2 si consumer2 raman fara tensiune.
+// Consumer1 si separator2 se leaga in ambele sensuri (connectedTo si connectsFrom), iar schema trebuie sa le uneasca o singura data.
+func feederSystem() utils.System {
+	return utils.System{
+		Source: utils.Source{ID: "source1", Voltage: 20, Power: 50, ConnectedTo: "separator1"},
+		Separators: []utils.Separator{
+			{ID: "separator1", ConnectsFrom: "source1", State: utils.StateClose, ConnectedTo: "line1"},
+			{ID: "separator2", ConnectsFrom: "consumer1", State: utils.StateOpen, ConnectedTo: "line2"},
+		},
+		Lines: []utils.Line{
+			{ID: "line1", Voltage: 20, Length: 5, Area: 100, Ro: 30, X: 0.4, ConnectedTo: "consumer1"},
+			{ID: "line2", Voltage: 20, Length: 5, Area: 100, Ro: 30, X: 0.4, ConnectedTo: "consumer2"},
+		},
+		Consumers: []utils.Consumer{
+			{ID: "consumer1", PowerNeeded: 2, ReactivePowerAbsorbed: 1, Voltage: 20, ConnectedTo: "separator2"},
+			{ID: "consumer2", PowerNeeded: 1, ReactivePowerAbsorbed: 0.5, Voltage: 20},
+		},
+		Solver: utils.Solver{Engine: utils.EngineSweep},
+	}
+}
+
+const feederDOT = `digraph network {
+  rankdir=TB;
+  node [fontname="Helvetica", fontsize=10, width=0.4, height=0.4, fixedsize=false];
+  edge [arrowsize=0.7];
+  "source1" [shape=circle, label="source1\n2.02 MW, 1.03 MVAr\n1.000 pu (+0.0 %)", color="#2e7d32", fontcolor="#2e7d32", penwidth=2];
+  "separator1" [shape=square, label="separator1\nclosed", color="#212121", fontcolor="#212121", style=bold];
+  "separator2" [shape=square, label="separator2\nopen\nde-energized", color="#9e9e9e", fontcolor="#9e9e9e", style=dashed];
+  "line1" [shape=box, label="line1\n2.00 MW, 1.00 MVAr\n0.0 % loaded", color="#2e7d32", fontcolor="#2e7d32"];
+  "line2" [shape=box, label="line2\nde-energized", color="#9e9e9e", fontcolor="#9e9e9e"];
+  "consumer1" [shape=invtriangle, label="consumer1\n2.00 MW, 1.00 MVAr\n0.987 pu (-1.3 %)", color="#2e7d32", fontcolor="#2e7d32"];
+  "consumer2" [shape=invtriangle, label="consumer2\nde-energized", color="#9e9e9e", fontcolor="#9e9e9e"];
+  "source1" -> "separator1" [dir=forward];
+  "separator1" -> "line1" [dir=forward];
+  "consumer1" -> "separator2" [dir=none, style=dashed, color="#9e9e9e"];
+  "separator2" -> "line2" [dir=none, style=dashed, color="#9e9e9e"];
+  "line1" -> "consumer1" [dir=forward];
+  "line2" -> "consumer2" [dir=none, style=dashed, color="#9e9e9e"];
+}
+`
+
+func TestFormatDOT(t *testing.T) {
+	system := feederSystem()
+	result := computing.SolveQuiet(system)
+	if dot := FormatDOT(Build(system, &result)); dot != feederDOT {
+		t.Fatalf("unexpected DOT:\n%s\nexpected:\n%s", dot, feederDOT)
+	}
+}
+
+func TestFormatDOTElementsAndColors(t *testing.T) {
+	system := feederSystem()
+	result := computing.SolveQuiet(system)
+	dot := FormatDOT(Build(system, &result))
+
+	// Fiecare element al configuratiei este declarat o singura data
+	declarations := map[string]int{}
+	for _, match := range regexp.MustCompile(`(?m)^  "([^"]+)" \[shape=`).FindAllStringSubmatch(dot, -1) {
+		declarations[match[1]]++
+	}
+	ids := []string{system.Source.ID}
+	for _, separator := range system.Separators {
+		ids = append(ids, separator.ID)
+	}
+	for _, line := range system.Lines {
+		ids = append(ids, line.ID)
+	}
+	for _, consumer := range system.Consumers {
+		ids = append(ids, consumer.ID)
+	}
+	for _, id := range ids {
+		if declarations[id] != 1 {
+			t.Fatalf("%s declared %d times", id, declarations[id])
+		}
+	}
+	if len(declarations) != len(ids) {
+		t.Fatalf("declared %v, expected %v", declarations, ids)
+	}
+
+	// Elementele fara tensiune sunt gri, iar cele alimentate nu
+	for _, id := range ids {
+		line := regexp.MustCompile(`(?m)^  "` + id + `" \[.*$`).FindString(dot)
+		deenergized := strings.Contains(line, `color="`+ColorDeenergized+`"`)
+		if deenergized == result.Elements[id].Energized {
+			t.Fatalf("%s energized %v, drawn as %s", id, result.Elements[id].Energized, line)
+		}
+	}
+
+	// O legatura este desenata o singura data si este gri cand unul dintre capete nu are tensiune
+	edges := regexp.MustCompile(`(?m)^  "([^"]+)" -> "([^"]+)" \[(.*)\];$`).FindAllStringSubmatch(dot, -1)
+	linked := map[string]bool{}
+	for _, edge := range edges {
+		from, to := edge[1], edge[2]
+		if linked[from+" "+to] || linked[to+" "+from] {
+			t.Fatalf("%s and %s linked twice", from, to)
+		}
+		linked[from+" "+to] = true
+		energized := result.Elements[from].Energized && result.Elements[to].Energized
+		if strings.Contains(edge[3], ColorDeenergized) == energized {
+			t.Fatalf("edge %s -> %s energized %v, drawn as [%s]", from, to, energized, edge[3])
+		}
+	}
+	if len(edges) != 6 {
+		t.Fatalf("%d edges, expected 6", len(edges))
+	}
+}
@@ -0,0 +1,228 @@
+package diagram
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strings"
+
+	"contor-system/src/utils"
+)
+
+// Asezarea schemei fara Graphviz: elementele sunt puse pe niveluri dupa distanta fata de sursa principala,
+// de sus in jos, iar retelele separate una langa alta.
+
+const (
+	svgMargin       = 40.0
+	svgColumnWidth  = 240.0 // px, simbolul si etichetele lui
+	svgRowHeight    = 100.0
+	svgLegendHeight = 40.0
+	svgLegendWidth  = 760.0
+	svgCurveOffset  = 60.0 // px, cat se departeaza de linia dreapta legaturile peste mai multe niveluri
+)
+
+type point struct {
+	X, Y float64
+}
+
+type placement struct {
+	point
+	Depth int
+	Index int // coloana
+}
+
+// layout intoarce pozitia fiecarui element si dimensiunile desenului. Fiecare element ia coloana elementului
+// din care a fost atins, daca este libera pe nivelul lui, altfel prima coloana libera din dreapta, astfel incat
+// lanturile connectedTo raman verticale si ramificatiile se deschid spre dreapta.
+func layout(d Diagram) (map[string]placement, float64, float64) {
+	neighbours := map[string][]string{}
+	// Transformatoarele de masura sunt asezate dupa elementele prin care circula puterea
+	for _, measure := range []bool{false, true} {
+		for _, edge := range d.Edges {
+			if edge.Measure == measure {
+				neighbours[edge.From] = append(neighbours[edge.From], edge.To)
+				neighbours[edge.To] = append(neighbours[edge.To], edge.From)
+			}
+		}
+	}
+
+	positions := map[string]placement{}
+	columns, depth := 0, 0
+	for _, node := range d.Nodes {
+		if _, placed := positions[node.ID]; placed {
+			continue
+		}
+
+		// Parcurgerea in latime a componentei, pornind de la sursa principala pentru prima componenta
+		occupied := map[int]map[int]bool{}
+		place := func(id string, level int, column int) {
+			if occupied[level] == nil {
+				occupied[level] = map[int]bool{}
+			}
+			for occupied[level][column] {
+				column++
+			}
+			occupied[level][column] = true
+			positions[id] = placement{
+				point: point{X: svgMargin + float64(column)*svgColumnWidth + 30, Y: svgMargin + float64(level)*svgRowHeight + 20},
+				Depth: level,
+				Index: column,
+			}
+			columns = max(columns, column+1)
+			depth = max(depth, level+1)
+		}
+		place(node.ID, 0, columns)
+		queue := []string{node.ID}
+		for len(queue) > 0 {
+			id := queue[0]
+			queue = queue[1:]
+			for _, next := range neighbours[id] {
+				if _, placed := positions[next]; !placed {
+					place(next, positions[id].Depth+1, positions[id].Index)
+					queue = append(queue, next)
+				}
+			}
+		}
+	}
+
+	return positions, 2*svgMargin + float64(columns)*svgColumnWidth, 2*svgMargin + float64(depth)*svgRowHeight + svgLegendHeight
+}
+
+// FormatSVG deseneaza schema ca imagine SVG de sine statatoare.
+func FormatSVG(d Diagram) string {
+	positions, width, height := layout(d)
+	if d.Flows {
+		width = math.Max(width, svgLegendWidth)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="sans-serif">`+"\n", width, height, width, height)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="#ffffff"/>`+"\n")
+
+	// Legaturile, sub simboluri
+	for _, edge := range d.Edges {
+		from, to := positions[edge.From], positions[edge.To]
+		color, style := "#424242", ""
+		switch {
+		case edge.Measure:
+			style = ` stroke-dasharray="2,3"`
+		case !edge.Energized:
+			color, style = ColorDeenergized, ` stroke-dasharray="6,4"`
+		}
+
+		// Legaturile intre elemente vecine sunt drepte, celelalte ocolesc elementele dintre ele
+		straight := math.Abs(float64(from.Depth-to.Depth)) == 1 || (from.Depth == to.Depth && math.Abs(float64(from.Index-to.Index)) == 1)
+		middle := point{(from.X + to.X) / 2, (from.Y + to.Y) / 2}
+		if straight {
+			fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="2"%s/>`+"\n", from.X, from.Y, to.X, to.Y, color, style)
+		} else {
+			dx, dy := to.X-from.X, to.Y-from.Y
+			length := math.Max(math.Hypot(dx, dy), 1)
+			control := point{middle.X - dy/length*svgCurveOffset, middle.Y + dx/length*svgCurveOffset}
+			fmt.Fprintf(&b, `<path d="M %.1f %.1f Q %.1f %.1f %.1f %.1f" fill="none" stroke="%s" stroke-width="2"%s/>`+"\n", from.X, from.Y, control.X, control.Y, to.X, to.Y, color, style)
+			// Mijlocul curbei Bezier de gradul doi
+			middle = point{0.25*from.X + 0.5*control.X + 0.25*to.X, 0.25*from.Y + 0.5*control.Y + 0.25*to.Y}
+		}
+
+		if edge.Flow != 0 {
+			dx, dy := to.X-from.X, to.Y-from.Y
+			if edge.Flow < 0 {
+				dx, dy = -dx, -dy
+			}
+			// Sagetile stau pe jumatatea legaturii, departe de simboluri
+			length := math.Max(math.Hypot(dx, dy), 1)
+			ux, uy := dx/length, dy/length
+			middle = point{middle.X + ux*4, middle.Y + uy*4}
+			fmt.Fprintf(&b, `<polygon points="%.1f,%.1f %.1f,%.1f %.1f,%.1f" fill="%s"/>`+"\n",
+				middle.X+ux*7, middle.Y+uy*7,
+				middle.X-ux*5-uy*6, middle.Y-uy*5+ux*6,
+				middle.X-ux*5+uy*6, middle.Y-uy*5-ux*6, color)
+		}
+	}
+
+	for _, node := range d.Nodes {
+		position := positions[node.ID]
+		symbol(&b, node, position.X, position.Y)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" font-size="13" font-weight="bold" fill="%s">%s</text>`+"\n", position.X+26, position.Y-6, node.Color, html.EscapeString(node.ID))
+		for i, label := range node.Labels {
+			fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" font-size="11" fill="#424242">%s</text>`+"\n", position.X+26, position.Y+9+float64(i)*13, html.EscapeString(label))
+		}
+	}
+
+	if d.Flows {
+		legend(&b, height-svgMargin)
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// symbol deseneaza simbolul unui element centrat in (x, y).
+func symbol(b *strings.Builder, node Node, x float64, y float64) {
+	c := node.Color
+	switch node.Kind {
+	case KindSource:
+		fmt.Fprintf(b, `<circle cx="%.1f" cy="%.1f" r="16" fill="#ffffff" stroke="%s" stroke-width="2"/>`+"\n", x, y, c)
+		fmt.Fprintf(b, `<path d="M %.1f %.1f q 4.5 -8 9 0 t 9 0" fill="none" stroke="%s" stroke-width="2"/>`+"\n", x-9, y, c)
+	case KindTransformer:
+		fmt.Fprintf(b, `<circle cx="%.1f" cy="%.1f" r="11" fill="#ffffff" stroke="%s" stroke-width="2"/>`+"\n", x, y-7, c)
+		fmt.Fprintf(b, `<circle cx="%.1f" cy="%.1f" r="11" fill="none" stroke="%s" stroke-width="2"/>`+"\n", x, y+7, c)
+	case KindMeasure:
+		fmt.Fprintf(b, `<circle cx="%.1f" cy="%.1f" r="7" fill="#ffffff" stroke="%s" stroke-width="1.5"/>`+"\n", x, y-4.5, c)
+		fmt.Fprintf(b, `<circle cx="%.1f" cy="%.1f" r="7" fill="none" stroke="%s" stroke-width="1.5"/>`+"\n", x, y+4.5, c)
+	case KindLine:
+		fmt.Fprintf(b, `<rect x="%.1f" y="%.1f" width="12" height="30" fill="#ffffff" stroke="%s" stroke-width="2"/>`+"\n", x-6, y-15, c)
+	case KindConsumer:
+		fmt.Fprintf(b, `<polygon points="%.1f,%.1f %.1f,%.1f %.1f,%.1f" fill="%s"/>`+"\n", x-12, y-8, x+12, y-8, x, y+12, c)
+	case KindSeparator:
+		fmt.Fprintf(b, `<rect x="%.1f" y="%.1f" width="24" height="30" fill="#ffffff"/>`+"\n", x-12, y-15)
+		if node.Type == string(utils.SeparatorTypeBreaker) {
+			fill := c
+			if node.Open {
+				fill = "#ffffff"
+			}
+			fmt.Fprintf(b, `<rect x="%.1f" y="%.1f" width="22" height="22" fill="%s" stroke="%s" stroke-width="2"/>`+"\n", x-11, y-11, fill, c)
+			break
+		}
+		// Separatorul cu cutit: inchis vertical, deschis rotit
+		end := point{x, y + 12}
+		if node.Open {
+			end = point{x + 11, y + 7}
+		}
+		fmt.Fprintf(b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="2"/>`+"\n", x, y-12, x, y-15, c)
+		fmt.Fprintf(b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="2.5"/>`+"\n", x, y-12, end.X, end.Y, c)
+		fmt.Fprintf(b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="2"/>`+"\n", x-5, y+12, x+5, y+12, c)
+		fmt.Fprintf(b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="2"/>`+"\n", x, y+12, x, y+15, c)
+		if node.Type == string(utils.SeparatorTypeLoadBreakSwitch) {
+			fmt.Fprintf(b, `<circle cx="%.1f" cy="%.1f" r="3" fill="#ffffff" stroke="%s" stroke-width="1.5"/>`+"\n", x, y-12, c)
+		}
+	case KindShunt:
+		fmt.Fprintf(b, `<rect x="%.1f" y="%.1f" width="26" height="16" fill="#ffffff"/>`+"\n", x-13, y-8)
+		if node.Type == string(utils.ShuntTypeReactor) {
+			fmt.Fprintf(b, `<circle cx="%.1f" cy="%.1f" r="10" fill="#ffffff" stroke="%s" stroke-width="2"/>`+"\n", x, y, c)
+			fmt.Fprintf(b, `<path d="M %.1f %.1f h 10 v -10" fill="none" stroke="%s" stroke-width="2"/>`+"\n", x-10, y, c)
+			break
+		}
+		fmt.Fprintf(b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="3"/>`+"\n", x-12, y-4, x+12, y-4, c)
+		fmt.Fprintf(b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="3"/>`+"\n", x-12, y+4, x+12, y+4, c)
+	case KindBattery:
+		fmt.Fprintf(b, `<rect x="%.1f" y="%.1f" width="28" height="16" fill="#ffffff"/>`+"\n", x-14, y-8)
+		fmt.Fprintf(b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="2"/>`+"\n", x-13, y-4, x+13, y-4, c)
+		fmt.Fprintf(b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="4"/>`+"\n", x-6, y+4, x+6, y+4, c)
+	}
+}
+
+// legend explica culorile elementelor.
+func legend(b *strings.Builder, y float64) {
+	entries := []struct{ color, text string }{
+		{ColorNormal, "normal"},
+		{ColorModerate, "loading > 50 %"},
+		{ColorHigh, "loading > 80 % or voltage deviation > 5 %"},
+		{ColorViolation, "limit exceeded"},
+		{ColorDeenergized, "de-energized"},
+	}
+	x := svgMargin
+	for _, entry := range entries {
+		fmt.Fprintf(b, `<rect x="%.1f" y="%.1f" width="12" height="12" fill="%s"/>`+"\n", x, y+8, entry.color)
+		fmt.Fprintf(b, `<text x="%.1f" y="%.1f" font-size="11" fill="#424242">%s</text>`+"\n", x+17, y+18, html.EscapeString(entry.text))
+		x += 30 + 6.5*float64(len(entry.text))
+	}
+}
@@ -0,0 +1,254 @@
+package dlms
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Codificarea A-XDR a datelor DLMS: un octet de tip urmat de valoare; lungimile peste 127 folosesc forma lunga.
+
+const (
+	tagNull               = 0x00
+	tagArray              = 0x01
+	tagStructure          = 0x02
+	tagBoolean            = 0x03
+	tagDoubleLong         = 0x05
+	tagDoubleLongUnsigned = 0x06
+	tagOctetString        = 0x09
+	tagVisibleString      = 0x0a
+	tagInteger            = 0x0f
+	tagLong               = 0x10
+	tagUnsigned           = 0x11
+	tagLongUnsigned       = 0x12
+	tagLong64             = 0x14
+	tagLong64Unsigned     = 0x15
+	tagEnum               = 0x16
+)
+
+type encoder struct {
+	b []byte
+}
+
+func (e *encoder) length(n int) {
+	switch {
+	case n < 0x80:
+		e.b = append(e.b, byte(n))
+	case n <= 0xff:
+		e.b = append(e.b, 0x81, byte(n))
+	default:
+		e.b = append(e.b, 0x82, byte(n>>8), byte(n))
+	}
+}
+
+func (e *encoder) array(n int) {
+	e.b = append(e.b, tagArray)
+	e.length(n)
+}
+
+func (e *encoder) structure(n int) {
+	e.b = append(e.b, tagStructure)
+	e.length(n)
+}
+
+func (e *encoder) octetString(value []byte) {
+	e.b = append(e.b, tagOctetString)
+	e.length(len(value))
+	e.b = append(e.b, value...)
+}
+
+func (e *encoder) integer(value int8) {
+	e.b = append(e.b, tagInteger, byte(value))
+}
+
+func (e *encoder) enum(value uint8) {
+	e.b = append(e.b, tagEnum, value)
+}
+
+func (e *encoder) longUnsigned(value uint16) {
+	e.b = append(e.b, tagLongUnsigned)
+	e.b = binary.BigEndian.AppendUint16(e.b, value)
+}
+
+func (e *encoder) doubleLong(value int32) {
+	e.b = append(e.b, tagDoubleLong)
+	e.b = binary.BigEndian.AppendUint32(e.b, uint32(value))
+}
+
+func (e *encoder) doubleLongUnsigned(value uint32) {
+	e.b = append(e.b, tagDoubleLongUnsigned)
+	e.b = binary.BigEndian.AppendUint32(e.b, value)
+}
+
+func (e *encoder) long64Unsigned(value uint64) {
+	e.b = append(e.b, tagLong64Unsigned)
+	e.b = binary.BigEndian.AppendUint64(e.b, value)
+}
+
+func (e *encoder) dateTime(t time.Time) {
+	e.octetString(dateTimeBytes(t))
+}
+
+/*
+Date-time COSEM (12 octeti): an (2), luna, zi, zi a saptamanii (1 = luni), ora, minut, secunda, sutimi,
+deviatia fata de UTC in minute (2, cu semn), starea ceasului
+*/
+func dateTimeBytes(t time.Time) []byte {
+	_, offset := t.Zone()
+	weekday := int(t.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	b := make([]byte, 12)
+	binary.BigEndian.PutUint16(b[0:2], uint16(t.Year()))
+	b[2], b[3], b[4] = byte(t.Month()), byte(t.Day()), byte(weekday)
+	b[5], b[6], b[7] = byte(t.Hour()), byte(t.Minute()), byte(t.Second())
+	b[8] = byte(t.Nanosecond() / 1e7)
+	// Deviatia COSEM este ora locala fata de UTC cu semn schimbat
+	binary.BigEndian.PutUint16(b[9:11], uint16(int16(-offset/60)))
+	return b
+}
+
+// parseDateTime citeste un date-time COSEM; campurile nespecificate (0xff) sunt luate ca zero.
+func parseDateTime(b []byte) (time.Time, error) {
+	if len(b) != 12 {
+		return time.Time{}, fmt.Errorf("date-time must have 12 bytes, got %d", len(b))
+	}
+	field := func(v byte) int {
+		if v == 0xff {
+			return 0
+		}
+		return int(v)
+	}
+	location := time.Local
+	if deviation := int16(binary.BigEndian.Uint16(b[9:11])); uint16(deviation) != 0x8000 {
+		location = time.FixedZone("", -int(deviation)*60)
+	}
+	return time.Date(int(binary.BigEndian.Uint16(b[0:2])), time.Month(field(b[2])), field(b[3]), field(b[5]), field(b[6]), field(b[7]), field(b[8])*1e7, location), nil
+}
+
+var errTruncated = errors.New("truncated A-XDR data")
+
+func readLength(b []byte) (int, []byte, error) {
+	if len(b) == 0 {
+		return 0, nil, errTruncated
+	}
+	if b[0] < 0x80 {
+		return int(b[0]), b[1:], nil
+	}
+	size := int(b[0] & 0x7f)
+	if size == 0 || size > 2 || len(b) < 1+size {
+		return 0, nil, errTruncated
+	}
+	n := 0
+	for _, v := range b[1 : 1+size] {
+		n = n<<8 | int(v)
+	}
+	return n, b[1+size:], nil
+}
+
+// decode citeste o valoare A-XDR: []any pentru array si structure, []byte pentru siruri, int64 / uint64 pentru numere.
+func decode(b []byte) (any, []byte, error) {
+	if len(b) == 0 {
+		return nil, nil, errTruncated
+	}
+	tag, b := b[0], b[1:]
+
+	fixed := func(size int) ([]byte, []byte, error) {
+		if len(b) < size {
+			return nil, nil, errTruncated
+		}
+		return b[:size], b[size:], nil
+	}
+
+	switch tag {
+	case tagNull:
+		return nil, b, nil
+	case tagArray, tagStructure:
+		n, rest, err := readLength(b)
+		if err != nil {
+			return nil, nil, err
+		}
+		items := make([]any, 0, n)
+		for i := 0; i < n; i++ {
+			var item any
+			if item, rest, err = decode(rest); err != nil {
+				return nil, nil, err
+			}
+			items = append(items, item)
+		}
+		return items, rest, nil
+	case tagOctetString, tagVisibleString:
+		n, rest, err := readLength(b)
+		if err != nil || len(rest) < n {
+			return nil, nil, errTruncated
+		}
+		return rest[:n], rest[n:], nil
+	case tagBoolean, tagUnsigned, tagEnum:
+		v, rest, err := fixed(1)
+		if err != nil {
+			return nil, nil, err
+		}
+		return uint64(v[0]), rest, nil
+	case tagInteger:
+		v, rest, err := fixed(1)
+		if err != nil {
+			return nil, nil, err
+		}
+		return int64(int8(v[0])), rest, nil
+	case tagLong:
+		v, rest, err := fixed(2)
+		if err != nil {
+			return nil, nil, err
+		}
+		return int64(int16(binary.BigEndian.Uint16(v))), rest, nil
+	case tagLongUnsigned:
+		v, rest, err := fixed(2)
+		if err != nil {
+			return nil, nil, err
+		}
+		return uint64(binary.BigEndian.Uint16(v)), rest, nil
+	case tagDoubleLong:
+		v, rest, err := fixed(4)
+		if err != nil {
+			return nil, nil, err
+		}
+		return int64(int32(binary.BigEndian.Uint32(v))), rest, nil
+	case tagDoubleLongUnsigned:
+		v, rest, err := fixed(4)
+		if err != nil {
+			return nil, nil, err
+		}
+		return uint64(binary.BigEndian.Uint32(v)), rest, nil
+	case tagLong64:
+		v, rest, err := fixed(8)
+		if err != nil {
+			return nil, nil, err
+		}
+		return int64(binary.BigEndian.Uint64(v)), rest, nil
+	case tagLong64Unsigned:
+		v, rest, err := fixed(8)
+		if err != nil {
+			return nil, nil, err
+		}
+		return binary.BigEndian.Uint64(v), rest, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported A-XDR type 0x%02x", tag)
+	}
+}
+
+// number intoarce valoarea numerica a unui element decodat.
+func number(v any) (int64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return n, true
+	case uint64:
+		if n > math.MaxInt64 {
+			return 0, false
+		}
+		return int64(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,79 @@
+package dlms
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestAXDRRoundTrip(t *testing.T) {
+	long := bytes.Repeat([]byte{0xab}, 300)
+	e := &encoder{}
+	e.structure(9)
+	e.integer(-3)
+	e.enum(27)
+	e.longUnsigned(0xbeef)
+	e.doubleLong(-123456)
+	e.doubleLongUnsigned(4000000000)
+	e.long64Unsigned(1 << 40)
+	e.octetString([]byte("meter1"))
+	e.octetString(long)
+	e.array(2)
+	e.integer(1)
+	e.integer(2)
+
+	value, rest, err := decode(e.b)
+	if err != nil || len(rest) != 0 {
+		t.Fatalf("decode: %v, %d bytes left", err, len(rest))
+	}
+	items, ok := value.([]any)
+	if !ok || len(items) != 9 {
+		t.Fatalf("structure %#v", value)
+	}
+	expected := []any{int64(-3), uint64(27), uint64(0xbeef), int64(-123456), uint64(4000000000), uint64(1 << 40)}
+	for i, want := range expected {
+		if items[i] != want {
+			t.Fatalf("item %d: %#v, expected %#v", i, items[i], want)
+		}
+	}
+	if s, _ := items[6].([]byte); string(s) != "meter1" {
+		t.Fatalf("octet string %q", items[6])
+	}
+	// 300 de octeti folosesc forma lunga a lungimii: 0x82 urmat de doi octeti
+	if s, _ := items[7].([]byte); !bytes.Equal(s, long) {
+		t.Fatalf("long octet string of %d bytes", len(s))
+	}
+	if nested, _ := items[8].([]any); len(nested) != 2 || nested[1] != int64(2) {
+		t.Fatalf("array %#v", items[8])
+	}
+}
+
+func TestAXDRTruncated(t *testing.T) {
+	e := &encoder{}
+	e.doubleLong(7)
+	if _, _, err := decode(e.b[:3]); err == nil {
+		t.Fatal("truncated double-long decoded")
+	}
+	if _, _, err := decode([]byte{tagOctetString, 0x82, 0x01}); err == nil {
+		t.Fatal("truncated length decoded")
+	}
+	if _, _, err := decode([]byte{0x7f}); err == nil {
+		t.Fatal("unknown type decoded")
+	}
+}
+
+func TestDateTimeRoundTrip(t *testing.T) {
+	moment := time.Date(2026, 3, 29, 14, 45, 30, 250e6, time.FixedZone("", 2*3600))
+	b := dateTimeBytes(moment)
+	// Duminica este ziua 7; deviatia COSEM este -120 de minute
+	if b[4] != 7 || int16(uint16(b[9])<<8|uint16(b[10])) != -120 {
+		t.Fatalf("date-time % x", b)
+	}
+	parsed, err := parseDateTime(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.Equal(moment) {
+		t.Fatalf("parsed %s, expected %s", parsed, moment)
+	}
+}
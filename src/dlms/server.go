@@ -0,0 +1,523 @@
+package dlms
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	"contor-system/src/metering"
+	"contor-system/src/utils"
+)
+
+// Interfata de citire a contoarelor care imita DLMS/COSEM peste TCP (IEC 62056-47): antetul wrapper,
+// asocierea LN fara criptare (AARQ / AARE, RLRQ / RLRE) si cererile GET, cu acces selectiv la curba de sarcina
+// si transfer in blocuri pentru raspunsurile lungi. Fiecare punct de masura este un dispozitiv logic separat.
+
+const (
+	wrapperVersion = 1
+	wrapperSize    = 8
+	maxPDU         = 1024
+
+	apduAARQ      = 0x60
+	apduAARE      = 0x61
+	apduRLRQ      = 0x62
+	apduRLRE      = 0x63
+	apduGetReq    = 0xc0
+	apduGetResp   = 0xc4
+	apduException = 0xd8
+
+	getNormal = 0x01
+	getNext   = 0x02
+
+	selectorRange = 1
+	selectorEntry = 2
+)
+
+// Rezultatele accesului la date (Data-Access-Result)
+const (
+	resultReadWriteDenied         = 3
+	resultObjectUndefined         = 4
+	resultObjectClassInconsistent = 9
+	resultObjectUnavailable       = 11
+	resultOtherReason             = 250
+	resultNoLongGetInProgress     = 19
+)
+
+type Server struct {
+	settings utils.DLMS
+	meters   *metering.Meters
+	listener net.Listener
+
+	mu     sync.Mutex
+	conns  map[net.Conn]struct{}
+	wg     sync.WaitGroup
+	logger *log.Logger
+}
+
+func NewServer(settings utils.DLMS, meters *metering.Meters, logger *log.Logger) *Server {
+	if settings.Address == "" {
+		settings.Address = ":4059"
+	}
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &Server{settings: settings, meters: meters, conns: map[net.Conn]struct{}{}, logger: logger}
+}
+
+// Start deschide portul si accepta conexiunile in fundal.
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.settings.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %v", s.settings.Address, err)
+	}
+	s.listener = listener
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				if !errors.Is(err, net.ErrClosed) {
+					s.logger.Printf("DLMS server stopped accepting connections: %v", err)
+				}
+				return
+			}
+			s.mu.Lock()
+			s.conns[conn] = struct{}{}
+			s.mu.Unlock()
+
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				s.serve(conn)
+			}()
+		}
+	}()
+	return nil
+}
+
+// Addr intoarce adresa pe care asculta serverul.
+func (s *Server) Addr() net.Addr {
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+// Close opreste serverul si inchide conexiunile deschise.
+func (s *Server) Close() error {
+	if s.listener == nil {
+		return nil
+	}
+	err := s.listener.Close()
+	s.mu.Lock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return err
+}
+
+// association este starea unei asocieri client - dispozitiv logic.
+type association struct {
+	pending []byte // datele ramase dintr-un transfer in blocuri
+	block   uint32
+}
+
+func (s *Server) serve(conn net.Conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	associations := map[uint16]*association{}
+	header := make([]byte, wrapperSize)
+	for {
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+		if binary.BigEndian.Uint16(header[0:2]) != wrapperVersion {
+			return
+		}
+		client := binary.BigEndian.Uint16(header[2:4])
+		device := binary.BigEndian.Uint16(header[4:6])
+		length := int(binary.BigEndian.Uint16(header[6:8]))
+		if length == 0 || length > maxPDU {
+			return
+		}
+		apdu := make([]byte, length)
+		if _, err := io.ReadFull(conn, apdu); err != nil {
+			return
+		}
+
+		response := s.respond(associations, device, apdu)
+		if response == nil {
+			continue
+		}
+		frame := make([]byte, wrapperSize, wrapperSize+len(response))
+		binary.BigEndian.PutUint16(frame[0:2], wrapperVersion)
+		binary.BigEndian.PutUint16(frame[2:4], device)
+		binary.BigEndian.PutUint16(frame[4:6], client)
+		binary.BigEndian.PutUint16(frame[6:8], uint16(len(response)))
+		if _, err := conn.Write(append(frame, response...)); err != nil {
+			return
+		}
+	}
+}
+
+// aare construieste raspunsul la cererea de asociere: contextul LN fara criptare, acceptat (0) sau respins (1).
+func aare(result byte) []byte {
+	return []byte{
+		apduAARE, 0x29,
+		0xa1, 0x09, 0x06, 0x07, 0x60, 0x85, 0x74, 0x05, 0x08, 0x01, 0x01, // application-context-name: LN, fara criptare
+		0xa2, 0x03, 0x02, 0x01, result, // result
+		0xa3, 0x05, 0xa1, 0x03, 0x02, 0x01, result, // result-source-diagnostic
+		0xbe, 0x10, 0x04, 0x0e, // user-information: InitiateResponse
+		0x08, 0x00, 0x06, 0x5f, 0x1f, 0x04, 0x00, 0x00, 0x10, 0x14, // versiunea 6, conformance: get, selective-access, block-transfer-with-get
+		byte(maxPDU >> 8), byte(maxPDU & 0xff), 0x00, 0x07,
+	}
+}
+
+// respond intoarce raspunsul la un APDU, nil daca cererea nu are raspuns.
+func (s *Server) respond(associations map[uint16]*association, device uint16, apdu []byte) []byte {
+	switch apdu[0] {
+	case apduAARQ:
+		if _, exists := s.meters.Meter(device); !exists {
+			return aare(1)
+		}
+		associations[device] = &association{}
+		return aare(0)
+
+	case apduRLRQ:
+		delete(associations, device)
+		return []byte{apduRLRE, 0x03, 0x80, 0x01, 0x00}
+
+	case apduGetReq:
+		a, associated := associations[device]
+		meter, exists := s.meters.Meter(device)
+		if !associated || !exists || len(apdu) < 3 {
+			// Exception-Response: state-error service-not-allowed, service-error operation-not-possible
+			return []byte{apduException, 0x01, 0x01}
+		}
+		invoke := apdu[2]
+
+		switch apdu[1] {
+		case getNormal:
+			data, result := s.get(meter, apdu[3:])
+			if result != 0 {
+				return []byte{apduGetResp, getNormal, invoke, 0x01, result}
+			}
+			if len(data) <= maxPDU-8 {
+				return append([]byte{apduGetResp, getNormal, invoke, 0x00}, data...)
+			}
+			a.pending, a.block = data, 0
+			return a.nextBlock(invoke)
+
+		case getNext:
+			if len(apdu) < 7 || a.pending == nil || binary.BigEndian.Uint32(apdu[3:7]) != a.block {
+				return []byte{apduGetResp, getNext, invoke, 0x01, 0, 0, 0, 0, 0x01, resultNoLongGetInProgress}
+			}
+			return a.nextBlock(invoke)
+
+		default:
+			return []byte{apduException, 0x01, 0x01}
+		}
+
+	default:
+		return []byte{apduException, 0x01, 0x01}
+	}
+}
+
+// nextBlock intoarce urmatorul bloc al unui raspuns lung (GET-Response-With-Datablock).
+func (a *association) nextBlock(invoke byte) []byte {
+	size := maxPDU - 16
+	chunk := a.pending
+	last := byte(1)
+	if len(chunk) > size {
+		chunk, last = chunk[:size], 0
+	}
+	a.pending = a.pending[len(chunk):]
+	a.block++
+	if last == 1 {
+		a.pending = nil
+	}
+
+	e := &encoder{b: []byte{apduGetResp, getNext, invoke, last}}
+	e.b = binary.BigEndian.AppendUint32(e.b, a.block)
+	e.b = append(e.b, 0x00) // raw-data
+	e.length(len(chunk))
+	e.b = append(e.b, chunk...)
+	return e.b
+}
+
+// get trateaza un Get-Request-Normal: class-id, instance-id (OBIS), attribute-id si accesul selectiv optional.
+func (s *Server) get(meter metering.Meter, request []byte) ([]byte, byte) {
+	if len(request) < 10 {
+		return nil, resultOtherReason
+	}
+	class := binary.BigEndian.Uint16(request[0:2])
+	var code metering.OBIS
+	copy(code[:], request[2:8])
+	attribute := request[8]
+	var selector byte
+	var parameters any
+	if request[9] != 0 {
+		if len(request) < 11 {
+			return nil, resultOtherReason
+		}
+		selector = request[10]
+		var err error
+		if parameters, _, err = decode(request[11:]); err != nil {
+			return nil, resultOtherReason
+		}
+	}
+
+	e := &encoder{}
+	expect := func(want uint16) byte {
+		if class != want {
+			return resultObjectClassInconsistent
+		}
+		return 0
+	}
+
+	switch {
+	case code == metering.LogicalDeviceName:
+		if result := expect(metering.ClassData); result != 0 {
+			return nil, result
+		}
+		switch attribute {
+		case 1:
+			e.octetString(code[:])
+		case 2:
+			e.octetString([]byte(meter.ID))
+		default:
+			return nil, resultObjectUnavailable
+		}
+
+	case code == metering.ClockOBIS:
+		if result := expect(metering.ClassClock); result != 0 {
+			return nil, result
+		}
+		switch attribute {
+		case 1:
+			e.octetString(code[:])
+		case 2:
+			e.dateTime(time.Now())
+		case 3:
+			_, offset := time.Now().Zone()
+			e.b = append(e.b, tagLong)
+			e.b = binary.BigEndian.AppendUint16(e.b, uint16(int16(-offset/60)))
+		default:
+			return nil, resultObjectUnavailable
+		}
+
+	case code == metering.LoadProfileOBIS:
+		if result := expect(metering.ClassProfileGeneric); result != 0 {
+			return nil, result
+		}
+		switch attribute {
+		case 1:
+			e.octetString(code[:])
+		case 2:
+			if result := s.buffer(e, meter, selector, parameters); result != 0 {
+				return nil, result
+			}
+		case 3:
+			columns := captureObjects()
+			e.array(len(columns))
+			for _, column := range columns {
+				encodeCaptureObject(e, column)
+			}
+		case 4:
+			e.doubleLongUnsigned(uint32(s.meters.Period().Seconds()))
+		case 7:
+			e.doubleLongUnsigned(uint32(len(meter.Profile)))
+		case 8:
+			depth := s.settings.ProfileDepth
+			if depth <= 0 {
+				depth = 2880
+			}
+			e.doubleLongUnsigned(uint32(depth))
+		default:
+			return nil, resultObjectUnavailable
+		}
+
+	default:
+		register, exists := meter.Register(code)
+		if !exists {
+			return nil, resultObjectUndefined
+		}
+		if result := expect(metering.ClassRegister); result != 0 {
+			return nil, result
+		}
+		switch attribute {
+		case 1:
+			e.octetString(code[:])
+		case 2:
+			encodeRegister(e, register)
+		case 3:
+			e.structure(2)
+			e.integer(register.Scaler)
+			e.enum(uint8(register.Unit))
+		default:
+			return nil, resultObjectUnavailable
+		}
+	}
+
+	return e.b, 0
+}
+
+// encodeRegister codifica valoarea registrului ca intreg: valoare / 10^scaler.
+func encodeRegister(e *encoder, register metering.Register) {
+	scaled := math.Round(register.Value / math.Pow10(int(register.Scaler)))
+	if register.Energy {
+		e.long64Unsigned(uint64(math.Max(0, scaled)))
+		return
+	}
+	e.doubleLong(int32(math.Max(math.MinInt32, math.Min(math.MaxInt32, scaled))))
+}
+
+// captureObject este o coloana a curbei de sarcina: clasa, OBIS si atributul capturat.
+type captureObject struct {
+	class     uint16
+	code      metering.OBIS
+	attribute int8
+}
+
+func captureObjects() []captureObject {
+	columns := []captureObject{{class: metering.ClassClock, code: metering.ClockOBIS, attribute: 2}}
+	for _, code := range metering.ProfileColumns {
+		columns = append(columns, captureObject{class: metering.ClassRegister, code: code, attribute: 2})
+	}
+	return columns
+}
+
+func encodeCaptureObject(e *encoder, column captureObject) {
+	e.structure(4)
+	e.longUnsigned(column.class)
+	e.octetString(column.code[:])
+	e.integer(column.attribute)
+	e.longUnsigned(0)
+}
+
+// buffer codifica inregistrarile curbei de sarcina, filtrate dupa interval (selector 1) sau dupa numarul inregistrarii (selector 2).
+func (s *Server) buffer(e *encoder, meter metering.Meter, selector byte, parameters any) byte {
+	entries := meter.Profile
+	columns := captureObjects()
+	first, last := 0, len(columns)-1
+
+	fields, _ := parameters.([]any)
+	switch selector {
+	case 0:
+	case selectorRange:
+		if len(fields) < 3 {
+			return resultOtherReason
+		}
+		fromBytes, ok1 := fields[1].([]byte)
+		toBytes, ok2 := fields[2].([]byte)
+		if !ok1 || !ok2 {
+			return resultOtherReason
+		}
+		from, err1 := parseDateTime(fromBytes)
+		to, err2 := parseDateTime(toBytes)
+		if err1 != nil || err2 != nil {
+			return resultOtherReason
+		}
+		var selected []metering.ProfileEntry
+		for _, entry := range entries {
+			if !entry.Time.Before(from) && !entry.Time.After(to) {
+				selected = append(selected, entry)
+			}
+		}
+		entries = selected
+
+		// Coloanele cerute (selected_values); lista goala inseamna toate coloanele
+		if len(fields) > 3 {
+			if wanted, ok := fields[3].([]any); ok && len(wanted) > 0 {
+				var chosen []captureObject
+				for _, w := range wanted {
+					definition, _ := w.([]any)
+					if len(definition) < 2 {
+						return resultOtherReason
+					}
+					raw, _ := definition[1].([]byte)
+					for _, column := range columns {
+						if string(column.code[:]) == string(raw) {
+							chosen = append(chosen, column)
+						}
+					}
+				}
+				return encodeEntries(e, entries, chosen)
+			}
+		}
+	case selectorEntry:
+		if len(fields) < 4 {
+			return resultOtherReason
+		}
+		var values [4]int64
+		for i := range values {
+			v, ok := number(fields[i])
+			if !ok {
+				return resultOtherReason
+			}
+			values[i] = v
+		}
+		// Numerotarea incepe de la 1; 0 ca limita superioara inseamna ultima inregistrare / coloana
+		fromEntry, toEntry := int(values[0]), int(values[1])
+		if toEntry == 0 || toEntry > len(entries) {
+			toEntry = len(entries)
+		}
+		if fromEntry < 1 {
+			fromEntry = 1
+		}
+		if fromEntry > toEntry {
+			entries = nil
+		} else {
+			entries = entries[fromEntry-1 : toEntry]
+		}
+		if values[2] > 1 {
+			first = int(values[2]) - 1
+		}
+		if values[3] > 0 && int(values[3])-1 < last {
+			last = int(values[3]) - 1
+		}
+		if first > last {
+			return resultOtherReason
+		}
+	default:
+		return resultReadWriteDenied
+	}
+
+	return encodeEntries(e, entries, columns[first:last+1])
+}
+
+// encodeEntries codifica inregistrarile cu coloanele date.
+func encodeEntries(e *encoder, entries []metering.ProfileEntry, columns []captureObject) byte {
+	e.array(len(entries))
+	for _, entry := range entries {
+		e.structure(len(columns))
+		for _, column := range columns {
+			if column.code == metering.ClockOBIS {
+				e.dateTime(entry.Time)
+				continue
+			}
+			for k, code := range metering.ProfileColumns {
+				if code == column.code {
+					register := metering.Register{Value: entry.Values[k], Energy: true}
+					encodeRegister(e, register)
+				}
+			}
+		}
+	}
+	return 0
+}
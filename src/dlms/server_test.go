@@ -0,0 +1,116 @@
+package dlms
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"contor-system/src/metering"
+	"contor-system/src/utils"
+)
+
+func testServer(t *testing.T) *Server {
+	t.Helper()
+	system := utils.System{Consumers: []utils.Consumer{{ID: "consumer1"}}}
+	result := utils.SystemResult{
+		Order: []string{"consumer1"},
+		Elements: map[string]*utils.ElementResult{
+			"consumer1": {ID: "consumer1", Kind: "consumer", Voltage: 20.5, ActivePower: 1.5, Current: 42.3, Energized: true},
+		},
+	}
+	meters := metering.NewMeters(time.Minute, 0)
+	meters.Update(system, result, metering.NewAccumulator(), time.Now())
+
+	s := NewServer(utils.DLMS{Address: "127.0.0.1:0"}, meters, nil)
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// exchange trimite un APDU catre dispozitivul logic device si intoarce APDU-ul raspunsului.
+func exchange(t *testing.T, conn net.Conn, device uint16, apdu []byte) []byte {
+	t.Helper()
+	frame := make([]byte, wrapperSize, wrapperSize+len(apdu))
+	binary.BigEndian.PutUint16(frame[0:2], wrapperVersion)
+	binary.BigEndian.PutUint16(frame[2:4], 16) // clientul public
+	binary.BigEndian.PutUint16(frame[4:6], device)
+	binary.BigEndian.PutUint16(frame[6:8], uint16(len(apdu)))
+	if _, err := conn.Write(append(frame, apdu...)); err != nil {
+		t.Fatal(err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	header := make([]byte, wrapperSize)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if source := binary.BigEndian.Uint16(header[2:4]); source != device {
+		t.Fatalf("response from device %d, expected %d", source, device)
+	}
+	response := make([]byte, binary.BigEndian.Uint16(header[6:8]))
+	if _, err := io.ReadFull(conn, response); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return response
+}
+
+// getRequest construieste un Get-Request-Normal fara acces selectiv.
+func getRequest(class uint16, code metering.OBIS, attribute byte) []byte {
+	request := []byte{apduGetReq, getNormal, 0x81}
+	request = binary.BigEndian.AppendUint16(request, class)
+	request = append(request, code[:]...)
+	return append(request, attribute, 0x00)
+}
+
+func TestGetRegister(t *testing.T) {
+	s := testServer(t)
+	conn, err := net.Dial("tcp", s.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	voltage := metering.OBIS{1, 0, 12, 7, 0, 255}
+	// Fara asociere cererea primeste Exception-Response
+	if response := exchange(t, conn, 1, getRequest(metering.ClassRegister, voltage, 2)); response[0] != apduException {
+		t.Fatalf("GET without association: % x", response)
+	}
+
+	// Octetul 17 al AARE este rezultatul asocierii: 0 acceptata, 1 respinsa
+	if response := exchange(t, conn, 1, []byte{apduAARQ, 0x00}); response[0] != apduAARE || response[17] != 0 {
+		t.Fatalf("AARE % x", response)
+	}
+
+	// Tensiunea de 20.5 kV cu scaler -1: 205000 ca double-long
+	response := exchange(t, conn, 1, getRequest(metering.ClassRegister, voltage, 2))
+	if len(response) != 9 || response[0] != apduGetResp || response[2] != 0x81 || response[3] != 0 || response[4] != tagDoubleLong {
+		t.Fatalf("GET voltage: % x", response)
+	}
+	if value := int32(binary.BigEndian.Uint32(response[5:9])); value != 205000 {
+		t.Fatalf("voltage %d, expected 205000", value)
+	}
+
+	// Scaler-ul si unitatea registrului (atributul 3)
+	response = exchange(t, conn, 1, getRequest(metering.ClassRegister, voltage, 3))
+	value, _, err := decode(response[4:])
+	if items, _ := value.([]any); err != nil || len(items) != 2 || items[0] != int64(-1) || items[1] != uint64(metering.UnitVolt) {
+		t.Fatalf("scaler and unit %#v: %v", value, err)
+	}
+
+	// Obiect necunoscut si clasa gresita
+	if response := exchange(t, conn, 1, getRequest(metering.ClassRegister, metering.OBIS{1, 0, 99, 99, 0, 255}, 2)); response[3] != 1 || response[4] != resultObjectUndefined {
+		t.Fatalf("unknown object: % x", response)
+	}
+	if response := exchange(t, conn, 1, getRequest(metering.ClassData, voltage, 2)); response[3] != 1 || response[4] != resultObjectClassInconsistent {
+		t.Fatalf("wrong class: % x", response)
+	}
+
+	// Dispozitivul logic 2 nu exista si asocierea este respinsa
+	if response := exchange(t, conn, 2, []byte{apduAARQ, 0x00}); response[17] != 1 {
+		t.Fatalf("AARE for an unknown device: % x", response)
+	}
+}
@@ -0,0 +1,187 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/xitongsys/parquet-go/writer"
+
+	"contor-system/src/utils"
+)
+
+// Exportul rezultatelor unui calcul: un rand pe element, ca tabel text, CSV, JSON sau Parquet.
+
+type Format string
+
+const (
+	FormatTable   Format = "table"
+	FormatCSV     Format = "csv"
+	FormatJSON    Format = "json"
+	FormatParquet Format = "parquet"
+)
+
+// Row este rezultatul unui element; etichetele parquet descriu schema fisierului exportat.
+type Row struct {
+	ID                  string  `json:"id" parquet:"name=id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Kind                string  `json:"kind" parquet:"name=kind, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Energized           bool    `json:"energized" parquet:"name=energized, type=BOOLEAN"`
+	ActivePower         float64 `json:"activePower" parquet:"name=active_power, type=DOUBLE"`     // MW
+	ReactivePower       float64 `json:"reactivePower" parquet:"name=reactive_power, type=DOUBLE"` // MVAr
+	Current             float64 `json:"current" parquet:"name=current, type=DOUBLE"`              // A
+	Voltage             float64 `json:"voltage" parquet:"name=voltage, type=DOUBLE"`              // kV
+	VoltagePU           float64 `json:"voltagePU" parquet:"name=voltage_pu, type=DOUBLE"`         // u.r.
+	Loading             float64 `json:"loading" parquet:"name=loading, type=DOUBLE"`              // %
+	PowerFactor         float64 `json:"powerFactor" parquet:"name=power_factor, type=DOUBLE"`
+	ActivePowerLosses   float64 `json:"activePowerLosses" parquet:"name=active_power_losses, type=DOUBLE"`     // MW
+	ReactivePowerLosses float64 `json:"reactivePowerLosses" parquet:"name=reactive_power_losses, type=DOUBLE"` // MVAr
+}
+
+// Rows intoarce randurile rezultatului in ordinea parcurgerii.
+func Rows(result utils.SystemResult) []Row {
+	rows := make([]Row, 0, len(result.Order))
+	for _, id := range result.Order {
+		element := result.Elements[id]
+		rows = append(rows, Row{
+			ID:                  element.ID,
+			Kind:                element.Kind,
+			Energized:           element.Energized,
+			ActivePower:         element.ActivePower,
+			ReactivePower:       element.ReactivePower,
+			Current:             element.Current,
+			Voltage:             element.Voltage,
+			VoltagePU:           element.VoltagePU,
+			Loading:             element.Loading,
+			PowerFactor:         element.PowerFactor,
+			ActivePowerLosses:   element.ActivePowerLosses,
+			ReactivePowerLosses: element.ReactivePowerLosses,
+		})
+	}
+	return rows
+}
+
+var header = []string{"id", "kind", "energized", "activePower", "reactivePower", "current", "voltage", "voltagePU", "loading", "powerFactor", "activePowerLosses", "reactivePowerLosses"}
+
+func number(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// FormatResultTable scrie rezultatul ca tabel text aliniat, cu totalurile sistemului.
+func FormatResultTable(result utils.SystemResult) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "Element\tKind\tP (MW)\tQ (MVAr)\tI (A)\tU (kV)\tU (pu)\tLoading (%)\tcosfi\tLosses (MW)\tEnergized\t")
+	for _, row := range Rows(result) {
+		energized := "yes"
+		if !row.Energized {
+			energized = "no"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%.3f\t%.3f\t%.1f\t%.2f\t%.3f\t%.1f\t%.3f\t%.4f\t%s\t\n",
+			row.ID, row.Kind, row.ActivePower, row.ReactivePower, row.Current, row.Voltage, row.VoltagePU, row.Loading, row.PowerFactor, row.ActivePowerLosses, energized)
+	}
+	w.Flush()
+
+	engine := result.Engine
+	if engine == "" {
+		engine = utils.EngineTraversal
+	}
+	fmt.Fprintf(&b, "Losses: %.4f MW, %.4f MVAr\n", result.ActivePowerLosses, result.ReactivePowerLosses)
+	if result.Iterations > 0 {
+		fmt.Fprintf(&b, "Engine: %s, %d iterations, converged: %v\n", engine, result.Iterations, result.Converged)
+	} else {
+		fmt.Fprintf(&b, "Engine: %s\n", engine)
+	}
+	for _, consumer := range result.ConsumersWithoutPower {
+		fmt.Fprintf(&b, "Consumer %s is missing %.2f MW\n", consumer.ID, math.Abs(consumer.RemainingPowerNeeded))
+	}
+	return b.String()
+}
+
+// Result este rezultatul complet al calculului, pentru iesirea JSON.
+type Result struct {
+	Elements              []Row                        `json:"elements"`
+	ConsumersWithoutPower []utils.ConsumerPowerDetails `json:"consumersWithoutPower"`
+	ActivePowerLosses     float64                      `json:"activePowerLosses"`   // MW
+	ReactivePowerLosses   float64                      `json:"reactivePowerLosses"` // MVAr
+	Engine                utils.EngineType             `json:"engine"`
+	Iterations            int                          `json:"iterations"`
+	Converged             bool                         `json:"converged"`
+}
+
+// WriteResultJSON scrie rezultatul complet ca JSON.
+func WriteResultJSON(w io.Writer, result utils.SystemResult) error {
+	engine := result.Engine
+	if engine == "" {
+		engine = utils.EngineTraversal
+	}
+	consumers := result.ConsumersWithoutPower
+	if consumers == nil {
+		consumers = []utils.ConsumerPowerDetails{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(Result{
+		Elements:              Rows(result),
+		ConsumersWithoutPower: consumers,
+		ActivePowerLosses:     result.ActivePowerLosses,
+		ReactivePowerLosses:   result.ReactivePowerLosses,
+		Engine:                engine,
+		Iterations:            result.Iterations,
+		Converged:             result.Converged,
+	})
+}
+
+// WriteCSV scrie randurile ca CSV, cu antet.
+func WriteCSV(w io.Writer, rows []Row) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(header); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := []string{
+			row.ID, row.Kind, strconv.FormatBool(row.Energized),
+			number(row.ActivePower), number(row.ReactivePower), number(row.Current), number(row.Voltage), number(row.VoltagePU),
+			number(row.Loading), number(row.PowerFactor), number(row.ActivePowerLosses), number(row.ReactivePowerLosses),
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// WriteJSON scrie randurile ca lista JSON.
+func WriteJSON(w io.Writer, rows []Row) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(rows)
+}
+
+// WriteParquet scrie randurile intr-un fisier Parquet.
+func WriteParquet(path string, rows []Row) error {
+	file, err := utils.NewLocalFileWriter(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", path, err)
+	}
+	defer file.Close()
+
+	pw, err := writer.NewParquetWriter(file, new(Row), 1)
+	if err != nil {
+		return fmt.Errorf("failed to create the parquet writer: %v", err)
+	}
+	for _, row := range rows {
+		if err := pw.Write(row); err != nil {
+			return fmt.Errorf("failed to write row %s: %v", row.ID, err)
+		}
+	}
+	if err := pw.WriteStop(); err != nil {
+		return fmt.Errorf("failed to finish %s: %v", path, err)
+	}
+	return nil
+}
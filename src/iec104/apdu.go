@@ -0,0 +1,178 @@
+package iec104
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+)
+
+// Formatul cadrelor IEC 60870-5-104: APCI (start, lungime, patru octeti de control) urmat de ASDU pentru cadrele I.
+
+const (
+	startByte    = 0x68
+	maxAPDU      = 253
+	maxASDU      = maxAPDU - 4
+	asduHeader   = 6 // tip, VSQ, cauza (2 octeti), adresa comuna (2 octeti)
+	ioaSize      = 3
+	sequenceMask = 0x7fff
+)
+
+// Functiile cadrelor U
+const (
+	startDTActivation = 0x07
+	startDTConfirm    = 0x0b
+	stopDTActivation  = 0x13
+	stopDTConfirm     = 0x23
+	testFRActivation  = 0x43
+	testFRConfirm     = 0x83
+)
+
+// Tipurile ASDU folosite
+const (
+	typeSinglePoint   = 1   // M_SP_NA_1
+	typeShortFloat    = 13  // M_ME_NC_1
+	typeSingleCommand = 45  // C_SC_NA_1
+	typeInterrogation = 100 // C_IC_NA_1
+)
+
+// Cauzele transmisiei
+const (
+	causeSpontaneous         = 3
+	causeActivation          = 6
+	causeActivationConfirm   = 7
+	causeActivationTerminate = 10
+	causeInterrogated        = 20
+	causeUnknownType         = 44
+	causeUnknownCause        = 45
+	causeUnknownCommonAddr   = 46
+	causeUnknownIOA          = 47
+
+	negativeBit = 0x40
+)
+
+// Calitatea valorilor
+const (
+	qualityInvalid = 0x80
+)
+
+type frameKind int
+
+const (
+	frameI frameKind = iota
+	frameS
+	frameU
+)
+
+// frame este un APDU decodat.
+type frame struct {
+	kind     frameKind
+	send     uint16 // N(S), cadre I
+	receive  uint16 // N(R), cadre I si S
+	function byte   // cadre U
+	asdu     []byte
+}
+
+var errFrame = errors.New("invalid APDU")
+
+func readFrame(r io.Reader) (frame, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return frame{}, err
+	}
+	if header[0] != startByte || header[1] < 4 || header[1] > maxAPDU {
+		return frame{}, errFrame
+	}
+	body := make([]byte, header[1])
+	if _, err := io.ReadFull(r, body); err != nil {
+		return frame{}, err
+	}
+
+	switch {
+	case body[0]&0x01 == 0:
+		return frame{
+			kind:    frameI,
+			send:    binary.LittleEndian.Uint16(body[0:2]) >> 1,
+			receive: binary.LittleEndian.Uint16(body[2:4]) >> 1,
+			asdu:    body[4:],
+		}, nil
+	case body[0]&0x03 == 0x01:
+		return frame{kind: frameS, receive: binary.LittleEndian.Uint16(body[2:4]) >> 1}, nil
+	default:
+		return frame{kind: frameU, function: body[0]}, nil
+	}
+}
+
+func iFrame(send uint16, receive uint16, asdu []byte) []byte {
+	b := make([]byte, 6+len(asdu))
+	b[0], b[1] = startByte, byte(4+len(asdu))
+	binary.LittleEndian.PutUint16(b[2:4], send<<1)
+	binary.LittleEndian.PutUint16(b[4:6], receive<<1)
+	copy(b[6:], asdu)
+	return b
+}
+
+func sFrame(receive uint16) []byte {
+	b := []byte{startByte, 4, 0x01, 0, 0, 0}
+	binary.LittleEndian.PutUint16(b[4:6], receive<<1)
+	return b
+}
+
+func uFrame(function byte) []byte {
+	return []byte{startByte, 4, function, 0, 0, 0}
+}
+
+// asduBuilder construieste un ASDU cu obiecte adresate individual (SQ = 0).
+type asduBuilder struct {
+	b     []byte
+	count int
+}
+
+func newASDU(typeID byte, cause byte, commonAddress uint16) *asduBuilder {
+	b := []byte{typeID, 0, cause, 0, 0, 0}
+	binary.LittleEndian.PutUint16(b[4:6], commonAddress)
+	return &asduBuilder{b: b}
+}
+
+func (a *asduBuilder) object(ioa uint32, element []byte) {
+	a.b = append(a.b, byte(ioa), byte(ioa>>8), byte(ioa>>16))
+	a.b = append(a.b, element...)
+	a.count++
+	a.b[1] = byte(a.count)
+}
+
+func (a *asduBuilder) fits(elementSize int) bool {
+	return len(a.b)+ioaSize+elementSize <= maxASDU && a.count < 127
+}
+
+func (a *asduBuilder) bytes() []byte {
+	return a.b
+}
+
+// shortFloat codifica o valoare masurata cu virgula mobila si descriptorul de calitate.
+func shortFloat(value float32, quality byte) []byte {
+	b := make([]byte, 5)
+	binary.LittleEndian.PutUint32(b, math.Float32bits(value))
+	b[4] = quality
+	return b
+}
+
+// singlePoint codifica o informatie simpla cu calitatea ei.
+func singlePoint(on bool, quality byte) []byte {
+	siq := quality
+	if on {
+		siq |= 0x01
+	}
+	return []byte{siq}
+}
+
+// mirror intoarce o copie a ASDU-ului primit cu alta cauza a transmisiei.
+func mirror(asdu []byte, cause byte) []byte {
+	b := append([]byte(nil), asdu...)
+	b[2] = cause
+	return b
+}
+
+func ioaOf(b []byte) uint32 {
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16
+}
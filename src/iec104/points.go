@@ -0,0 +1,96 @@
+package iec104
+
+import (
+	"contor-system/src/utils"
+)
+
+// Harta punctelor: masurile liniilor, transformatoarelor si consumatorilor si starea separatoarelor.
+
+// measurementQuantities sunt marimile din harta implicita, in ordinea offseturilor IOA.
+var measurementQuantities = []string{"activePower", "reactivePower", "current", "voltage", "loading"}
+
+const (
+	stateQuantity   = "state"
+	measurementBase = 1001 // IOA-ul primei masuri din harta implicita
+	measurementStep = 10   // IOA-uri rezervate fiecarui element masurat
+)
+
+// DefaultPoints construieste harta implicita: separatorul i are IOA 1+i (stare si comanda), iar elementul masurat n
+// (liniile, transformatoarele, apoi consumatorii, in ordinea din configuratie) are IOA 1001+10n+k.
+func DefaultPoints(system utils.System) []utils.IEC104Point {
+	var points []utils.IEC104Point
+	for i, separator := range system.Separators {
+		points = append(points, utils.IEC104Point{Element: separator.ID, Quantity: stateQuantity, IOA: uint32(1 + i)})
+	}
+
+	var measured []string
+	for _, l := range system.Lines {
+		measured = append(measured, l.ID)
+	}
+	for _, t := range system.Transformers {
+		measured = append(measured, t.ID)
+	}
+	for _, c := range system.Consumers {
+		measured = append(measured, c.ID)
+	}
+	for n, id := range measured {
+		for k, quantity := range measurementQuantities {
+			points = append(points, utils.IEC104Point{Element: id, Quantity: quantity, IOA: uint32(measurementBase + measurementStep*n + k)})
+		}
+	}
+	return points
+}
+
+// pointValue este ultima valoare a unui punct.
+type pointValue struct {
+	point   utils.IEC104Point
+	typeID  byte
+	value   float32
+	on      bool
+	quality byte
+}
+
+func (v pointValue) element() []byte {
+	if v.typeID == typeSinglePoint {
+		return singlePoint(v.on, v.quality)
+	}
+	return shortFloat(v.value, v.quality)
+}
+
+// evaluate calculeaza valoarea punctului din rezultat; starea separatoarelor se ia din sistem.
+func evaluate(point utils.IEC104Point, system utils.System, result utils.SystemResult) pointValue {
+	v := pointValue{point: point, typeID: typeShortFloat}
+
+	if point.Quantity == stateQuantity {
+		v.typeID = typeSinglePoint
+		v.quality = qualityInvalid
+		for _, separator := range system.Separators {
+			if separator.ID == point.Element {
+				v.on = separator.State == utils.StateClose
+				v.quality = 0
+			}
+		}
+		return v
+	}
+
+	element, exists := result.Elements[point.Element]
+	if !exists {
+		v.quality = qualityInvalid
+		return v
+	}
+	switch point.Quantity {
+	case "activePower":
+		v.value = float32(element.ActivePower)
+	case "reactivePower":
+		v.value = float32(element.ReactivePower)
+	case "current":
+		v.value = float32(element.Current)
+	case "voltage":
+		v.value = float32(element.Voltage)
+	case "loading":
+		v.value = float32(element.Loading)
+	default:
+		v.quality = qualityInvalid
+	}
+	return v
+}
@@ -0,0 +1,508 @@
+package iec104
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	"contor-system/src/switching"
+	"contor-system/src/utils"
+)
+
+// Statie controlata IEC 60870-5-104: masuri si stari transmise spontan si la interogarea generala,
+// comenzi simple (C_SC_NA_1) care manevreaza separatoarele modelului in functiune.
+
+const (
+	windowK        = 12               // cadre I trimise fara confirmare
+	windowW        = 8                // cadre I primite dupa care se trimite confirmarea
+	timeoutT1      = 15 * time.Second // confirmarea cadrelor I si U trimise; depasirea inchide conexiunea
+	timeoutT2      = 10 * time.Second // confirmarea cadrelor I primite
+	timeoutT3      = 20 * time.Second // cadru de test dupa inactivitate
+	commandTimeout = 10 * time.Second
+	writeTimeout   = timeoutT1 // termenul scrierii unui cadru
+)
+
+type Server struct {
+	settings utils.IEC104
+	commands chan<- switching.Command
+	listener net.Listener
+
+	mu       sync.Mutex
+	values   map[uint32]pointValue
+	order    []uint32 // IOA-urile in ordinea hartii
+	sessions map[*session]struct{}
+	wg       sync.WaitGroup
+	logger   *log.Logger
+}
+
+func NewServer(settings utils.IEC104, commands chan<- switching.Command, logger *log.Logger) *Server {
+	if logger == nil {
+		logger = log.Default()
+	}
+	if settings.Address == "" {
+		settings.Address = ":2404"
+	}
+	if settings.CommonAddress == 0 {
+		settings.CommonAddress = 1
+	}
+	if settings.Deadband <= 0 {
+		settings.Deadband = 1
+	}
+	return &Server{
+		settings: settings,
+		commands: commands,
+		values:   map[uint32]pointValue{},
+		sessions: map[*session]struct{}{},
+		logger:   logger,
+	}
+}
+
+// Start deschide portul si accepta conexiunile in fundal.
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.settings.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %v", s.settings.Address, err)
+	}
+	s.listener = listener
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				if !errors.Is(err, net.ErrClosed) {
+					s.logger.Printf("IEC 104 server stopped accepting connections: %v", err)
+				}
+				return
+			}
+			session := newSession(s, conn)
+			s.mu.Lock()
+			s.sessions[session] = struct{}{}
+			s.mu.Unlock()
+
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				session.serve()
+				s.mu.Lock()
+				delete(s.sessions, session)
+				s.mu.Unlock()
+			}()
+		}
+	}()
+	return nil
+}
+
+// Addr intoarce adresa pe care asculta serverul.
+func (s *Server) Addr() net.Addr {
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+// Close opreste serverul si inchide conexiunile deschise.
+func (s *Server) Close() error {
+	if s.listener == nil {
+		return nil
+	}
+	err := s.listener.Close()
+	s.mu.Lock()
+	for session := range s.sessions {
+		session.conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return err
+}
+
+// changed verifica daca valoarea noua depaseste banda moarta fata de ultima valoare transmisa.
+func (s *Server) changed(old pointValue, new pointValue) bool {
+	if old.quality != new.quality || old.typeID != new.typeID {
+		return true
+	}
+	if new.typeID == typeSinglePoint {
+		return old.on != new.on
+	}
+	threshold := math.Max(math.Abs(float64(old.value))*s.settings.Deadband/100, 1e-3)
+	return math.Abs(float64(new.value-old.value)) > threshold
+}
+
+// Update recalculeaza punctele din rezultatul unui pas de calcul si transmite spontan valorile modificate.
+func (s *Server) Update(system utils.System, result utils.SystemResult) {
+	points := s.settings.Points
+	if len(points) == 0 {
+		points = DefaultPoints(system)
+	}
+
+	s.mu.Lock()
+	values := map[uint32]pointValue{}
+	order := make([]uint32, 0, len(points))
+	var spontaneous []pointValue
+	for _, point := range points {
+		value := evaluate(point, system, result)
+		if old, exists := s.values[point.IOA]; !exists || s.changed(old, value) {
+			spontaneous = append(spontaneous, value)
+		} else if value.typeID == typeShortFloat {
+			// Valoarea transmisa ramane referinta pentru banda moarta
+			value.value = old.value
+		}
+		values[point.IOA] = value
+		order = append(order, point.IOA)
+	}
+	s.values = values
+	s.order = order
+	sessions := make([]*session, 0, len(s.sessions))
+	for session := range s.sessions {
+		sessions = append(sessions, session)
+	}
+	s.mu.Unlock()
+
+	if len(spontaneous) == 0 {
+		return
+	}
+	// send doar pune cadrele in coada sesiunii; scrierea pe conexiune se face de sesiune, fara s.mu
+	asdus := s.pack(spontaneous, causeSpontaneous)
+	for _, session := range sessions {
+		for _, asdu := range asdus {
+			session.send(asdu)
+		}
+	}
+}
+
+// pack grupeaza valorile de acelasi tip in ASDU-uri de lungime maxima.
+func (s *Server) pack(values []pointValue, cause byte) [][]byte {
+	var asdus [][]byte
+	for _, typeID := range []byte{typeSinglePoint, typeShortFloat} {
+		var current *asduBuilder
+		for _, v := range values {
+			if v.typeID != typeID {
+				continue
+			}
+			element := v.element()
+			if current == nil || !current.fits(len(element)) {
+				if current != nil {
+					asdus = append(asdus, current.bytes())
+				}
+				current = newASDU(typeID, cause, s.settings.CommonAddress)
+			}
+			current.object(v.point.IOA, element)
+		}
+		if current != nil {
+			asdus = append(asdus, current.bytes())
+		}
+	}
+	return asdus
+}
+
+// snapshot intoarce valorile curente in ordinea hartii.
+func (s *Server) snapshot() []pointValue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	values := make([]pointValue, 0, len(s.order))
+	for _, ioa := range s.order {
+		values = append(values, s.values[ioa])
+	}
+	return values
+}
+
+// commandPoint intoarce separatorul comandat prin IOA.
+func (s *Server) commandPoint(ioa uint32) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, exists := s.values[ioa]
+	if !exists || v.point.Quantity != stateQuantity {
+		return "", false
+	}
+	return v.point.Element, true
+}
+
+// session este o conexiune cu statia de dispecer. Cadrele sunt puse in coada out si scrise de writer, astfel
+// incat nicio scriere pe conexiune nu se face cu un mutex blocat.
+type session struct {
+	server *Server
+	conn   net.Conn
+
+	mu           sync.Mutex
+	started      bool
+	sendSeq      uint16 // V(S)
+	receiveSeq   uint16 // V(R)
+	acked        uint16 // ultimul N(R) primit
+	unacked      int    // cadre I primite si neconfirmate
+	queue        [][]byte
+	sent         []time.Time // momentele trimiterii cadrelor I neconfirmate, in ordine
+	testSent     time.Time   // momentul trimiterii TESTFR act neconfirmat
+	lastReceived time.Time
+	out          [][]byte      // cadre de scris pe conexiune
+	wake         chan struct{} // semnaleaza writer-ului cadre noi in out
+	closed       bool
+}
+
+func newSession(server *Server, conn net.Conn) *session {
+	return &session{server: server, conn: conn, lastReceived: time.Now(), wake: make(chan struct{}, 1)}
+}
+
+func (c *session) serve() {
+	defer c.close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go c.timers(done)
+	go c.writer(done)
+
+	for {
+		f, err := readFrame(c.conn)
+		if err != nil {
+			return
+		}
+
+		c.mu.Lock()
+		c.lastReceived = time.Now()
+		switch f.kind {
+		case frameU:
+			c.handleU(f.function)
+			c.mu.Unlock()
+		case frameS:
+			c.acknowledge(f.receive)
+			c.flush()
+			c.mu.Unlock()
+		case frameI:
+			if f.send != c.receiveSeq {
+				c.mu.Unlock()
+				c.server.logger.Printf("IEC 104 sequence error from %s: expected %d, received %d", c.conn.RemoteAddr(), c.receiveSeq, f.send)
+				return
+			}
+			c.receiveSeq = (c.receiveSeq + 1) & sequenceMask
+			c.acknowledge(f.receive)
+			c.unacked++
+			if c.unacked >= windowW {
+				c.write(sFrame(c.receiveSeq))
+				c.unacked = 0
+			}
+			c.flush()
+			started := c.started
+			c.mu.Unlock()
+			if started && len(f.asdu) >= asduHeader {
+				c.handleASDU(f.asdu)
+			}
+		}
+	}
+}
+
+// acknowledge elibereaza cadrele I confirmate de N(R); se apeleaza cu mu blocat.
+func (c *session) acknowledge(receive uint16) {
+	confirmed := int((receive - c.acked) & sequenceMask)
+	if confirmed > len(c.sent) {
+		c.server.logger.Printf("IEC 104 invalid acknowledgement from %s: N(R) %d outside %d..%d", c.conn.RemoteAddr(), receive, c.acked, c.sendSeq)
+		c.closeLocked()
+		return
+	}
+	c.acked = receive
+	c.sent = c.sent[confirmed:]
+}
+
+// timers trimite confirmarile intarziate (t2) si cadrele de test dupa inactivitate (t3) si inchide conexiunea
+// cand un cadru I sau un cadru de test ramane neconfirmat mai mult de t1.
+func (c *session) timers(done <-chan struct{}) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-ticker.C:
+			c.mu.Lock()
+			if (len(c.sent) > 0 && now.Sub(c.sent[0]) >= timeoutT1) || (!c.testSent.IsZero() && now.Sub(c.testSent) >= timeoutT1) {
+				c.server.logger.Printf("IEC 104 connection from %s timed out (t1)", c.conn.RemoteAddr())
+				c.closeLocked()
+				c.mu.Unlock()
+				return
+			}
+			idle := now.Sub(c.lastReceived)
+			if c.unacked > 0 && idle >= timeoutT2 {
+				c.write(sFrame(c.receiveSeq))
+				c.unacked = 0
+			}
+			if idle >= timeoutT3 && c.testSent.IsZero() {
+				c.write(uFrame(testFRActivation))
+				c.testSent = now
+			}
+			c.mu.Unlock()
+		}
+	}
+}
+
+// write pune un cadru in coada de scriere; se apeleaza cu mu blocat.
+func (c *session) write(b []byte) {
+	if c.closed {
+		return
+	}
+	c.out = append(c.out, b)
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// writer scrie cadrele din coada, fiecare cu termenul writeTimeout; o scriere esuata inchide conexiunea.
+func (c *session) writer(done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case <-c.wake:
+		}
+		c.mu.Lock()
+		frames := c.out
+		c.out = nil
+		c.mu.Unlock()
+
+		for _, frame := range frames {
+			c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if _, err := c.conn.Write(frame); err != nil {
+				c.close()
+				return
+			}
+		}
+	}
+}
+
+// closeLocked inchide conexiunea; se apeleaza cu mu blocat.
+func (c *session) closeLocked() {
+	c.closed = true
+	c.out = nil
+	c.conn.Close()
+}
+
+func (c *session) close() {
+	c.mu.Lock()
+	c.closeLocked()
+	c.mu.Unlock()
+}
+
+// handleU raspunde cadrelor U; se apeleaza cu mu blocat.
+func (c *session) handleU(function byte) {
+	switch function {
+	case startDTActivation:
+		c.started = true
+		c.write(uFrame(startDTConfirm))
+	case stopDTActivation:
+		c.started = false
+		c.queue = nil
+		c.write(uFrame(stopDTConfirm))
+	case testFRActivation:
+		c.write(uFrame(testFRConfirm))
+	case testFRConfirm:
+		c.testSent = time.Time{}
+	}
+}
+
+// send pune un ASDU in coada de transmisie; cadrele pleaca cat timp fereastra k permite.
+func (c *session) send(asdu []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.started {
+		return
+	}
+	c.queue = append(c.queue, asdu)
+	c.flush()
+}
+
+// flush trimite cadrele din coada in limita ferestrei k; se apeleaza cu mu blocat.
+func (c *session) flush() {
+	for len(c.queue) > 0 && (c.sendSeq-c.acked)&sequenceMask < windowK {
+		c.write(iFrame(c.sendSeq, c.receiveSeq, c.queue[0]))
+		c.sent = append(c.sent, time.Now())
+		c.sendSeq = (c.sendSeq + 1) & sequenceMask
+		c.unacked = 0
+		c.queue = c.queue[1:]
+	}
+}
+
+func (c *session) handleASDU(asdu []byte) {
+	typeID := asdu[0]
+	cause := asdu[2] & 0x3f
+	commonAddress := uint16(asdu[4]) | uint16(asdu[5])<<8
+
+	if commonAddress != c.server.settings.CommonAddress && commonAddress != 0xffff {
+		c.send(mirror(asdu, causeUnknownCommonAddr|negativeBit))
+		return
+	}
+
+	switch typeID {
+	case typeInterrogation:
+		if cause != causeActivation {
+			c.send(mirror(asdu, causeUnknownCause|negativeBit))
+			return
+		}
+		c.send(mirror(asdu, causeActivationConfirm))
+		for _, data := range c.server.pack(c.server.snapshot(), causeInterrogated) {
+			c.send(data)
+		}
+		c.send(mirror(asdu, causeActivationTerminate))
+
+	case typeSingleCommand:
+		if len(asdu) < asduHeader+ioaSize+1 {
+			return
+		}
+		if cause != causeActivation {
+			c.send(mirror(asdu, causeUnknownCause|negativeBit))
+			return
+		}
+		ioa := ioaOf(asdu[asduHeader:])
+		sco := asdu[asduHeader+ioaSize]
+		separator, exists := c.server.commandPoint(ioa)
+		if !exists {
+			c.send(mirror(asdu, causeUnknownIOA|negativeBit))
+			return
+		}
+		// Selectia (S/E = 1) este confirmata fara manevra; executia urmeaza in cererea urmatoare
+		if sco&0x80 != 0 {
+			c.send(mirror(asdu, causeActivationConfirm))
+			return
+		}
+
+		state := utils.StateOpen
+		if sco&0x01 != 0 {
+			state = utils.StateClose
+		}
+		go c.execute(asdu, separator, state)
+
+	default:
+		c.send(mirror(asdu, causeUnknownType|negativeBit))
+	}
+}
+
+// execute trimite comanda buclei de calcul; confirmarea pozitiva sau negativa si terminarea sunt trimise de bucla.
+func (c *session) execute(asdu []byte, separator string, state utils.StateType) {
+	command := switching.Command{
+		Separator: separator,
+		State:     state,
+		Confirm: func(err error) {
+			if err != nil {
+				c.server.logger.Printf("IEC 104 command %s %s rejected: %v", state, separator, err)
+				c.send(mirror(asdu, causeActivationConfirm|negativeBit))
+				return
+			}
+			c.send(mirror(asdu, causeActivationConfirm))
+		},
+		Terminate: func() {
+			c.send(mirror(asdu, causeActivationTerminate))
+		},
+	}
+
+	if c.server.commands == nil {
+		command.Confirm(errors.New("remote commands are not enabled"))
+		return
+	}
+	select {
+	case c.server.commands <- command:
+	case <-time.After(commandTimeout):
+		command.Confirm(errors.New("command queue is busy"))
+	}
+}
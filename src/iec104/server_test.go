@@ -0,0 +1,113 @@
+package iec104
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"contor-system/src/utils"
+)
+
+func testServer(t *testing.T) *Server {
+	t.Helper()
+	s := NewServer(utils.IEC104{
+		Address: "127.0.0.1:0",
+		Points:  []utils.IEC104Point{{Element: "line1", Quantity: "activePower", IOA: 1000}},
+	}, nil, nil)
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func testResult(power float64) utils.SystemResult {
+	return utils.SystemResult{
+		Order:    []string{"line1"},
+		Elements: map[string]*utils.ElementResult{"line1": {ID: "line1", ActivePower: power}},
+	}
+}
+
+// connect deschide o conexiune de dispecer si activeaza transferul de date.
+func connect(t *testing.T, s *Server) net.Conn {
+	t.Helper()
+	conn, err := net.Dial("tcp", s.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.Write(uFrame(startDTActivation))
+	if f := expectFrame(t, conn); f.kind != frameU || f.function != startDTConfirm {
+		t.Fatalf("expected STARTDT con, received %+v", f)
+	}
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		s.mu.Lock()
+		sessions := len(s.sessions)
+		s.mu.Unlock()
+		if sessions > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the session was not registered")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return conn
+}
+
+func expectFrame(t *testing.T, conn net.Conn) frame {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	f, err := readFrame(conn)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return f
+}
+
+func TestSpontaneousTransmission(t *testing.T) {
+	s := testServer(t)
+	conn := connect(t, s)
+
+	s.Update(utils.System{}, testResult(12.5))
+	f := expectFrame(t, conn)
+	if f.kind != frameI || f.asdu[0] != typeShortFloat || f.asdu[2] != causeSpontaneous {
+		t.Fatalf("expected a spontaneous M_ME_NC_1, received %+v", f)
+	}
+	if ioa := ioaOf(f.asdu[asduHeader:]); ioa != 1000 {
+		t.Fatalf("IOA %d", ioa)
+	}
+
+	// O variatie in banda moarta nu se transmite
+	s.Update(utils.System{}, testResult(12.51))
+	s.Update(utils.System{}, testResult(20))
+	if f := expectFrame(t, conn); f.send != 1 {
+		t.Fatalf("expected the second I frame, received N(S) %d", f.send)
+	}
+}
+
+func TestUpdateDoesNotWaitForStalledSession(t *testing.T) {
+	s := testServer(t)
+	connect(t, s)
+
+	// Dispecerul nu mai citeste si nu confirma: Update doar pune cadrele in coada
+	start := time.Now()
+	for i := 0; i < 1000; i++ {
+		s.Update(utils.System{}, testResult(float64(i*10)))
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Update took %s with a stalled session", elapsed)
+	}
+}
+
+func TestInvalidAcknowledgementClosesConnection(t *testing.T) {
+	s := testServer(t)
+	conn := connect(t, s)
+
+	// Nu a fost trimis niciun cadru I, deci N(R) = 5 nu poate fi confirmat
+	conn.Write(sFrame(5))
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := readFrame(conn); err == nil {
+		t.Fatal("the connection stayed open after an invalid N(R)")
+	}
+}
@@ -1,16 +1,21 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
-	"sync"
+	"strings"
 	"syscall"
 	"time"
 
+	"contor-system/src/alarms"
 	"contor-system/src/computing"
+	"contor-system/src/switching"
 	"contor-system/src/utils"
 )
 
@@ -23,14 +28,14 @@ func ensureDirectory(dir string) error {
 func loadConfig(filePath string) (utils.System, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
-		return utils.System{}, fmt.Errorf("failed to open config.json: %v", err)
+		return utils.System{}, fmt.Errorf("failed to open config: %v", err)
 	}
 	defer file.Close()
 
 	var system utils.System
 	decoder := json.NewDecoder(file)
 	if err := decoder.Decode(&system); err != nil {
-		return utils.System{}, fmt.Errorf("failed to decode config.json: %v", err)
+		return utils.System{}, fmt.Errorf("failed to decode %s: %v", filePath, err)
 	}
 
 	return system, nil
@@ -53,9 +58,102 @@ func writeToFile(logs []utils.LogEntry, filePath string) error {
 	return nil
 }
 
+// writeAlarms salveaza alarmele active si ultimele evenimente, pentru a fi servite de API.
+func writeAlarms(active []alarms.Alarm, events []alarms.Event, filePath string) error {
+	content, err := json.MarshalIndent(struct {
+		Active []alarms.Alarm `json:"active"`
+		Events []alarms.Event `json:"events"`
+	}{Active: active, Events: events}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode alarms: %v", err)
+	}
+
+	if err := os.WriteFile(filePath, content, 0644); err != nil {
+		return fmt.Errorf("failed to write alarms: %v", err)
+	}
+
+	return nil
+}
+
+// runDaemon calculeaza reteaua din configPath, sau fiecare retea din configDir, pana la o intrerupere.
+func runDaemon(configPath string, configDir string, logDir string, tick time.Duration, switchingRunner *switching.Runner) {
+	// Ensure graceful shutdown on interrupt
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if configDir != "" {
+			runDirectory(ctx, configDir, logDir, tick)
+			return
+		}
+		n := newNetwork("", configPath, logDir, tick)
+		n.switching = switchingRunner
+		n.run(ctx)
+	}()
+
+	select {
+	case <-signals:
+		log.Println("Received shutdown signal.")
+		cancel()
+		<-done
+	case <-done:
+	}
+	log.Println("Shutdown complete.")
+}
+
 // Funcția principală
 func main() {
-	configPath := "./config.json"
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		handled, err := runSubcommand(os.Args[1], os.Args[2:])
+		if !handled {
+			printUsage()
+			os.Exit(2)
+		}
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	contingency := flag.Bool("contingency", false, "run the N-1 contingency analysis once and exit")
+	contingencySources := flag.Bool("contingency-sources", false, "include source outages in the contingency analysis")
+	shortCircuit := flag.Bool("short-circuit", false, "compute three-phase short-circuit currents (IEC 60909) once and exit")
+	shortCircuitCase := flag.String("sc-case", "max", "short-circuit case: max or min (selects the voltage factor c)")
+	shortCircuitC := flag.Float64("sc-c", 0, "override the voltage factor c")
+	shortCircuitTmin := flag.Float64("sc-tmin", 0.1, "minimum time delay in seconds for the breaking current")
+	fault := flag.String("fault", "", "compute an unsymmetrical fault at every node once and exit: slg, ll or dlg")
+	faultR := flag.Float64("fault-r", 0, "fault resistance in ohm")
+	faultX := flag.Float64("fault-x", 0, "fault reactance in ohm")
+	unbalanced := flag.Bool("unbalanced", false, "run the three-phase unbalanced power flow once and exit")
+	sweep := flag.Bool("sweep", false, "run the backward/forward sweep power flow once and exit")
+	opf := flag.String("opf", "", "run the optimal power flow once and exit: dc or ac")
+	opfObjective := flag.String("opf-objective", "cost", "optimal power flow objective: cost or losses")
+	reconfigure := flag.Bool("reconfigure", false, "search the separator states that minimise losses or loading once and exit")
+	reconfigureObjective := flag.String("reconfigure-objective", "losses", "reconfiguration objective: losses or loading")
+	switchingPlan := flag.String("switching", "", "execute a switching sequence file against the running model")
+	switchingSimulate := flag.Bool("switching-simulate", false, "simulate the whole switching sequence without waiting for the delays and exit")
+	configFlag := flag.String("config", "./config.json", "network configuration file")
+	configDir := flag.String("config-dir", "", "run every *.json network configuration of the directory concurrently")
+	logDir := flag.String("log-dir", "logs", "log directory; with -config-dir each network logs to a subdirectory named after its file")
+	tick := flag.Duration("tick", time.Second, "calculation interval of the networks without daemon.tick")
+	flag.Parse()
+
+	configPath := *configFlag
+
+	if *configDir != "" {
+		if *switchingPlan != "" {
+			log.Fatalf("-switching runs against a single network and cannot be used with -config-dir")
+		}
+		runDaemon("", *configDir, *logDir, *tick, nil)
+		return
+	}
 
 	// Initial config load
 	config, err := loadConfig(configPath)
@@ -63,68 +161,85 @@ func main() {
 		log.Fatalf("Failed to load initial config: %v", err)
 	}
 
-	lastConfigJSON, _ := os.ReadFile(configPath)
+	if *contingency {
+		report := computing.RunContingencies(config, computing.ContingencyOptions{IncludeSources: *contingencySources})
+		fmt.Print(computing.FormatContingencyReport(report))
+		return
+	}
 
-	// Ensure graceful shutdown on interrupt
-	signals := make(chan os.Signal, 1)
-	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
-	defer close(signals)
+	if *shortCircuit {
+		results := computing.ComputeShortCircuit(config, computing.ShortCircuitOptions{
+			Case:         computing.ShortCircuitCase(*shortCircuitCase),
+			C:            *shortCircuitC,
+			MinTimeDelay: *shortCircuitTmin,
+		})
+		fmt.Print(computing.FormatShortCircuitReport(results))
+		return
+	}
+
+	if *fault != "" {
+		results, err := computing.ComputeFaults(config, computing.FaultOptions{
+			Type:           computing.FaultType(*fault),
+			Case:           computing.ShortCircuitCase(*shortCircuitCase),
+			C:              *shortCircuitC,
+			FaultImpedance: complex(*faultR, *faultX),
+		})
+		if err != nil {
+			log.Fatalf("Failed to compute faults: %v", err)
+		}
+		fmt.Print(computing.FormatFaultReport(results))
+		return
+	}
 
-	cleanupManager := sync.Once{}
-	cleanup := func() {
-		log.Println("Cleaning up resources...")
+	if *unbalanced {
+		result := computing.ComputeUnbalanced(config, computing.SweepOptions{})
+		fmt.Print(computing.FormatUnbalancedReport(result))
+		return
 	}
-	defer cleanupManager.Do(cleanup)
 
-	// Infinite loop to monitor logs and handle writes
-	// ticker := time.NewTicker(1 * time.Minute)
-	ticker := time.NewTicker(1 * time.Second)
-	defer ticker.Stop()
+	if *opf != "" {
+		result, err := computing.ComputeOPF(config, computing.OPFOptions{
+			Mode:      computing.OPFMode(*opf),
+			Objective: computing.OPFObjective(*opfObjective),
+			Sweep:     computing.SweepOptions{Tolerance: config.Solver.Tolerance, MaxIterations: config.Solver.MaxIterations},
+		})
+		if err != nil {
+			log.Fatalf("Failed to compute the optimal power flow: %v", err)
+		}
+		fmt.Print(computing.FormatOPFReport(result))
+		return
+	}
 
-	// Generate the initial log file path
-	logFilePath := fmt.Sprintf("logs/%s.txt", time.Now().Format("2006-01-02_150405"))
+	if *reconfigure {
+		result, err := computing.Reconfigure(config, computing.ReconfigurationOptions{Objective: computing.ReconfigurationObjective(*reconfigureObjective)})
+		if err != nil {
+			log.Fatalf("Failed to reconfigure the network: %v", err)
+		}
+		fmt.Print(computing.FormatReconfigurationReport(result))
+		return
+	}
 
-	for {
-		select {
-		case <-signals:
-			log.Println("Received shutdown signal.")
-			cleanupManager.Do(cleanup)
-			log.Println("Shutdown complete.")
+	var switchingRunner *switching.Runner
+	if *switchingPlan != "" {
+		sequence, err := switching.Load(*switchingPlan)
+		if err != nil {
+			log.Fatalf("Error loading switching sequence: %v", err)
+		}
+		if *switchingSimulate {
+			steps, _, _ := switching.Simulate(config, sequence, time.Now(), computing.SolveQuiet)
+			fmt.Print(switching.FormatReport(sequence, steps))
 			return
-		case <-ticker.C:
-			// Reload configuration every minute and check for changes
-			currentConfig, err := loadConfig(configPath)
-			if err != nil {
-				log.Printf("Failed to reload config: %v", err)
-				continue
-			}
-
-			currentConfigJSON, _ := os.ReadFile(configPath)
-			if string(currentConfigJSON) != string(lastConfigJSON) {
-				log.Println("Configuration has changed. New configuration loaded.")
-				lastConfigJSON = currentConfigJSON
-				config = currentConfig
-
-				// Generate a new log file path when the config changes
-				logFilePath = fmt.Sprintf("logs/%s.txt", time.Now().Format("2006-01-02_150405"))
-				log.Printf("Switched to new log file: %s", logFilePath)
-			}
-
-			// Simulate log calculation
-			logEntries := computing.ComputeSystem(config)
-
-			// Ensure the log directory exists
-			if err := os.MkdirAll("logs", os.ModePerm); err != nil {
-				log.Printf("Error creating log directory: %v", err)
-				continue
-			}
-
-			// Write logs to the new log file
-			if err := writeToFile(logEntries, logFilePath); err != nil {
-				log.Printf("Error writing to file: %v", err)
-			} else {
-				log.Printf("Logged %d entries to %s", len(logEntries), logFilePath)
-			}
 		}
+		switchingRunner = switching.NewRunner(sequence, time.Now())
 	}
+
+	if *sweep {
+		_, logEntries := computing.ComputeSweep(config, computing.SweepOptions{Tolerance: config.Solver.Tolerance, MaxIterations: config.Solver.MaxIterations})
+		for _, entry := range logEntries {
+			fmt.Printf("%s | %s | %s", entry.Timestamp, entry.ComponentID, entry.Message)
+		}
+		return
+	}
+
+	runDaemon(configPath, "", *logDir, *tick, switchingRunner)
 }
@@ -0,0 +1,81 @@
+package metering
+
+import (
+	"sync"
+	"time"
+
+	"contor-system/src/utils"
+)
+
+// Registrele de energie ale punctelor de masura: puterile calculate la fiecare pas integrate in timp.
+
+// Energy contine indexurile de energie ale unui element.
+type Energy struct {
+	ActiveImport   float64 `json:"activeImport"`   // kWh, puterea activa pozitiva (absorbita)
+	ActiveExport   float64 `json:"activeExport"`   // kWh, puterea activa negativa (debitata)
+	ReactiveImport float64 `json:"reactiveImport"` // kvarh
+	ReactiveExport float64 `json:"reactiveExport"` // kvarh
+}
+
+// Accumulator integreaza puterile fiecarui element intre doi pasi de calcul.
+type Accumulator struct {
+	mu     sync.RWMutex
+	energy map[string]*Energy
+	last   time.Time
+}
+
+func NewAccumulator() *Accumulator {
+	return &Accumulator{energy: map[string]*Energy{}}
+}
+
+/*
+Functie de integrare a energiei: E += P * dt
+- puterea se considera constanta intre doi pasi (valoarea pasului curent)
+- P: MW, dt: h, E: kWh
+*/
+func (a *Accumulator) Update(result utils.SystemResult, now time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var hours float64
+	if !a.last.IsZero() && now.After(a.last) {
+		hours = now.Sub(a.last).Hours()
+	}
+	a.last = now
+
+	for _, id := range result.Order {
+		element := result.Elements[id]
+		energy, exists := a.energy[id]
+		if !exists {
+			energy = &Energy{}
+			a.energy[id] = energy
+		}
+		if !element.Energized {
+			continue
+		}
+
+		active := element.ActivePower * hours * 1000
+		reactive := element.ReactivePower * hours * 1000
+		if active >= 0 {
+			energy.ActiveImport += active
+		} else {
+			energy.ActiveExport -= active
+		}
+		if reactive >= 0 {
+			energy.ReactiveImport += reactive
+		} else {
+			energy.ReactiveExport -= reactive
+		}
+	}
+}
+
+// Energy intoarce indexurile elementului, zero pentru un element necunoscut.
+func (a *Accumulator) Energy(id string) Energy {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if energy, exists := a.energy[id]; exists {
+		return *energy
+	}
+	return Energy{}
+}
@@ -0,0 +1,72 @@
+package metering
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"contor-system/src/utils"
+)
+
+func step(p float64, q float64, energized bool) utils.SystemResult {
+	return utils.SystemResult{
+		Order: []string{"consumer1"},
+		Elements: map[string]*utils.ElementResult{
+			"consumer1": {ID: "consumer1", ActivePower: p, ReactivePower: q, Energized: energized},
+		},
+	}
+}
+
+func TestEnergyAccumulatesOverSteps(t *testing.T) {
+	a := NewAccumulator()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Primul pas doar porneste integrarea; puterea pasului curent se considera constanta de la pasul anterior
+	a.Update(step(2, 0.5, true), start)
+	a.Update(step(2, 0.5, true), start.Add(30*time.Minute))
+	a.Update(step(-1, -0.2, true), start.Add(90*time.Minute))
+	// Un element fara tensiune nu inregistreaza energie
+	a.Update(step(5, 1, false), start.Add(150*time.Minute))
+
+	expected := Energy{ActiveImport: 1000, ActiveExport: 1000, ReactiveImport: 250, ReactiveExport: 200}
+	got := a.Energy("consumer1")
+	for _, c := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"active import", got.ActiveImport, expected.ActiveImport},
+		{"active export", got.ActiveExport, expected.ActiveExport},
+		{"reactive import", got.ReactiveImport, expected.ReactiveImport},
+		{"reactive export", got.ReactiveExport, expected.ReactiveExport},
+	} {
+		if math.Abs(c.got-c.want) > 1e-9 {
+			t.Fatalf("%s %.4f kWh, expected %.4f", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestMeterRegistersFollowTheAccumulator(t *testing.T) {
+	system := utils.System{Consumers: []utils.Consumer{{ID: "consumer1"}}}
+	a := NewAccumulator()
+	meters := NewMeters(15*time.Minute, 0)
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for k := 0; k <= 4; k++ {
+		now := start.Add(time.Duration(k) * 15 * time.Minute)
+		a.Update(step(4, 0, true), now)
+		meters.Update(system, step(4, 0, true), a, now)
+	}
+
+	meter, exists := meters.Meter(1)
+	if !exists {
+		t.Fatal("no meter at address 1")
+	}
+	// 4 MW timp de o ora: 4 MWh = 4e6 Wh
+	if register, _ := meter.Register(OBIS{1, 0, 1, 8, 0, 255}); math.Abs(register.Value-4e6) > 1e-3 {
+		t.Fatalf("active energy import %.1f Wh, expected 4e6", register.Value)
+	}
+	// Curba de sarcina captureaza indexul la sfarsitul fiecarei perioade, dupa prima
+	if len(meter.Profile) != 4 || math.Abs(meter.Profile[3].Values[0]-4e6) > 1e-3 || math.Abs(meter.Profile[0].Values[0]-1e6) > 1e-3 {
+		t.Fatalf("profile %+v", meter.Profile)
+	}
+}
@@ -0,0 +1,235 @@
+package metering
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"contor-system/src/utils"
+)
+
+// Modelul de date al contoarelor, dupa DLMS/COSEM: fiecare punct de masura (consumator sau transformator de masura)
+// are valori instantanee, registre de energie si o curba de sarcina, identificate prin coduri OBIS.
+
+// Unit este codul unitatii de masura din DLMS (IEC 62056-62).
+type Unit uint8
+
+const (
+	UnitWatt     Unit = 27
+	UnitVar      Unit = 29
+	UnitWattHour Unit = 30
+	UnitVarHour  Unit = 32
+	UnitAmpere   Unit = 33
+	UnitVolt     Unit = 35
+	UnitHertz    Unit = 44
+	UnitUnitless Unit = 255
+)
+
+// Clasele COSEM folosite
+const (
+	ClassData           = 1
+	ClassRegister       = 3
+	ClassProfileGeneric = 7
+	ClassClock          = 8
+)
+
+// Coduri OBIS ale obiectelor fiecarui contor
+var (
+	LogicalDeviceName = OBIS{0, 0, 42, 0, 0, 255}
+	ClockOBIS         = OBIS{0, 0, 1, 0, 0, 255}
+	LoadProfileOBIS   = OBIS{1, 0, 99, 1, 0, 255}
+)
+
+// Register este un obiect de clasa Register: valoarea in unitati SI si scaler-ul folosit la codificare (valoare = intreg * 10^scaler).
+type Register struct {
+	OBIS   OBIS
+	Name   string
+	Value  float64
+	Scaler int8
+	Unit   Unit
+	Energy bool // registru cumulativ, codificat fara semn pe 64 de biti
+}
+
+// registerDefinitions sunt registrele fiecarui contor; puterile sunt separate pe sensuri (import / export).
+var registerDefinitions = []Register{
+	{OBIS: OBIS{1, 0, 12, 7, 0, 255}, Name: "voltage", Scaler: -1, Unit: UnitVolt},
+	{OBIS: OBIS{1, 0, 11, 7, 0, 255}, Name: "current", Scaler: -2, Unit: UnitAmpere},
+	{OBIS: OBIS{1, 0, 1, 7, 0, 255}, Name: "activePowerImport", Scaler: 0, Unit: UnitWatt},
+	{OBIS: OBIS{1, 0, 2, 7, 0, 255}, Name: "activePowerExport", Scaler: 0, Unit: UnitWatt},
+	{OBIS: OBIS{1, 0, 3, 7, 0, 255}, Name: "reactivePowerImport", Scaler: 0, Unit: UnitVar},
+	{OBIS: OBIS{1, 0, 4, 7, 0, 255}, Name: "reactivePowerExport", Scaler: 0, Unit: UnitVar},
+	{OBIS: OBIS{1, 0, 13, 7, 0, 255}, Name: "powerFactor", Scaler: -3, Unit: UnitUnitless},
+	{OBIS: OBIS{1, 0, 14, 7, 0, 255}, Name: "frequency", Scaler: -2, Unit: UnitHertz},
+	{OBIS: OBIS{1, 0, 1, 8, 0, 255}, Name: "activeEnergyImport", Scaler: 0, Unit: UnitWattHour, Energy: true},
+	{OBIS: OBIS{1, 0, 2, 8, 0, 255}, Name: "activeEnergyExport", Scaler: 0, Unit: UnitWattHour, Energy: true},
+	{OBIS: OBIS{1, 0, 3, 8, 0, 255}, Name: "reactiveEnergyImport", Scaler: 0, Unit: UnitVarHour, Energy: true},
+	{OBIS: OBIS{1, 0, 4, 8, 0, 255}, Name: "reactiveEnergyExport", Scaler: 0, Unit: UnitVarHour, Energy: true},
+}
+
+// ProfileColumns sunt registrele capturate in curba de sarcina, dupa ceas.
+var ProfileColumns = []OBIS{
+	{1, 0, 1, 8, 0, 255},
+	{1, 0, 2, 8, 0, 255},
+	{1, 0, 3, 8, 0, 255},
+	{1, 0, 4, 8, 0, 255},
+}
+
+// ProfileEntry este o inregistrare a curbei de sarcina: momentul capturii si valorile coloanelor.
+type ProfileEntry struct {
+	Time   time.Time
+	Values []float64
+}
+
+// Meter este instantaneul unui contor.
+type Meter struct {
+	ID        string
+	Address   uint16 // adresa dispozitivului logic
+	Time      time.Time
+	Registers []Register
+	Profile   []ProfileEntry
+}
+
+// Register intoarce registrul cu codul OBIS dat.
+func (m Meter) Register(code OBIS) (Register, bool) {
+	for _, register := range m.Registers {
+		if register.OBIS == code {
+			return register, true
+		}
+	}
+	return Register{}, false
+}
+
+// MeteringPoints intoarce punctele de masura in ordinea din configuratie: consumatorii, apoi transformatoarele de masura.
+func MeteringPoints(system utils.System) []string {
+	var ids []string
+	for _, c := range system.Consumers {
+		ids = append(ids, c.ID)
+	}
+	for _, t := range system.Transformers {
+		if t.Type == utils.TransformerTypeMeasure {
+			ids = append(ids, t.ID)
+		}
+	}
+	return ids
+}
+
+// Meters pastreaza contoarele punctelor de masura si curbele lor de sarcina.
+type Meters struct {
+	mu      sync.RWMutex
+	period  time.Duration
+	depth   int
+	meters  map[string]*Meter
+	order   []string
+	capture time.Time // sfarsitul ultimei perioade capturate
+}
+
+// NewMeters construieste contoarele cu perioada de captura si numarul maxim de inregistrari ale curbei de sarcina.
+func NewMeters(period time.Duration, depth int) *Meters {
+	if period <= 0 {
+		period = 15 * time.Minute
+	}
+	if depth <= 0 {
+		depth = 2880
+	}
+	return &Meters{period: period, depth: depth, meters: map[string]*Meter{}}
+}
+
+// Period intoarce perioada de captura a curbei de sarcina.
+func (m *Meters) Period() time.Duration {
+	return m.period
+}
+
+func split(value float64) (float64, float64) {
+	if value >= 0 {
+		return value, 0
+	}
+	return 0, -value
+}
+
+// Update actualizeaza valorile instantanee si registrele de energie si captureaza curba de sarcina
+// la fiecare sfarsit de perioada (aliniat la ora exacta).
+func (m *Meters) Update(system utils.System, result utils.SystemResult, energy *Accumulator, now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ids := MeteringPoints(system)
+	meters := map[string]*Meter{}
+	for i, id := range ids {
+		meter, exists := m.meters[id]
+		if !exists {
+			meter = &Meter{ID: id}
+		}
+		meter.Address = uint16(i + 1)
+		meter.Time = now
+
+		var element utils.ElementResult
+		if e, exists := result.Elements[id]; exists {
+			element = *e
+		}
+		e := energy.Energy(id)
+		activeImport, activeExport := split(element.ActivePower * 1e6)
+		reactiveImport, reactiveExport := split(element.ReactivePower * 1e6)
+		frequency := 0.0
+		if element.Energized {
+			frequency = 50
+		}
+		values := map[string]float64{
+			"voltage":              element.Voltage * 1000,
+			"current":              math.Abs(element.Current),
+			"activePowerImport":    activeImport,
+			"activePowerExport":    activeExport,
+			"reactivePowerImport":  reactiveImport,
+			"reactivePowerExport":  reactiveExport,
+			"powerFactor":          element.PowerFactor,
+			"frequency":            frequency,
+			"activeEnergyImport":   e.ActiveImport * 1000,
+			"activeEnergyExport":   e.ActiveExport * 1000,
+			"reactiveEnergyImport": e.ReactiveImport * 1000,
+			"reactiveEnergyExport": e.ReactiveExport * 1000,
+		}
+		registers := make([]Register, len(registerDefinitions))
+		for k, definition := range registerDefinitions {
+			registers[k] = definition
+			registers[k].Value = values[definition.Name]
+		}
+		meter.Registers = registers
+
+		meters[id] = meter
+	}
+	m.meters = meters
+	m.order = ids
+
+	// Captura curbei de sarcina la trecerea intr-o perioada noua
+	end := now.Truncate(m.period)
+	if !m.capture.IsZero() && end.After(m.capture) {
+		for _, id := range ids {
+			meter := m.meters[id]
+			entry := ProfileEntry{Time: end}
+			for _, column := range ProfileColumns {
+				register, _ := meter.Register(column)
+				entry.Values = append(entry.Values, register.Value)
+			}
+			meter.Profile = append(meter.Profile, entry)
+			if len(meter.Profile) > m.depth {
+				meter.Profile = meter.Profile[len(meter.Profile)-m.depth:]
+			}
+		}
+	}
+	m.capture = end
+}
+
+// Meter intoarce o copie a contorului cu adresa logica data.
+func (m *Meters) Meter(address uint16) (Meter, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, id := range m.order {
+		if meter := m.meters[id]; meter.Address == address {
+			copied := *meter
+			copied.Registers = append([]Register(nil), meter.Registers...)
+			copied.Profile = append([]ProfileEntry(nil), meter.Profile...)
+			return copied, true
+		}
+	}
+	return Meter{}, false
+}
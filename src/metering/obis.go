@@ -0,0 +1,35 @@
+package metering
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Coduri OBIS (IEC 62056-61): A-B:C.D.E.F, sase octeti care identifica un obiect COSEM.
+
+type OBIS [6]byte
+
+// ParseOBIS citeste un cod OBIS scris ca A-B:C.D.E.F sau A.B.C.D.E.F; F lipsa inseamna 255.
+func ParseOBIS(s string) (OBIS, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == '-' || r == ':' || r == '.' })
+	if len(fields) == 5 {
+		fields = append(fields, "255")
+	}
+	if len(fields) != 6 {
+		return OBIS{}, fmt.Errorf("invalid OBIS code %q", s)
+	}
+	var code OBIS
+	for i, field := range fields {
+		value, err := strconv.ParseUint(field, 10, 8)
+		if err != nil {
+			return OBIS{}, fmt.Errorf("invalid OBIS code %q: %v", s, err)
+		}
+		code[i] = byte(value)
+	}
+	return code, nil
+}
+
+func (o OBIS) String() string {
+	return fmt.Sprintf("%d-%d:%d.%d.%d.%d", o[0], o[1], o[2], o[3], o[4], o[5])
+}
@@ -0,0 +1,234 @@
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"contor-system/src/computing"
+	"contor-system/src/utils"
+)
+
+// Metrici Prometheus in formatul text de expunere: marimile fiecarui element, pierderile, puterea nealimentata,
+// starea calculului, durata calculelor si reincarcarile configuratiei.
+
+// durationBuckets sunt limitele histogramei duratei de calcul: s.
+var durationBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+
+type Registry struct {
+	mu sync.Mutex
+
+	result   utils.SystemResult
+	unserved map[string]float64
+	observed bool
+
+	durationCounts []uint64 // numarul de observatii din fiecare interval al histogramei
+	durationSum    float64
+	durationCount  uint64
+
+	reloads      uint64
+	reloadErrors uint64
+}
+
+func NewRegistry() *Registry {
+	return &Registry{durationCounts: make([]uint64, len(durationBuckets))}
+}
+
+// Observe inregistreaza rezultatul si durata unui pas de calcul.
+func (r *Registry) Observe(system utils.System, result utils.SystemResult, duration time.Duration) {
+	unserved := computing.UnservedPower(system, result)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.result = result
+	r.unserved = unserved
+	r.observed = true
+
+	seconds := duration.Seconds()
+	for i, bound := range durationBuckets {
+		if seconds <= bound {
+			r.durationCounts[i]++
+		}
+	}
+	r.durationSum += seconds
+	r.durationCount++
+}
+
+// ConfigReloaded numara o reincarcare a configuratiei modificate sau o citire esuata.
+func (r *Registry) ConfigReloaded(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		r.reloadErrors++
+	} else {
+		r.reloads++
+	}
+}
+
+func escape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func number(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func boolean(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// family scrie antetul unei metrici.
+func family(w io.Writer, name string, kind string, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// Write scrie toate metricile in formatul text Prometheus. Textul se construieste sub mu si se scrie dupa
+// eliberarea lui, ca un client lent sa nu blocheze ciclul de calcul.
+func (r *Registry) Write(w io.Writer) error {
+	var b bytes.Buffer
+	r.render(&b)
+	_, err := b.WriteTo(w)
+	return err
+}
+
+// render construieste textul metricilor.
+func (r *Registry) render(w io.Writer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.observed {
+		elements := []struct {
+			name  string
+			help  string
+			value func(*utils.ElementResult) float64
+		}{
+			{"contor_element_active_power_megawatts", "Active power flowing through the element.", func(e *utils.ElementResult) float64 { return e.ActivePower }},
+			{"contor_element_reactive_power_megavars", "Reactive power flowing through the element.", func(e *utils.ElementResult) float64 { return e.ReactivePower }},
+			{"contor_element_current_amperes", "Current through the element.", func(e *utils.ElementResult) float64 { return e.Current }},
+			{"contor_element_voltage_kilovolts", "Voltage at the element.", func(e *utils.ElementResult) float64 { return e.Voltage }},
+			{"contor_element_voltage_per_unit", "Voltage at the element in per unit.", func(e *utils.ElementResult) float64 { return e.VoltagePU }},
+			{"contor_element_loading_percent", "Loading of the element relative to its rating.", func(e *utils.ElementResult) float64 { return e.Loading }},
+			{"contor_element_active_power_losses_megawatts", "Active power losses of the element.", func(e *utils.ElementResult) float64 { return e.ActivePowerLosses }},
+			{"contor_element_energized", "Whether the element is energized (1) or not (0).", func(e *utils.ElementResult) float64 { return boolean(e.Energized) }},
+		}
+		for _, metric := range elements {
+			family(w, metric.name, "gauge", metric.help)
+			for _, id := range r.result.Order {
+				element := r.result.Elements[id]
+				fmt.Fprintf(w, "%s{element=\"%s\",kind=\"%s\"} %s\n", metric.name, escape(id), escape(element.Kind), number(metric.value(element)))
+			}
+		}
+
+		family(w, "contor_system_active_power_losses_megawatts", "gauge", "Total active power losses of the system.")
+		fmt.Fprintf(w, "contor_system_active_power_losses_megawatts %s\n", number(r.result.ActivePowerLosses))
+		family(w, "contor_system_reactive_power_losses_megavars", "gauge", "Total reactive power losses of the system.")
+		fmt.Fprintf(w, "contor_system_reactive_power_losses_megavars %s\n", number(r.result.ReactivePowerLosses))
+
+		family(w, "contor_consumer_unserved_power_megawatts", "gauge", "Power demand of the consumer that is not supplied.")
+		consumers := make([]string, 0, len(r.unserved))
+		for id := range r.unserved {
+			consumers = append(consumers, id)
+		}
+		sort.Strings(consumers)
+		for _, id := range consumers {
+			fmt.Fprintf(w, "contor_consumer_unserved_power_megawatts{consumer=\"%s\"} %s\n", escape(id), number(r.unserved[id]))
+		}
+
+		engine := string(r.result.Engine)
+		if engine == "" {
+			engine = string(utils.EngineTraversal)
+		}
+		family(w, "contor_solver_iterations", "gauge", "Iterations of the last power flow calculation (0 for the traversal engine).")
+		fmt.Fprintf(w, "contor_solver_iterations{engine=\"%s\"} %d\n", escape(engine), r.result.Iterations)
+		family(w, "contor_solver_converged", "gauge", "Whether the last power flow calculation converged.")
+		fmt.Fprintf(w, "contor_solver_converged{engine=\"%s\"} %s\n", escape(engine), number(boolean(r.result.Converged || r.result.Iterations == 0)))
+	}
+
+	family(w, "contor_compute_duration_seconds", "histogram", "Duration of the power flow calculation of each step.")
+	for i, bound := range durationBuckets {
+		fmt.Fprintf(w, "contor_compute_duration_seconds_bucket{le=\"%s\"} %d\n", number(bound), r.durationCounts[i])
+	}
+	fmt.Fprintf(w, "contor_compute_duration_seconds_bucket{le=\"+Inf\"} %d\n", r.durationCount)
+	fmt.Fprintf(w, "contor_compute_duration_seconds_sum %s\n", number(r.durationSum))
+	fmt.Fprintf(w, "contor_compute_duration_seconds_count %d\n", r.durationCount)
+
+	family(w, "contor_config_reloads_total", "counter", "Configuration reloads by result.")
+	fmt.Fprintf(w, "contor_config_reloads_total{result=\"success\"} %d\n", r.reloads)
+	fmt.Fprintf(w, "contor_config_reloads_total{result=\"failure\"} %d\n", r.reloadErrors)
+}
+
+// ServeHTTP raspunde cererilor Prometheus.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	r.Write(w)
+}
+
+// Server expune registrul pe HTTP.
+type Server struct {
+	settings utils.Metrics
+	registry *Registry
+	listener net.Listener
+	server   *http.Server
+	logger   *log.Logger
+}
+
+func NewServer(settings utils.Metrics, registry *Registry, logger *log.Logger) *Server {
+	if logger == nil {
+		logger = log.Default()
+	}
+	if settings.Address == "" {
+		settings.Address = ":9464"
+	}
+	if settings.Path == "" {
+		settings.Path = "/metrics"
+	}
+	return &Server{settings: settings, registry: registry, logger: logger}
+}
+
+// Start deschide portul si serveste cererile in fundal.
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.settings.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %v", s.settings.Address, err)
+	}
+	s.listener = listener
+
+	mux := http.NewServeMux()
+	mux.Handle(s.settings.Path, s.registry)
+	s.server = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second, WriteTimeout: 10 * time.Second, ErrorLog: s.logger}
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Printf("Metrics server stopped: %v", err)
+		}
+	}()
+	return nil
+}
+
+// Addr intoarce adresa pe care asculta serverul.
+func (s *Server) Addr() net.Addr {
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+// Close opreste serverul HTTP.
+func (s *Server) Close() error {
+	if s.server == nil {
+		return nil
+	}
+	return s.server.Close()
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"contor-system/src/utils"
+)
+
+func TestServeMetrics(t *testing.T) {
+	registry := NewRegistry()
+	registry.Observe(utils.System{}, utils.SystemResult{
+		Order:    []string{"line1"},
+		Elements: map[string]*utils.ElementResult{"line1": {ID: "line1", Kind: "line", Loading: 42, Energized: true}},
+	}, 3*time.Millisecond)
+
+	server := NewServer(utils.Metrics{Address: "127.0.0.1:0"}, registry, nil)
+	if err := server.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	response, err := http.Get("http://" + server.Addr().String() + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	body, _ := io.ReadAll(response.Body)
+	for _, expected := range []string{
+		`contor_element_loading_percent{element="line1",kind="line"} 42`,
+		`contor_compute_duration_seconds_count 1`,
+	} {
+		if !strings.Contains(string(body), expected) {
+			t.Fatalf("missing %q in:\n%s", expected, body)
+		}
+	}
+}
+
+func TestStartReportsBindError(t *testing.T) {
+	first := NewServer(utils.Metrics{Address: "127.0.0.1:0"}, NewRegistry(), nil)
+	if err := first.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+
+	second := NewServer(utils.Metrics{Address: first.Addr().String()}, NewRegistry(), nil)
+	if err := second.Start(); err == nil {
+		second.Close()
+		t.Fatal("a second server started on the same address")
+	}
+}
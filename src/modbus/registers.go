@@ -0,0 +1,185 @@
+package modbus
+
+import (
+	"fmt"
+	"math"
+
+	"contor-system/src/metering"
+	"contor-system/src/utils"
+)
+
+// Harta registrelor: fiecare marime configurata a unui element ocupa unul sau doua registre de 16 biti.
+
+// defaultQuantities sunt marimile din harta implicita, fiecare pe doua registre float32, in ordinea offseturilor.
+var defaultQuantities = []string{
+	"voltage",
+	"current",
+	"activePower",
+	"reactivePower",
+	"powerFactor",
+	"loading",
+	"voltagePU",
+	"activeEnergyImport",
+	"activeEnergyExport",
+	"reactiveEnergyImport",
+	"reactiveEnergyExport",
+	"energized",
+}
+
+// blockSize este numarul de registre rezervate fiecarui element in harta implicita.
+const blockSize = 32
+
+// registerSpace este numarul de adrese ale unui tabel Modbus: 0..65535.
+const registerSpace = 1 << 16
+
+// elementIDs intoarce elementele sistemului in ordinea din configuratie.
+func elementIDs(system utils.System) []string {
+	ids := []string{system.Source.ID}
+	for _, t := range system.Transformers {
+		ids = append(ids, t.ID)
+	}
+	for _, l := range system.Lines {
+		ids = append(ids, l.ID)
+	}
+	for _, s := range system.Separators {
+		ids = append(ids, s.ID)
+	}
+	for _, c := range system.Consumers {
+		ids = append(ids, c.ID)
+	}
+	for _, s := range system.AdditionalSources {
+		ids = append(ids, s.ID)
+	}
+	for _, b := range system.Batteries {
+		ids = append(ids, b.ID)
+	}
+	return ids
+}
+
+// DefaultRegisters construieste harta implicita: elementul i incepe la adresa i*32, in ambele tabele.
+// Harta cuprinde cel mult 2048 de elemente; pentru cele care nu mai incap intoarce si o eroare.
+func DefaultRegisters(system utils.System) ([]utils.ModbusRegister, error) {
+	var registers []utils.ModbusRegister
+	ids := elementIDs(system)
+	for i, id := range ids {
+		if (i+1)*blockSize > registerSpace {
+			return registers, fmt.Errorf("the default map holds %d elements, %d of %d do not fit: configure the registers explicitly", registerSpace/blockSize, len(ids)-i, len(ids))
+		}
+		for k, quantity := range defaultQuantities {
+			address := uint16(i*blockSize + 2*k)
+			for _, table := range []utils.ModbusTable{utils.ModbusTableInput, utils.ModbusTableHolding} {
+				registers = append(registers, utils.ModbusRegister{Element: id, Quantity: quantity, Table: table, Address: address, Type: utils.ModbusFloat32})
+			}
+		}
+	}
+	return registers, nil
+}
+
+// quantity intoarce valoarea marimii pentru element: kV, A, MW, MVAr, %, kWh, kvarh.
+func quantity(name string, element *utils.ElementResult, energy metering.Energy) (float64, error) {
+	switch name {
+	case "voltage":
+		return element.Voltage, nil
+	case "voltagePU":
+		return element.VoltagePU, nil
+	case "current":
+		return element.Current, nil
+	case "activePower":
+		return element.ActivePower, nil
+	case "reactivePower":
+		return element.ReactivePower, nil
+	case "powerFactor":
+		return element.PowerFactor, nil
+	case "loading":
+		return element.Loading, nil
+	case "energized":
+		if element.Energized {
+			return 1, nil
+		}
+		return 0, nil
+	case "activeEnergyImport":
+		return energy.ActiveImport, nil
+	case "activeEnergyExport":
+		return energy.ActiveExport, nil
+	case "reactiveEnergyImport":
+		return energy.ReactiveImport, nil
+	case "reactiveEnergyExport":
+		return energy.ReactiveExport, nil
+	default:
+		return 0, fmt.Errorf("unknown quantity %q", name)
+	}
+}
+
+// encode codifica valoarea in registre, cu cuvantul superior primul; valorile intregi sunt rotunjite si limitate la domeniul tipului.
+func encode(value float64, register utils.ModbusRegister) []uint16 {
+	scale := register.Scale
+	if scale == 0 {
+		scale = 1
+	}
+	value *= scale
+	clamp := func(min float64, max float64) float64 {
+		return math.Max(min, math.Min(max, math.Round(value)))
+	}
+
+	switch register.Type {
+	case utils.ModbusInt16:
+		return []uint16{uint16(int16(clamp(math.MinInt16, math.MaxInt16)))}
+	case utils.ModbusUint16:
+		return []uint16{uint16(clamp(0, math.MaxUint16))}
+	case utils.ModbusInt32:
+		v := uint32(int32(clamp(math.MinInt32, math.MaxInt32)))
+		return []uint16{uint16(v >> 16), uint16(v)}
+	case utils.ModbusUint32:
+		v := uint32(clamp(0, math.MaxUint32))
+		return []uint16{uint16(v >> 16), uint16(v)}
+	default:
+		v := math.Float32bits(float32(value))
+		return []uint16{uint16(v >> 16), uint16(v)}
+	}
+}
+
+// buildTables calculeaza continutul tabelelor de registre pentru rezultatul dat.
+// Elementele care lipsesc din rezultat (neparcurse) au toate marimile zero.
+func buildTables(registers []utils.ModbusRegister, result utils.SystemResult, energy *metering.Accumulator) (map[utils.ModbusTable]map[uint16]uint16, []error) {
+	tables := map[utils.ModbusTable]map[uint16]uint16{
+		utils.ModbusTableHolding: {},
+		utils.ModbusTableInput:   {},
+	}
+	var errs []error
+
+	for _, register := range registers {
+		table := register.Table
+		if table == "" {
+			table = utils.ModbusTableInput
+		}
+		if _, exists := tables[table]; !exists {
+			errs = append(errs, fmt.Errorf("register %s/%s: unknown table %q", register.Element, register.Quantity, table))
+			continue
+		}
+
+		element, exists := result.Elements[register.Element]
+		if !exists {
+			element = &utils.ElementResult{ID: register.Element}
+		}
+		var e metering.Energy
+		if energy != nil {
+			e = energy.Energy(register.Element)
+		}
+		value, err := quantity(register.Quantity, element, e)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("register %s at %d: %v", register.Element, register.Address, err))
+			continue
+		}
+
+		words := encode(value, register)
+		if int(register.Address)+len(words) > registerSpace {
+			errs = append(errs, fmt.Errorf("register %s/%s at %d: %d registers do not fit below address %d", register.Element, register.Quantity, register.Address, len(words), registerSpace))
+			continue
+		}
+		for k, word := range words {
+			tables[table][register.Address+uint16(k)] = word
+		}
+	}
+
+	return tables, errs
+}
@@ -0,0 +1,223 @@
+package modbus
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sync"
+
+	"contor-system/src/metering"
+	"contor-system/src/utils"
+)
+
+// Server Modbus TCP care expune valorile calculate ca registre de masura. Registrele sunt doar citite:
+// functiile 3 (holding) si 4 (input); scrierile primesc exceptia 01.
+
+const (
+	functionReadHolding = 0x03
+	functionReadInput   = 0x04
+
+	exceptionIllegalFunction    = 0x01
+	exceptionIllegalDataAddress = 0x02
+	exceptionIllegalDataValue   = 0x03
+
+	maxReadQuantity = 125
+	headerSize      = 7 // antetul MBAP: tranzactie, protocol, lungime, unit id
+)
+
+type Server struct {
+	settings utils.Modbus
+	listener net.Listener
+
+	mu     sync.RWMutex
+	tables map[utils.ModbusTable]map[uint16]uint16
+	conns  map[net.Conn]struct{}
+	wg     sync.WaitGroup
+	logger *log.Logger
+}
+
+func NewServer(settings utils.Modbus, logger *log.Logger) *Server {
+	if settings.Address == "" {
+		settings.Address = ":5020"
+	}
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &Server{
+		settings: settings,
+		tables:   map[utils.ModbusTable]map[uint16]uint16{},
+		conns:    map[net.Conn]struct{}{},
+		logger:   logger,
+	}
+}
+
+// Start deschide portul si accepta conexiunile in fundal.
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.settings.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %v", s.settings.Address, err)
+	}
+	s.listener = listener
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				if !errors.Is(err, net.ErrClosed) {
+					s.logger.Printf("Modbus server stopped accepting connections: %v", err)
+				}
+				return
+			}
+			s.mu.Lock()
+			s.conns[conn] = struct{}{}
+			s.mu.Unlock()
+
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				s.serve(conn)
+			}()
+		}
+	}()
+	return nil
+}
+
+// Addr intoarce adresa pe care asculta serverul.
+func (s *Server) Addr() net.Addr {
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+// Close opreste serverul si inchide conexiunile deschise.
+func (s *Server) Close() error {
+	if s.listener == nil {
+		return nil
+	}
+	err := s.listener.Close()
+	s.mu.Lock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return err
+}
+
+// Update recalculeaza registrele din rezultatul unui pas de calcul.
+func (s *Server) Update(system utils.System, result utils.SystemResult, energy *metering.Accumulator) error {
+	registers := s.settings.Registers
+	var mapErr error
+	if len(registers) == 0 {
+		registers, mapErr = DefaultRegisters(system)
+	}
+	tables, errs := buildTables(registers, result, energy)
+	if mapErr != nil {
+		errs = append(errs, mapErr)
+	}
+
+	s.mu.Lock()
+	s.tables = tables
+	s.mu.Unlock()
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid modbus registers: %v", errs)
+	}
+	return nil
+}
+
+// serve trateaza cererile unei conexiuni pana la inchiderea ei.
+func (s *Server) serve(conn net.Conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	header := make([]byte, headerSize)
+	for {
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+		protocol := binary.BigEndian.Uint16(header[2:4])
+		length := int(binary.BigEndian.Uint16(header[4:6]))
+		if protocol != 0 || length < 2 || length > 254 {
+			return
+		}
+		pdu := make([]byte, length-1)
+		if _, err := io.ReadFull(conn, pdu); err != nil {
+			return
+		}
+
+		unit := header[6]
+		if s.settings.UnitID != 0 && unit != s.settings.UnitID {
+			continue
+		}
+
+		response := s.respond(pdu)
+		frame := make([]byte, headerSize+len(response))
+		copy(frame, header[:4])
+		binary.BigEndian.PutUint16(frame[4:6], uint16(len(response)+1))
+		frame[6] = unit
+		copy(frame[headerSize:], response)
+		if _, err := conn.Write(frame); err != nil {
+			return
+		}
+	}
+}
+
+func exception(function byte, code byte) []byte {
+	return []byte{function | 0x80, code}
+}
+
+// respond intoarce raspunsul (PDU) la o cerere.
+func (s *Server) respond(pdu []byte) []byte {
+	function := pdu[0]
+
+	var table utils.ModbusTable
+	switch function {
+	case functionReadHolding:
+		table = utils.ModbusTableHolding
+	case functionReadInput:
+		table = utils.ModbusTableInput
+	default:
+		return exception(function, exceptionIllegalFunction)
+	}
+
+	if len(pdu) != 5 {
+		return exception(function, exceptionIllegalDataValue)
+	}
+	start := int(binary.BigEndian.Uint16(pdu[1:3]))
+	quantity := int(binary.BigEndian.Uint16(pdu[3:5]))
+	if quantity < 1 || quantity > maxReadQuantity {
+		return exception(function, exceptionIllegalDataValue)
+	}
+	if start+quantity > 0x10000 {
+		return exception(function, exceptionIllegalDataAddress)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Golurile din harta se citesc ca zero; un interval fara niciun registru configurat este o adresa invalida
+	response := make([]byte, 2+2*quantity)
+	response[0] = function
+	response[1] = byte(2 * quantity)
+	mapped := false
+	for k := 0; k < quantity; k++ {
+		word, exists := s.tables[table][uint16(start+k)]
+		mapped = mapped || exists
+		binary.BigEndian.PutUint16(response[2+2*k:], word)
+	}
+	if !mapped {
+		return exception(function, exceptionIllegalDataAddress)
+	}
+	return response
+}
@@ -0,0 +1,229 @@
+package modbus
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"testing"
+	"time"
+
+	"contor-system/src/utils"
+)
+
+func testResult() utils.SystemResult {
+	return utils.SystemResult{
+		Order: []string{"line1"},
+		Elements: map[string]*utils.ElementResult{
+			"line1": {ID: "line1", Kind: "line", Voltage: 20.5, ActivePower: -12.5, Current: 361, Energized: true},
+		},
+	}
+}
+
+func testServer(t *testing.T, registers []utils.ModbusRegister) *Server {
+	t.Helper()
+	s := NewServer(utils.Modbus{Address: "127.0.0.1:0", Registers: registers}, nil)
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	if err := s.Update(utils.System{}, testResult(), nil); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// read trimite o cerere de citire si intoarce PDU-ul raspunsului.
+func read(t *testing.T, conn net.Conn, function byte, start uint16, quantity uint16) []byte {
+	t.Helper()
+	request := make([]byte, headerSize+5)
+	binary.BigEndian.PutUint16(request[0:2], 7) // tranzactia
+	binary.BigEndian.PutUint16(request[4:6], 6)
+	request[6] = 1
+	request[7] = function
+	binary.BigEndian.PutUint16(request[8:10], start)
+	binary.BigEndian.PutUint16(request[10:12], quantity)
+	if _, err := conn.Write(request); err != nil {
+		t.Fatal(err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if transaction := binary.BigEndian.Uint16(header[0:2]); transaction != 7 {
+		t.Fatalf("transaction %d, expected 7", transaction)
+	}
+	pdu := make([]byte, binary.BigEndian.Uint16(header[4:6])-1)
+	if _, err := io.ReadFull(conn, pdu); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return pdu
+}
+
+func dial(t *testing.T, s *Server) net.Conn {
+	t.Helper()
+	conn, err := net.Dial("tcp", s.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestEncode(t *testing.T) {
+	bits := math.Float32bits(20.5)
+	for _, c := range []struct {
+		register utils.ModbusRegister
+		value    float64
+		expected []uint16
+	}{
+		{utils.ModbusRegister{}, 20.5, []uint16{uint16(bits >> 16), uint16(bits)}},
+		{utils.ModbusRegister{Type: utils.ModbusInt16, Scale: 100}, -12.5, []uint16{uint16(0x10000 - 1250)}},
+		{utils.ModbusRegister{Type: utils.ModbusUint16}, -3, []uint16{0}},
+		{utils.ModbusRegister{Type: utils.ModbusUint16}, 70000, []uint16{math.MaxUint16}},
+		{utils.ModbusRegister{Type: utils.ModbusInt32, Scale: 1000}, 361, []uint16{0x0005, 0x8228}},
+		{utils.ModbusRegister{Type: utils.ModbusUint32}, 1e12, []uint16{0xffff, 0xffff}},
+	} {
+		words := encode(c.value, c.register)
+		if len(words) != len(c.expected) {
+			t.Fatalf("%+v: %v, expected %v", c.register, words, c.expected)
+		}
+		for k := range words {
+			if words[k] != c.expected[k] {
+				t.Fatalf("%+v: %#04x, expected %#04x", c.register, words, c.expected)
+			}
+		}
+	}
+}
+
+func TestDefaultRegisters(t *testing.T) {
+	system := utils.System{
+		Source: utils.Source{ID: "source1"},
+		Lines:  []utils.Line{{ID: "line1"}},
+	}
+	registers, err := DefaultRegisters(system)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addresses := map[string]uint16{}
+	for _, register := range registers {
+		if register.Table == utils.ModbusTableInput {
+			addresses[register.Element+"/"+register.Quantity] = register.Address
+		}
+	}
+	// Elementul al doilea incepe la 32; fiecare marime float32 ocupa doua registre
+	if address := addresses["line1/voltage"]; address != 32 {
+		t.Fatalf("line1 voltage at %d, expected 32", address)
+	}
+	if address := addresses["line1/energized"]; address != 32+2*11 {
+		t.Fatalf("line1 energized at %d, expected %d", address, 32+2*11)
+	}
+}
+
+func TestDefaultRegistersLimit(t *testing.T) {
+	// 2048 de elemente umplu exact cele 65536 de adrese; al 2049-lea ar incepe din nou de la 0
+	system := utils.System{Source: utils.Source{ID: "source1"}}
+	for i := 1; i < 2049; i++ {
+		system.Lines = append(system.Lines, utils.Line{ID: fmt.Sprintf("line%d", i)})
+	}
+	registers, err := DefaultRegisters(system)
+	if err == nil {
+		t.Fatal("a map past address 65535 was accepted")
+	}
+	last := registers[len(registers)-1]
+	if last.Element != "line2047" || last.Address != 2047*32+2*11 {
+		t.Fatalf("last register %+v, expected line2047 energized at %d", last, 2047*32+2*11)
+	}
+	for _, register := range registers {
+		if register.Element == "source1" && register.Quantity == "voltage" && register.Address != 0 {
+			t.Fatalf("source1 voltage moved to %d", register.Address)
+		}
+	}
+
+	system.Lines = system.Lines[:2047]
+	if _, err := DefaultRegisters(system); err != nil {
+		t.Fatalf("2048 elements rejected: %v", err)
+	}
+}
+
+func TestRegisterPastLastAddress(t *testing.T) {
+	registers := []utils.ModbusRegister{
+		{Element: "line1", Quantity: "voltage", Address: 0},
+		{Element: "line1", Quantity: "current", Address: 65535},
+		{Element: "line1", Quantity: "loading", Address: 65535, Type: utils.ModbusUint16},
+	}
+	result := utils.SystemResult{Elements: map[string]*utils.ElementResult{"line1": {Voltage: 20, Current: 100, Loading: 50}}}
+	tables, errs := buildTables(registers, result, nil)
+	if len(errs) != 1 {
+		t.Fatalf("errors %v, expected one for the float32 at 65535", errs)
+	}
+	// Registrul float32 de la 65535 nu suprascrie adresa 0
+	if tables[utils.ModbusTableInput][0] != uint16(math.Float32bits(20)>>16) {
+		t.Fatalf("address 0 holds %#04x", tables[utils.ModbusTableInput][0])
+	}
+	if tables[utils.ModbusTableInput][65535] != 50 {
+		t.Fatalf("address 65535 holds %d, expected 50", tables[utils.ModbusTableInput][65535])
+	}
+}
+
+func TestReadRegisters(t *testing.T) {
+	s := testServer(t, []utils.ModbusRegister{
+		{Element: "line1", Quantity: "voltage", Address: 0},
+		{Element: "line1", Quantity: "activePower", Table: utils.ModbusTableHolding, Address: 10, Type: utils.ModbusInt16, Scale: 10},
+	})
+	conn := dial(t, s)
+
+	pdu := read(t, conn, functionReadInput, 0, 2)
+	if pdu[0] != functionReadInput || pdu[1] != 4 {
+		t.Fatalf("response %x", pdu)
+	}
+	if voltage := math.Float32frombits(binary.BigEndian.Uint32(pdu[2:6])); voltage != 20.5 {
+		t.Fatalf("voltage %v, expected 20.5", voltage)
+	}
+
+	// Registrul 11 nu este configurat si se citeste ca zero
+	pdu = read(t, conn, functionReadHolding, 10, 2)
+	if power := int16(binary.BigEndian.Uint16(pdu[2:4])); power != -125 || binary.BigEndian.Uint16(pdu[4:6]) != 0 {
+		t.Fatalf("response %x, expected -125 then 0", pdu)
+	}
+}
+
+func TestReadExceptions(t *testing.T) {
+	s := testServer(t, []utils.ModbusRegister{{Element: "line1", Quantity: "voltage", Address: 0}})
+	conn := dial(t, s)
+
+	for _, c := range []struct {
+		function  byte
+		start     uint16
+		quantity  uint16
+		exception byte
+	}{
+		{0x06, 0, 1, exceptionIllegalFunction},
+		{functionReadInput, 100, 2, exceptionIllegalDataAddress},
+		{functionReadHolding, 0, 2, exceptionIllegalDataAddress}, // registrul este doar in tabelul input
+		{functionReadInput, 0, 0, exceptionIllegalDataValue},
+		{functionReadInput, 0, maxReadQuantity + 1, exceptionIllegalDataValue},
+	} {
+		pdu := read(t, conn, c.function, c.start, c.quantity)
+		if len(pdu) != 2 || pdu[0] != c.function|0x80 || pdu[1] != c.exception {
+			t.Fatalf("function %#02x at %d x %d: response %x, expected exception %d", c.function, c.start, c.quantity, pdu, c.exception)
+		}
+	}
+}
+
+func TestUpdateRejectsUnknownQuantity(t *testing.T) {
+	s := NewServer(utils.Modbus{Registers: []utils.ModbusRegister{
+		{Element: "line1", Quantity: "voltage", Address: 0},
+		{Element: "line1", Quantity: "bogus", Address: 2},
+	}}, nil)
+	if err := s.Update(utils.System{}, testResult(), nil); err == nil {
+		t.Fatal("unknown quantity accepted")
+	}
+	// Registrele valide se actualizeaza oricum
+	if _, exists := s.tables[utils.ModbusTableInput][0]; !exists {
+		t.Fatal("the valid register was not written")
+	}
+}
@@ -0,0 +1,201 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"contor-system/src/utils"
+)
+
+// Compararea a doua configuratii: elementele adaugate, eliminate si campurile modificate, cu numele din config.json.
+
+type ChangeKind string
+
+const (
+	ChangeAdded    ChangeKind = "added"
+	ChangeRemoved  ChangeKind = "removed"
+	ChangeModified ChangeKind = "modified"
+)
+
+type Change struct {
+	Kind    ChangeKind  `json:"kind"`
+	Section string      `json:"section"`           // ex. lines, consumers, source, solver
+	Element string      `json:"element,omitempty"` // id-ul elementului, gol pentru sectiunile fara lista
+	Field   string      `json:"field,omitempty"`   // campul modificat, ex. limits.maxLoading
+	Old     interface{} `json:"old,omitempty"`
+	New     interface{} `json:"new,omitempty"`
+}
+
+// sections intoarce sectiunile configuratiei ca valori JSON generice, in ordinea din config.json.
+func sections(system utils.System) ([]string, map[string]interface{}, error) {
+	content, err := json.Marshal(system)
+	if err != nil {
+		return nil, nil, err
+	}
+	values := map[string]interface{}{}
+	if err := json.Unmarshal(content, &values); err != nil {
+		return nil, nil, err
+	}
+
+	var order []string
+	systemType := reflect.TypeOf(system)
+	for i := 0; i < systemType.NumField(); i++ {
+		name := strings.Split(systemType.Field(i).Tag.Get("json"), ",")[0]
+		if _, exists := values[name]; exists {
+			order = append(order, name)
+		}
+	}
+	return order, values, nil
+}
+
+// byID indexeaza elementele unei liste dupa id, pastrand ordinea.
+func byID(list []interface{}) ([]string, map[string]interface{}) {
+	var ids []string
+	elements := map[string]interface{}{}
+	for i, item := range list {
+		id := fmt.Sprintf("#%d", i)
+		if object, ok := item.(map[string]interface{}); ok {
+			if value, ok := object["id"].(string); ok && value != "" {
+				id = value
+			}
+		}
+		ids = append(ids, id)
+		elements[id] = item
+	}
+	return ids, elements
+}
+
+// fields compara doua valori si intoarce campurile diferite, cu calea lor.
+func fields(path string, old interface{}, new interface{}) []Change {
+	oldObject, oldIsObject := old.(map[string]interface{})
+	newObject, newIsObject := new.(map[string]interface{})
+	if oldIsObject && newIsObject {
+		keys := map[string]bool{}
+		for key := range oldObject {
+			keys[key] = true
+		}
+		for key := range newObject {
+			keys[key] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for key := range keys {
+			sorted = append(sorted, key)
+		}
+		sort.Strings(sorted)
+
+		var changes []Change
+		for _, key := range sorted {
+			field := key
+			if path != "" {
+				field = path + "." + key
+			}
+			changes = append(changes, fields(field, oldObject[key], newObject[key])...)
+		}
+		return changes
+	}
+	if reflect.DeepEqual(old, new) {
+		return nil
+	}
+	return []Change{{Kind: ChangeModified, Field: path, Old: old, New: new}}
+}
+
+// Diff intoarce diferentele dintre doua configuratii.
+func Diff(a utils.System, b utils.System) ([]Change, error) {
+	order, oldSections, err := sections(a)
+	if err != nil {
+		return nil, err
+	}
+	newOrder, newSections, err := sections(b)
+	if err != nil {
+		return nil, err
+	}
+	for _, section := range newOrder {
+		if _, exists := oldSections[section]; !exists {
+			order = append(order, section)
+		}
+	}
+
+	var changes []Change
+	for _, section := range order {
+		oldValue, newValue := oldSections[section], newSections[section]
+		oldList, oldIsList := oldValue.([]interface{})
+		newList, newIsList := newValue.([]interface{})
+		if !oldIsList && !newIsList {
+			if section == "source" {
+				if id, ok := oldValue.(map[string]interface{})["id"].(string); ok {
+					for _, change := range fields("", oldValue, newValue) {
+						change.Section, change.Element = section, id
+						changes = append(changes, change)
+					}
+					continue
+				}
+			}
+			for _, change := range fields("", oldValue, newValue) {
+				change.Section = section
+				changes = append(changes, change)
+			}
+			continue
+		}
+
+		oldIDs, oldElements := byID(oldList)
+		newIDs, newElements := byID(newList)
+		for _, id := range oldIDs {
+			newElement, exists := newElements[id]
+			if !exists {
+				changes = append(changes, Change{Kind: ChangeRemoved, Section: section, Element: id})
+				continue
+			}
+			for _, change := range fields("", oldElements[id], newElement) {
+				change.Section, change.Element = section, id
+				changes = append(changes, change)
+			}
+		}
+		for _, id := range newIDs {
+			if _, exists := oldElements[id]; !exists {
+				changes = append(changes, Change{Kind: ChangeAdded, Section: section, Element: id})
+			}
+		}
+	}
+	return changes, nil
+}
+
+func formatValue(value interface{}) string {
+	if value == nil {
+		return "-"
+	}
+	content, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(content)
+}
+
+// FormatDiff scrie diferentele ca lista text.
+func FormatDiff(changes []Change) string {
+	if len(changes) == 0 {
+		return "The configurations are identical\n"
+	}
+	var b strings.Builder
+	for _, change := range changes {
+		target := change.Section
+		if change.Element != "" {
+			target += " " + change.Element
+		}
+		switch change.Kind {
+		case ChangeAdded:
+			fmt.Fprintf(&b, "+ %s\n", target)
+		case ChangeRemoved:
+			fmt.Fprintf(&b, "- %s\n", target)
+		default:
+			if change.Field != "" {
+				target += " " + change.Field
+			}
+			fmt.Fprintf(&b, "~ %s: %s -> %s\n", target, formatValue(change.Old), formatValue(change.New))
+		}
+	}
+	fmt.Fprintf(&b, "%d changes\n", len(changes))
+	return b.String()
+}
@@ -0,0 +1,407 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"contor-system/src/utils"
+)
+
+// Verificarea unei configuratii inainte de calcul: identificatori, referinte connectedTo, valori numerice si
+// valori enumerate. Erorile fac calculul imposibil sau gresit, avertismentele semnaleaza date probabil incomplete.
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+type Issue struct {
+	Severity Severity `json:"severity"`
+	Element  string   `json:"element"` // gol pentru problemele sistemului
+	Message  string   `json:"message"`
+}
+
+// validator aduna problemele gasite.
+type validator struct {
+	issues []Issue
+}
+
+func (v *validator) errorf(element string, format string, args ...interface{}) {
+	v.issues = append(v.issues, Issue{Severity: SeverityError, Element: element, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) warnf(element string, format string, args ...interface{}) {
+	v.issues = append(v.issues, Issue{Severity: SeverityWarning, Element: element, Message: fmt.Sprintf(format, args...)})
+}
+
+// oneOf verifica o valoare enumerata; valoarea goala este acceptata cand campul are o valoare implicita.
+func (v *validator) oneOf(element string, field string, value string, allowEmpty bool, values ...string) {
+	if value == "" && allowEmpty {
+		return
+	}
+	for _, allowed := range values {
+		if value == allowed {
+			return
+		}
+	}
+	v.errorf(element, "%s %q is not one of %s", field, value, strings.Join(values, ", "))
+}
+
+// Validate intoarce problemele configuratiei, in ordinea elementelor.
+func Validate(system utils.System) []Issue {
+	v := &validator{}
+
+	// Identificatori unici
+	kinds := map[string]string{}
+	var order []string
+	register := func(id string, kind string) {
+		if id == "" {
+			v.errorf("", "a %s has no id", kind)
+			return
+		}
+		if previous, exists := kinds[id]; exists {
+			v.errorf(id, "id is used by a %s and a %s", previous, kind)
+			return
+		}
+		kinds[id] = kind
+		order = append(order, id)
+	}
+	register(system.Source.ID, "source")
+	for _, source := range system.AdditionalSources {
+		register(source.ID, "source")
+	}
+	for _, transformer := range system.Transformers {
+		register(transformer.ID, "transformer")
+	}
+	for _, line := range system.Lines {
+		register(line.ID, "line")
+	}
+	for _, consumer := range system.Consumers {
+		register(consumer.ID, "consumer")
+	}
+	for _, separator := range system.Separators {
+		register(separator.ID, "separator")
+	}
+	for _, shunt := range system.Shunts {
+		register(shunt.ID, "shunt")
+	}
+	for _, battery := range system.Batteries {
+		register(battery.ID, "battery")
+	}
+
+	// Referintele trebuie sa indice un element al retelei
+	reference := func(id string, field string, target string, required bool) {
+		if target == "" {
+			if required {
+				v.errorf(id, "%s is empty", field)
+			}
+			return
+		}
+		kind, exists := kinds[target]
+		if !exists {
+			v.errorf(id, "%s %q does not exist", field, target)
+			return
+		}
+		if kind == "shunt" || kind == "battery" {
+			v.errorf(id, "%s %q is a %s, which is not part of the connectedTo chain", field, target, kind)
+		}
+	}
+
+	validateSource(v, system.Source, true)
+	reference(system.Source.ID, "connectedTo", system.Source.ConnectedTo, true)
+	for _, source := range system.AdditionalSources {
+		validateSource(v, source, false)
+		reference(source.ID, "connectedTo", source.ConnectedTo, true)
+	}
+
+	for _, transformer := range system.Transformers {
+		id := transformer.ID
+		reference(id, "connectedTo", transformer.ConnectedTo, false)
+		v.oneOf(id, "type", string(transformer.Type), false, string(utils.TransformerTypePower), string(utils.TransformerTypeMeasure))
+		if transformer.InputVoltage <= 0 || transformer.OutputVoltage <= 0 {
+			v.errorf(id, "inputVoltage and outputVoltage must be positive")
+		}
+		if transformer.Type == utils.TransformerTypePower && transformer.ApparentPower <= 0 {
+			v.errorf(id, "apparentPower must be positive for a power transformer")
+		}
+		if transformer.Efficency < 0 || transformer.Efficency > 1 {
+			v.errorf(id, "efficency %.3f must be between 0 and 1", transformer.Efficency)
+		}
+		if transformer.Type == utils.TransformerTypePower && transformer.Uk == 0 {
+			v.warnf(id, "uk is not set: the short-circuit and sweep calculations use a default")
+		}
+		if tap := transformer.Tap; tap != nil {
+			if tap.MinPosition > tap.MaxPosition {
+				v.errorf(id, "tap minPosition %d is above maxPosition %d", tap.MinPosition, tap.MaxPosition)
+			} else if tap.Position < tap.MinPosition || tap.Position > tap.MaxPosition {
+				v.errorf(id, "tap position %d is outside %d..%d", tap.Position, tap.MinPosition, tap.MaxPosition)
+			}
+		}
+	}
+
+	for _, line := range system.Lines {
+		id := line.ID
+		reference(id, "connectedTo", line.ConnectedTo, false)
+		if line.Voltage <= 0 {
+			v.errorf(id, "voltage must be positive")
+		}
+		if line.Length <= 0 {
+			v.errorf(id, "length must be positive")
+		}
+		if line.Area <= 0 && line.R == 0 {
+			v.errorf(id, "area or r is needed to compute the line resistance")
+		}
+		if line.RatedCurrent == 0 {
+			v.warnf(id, "ratedCurrent is not set: the loading is not computed")
+		}
+	}
+
+	for _, consumer := range system.Consumers {
+		id := consumer.ID
+		reference(id, "connectedTo", consumer.ConnectedTo, false)
+		if consumer.PowerNeeded < 0 {
+			v.errorf(id, "powerNeeded must not be negative")
+		}
+		if consumer.Voltage <= 0 {
+			v.errorf(id, "voltage must be positive")
+		}
+		if consumer.PowerFactor < 0 || consumer.PowerFactor > 1 {
+			v.errorf(id, "powerFactor %.3f must be between 0 and 1", consumer.PowerFactor)
+		}
+		for _, phase := range consumer.Phases {
+			if !strings.ContainsRune("abc", phase) {
+				v.errorf(id, "phases %q may only contain a, b and c", consumer.Phases)
+				break
+			}
+		}
+		for phase := range consumer.PhaseLoads {
+			if phase != "a" && phase != "b" && phase != "c" {
+				v.errorf(id, "phaseLoads has an unknown phase %q", phase)
+			}
+		}
+		if len(consumer.PhaseLoads) > 0 {
+			v.warnf(id, "phaseLoads are used only by the unbalanced power flow (-unbalanced); the balanced engines apply the load model to powerNeeded and the reactive demand")
+		}
+		if model := consumer.LoadModel; model != nil {
+			v.oneOf(id, "loadModel.type", string(model.Type), false, string(utils.LoadModelConstantPower), string(utils.LoadModelZIP), string(utils.LoadModelExponential))
+		}
+	}
+
+	for _, separator := range system.Separators {
+		id := separator.ID
+		reference(id, "connectedTo", separator.ConnectedTo, true)
+		v.oneOf(id, "state", string(separator.State), false, string(utils.StateOpen), string(utils.StateClose))
+		v.oneOf(id, "type", string(separator.Type), true, string(utils.SeparatorTypeDisconnector), string(utils.SeparatorTypeLoadBreakSwitch), string(utils.SeparatorTypeBreaker))
+	}
+
+	for _, shunt := range system.Shunts {
+		id := shunt.ID
+		reference(id, "connectedTo", shunt.ConnectedTo, true)
+		v.oneOf(id, "type", string(shunt.Type), false, string(utils.ShuntTypeCapacitor), string(utils.ShuntTypeReactor))
+		v.oneOf(id, "control", string(shunt.Control), true, string(utils.ShuntControlVoltage), string(utils.ShuntControlPowerFactor))
+		if shunt.Steps <= 0 || shunt.StepSize <= 0 {
+			v.errorf(id, "steps and stepSize must be positive")
+		}
+		if shunt.Step < 0 || shunt.Step > shunt.Steps {
+			v.errorf(id, "step %d is outside 0..%d", shunt.Step, shunt.Steps)
+		}
+	}
+
+	for _, battery := range system.Batteries {
+		id := battery.ID
+		reference(id, "connectedTo", battery.ConnectedTo, true)
+		v.oneOf(id, "strategy", string(battery.Strategy), true, string(utils.BatteryStrategyFixed), string(utils.BatteryStrategyPeakShaving), string(utils.BatteryStrategySchedule), string(utils.BatteryStrategySelfConsumption))
+		if battery.Capacity <= 0 {
+			v.errorf(id, "capacity must be positive")
+		}
+		if battery.MinSOC > 0 && battery.MaxSOC > 0 && battery.MinSOC >= battery.MaxSOC {
+			v.errorf(id, "minSoc %.1f must be below maxSoc %.1f", battery.MinSOC, battery.MaxSOC)
+		}
+		if battery.MonitoredElement != "" {
+			reference(id, "monitoredElement", battery.MonitoredElement, false)
+		}
+	}
+
+	v.oneOf("", "solver.engine", string(system.Solver.Engine), true, string(utils.EngineTraversal), string(utils.EngineSweep))
+	if len(system.Batteries) > 0 && system.Solver.Engine != utils.EngineSweep {
+		v.warnf("", "batteries take part in the power flow only with the sweep engine")
+	}
+	if system.Daemon != nil {
+		if system.Daemon.Tick < 0 {
+			v.errorf("", "daemon.tick must not be negative")
+		}
+		v.oneOf("", "daemon.report", system.Daemon.Report, true, "text", "markdown", "html")
+		v.oneOf("", "daemon.diagram", system.Daemon.Diagram, true, "svg", "dot")
+	}
+
+	validateTopology(v, system, kinds, order)
+	return v.issues
+}
+
+func validateSource(v *validator, source utils.Source, main bool) {
+	id := source.ID
+	if source.Voltage <= 0 {
+		v.errorf(id, "voltage must be positive")
+	}
+	if source.Power < 0 {
+		v.errorf(id, "power must not be negative")
+	}
+	if source.MinReactivePower != 0 && source.MaxReactivePower != 0 && source.MinReactivePower > source.MaxReactivePower {
+		v.errorf(id, "minReactivePower %.2f is above maxReactivePower %.2f", source.MinReactivePower, source.MaxReactivePower)
+	}
+	if source.MaxPower > 0 && source.MinPower > source.MaxPower {
+		v.errorf(id, "minPower %.2f is above maxPower %.2f", source.MinPower, source.MaxPower)
+	}
+	if main && source.ShortCircuitPower == 0 {
+		v.warnf(id, "shortCircuitPower is not set: the source is an infinite bus for short-circuit calculations")
+	}
+}
+
+// validateTopology parcurge lantul connectedTo din sursa principala, ignorand starea separatoarelor, si semnaleaza
+// buclele si elementele la care nu se ajunge.
+func validateTopology(v *validator, system utils.System, kinds map[string]string, order []string) {
+	next := map[string][]string{}
+	add := func(id string, target string) {
+		if id != "" && target != "" {
+			next[id] = append(next[id], target)
+		}
+	}
+	add(system.Source.ID, system.Source.ConnectedTo)
+	for _, transformer := range system.Transformers {
+		add(transformer.ID, transformer.ConnectedTo)
+	}
+	for _, line := range system.Lines {
+		add(line.ID, line.ConnectedTo)
+	}
+	for _, consumer := range system.Consumers {
+		add(consumer.ID, consumer.ConnectedTo)
+	}
+	for _, separator := range system.Separators {
+		add(separator.ID, separator.ConnectedTo)
+	}
+	// Sursele aditionale si elementele racordate in nodul altui element sunt legate in ambele sensuri
+	linked := map[string][]string{}
+	for _, source := range system.AdditionalSources {
+		linked[source.ConnectedTo] = append(linked[source.ConnectedTo], source.ID)
+	}
+
+	reached := map[string]bool{}
+	var walk func(id string)
+	walk = func(id string) {
+		if reached[id] {
+			return
+		}
+		reached[id] = true
+		for _, target := range next[id] {
+			walk(target)
+		}
+		for _, source := range linked[id] {
+			walk(source)
+		}
+	}
+	walk(system.Source.ID)
+
+	// Elementele care se alimenteaza unul pe altul pe lantul connectedTo
+	state := map[string]int{} // 1: pe drumul curent, 2: terminat
+	var cycle func(id string) bool
+	cycle = func(id string) bool {
+		state[id] = 1
+		for _, target := range next[id] {
+			if state[target] == 1 {
+				v.errorf(id, "connectedTo %q closes a loop in the connectedTo chain", target)
+				return true
+			}
+			if state[target] == 0 && cycle(target) {
+				return true
+			}
+		}
+		state[id] = 2
+		return false
+	}
+	for _, id := range order {
+		if state[id] == 0 && kinds[id] != "source" {
+			cycle(id)
+		}
+	}
+
+	for _, group := range []struct {
+		kind string
+		ids  []string
+	}{
+		{"power transformer", powerTransformerIDs(system)},
+		{"line", lineIDs(system)},
+		{"consumer", consumerIDs(system)},
+		{"separator", separatorIDs(system)},
+	} {
+		for _, id := range group.ids {
+			if !reached[id] {
+				v.warnf(id, "%s is not reached from the main source %s", group.kind, system.Source.ID)
+			}
+		}
+	}
+}
+
+// powerTransformerIDs intoarce transformatoarele de putere; cele de masura sunt racordate in nodul elementului masurat.
+func powerTransformerIDs(system utils.System) []string {
+	var ids []string
+	for _, transformer := range system.Transformers {
+		if transformer.Type != utils.TransformerTypeMeasure {
+			ids = append(ids, transformer.ID)
+		}
+	}
+	return ids
+}
+
+func lineIDs(system utils.System) []string {
+	ids := make([]string, len(system.Lines))
+	for i, line := range system.Lines {
+		ids[i] = line.ID
+	}
+	return ids
+}
+
+func consumerIDs(system utils.System) []string {
+	ids := make([]string, len(system.Consumers))
+	for i, consumer := range system.Consumers {
+		ids[i] = consumer.ID
+	}
+	return ids
+}
+
+func separatorIDs(system utils.System) []string {
+	ids := make([]string, len(system.Separators))
+	for i, separator := range system.Separators {
+		ids[i] = separator.ID
+	}
+	return ids
+}
+
+// HasErrors verifica daca printre probleme exista erori.
+func HasErrors(issues []Issue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatIssues scrie problemele ca lista text.
+func FormatIssues(issues []Issue) string {
+	var b strings.Builder
+	errors := 0
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			errors++
+		}
+		element := issue.Element
+		if element == "" {
+			element = "system"
+		}
+		fmt.Fprintf(&b, "%-7s %s: %s\n", issue.Severity, element, issue.Message)
+	}
+	fmt.Fprintf(&b, "%d errors, %d warnings\n", errors, len(issues)-errors)
+	return b.String()
+}
@@ -0,0 +1,510 @@
+package mqtt
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Client MQTT 3.1.1 minimal: publicare cu QoS 0 si 1, abonare, keep-alive si reconectare cu pauza crescatoare.
+// Mesajele QoS 1 neconfirmate sunt retrimise (cu DUP) dupa reconectare.
+
+const (
+	packetConnect     = 1
+	packetConnAck     = 2
+	packetPublish     = 3
+	packetPubAck      = 4
+	packetSubscribe   = 8
+	packetSubAck      = 9
+	packetPingReq     = 12
+	packetPingResp    = 13
+	packetDisconnect  = 14
+	maxInflight       = 1000
+	messageQueue      = 64
+	connectTimeout    = 10 * time.Second
+	writeTimeout      = 5 * time.Second
+	protocolLevel311  = 4
+	cleanSessionFlag  = 0x02
+	usernameFlag      = 0x80
+	passwordFlag      = 0x40
+	publishRetainFlag = 0x01
+	publishDupFlag    = 0x08
+)
+
+// Options sunt parametrii conexiunii cu brokerul.
+type Options struct {
+	Broker       string // host:port
+	ClientID     string
+	Username     string
+	Password     string
+	KeepAlive    time.Duration
+	ReconnectMin time.Duration
+	ReconnectMax time.Duration
+	Logger       *log.Logger // implicit jurnalul standard
+}
+
+// Handler trateaza un mesaj primit pe un topic abonat.
+type Handler func(topic string, payload []byte)
+
+// ErrInflightFull este intoarsa de Publish cand prea multe mesaje QoS 1 asteapta confirmarea; mesajul nu este trimis.
+var ErrInflightFull = errors.New("too many unacknowledged messages")
+
+type subscription struct {
+	filter  string
+	qos     byte
+	handler Handler
+}
+
+// message este un PUBLISH primit, in asteptarea handlerelor.
+type message struct {
+	topic    string
+	payload  []byte
+	handlers []Handler
+}
+
+type Client struct {
+	options Options
+
+	mu            sync.Mutex
+	conn          net.Conn
+	connected     bool
+	nextID        uint16
+	inflight      map[uint16][]byte // PUBLISH QoS 1 asteptand PUBACK
+	inflightOrder []uint16
+	dropped       int // mesaje QoS 1 refuzate pentru ca inflight era plin
+	subscriptions []subscription
+
+	messages chan message // mesajele primite, tratate pe rand in ordinea sosirii
+	done     chan struct{}
+	wg       sync.WaitGroup
+}
+
+func NewClient(options Options) *Client {
+	options.Broker = strings.TrimPrefix(options.Broker, "tcp://")
+	if options.ClientID == "" {
+		options.ClientID = fmt.Sprintf("contor-%d", time.Now().UnixNano()%1000000)
+	}
+	if options.KeepAlive <= 0 {
+		options.KeepAlive = 30 * time.Second
+	}
+	if options.ReconnectMin <= 0 {
+		options.ReconnectMin = time.Second
+	}
+	if options.ReconnectMax < options.ReconnectMin {
+		options.ReconnectMax = 60 * time.Second
+	}
+	if options.Logger == nil {
+		options.Logger = log.Default()
+	}
+	return &Client{
+		options:  options,
+		inflight: map[uint16][]byte{},
+		messages: make(chan message, messageQueue),
+		done:     make(chan struct{}),
+	}
+}
+
+// Subscribe inregistreaza un abonament; se reface la fiecare reconectare.
+func (c *Client) Subscribe(filter string, qos byte, handler Handler) {
+	c.mu.Lock()
+	c.subscriptions = append(c.subscriptions, subscription{filter: filter, qos: qos, handler: handler})
+	connected := c.connected
+	c.mu.Unlock()
+	if connected {
+		c.sendSubscribe()
+	}
+}
+
+// Start porneste bucla de conectare si tratarea mesajelor primite in fundal.
+func (c *Client) Start() {
+	c.wg.Add(2)
+	go func() {
+		defer c.wg.Done()
+		c.run()
+	}()
+	go func() {
+		defer c.wg.Done()
+		c.handle()
+	}()
+}
+
+// handle apeleaza handlerele mesajelor primite, pe rand, astfel incat comenzile se executa in ordinea sosirii.
+func (c *Client) handle() {
+	for {
+		select {
+		case <-c.done:
+			return
+		case m := <-c.messages:
+			for _, handler := range m.handlers {
+				handler(m.topic, m.payload)
+			}
+		}
+	}
+}
+
+// Close deconecteaza clientul si opreste reconectarea.
+func (c *Client) Close() {
+	close(c.done)
+	c.mu.Lock()
+	if c.conn != nil {
+		c.writeLocked([]byte{packetDisconnect << 4, 0})
+		c.conn.Close()
+	}
+	c.mu.Unlock()
+	c.wg.Wait()
+}
+
+// Connected verifica daca sesiunea cu brokerul este activa.
+func (c *Client) Connected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.connected
+}
+
+// run se conecteaza la broker si, dupa fiecare pierdere a conexiunii, asteapta o pauza care se dubleaza pana la ReconnectMax.
+func (c *Client) run() {
+	backoff := c.options.ReconnectMin
+	for {
+		established, err := c.session()
+		select {
+		case <-c.done:
+			return
+		default:
+		}
+		if established {
+			backoff = c.options.ReconnectMin
+		}
+		c.options.Logger.Printf("MQTT connection to %s lost: %v; reconnecting in %s", c.options.Broker, err, backoff)
+		select {
+		case <-c.done:
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > c.options.ReconnectMax {
+			backoff = c.options.ReconnectMax
+		}
+	}
+}
+
+// session tine o conexiune pana la eroare si intoarce daca sesiunea fusese stabilita.
+func (c *Client) session() (bool, error) {
+	conn, err := net.DialTimeout("tcp", c.options.Broker, connectTimeout)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	conn.SetDeadline(time.Now().Add(connectTimeout))
+	if _, err := conn.Write(c.connectPacket()); err != nil {
+		return false, err
+	}
+	header, body, err := readPacket(reader)
+	if err != nil {
+		return false, err
+	}
+	if header>>4 != packetConnAck || len(body) < 2 {
+		return false, errors.New("expected CONNACK")
+	}
+	if body[1] != 0 {
+		return false, fmt.Errorf("connection refused by broker (return code %d)", body[1])
+	}
+	// De aici citirile au termenul keep-alive, iar fiecare scriere termenul writeTimeout
+	conn.SetDeadline(time.Time{})
+
+	c.mu.Lock()
+	c.conn = conn
+	c.connected = true
+	// Mesajele QoS 1 neconfirmate pleaca din nou, marcate ca duplicate
+	for _, id := range c.inflightOrder {
+		packet := c.inflight[id]
+		packet[0] |= publishDupFlag
+		c.writeLocked(packet)
+	}
+	c.mu.Unlock()
+	c.options.Logger.Printf("MQTT connected to %s", c.options.Broker)
+	c.sendSubscribe()
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go c.keepAlive(stop)
+
+	// Conexiunea se inchide inainte de blocarea mu, ca o scriere blocata sa se termine imediat
+	defer func() {
+		conn.Close()
+		c.mu.Lock()
+		c.connected = false
+		c.conn = nil
+		c.mu.Unlock()
+	}()
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(c.options.KeepAlive * 3 / 2))
+		header, body, err := readPacket(reader)
+		if err != nil {
+			return true, err
+		}
+		switch header >> 4 {
+		case packetPublish:
+			c.receive(header, body)
+		case packetPubAck:
+			if len(body) >= 2 {
+				c.acknowledge(binary.BigEndian.Uint16(body))
+			}
+		case packetSubAck:
+			if len(body) < 2 {
+				continue
+			}
+			for _, code := range body[2:] {
+				if code == 0x80 {
+					c.options.Logger.Printf("MQTT subscription refused by broker")
+				}
+			}
+		case packetPingResp:
+		}
+	}
+}
+
+// keepAlive trimite PINGREQ la fiecare interval de keep-alive.
+func (c *Client) keepAlive(stop <-chan struct{}) {
+	ticker := time.NewTicker(c.options.KeepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			c.mu.Lock()
+			c.writeLocked([]byte{packetPingReq << 4, 0})
+			c.mu.Unlock()
+		}
+	}
+}
+
+func (c *Client) connectPacket() []byte {
+	var body []byte
+	body = appendString(body, "MQTT")
+	flags := byte(cleanSessionFlag)
+	if c.options.Username != "" {
+		flags |= usernameFlag
+	}
+	if c.options.Password != "" {
+		flags |= passwordFlag
+	}
+	body = append(body, protocolLevel311, flags)
+	body = binary.BigEndian.AppendUint16(body, uint16(c.options.KeepAlive/time.Second))
+	body = appendString(body, c.options.ClientID)
+	if c.options.Username != "" {
+		body = appendString(body, c.options.Username)
+	}
+	if c.options.Password != "" {
+		body = appendString(body, c.options.Password)
+	}
+	return packet(packetConnect<<4, body)
+}
+
+func (c *Client) sendSubscribe() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.connected || len(c.subscriptions) == 0 {
+		return
+	}
+	body := binary.BigEndian.AppendUint16(nil, c.packetID())
+	for _, s := range c.subscriptions {
+		body = appendString(body, s.filter)
+		body = append(body, s.qos)
+	}
+	c.writeLocked(packet(packetSubscribe<<4|0x02, body))
+}
+
+// Publish trimite un mesaj. Cu QoS 1 mesajul este pastrat pana la confirmare si retrimis dupa reconectare;
+// cu QoS 0 mesajul se pierde daca clientul nu este conectat.
+func (c *Client) Publish(topic string, payload []byte, qos byte, retain bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if qos > 0 && len(c.inflightOrder) >= maxInflight {
+		c.dropped++
+		return ErrInflightFull
+	}
+
+	header := byte(packetPublish << 4)
+	if retain {
+		header |= publishRetainFlag
+	}
+	body := appendString(nil, topic)
+	var id uint16
+	if qos > 0 {
+		header |= 1 << 1
+		id = c.packetID()
+		body = binary.BigEndian.AppendUint16(body, id)
+	}
+	body = append(body, payload...)
+	p := packet(header, body)
+
+	if qos > 0 {
+		c.inflight[id] = p
+		c.inflightOrder = append(c.inflightOrder, id)
+	}
+	if !c.connected {
+		return errors.New("not connected")
+	}
+	return c.writeLocked(p)
+}
+
+// Dropped intoarce numarul de mesaje QoS 1 refuzate pentru ca prea multe asteptau confirmarea.
+func (c *Client) Dropped() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.dropped
+}
+
+func (c *Client) acknowledge(id uint16) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exists := c.inflight[id]; !exists {
+		return
+	}
+	delete(c.inflight, id)
+	for i, pending := range c.inflightOrder {
+		if pending == id {
+			c.inflightOrder = append(c.inflightOrder[:i], c.inflightOrder[i+1:]...)
+			break
+		}
+	}
+}
+
+// receive trateaza un PUBLISH primit: confirma QoS 1 si apeleaza handlerele abonamentelor potrivite.
+func (c *Client) receive(header byte, body []byte) {
+	if len(body) < 2 {
+		return
+	}
+	length := int(binary.BigEndian.Uint16(body))
+	if len(body) < 2+length {
+		return
+	}
+	topic := string(body[2 : 2+length])
+	rest := body[2+length:]
+	if qos := (header >> 1) & 0x03; qos > 0 {
+		if len(rest) < 2 {
+			return
+		}
+		c.mu.Lock()
+		c.writeLocked(packet(packetPubAck<<4, rest[:2]))
+		c.mu.Unlock()
+		rest = rest[2:]
+	}
+
+	c.mu.Lock()
+	var handlers []Handler
+	for _, s := range c.subscriptions {
+		if matches(s.filter, topic) {
+			handlers = append(handlers, s.handler)
+		}
+	}
+	c.mu.Unlock()
+	if len(handlers) == 0 {
+		return
+	}
+	select {
+	case c.messages <- message{topic: topic, payload: append([]byte(nil), rest...), handlers: handlers}:
+	case <-c.done:
+	}
+}
+
+// packetID intoarce urmatorul identificator de pachet (diferit de zero); se apeleaza cu mu blocat.
+func (c *Client) packetID() uint16 {
+	c.nextID++
+	if c.nextID == 0 {
+		c.nextID = 1
+	}
+	return c.nextID
+}
+
+// writeLocked scrie un pachet cu termenul writeTimeout; se apeleaza cu mu blocat. O scriere esuata inchide
+// conexiunea, iar sesiunea se reface prin reconectare.
+func (c *Client) writeLocked(p []byte) error {
+	if c.conn == nil {
+		return errors.New("not connected")
+	}
+	c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if _, err := c.conn.Write(p); err != nil {
+		c.conn.Close()
+		c.connected = false
+		return err
+	}
+	return nil
+}
+
+// matches verifica daca topicul corespunde filtrului (cu + pentru un nivel si # pentru restul nivelurilor).
+func matches(filter string, topic string) bool {
+	f := strings.Split(filter, "/")
+	t := strings.Split(topic, "/")
+	for i, level := range f {
+		if level == "#" {
+			return true
+		}
+		if i >= len(t) || (level != "+" && level != t[i]) {
+			return false
+		}
+	}
+	return len(f) == len(t)
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+	return append(b, s...)
+}
+
+// packet construieste un pachet: antetul fix, lungimea ramasa (codificare variabila, 7 biti pe octet) si corpul.
+func packet(header byte, body []byte) []byte {
+	p := []byte{header}
+	n := len(body)
+	for {
+		digit := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			digit |= 0x80
+		}
+		p = append(p, digit)
+		if n == 0 {
+			break
+		}
+	}
+	return append(p, body...)
+}
+
+func readPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	length, multiplier := 0, 1
+	for i := 0; ; i++ {
+		digit, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(digit&0x7f) * multiplier
+		if digit&0x80 == 0 {
+			break
+		}
+		if i == 3 {
+			return 0, nil, errors.New("malformed remaining length")
+		}
+		multiplier *= 128
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return header, body, nil
+}
@@ -0,0 +1,210 @@
+package mqtt
+
+import (
+	"bufio"
+	"encoding/binary"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// broker este un broker de test pe interfata loopback care accepta o singura conexiune.
+type broker struct {
+	t        *testing.T
+	listener net.Listener
+	conn     net.Conn
+	reader   *bufio.Reader
+}
+
+func newBroker(t *testing.T) *broker {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	return &broker{t: t, listener: listener}
+}
+
+// accept asteapta conexiunea clientului si raspunde la CONNECT cu CONNACK.
+func (b *broker) accept() {
+	b.t.Helper()
+	conn, err := b.listener.Accept()
+	if err != nil {
+		b.t.Fatal(err)
+	}
+	b.t.Cleanup(func() { conn.Close() })
+	b.conn, b.reader = conn, bufio.NewReader(conn)
+	b.expect(packetConnect)
+	b.conn.Write(packet(packetConnAck<<4, []byte{0, 0}))
+}
+
+// expect citeste urmatorul pachet, ignorand PINGREQ, si verifica tipul lui.
+func (b *broker) expect(kind byte) []byte {
+	b.t.Helper()
+	b.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		header, body, err := readPacket(b.reader)
+		if err != nil {
+			b.t.Fatalf("broker read: %v", err)
+		}
+		if header>>4 == packetPingReq {
+			continue
+		}
+		if header>>4 != kind {
+			b.t.Fatalf("broker received packet %d, expected %d", header>>4, kind)
+		}
+		return append([]byte{header}, body...)
+	}
+}
+
+func (b *broker) publish(topic string, payload string) {
+	body := appendString(nil, topic)
+	b.conn.Write(packet(packetPublish<<4, append(body, payload...)))
+}
+
+func testClient(b *broker) *Client {
+	return NewClient(Options{Broker: b.listener.Addr().String(), ClientID: "test", ReconnectMin: 10 * time.Millisecond})
+}
+
+func TestPublishQoS1IsAcknowledged(t *testing.T) {
+	b := newBroker(t)
+	c := testClient(b)
+	c.Start()
+	defer c.Close()
+	b.accept()
+
+	for deadline := time.Now().Add(5 * time.Second); !c.Connected(); {
+		if time.Now().After(deadline) {
+			t.Fatal("client did not connect")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := c.Publish("contor/line1/loading", []byte("42"), 1, false); err != nil {
+		t.Fatal(err)
+	}
+
+	p := b.expect(packetPublish)
+	body := p[1:]
+	length := int(binary.BigEndian.Uint16(body))
+	if topic := string(body[2 : 2+length]); topic != "contor/line1/loading" {
+		t.Fatalf("topic %q", topic)
+	}
+	id := body[2+length : 4+length]
+	if payload := string(body[4+length:]); payload != "42" {
+		t.Fatalf("payload %q", payload)
+	}
+	b.conn.Write(packet(packetPubAck<<4, id))
+
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		c.mu.Lock()
+		pending := len(c.inflight)
+		c.mu.Unlock()
+		if pending == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the PUBACK did not clear the inflight message")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestMessagesAreHandledInOrder(t *testing.T) {
+	b := newBroker(t)
+	c := testClient(b)
+
+	var mu sync.Mutex
+	var received []string
+	done := make(chan struct{})
+	c.Subscribe("contor/+/command", 0, func(topic string, payload []byte) {
+		// Primul mesaj este tratat incet; urmatoarele trebuie sa astepte dupa el
+		if string(payload) == "1" {
+			time.Sleep(50 * time.Millisecond)
+		}
+		mu.Lock()
+		received = append(received, string(payload))
+		if len(received) == 5 {
+			close(done)
+		}
+		mu.Unlock()
+	})
+	c.Start()
+	defer c.Close()
+	b.accept()
+	b.expect(packetSubscribe)
+
+	for _, payload := range []string{"1", "2", "3", "4", "5"} {
+		b.publish("contor/system/command", payload)
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the messages were not handled")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for i, payload := range received {
+		if payload != string(rune('1'+i)) {
+			t.Fatalf("messages handled in order %v", received)
+		}
+	}
+}
+
+func TestReconnectResendsUnacknowledged(t *testing.T) {
+	b := newBroker(t)
+	c := testClient(b)
+	if err := c.Publish("contor/line1/loading", []byte("42"), 1, false); err == nil {
+		t.Fatal("publish succeeded while disconnected")
+	}
+	c.Start()
+	defer c.Close()
+	b.accept()
+
+	p := b.expect(packetPublish)
+	if p[0]&publishDupFlag == 0 {
+		t.Fatal("the resent message is not marked as duplicate")
+	}
+}
+
+func TestInflightLimit(t *testing.T) {
+	c := NewClient(Options{Broker: "127.0.0.1:1"})
+	for i := 0; i < maxInflight; i++ {
+		if err := c.Publish("contor/line1/loading", []byte("42"), 1, false); err == ErrInflightFull {
+			t.Fatalf("message %d refused", i)
+		}
+	}
+	if err := c.Publish("contor/line1/loading", []byte("42"), 1, false); err != ErrInflightFull {
+		t.Fatalf("publish over the limit returned %v", err)
+	}
+	if c.Dropped() != 1 {
+		t.Fatalf("dropped %d messages, expected 1", c.Dropped())
+	}
+}
+
+func TestStalledBrokerDoesNotBlockPublish(t *testing.T) {
+	b := newBroker(t)
+	c := testClient(b)
+	c.Start()
+	defer c.Close()
+	b.accept()
+	for deadline := time.Now().Add(5 * time.Second); !c.Connected(); {
+		if time.Now().After(deadline) {
+			t.Fatal("client did not connect")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Brokerul nu mai citeste: scrierile umplu bufferele si se opresc la termenul writeTimeout
+	payload := make([]byte, 1<<20)
+	start := time.Now()
+	for i := 0; i < 64; i++ {
+		if err := c.Publish("contor/line1/loading", payload, 0, false); err != nil {
+			break
+		}
+	}
+	if elapsed := time.Since(start); elapsed > writeTimeout+5*time.Second {
+		t.Fatalf("publishing to a stalled broker took %s", elapsed)
+	}
+}
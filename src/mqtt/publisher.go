@@ -0,0 +1,173 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"contor-system/src/switching"
+	"contor-system/src/utils"
+)
+
+// Publicarea rezultatelor pe topicuri <topic>/<systemId>/<elementId>/<marime> si primirea comenzilor de manevra
+// pe <topic>/<systemId>/command, cu raspunsul pe <topic>/<systemId>/command/result.
+
+var defaultQuantities = []string{"activePower", "reactivePower", "current", "voltage", "loading", "energized"}
+
+// CommandRequest este continutul unui mesaj de comanda.
+type CommandRequest struct {
+	Separator string          `json:"separator"`
+	State     utils.StateType `json:"state"`
+}
+
+// CommandResult este raspunsul publicat dupa verificarea comenzii.
+type CommandResult struct {
+	Separator string          `json:"separator"`
+	State     utils.StateType `json:"state"`
+	Accepted  bool            `json:"accepted"`
+	Error     string          `json:"error,omitempty"`
+}
+
+type Publisher struct {
+	settings utils.MQTT
+	client   *Client
+	prefix   string
+	dropped  int // mesajele refuzate raportate deja in jurnal
+	logger   *log.Logger
+}
+
+func seconds(value float64) time.Duration {
+	return time.Duration(value * float64(time.Second))
+}
+
+// NewPublisher se conecteaza la broker; comenzile primite sunt trimise pe canalul commands, daca exista.
+// Mesajele clientului si ale publicatorului se scriu in logger (implicit jurnalul standard).
+func NewPublisher(settings utils.MQTT, systemID string, commands chan<- switching.Command, logger *log.Logger) *Publisher {
+	if settings.Topic == "" {
+		settings.Topic = "contor"
+	}
+	if settings.SystemID == "" {
+		settings.SystemID = systemID
+	}
+	if settings.ClientID == "" {
+		settings.ClientID = "contor-" + settings.SystemID
+	}
+	if len(settings.Quantities) == 0 {
+		settings.Quantities = defaultQuantities
+	}
+	if settings.QoS > 1 {
+		settings.QoS = 1
+	}
+
+	client := NewClient(Options{
+		Broker:       settings.Broker,
+		ClientID:     settings.ClientID,
+		Username:     settings.Username,
+		Password:     settings.Password,
+		KeepAlive:    seconds(settings.KeepAlive),
+		ReconnectMin: seconds(settings.ReconnectMin),
+		ReconnectMax: seconds(settings.ReconnectMax),
+		Logger:       logger,
+	})
+	p := &Publisher{settings: settings, client: client, prefix: settings.Topic + "/" + settings.SystemID, logger: client.options.Logger}
+
+	if commands != nil {
+		client.Subscribe(p.prefix+"/command", settings.QoS, func(topic string, payload []byte) {
+			p.command(payload, commands)
+		})
+	}
+	client.Start()
+	return p
+}
+
+// Close deconecteaza clientul.
+func (p *Publisher) Close() {
+	p.client.Close()
+}
+
+// value intoarce marimea elementului ca text.
+func value(quantity string, element *utils.ElementResult) (string, bool) {
+	var v float64
+	switch quantity {
+	case "activePower":
+		v = element.ActivePower
+	case "reactivePower":
+		v = element.ReactivePower
+	case "current":
+		v = element.Current
+	case "voltage":
+		v = element.Voltage
+	case "voltagePU":
+		v = element.VoltagePU
+	case "loading":
+		v = element.Loading
+	case "powerFactor":
+		v = element.PowerFactor
+	case "losses":
+		v = element.ActivePowerLosses
+	case "energized":
+		return strconv.FormatBool(element.Energized), true
+	default:
+		return "", false
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64), true
+}
+
+// Publish publica marimile fiecarui element si pierderile totale ale sistemului.
+func (p *Publisher) Publish(result utils.SystemResult) {
+	if !p.client.Connected() && p.settings.QoS == 0 {
+		return
+	}
+	publish := func(topic string, payload string) {
+		p.client.Publish(p.prefix+"/"+topic, []byte(payload), p.settings.QoS, p.settings.Retain)
+	}
+
+	for _, id := range result.Order {
+		element := result.Elements[id]
+		for _, quantity := range p.settings.Quantities {
+			if payload, ok := value(quantity, element); ok {
+				publish(id+"/"+quantity, payload)
+			}
+		}
+	}
+	publish("system/activePowerLosses", strconv.FormatFloat(result.ActivePowerLosses, 'f', -1, 64))
+	publish("system/reactivePowerLosses", strconv.FormatFloat(result.ReactivePowerLosses, 'f', -1, 64))
+	publish("system/converged", strconv.FormatBool(result.Converged))
+
+	if dropped := p.client.Dropped(); dropped > p.dropped {
+		p.logger.Printf("MQTT broker %s is not acknowledging: %d messages dropped (%d in total)", p.settings.Broker, dropped-p.dropped, dropped)
+		p.dropped = dropped
+	}
+}
+
+// command decodeaza o comanda, o trimite buclei de calcul si publica rezultatul verificarii.
+func (p *Publisher) command(payload []byte, commands chan<- switching.Command) {
+	var request CommandRequest
+	reply := func(err error) {
+		result := CommandResult{Separator: request.Separator, State: request.State, Accepted: err == nil}
+		if err != nil {
+			result.Error = err.Error()
+			p.logger.Printf("MQTT command %s %s rejected: %v", request.State, request.Separator, err)
+		}
+		data, _ := json.Marshal(result)
+		p.client.Publish(p.prefix+"/command/result", data, p.settings.QoS, false)
+	}
+
+	if err := json.Unmarshal(payload, &request); err != nil {
+		reply(fmt.Errorf("invalid command: %v", err))
+		return
+	}
+	if request.State != utils.StateOpen && request.State != utils.StateClose {
+		reply(fmt.Errorf("state must be %q or %q", utils.StateOpen, utils.StateClose))
+		return
+	}
+
+	command := switching.Command{Separator: request.Separator, State: request.State, Confirm: reply, Terminate: func() {}}
+	select {
+	case commands <- command:
+	case <-time.After(10 * time.Second):
+		reply(fmt.Errorf("command queue is busy"))
+	}
+}
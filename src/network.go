@@ -0,0 +1,655 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"contor-system/src/alarms"
+	"contor-system/src/computing"
+	"contor-system/src/diagram"
+	"contor-system/src/dlms"
+	"contor-system/src/iec104"
+	"contor-system/src/metering"
+	"contor-system/src/metrics"
+	"contor-system/src/modbus"
+	"contor-system/src/mqtt"
+	"contor-system/src/notify"
+	"contor-system/src/report"
+	"contor-system/src/rpc"
+	"contor-system/src/storage"
+	"contor-system/src/switching"
+	"contor-system/src/utils"
+)
+
+// network ruleaza bucla de calcul a unei retele: reincarcarea configuratiei, pasul de calcul, serverele, jurnalele si alarmele.
+// Retelele nu au nimic in comun, deci mai multe pot rula in paralel in acelasi proces.
+type network struct {
+	name       string // empty when the process runs a single network
+	configPath string
+	logDir     string // used when the configuration has no daemon.logDir
+	switching  *switching.Runner
+	tick       time.Duration // used when the configuration has no daemon.tick
+	logger     *log.Logger
+	trace      io.Writer    // the solver trace; only a single network writes it to stdout
+	addresses  *addressBook // the listen addresses of the other networks in the directory; nil for a single network
+}
+
+func newNetwork(name string, configPath string, logDir string, tick time.Duration) *network {
+	prefix := ""
+	trace := io.Writer(os.Stdout)
+	if name != "" {
+		prefix = fmt.Sprintf("[%s] ", name)
+		trace = io.Discard
+	}
+	return &network{
+		name:       name,
+		configPath: configPath,
+		logDir:     logDir,
+		tick:       tick,
+		logger:     log.New(os.Stderr, prefix, log.LstdFlags),
+		trace:      trace,
+	}
+}
+
+// interval intoarce intervalul de calcul al configuratiei.
+func (n *network) interval(config utils.System) time.Duration {
+	if config.Daemon == nil || config.Daemon.Tick <= 0 {
+		return n.tick
+	}
+	return time.Duration(config.Daemon.Tick * float64(time.Second))
+}
+
+// directory intoarce directorul de jurnale al configuratiei.
+func (n *network) directory(config utils.System) string {
+	if config.Daemon != nil && config.Daemon.LogDir != "" {
+		return config.Daemon.LogDir
+	}
+	return n.logDir
+}
+
+// reportExtensions sunt extensiile fisierelor pentru formatele raportului.
+var reportExtensions = map[string]string{"text": "txt", "markdown": "md", "html": "html"}
+
+// writeReport scrie raportul ultimului calcul in directorul de jurnale, cand daemon.report este setat.
+func (n *network) writeReport(config utils.System, system utils.System, result utils.SystemResult, logDir string) error {
+	if config.Daemon == nil || config.Daemon.Report == "" {
+		return nil
+	}
+	title := "Power flow report"
+	if n.name != "" {
+		title += ": " + n.name
+	}
+	content, err := formatReport(report.Build(title, system, result, time.Now()), config.Daemon.Report)
+	if err != nil {
+		return fmt.Errorf("failed to write the report: %v", err)
+	}
+	path := filepath.Join(logDir, "report."+reportExtensions[config.Daemon.Report])
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write the report: %v", err)
+	}
+	return nil
+}
+
+// writeDiagram scrie schema monofilara a ultimului calcul in directorul de jurnale, cand daemon.diagram este setat.
+func (n *network) writeDiagram(config utils.System, system utils.System, result utils.SystemResult, logDir string) error {
+	if config.Daemon == nil || config.Daemon.Diagram == "" {
+		return nil
+	}
+	content, err := formatDiagram(diagram.Build(system, &result), config.Daemon.Diagram)
+	if err != nil {
+		return fmt.Errorf("failed to write the diagram: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(logDir, "diagram."+config.Daemon.Diagram), []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write the diagram: %v", err)
+	}
+	return nil
+}
+
+// warnEngine semnaleaza datele din configuratie pe care motorul ales le lasa in afara calculului.
+func (n *network) warnEngine(config utils.System) {
+	for _, warning := range computing.EngineWarnings(config) {
+		n.logger.Printf("Warning: %s", warning)
+	}
+}
+
+// startModbus porneste serverul Modbus TCP, daca exista in configuratie.
+func (n *network) startModbus(settings *utils.Modbus) *modbus.Server {
+	if settings == nil {
+		return nil
+	}
+	server := modbus.NewServer(*settings, n.logger)
+	if err := server.Start(); err != nil {
+		n.logger.Printf("Failed to start Modbus server: %v", err)
+		return nil
+	}
+	n.logger.Printf("Modbus TCP server listening on %s", server.Addr())
+	return server
+}
+
+// startIEC104 porneste statia IEC 104, daca exista in configuratie.
+func (n *network) startIEC104(settings *utils.IEC104, commands chan<- switching.Command) *iec104.Server {
+	if settings == nil {
+		return nil
+	}
+	server := iec104.NewServer(*settings, commands, n.logger)
+	if err := server.Start(); err != nil {
+		n.logger.Printf("Failed to start IEC 104 server: %v", err)
+		return nil
+	}
+	n.logger.Printf("IEC 104 outstation listening on %s", server.Addr())
+	return server
+}
+
+// startDLMS porneste serverul DLMS/COSEM de citire a contoarelor, daca exista in configuratie.
+func (n *network) startDLMS(settings *utils.DLMS) (*dlms.Server, *metering.Meters) {
+	if settings == nil {
+		return nil, nil
+	}
+	meters := metering.NewMeters(time.Duration(settings.CapturePeriod*float64(time.Second)), settings.ProfileDepth)
+	server := dlms.NewServer(*settings, meters, n.logger)
+	if err := server.Start(); err != nil {
+		n.logger.Printf("Failed to start DLMS server: %v", err)
+		return nil, nil
+	}
+	n.logger.Printf("DLMS/COSEM server listening on %s", server.Addr())
+	return server, meters
+}
+
+// startMQTT conecteaza publicatorul MQTT, daca exista in configuratie.
+func (n *network) startMQTT(config utils.System, commands chan<- switching.Command) *mqtt.Publisher {
+	if config.MQTT == nil {
+		return nil
+	}
+	return mqtt.NewPublisher(*config.MQTT, config.Source.ID, commands, n.logger)
+}
+
+// startGRPC porneste serviciul gRPC de analiza what-if, daca exista in configuratie.
+func (n *network) startGRPC(settings *utils.GRPC) *rpc.Server {
+	if settings == nil {
+		return nil
+	}
+	server := rpc.NewServer(*settings)
+	if err := server.Start(); err != nil {
+		n.logger.Printf("Failed to start gRPC server: %v", err)
+		return nil
+	}
+	n.logger.Printf("gRPC server listening on %s", server.Addr())
+	return server
+}
+
+// startMetrics expune metricile Prometheus, daca configuratia are sectiunea metrics.
+func (n *network) startMetrics(settings *utils.Metrics, registry *metrics.Registry) *metrics.Server {
+	if settings == nil {
+		return nil
+	}
+	server := metrics.NewServer(*settings, registry, n.logger)
+	if err := server.Start(); err != nil {
+		n.logger.Printf("Failed to start metrics server: %v", err)
+		return nil
+	}
+	n.logger.Printf("Prometheus metrics served on %s", server.Addr())
+	return server
+}
+
+// readConfig citeste configuratia si, in modul director, rezerva adresele pe care asculta serverele ei.
+// O configuratie ale carei adrese sunt deja folosite de alta retea este refuzata.
+func (n *network) readConfig() (utils.System, error) {
+	config, err := loadConfig(n.configPath)
+	if err != nil || n.addresses == nil {
+		return config, err
+	}
+	if err := n.addresses.claim(n.name, config); err != nil {
+		return utils.System{}, err
+	}
+	return config, nil
+}
+
+// load citeste configuratia, reincercand la fiecare secunda pana cand reuseste sau contextul se incheie.
+func (n *network) load(ctx context.Context) (utils.System, bool) {
+	for {
+		config, err := n.readConfig()
+		if err == nil {
+			return config, true
+		}
+		n.logger.Printf("Failed to load config: %v", err)
+		select {
+		case <-ctx.Done():
+			return utils.System{}, false
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// run calculeaza reteaua la fiecare pas, pana cand contextul se incheie.
+func (n *network) run(ctx context.Context) {
+	config, ok := n.load(ctx)
+	if !ok {
+		return
+	}
+	if n.addresses != nil {
+		defer n.addresses.release(n.name)
+	}
+	lastConfigJSON, _ := os.ReadFile(n.configPath)
+
+	dispatcher, err := notify.NewDispatcher(config.Notifiers, n.logger)
+	if err != nil {
+		n.logger.Printf("Failed to configure notifiers: %v", err)
+	}
+	defer func() {
+		n.logger.Println("Cleaning up resources...")
+		dispatcher.Close()
+	}()
+
+	interval := n.interval(config)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	n.warnEngine(config)
+	alarmManager := alarms.NewManager()
+	storageManager := storage.NewManager()
+	energy := metering.NewAccumulator()
+	modbusServer := n.startModbus(config.Modbus)
+	defer func() {
+		if modbusServer != nil {
+			modbusServer.Close()
+		}
+	}()
+	dlmsServer, meters := n.startDLMS(config.DLMS)
+	defer func() {
+		if dlmsServer != nil {
+			dlmsServer.Close()
+		}
+	}()
+	commands := make(chan switching.Command)
+	iec104Server := n.startIEC104(config.IEC104, commands)
+	defer func() {
+		if iec104Server != nil {
+			iec104Server.Close()
+		}
+	}()
+	mqttPublisher := n.startMQTT(config, commands)
+	defer func() {
+		if mqttPublisher != nil {
+			mqttPublisher.Close()
+		}
+	}()
+	registry := metrics.NewRegistry()
+	metricsServer := n.startMetrics(config.Metrics, registry)
+	defer func() {
+		if metricsServer != nil {
+			metricsServer.Close()
+		}
+	}()
+	grpcServer := n.startGRPC(config.GRPC)
+	defer func() {
+		if grpcServer != nil {
+			grpcServer.Close()
+		}
+	}()
+	remoteStates := map[string]utils.StateType{}
+	var lastResult *utils.SystemResult
+	liveSystem := config
+	recentAlarmEvents := []alarms.Event{}
+	const maxRecentAlarmEvents = 100
+
+	// Generate the initial log file path
+	logDir := n.directory(config)
+	logFilePath := filepath.Join(logDir, fmt.Sprintf("%s.txt", time.Now().Format("2006-01-02_150405")))
+
+	// compute ruleaza un pas de calcul pe modelul curent si scrie rezultatele
+	compute := func(extraEntries []utils.LogEntry) {
+		// Comenzile de la distanta raman aplicate peste configuratie pana cand aceasta impune aceleasi stari
+		live := switching.WithStates(config, remoteStates)
+
+		// Bateriile se dispecerizeaza dupa circulatia de puteri a pasului anterior, apoi se calculeaza pasul nou
+		dispatched, storageEntries := storageManager.Dispatch(live, lastResult, time.Now())
+
+		// Se executa manevrele scadente; cele executate raman aplicate si dupa reincarcarea configuratiei
+		var switchingEntries []utils.LogEntry
+		if n.switching != nil {
+			dispatched, switchingEntries = n.switching.Step(dispatched, time.Now(), computing.SolveQuiet)
+			for _, entry := range switchingEntries {
+				n.logger.Print(entry.Message)
+			}
+		}
+
+		// Simulate log calculation
+		started := time.Now()
+		result, logEntries := computing.SolveTo(dispatched, n.trace)
+		registry.Observe(dispatched, result, time.Since(started))
+		logEntries = append(logEntries, storageEntries...)
+		logEntries = append(logEntries, switchingEntries...)
+		logEntries = append(logEntries, extraEntries...)
+		lastResult = &result
+		liveSystem = dispatched
+
+		// Se integreaza registrele de energie si se publica valorile noi catre clientii Modbus, IEC 104, DLMS si MQTT
+		energy.Update(result, time.Now())
+		if modbusServer != nil {
+			if err := modbusServer.Update(dispatched, result, energy); err != nil {
+				n.logger.Print(err)
+			}
+		}
+		if iec104Server != nil {
+			iec104Server.Update(dispatched, result)
+		}
+		if meters != nil {
+			meters.Update(dispatched, result, energy, time.Now())
+		}
+		if mqttPublisher != nil {
+			mqttPublisher.Publish(result)
+		}
+		if grpcServer != nil {
+			if err := grpcServer.Update(dispatched); err != nil {
+				n.logger.Print(err)
+			}
+		}
+
+		// Se verifica limitele alarmelor pe valorile calculate
+		alarmEvents := alarmManager.Evaluate(dispatched, result, time.Now())
+		for _, event := range alarmEvents {
+			n.logger.Print(event.LogEntry().Message)
+			logEntries = append(logEntries, event.LogEntry())
+		}
+		dispatcher.Dispatch(alarmEvents)
+		recentAlarmEvents = append(recentAlarmEvents, alarmEvents...)
+		if len(recentAlarmEvents) > maxRecentAlarmEvents {
+			recentAlarmEvents = recentAlarmEvents[len(recentAlarmEvents)-maxRecentAlarmEvents:]
+		}
+
+		// Ensure the log directory exists
+		if err := ensureDirectory(logDir); err != nil {
+			n.logger.Printf("Error creating log directory: %v", err)
+			return
+		}
+
+		// Write logs to the new log file
+		if err := writeToFile(logEntries, logFilePath); err != nil {
+			n.logger.Printf("Error writing to file: %v", err)
+		} else {
+			n.logger.Printf("Logged %d entries to %s", len(logEntries), logFilePath)
+		}
+
+		if err := writeAlarms(alarmManager.Active(), recentAlarmEvents, filepath.Join(logDir, "alarms.json")); err != nil {
+			n.logger.Printf("Error writing alarms: %v", err)
+		}
+		if err := n.writeReport(config, dispatched, result, logDir); err != nil {
+			n.logger.Print(err)
+		}
+		if err := n.writeDiagram(config, dispatched, result, logDir); err != nil {
+			n.logger.Print(err)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case command := <-commands:
+			// Manevra de la distanta: se verifica interblocarile pe modelul curent, se aplica starea noua si se recalculeaza imediat
+			// Cat timp secventa de manevre mai are de manevrat separatorul, telecomanda este refuzata; dupa aceea
+			// telecomanda inlocuieste starea impusa de secventa
+			if n.switching != nil && n.switching.Pending(command.Separator) {
+				err := fmt.Errorf("separator %s is operated by the running switching sequence %s", command.Separator, n.switching.Name())
+				n.logger.Printf("Remote command refused: %v", err)
+				command.Confirm(err)
+				continue
+			}
+			op := switching.Operation{Separator: command.Separator, State: command.State}
+			if err := switching.Interlock(liveSystem, op, computing.SolveQuiet); err != nil {
+				command.Confirm(err)
+				continue
+			}
+			if n.switching != nil {
+				n.switching.Release(command.Separator)
+			}
+			remoteStates[command.Separator] = command.State
+			command.Confirm(nil)
+			message := fmt.Sprintf("Remote command: separator %s set to %s\n", command.Separator, command.State)
+			n.logger.Print(message)
+			compute([]utils.LogEntry{{Timestamp: time.Now().Format("2006/01/02-15:04:05"), ComponentID: command.Separator, Message: message}})
+			command.Terminate()
+		case <-ticker.C:
+			// Configuratia se reincarca la fiecare pas si se verifica modificarile
+			currentConfig, err := n.readConfig()
+			if err != nil {
+				n.logger.Printf("Failed to reload config: %v", err)
+				registry.ConfigReloaded(err)
+				continue
+			}
+
+			currentConfigJSON, _ := os.ReadFile(n.configPath)
+			if string(currentConfigJSON) != string(lastConfigJSON) {
+				n.logger.Println("Configuration has changed. New configuration loaded.")
+				lastConfigJSON = currentConfigJSON
+				registry.ConfigReloaded(nil)
+				if !reflect.DeepEqual(config.Modbus, currentConfig.Modbus) {
+					if modbusServer != nil {
+						modbusServer.Close()
+					}
+					modbusServer = n.startModbus(currentConfig.Modbus)
+				}
+				if !reflect.DeepEqual(config.IEC104, currentConfig.IEC104) {
+					if iec104Server != nil {
+						iec104Server.Close()
+					}
+					iec104Server = n.startIEC104(currentConfig.IEC104, commands)
+				}
+				if !reflect.DeepEqual(config.DLMS, currentConfig.DLMS) {
+					if dlmsServer != nil {
+						dlmsServer.Close()
+					}
+					dlmsServer, meters = n.startDLMS(currentConfig.DLMS)
+				}
+				if !reflect.DeepEqual(config.MQTT, currentConfig.MQTT) || config.Source.ID != currentConfig.Source.ID {
+					if mqttPublisher != nil {
+						mqttPublisher.Close()
+					}
+					mqttPublisher = n.startMQTT(currentConfig, commands)
+				}
+				if !reflect.DeepEqual(config.GRPC, currentConfig.GRPC) {
+					if grpcServer != nil {
+						grpcServer.Close()
+					}
+					grpcServer = n.startGRPC(currentConfig.GRPC)
+				}
+				if !reflect.DeepEqual(config.Metrics, currentConfig.Metrics) {
+					if metricsServer != nil {
+						metricsServer.Close()
+					}
+					metricsServer = n.startMetrics(currentConfig.Metrics, registry)
+				}
+				if n.interval(currentConfig) != interval {
+					interval = n.interval(currentConfig)
+					ticker.Reset(interval)
+					n.logger.Printf("Calculation interval changed to %s", interval)
+				}
+				config = currentConfig
+				n.warnEngine(config)
+
+				// O stare scrisa in configuratie inlocuieste comanda de la distanta pentru acel separator
+				for _, separator := range config.Separators {
+					if state, exists := remoteStates[separator.ID]; exists && state == separator.State {
+						delete(remoteStates, separator.ID)
+					}
+				}
+
+				// Notificatorii isi pastreaza cozile si limitele de rata; reincarcarea nu asteapta reincercarile in curs
+				if err := dispatcher.Reload(config.Notifiers); err != nil {
+					n.logger.Printf("Failed to configure notifiers: %v", err)
+				}
+
+				// Generate a new log file path when the config changes
+				logDir = n.directory(config)
+				logFilePath = filepath.Join(logDir, fmt.Sprintf("%s.txt", time.Now().Format("2006-01-02_150405")))
+				n.logger.Printf("Switched to new log file: %s", logFilePath)
+			}
+
+			compute(nil)
+		}
+	}
+}
+
+// addressBook retine adresele pe care asculta serverele fiecarei retele pornite din director.
+type addressBook struct {
+	mu     sync.Mutex
+	owners map[string]map[string]string // network -> server -> address
+}
+
+func newAddressBook() *addressBook {
+	return &addressBook{owners: map[string]map[string]string{}}
+}
+
+// listenAddresses intoarce adresele pe care asculta serverele configuratiei, cu valorile implicite ale fiecarui server.
+func listenAddresses(config utils.System) map[string]string {
+	addresses := map[string]string{}
+	add := func(server string, address string, fallback string) {
+		if address == "" {
+			address = fallback
+		}
+		addresses[server] = address
+	}
+	if config.Modbus != nil {
+		add("modbus", config.Modbus.Address, ":5020")
+	}
+	if config.IEC104 != nil {
+		add("iec104", config.IEC104.Address, ":2404")
+	}
+	if config.DLMS != nil {
+		add("dlms", config.DLMS.Address, ":4059")
+	}
+	if config.GRPC != nil {
+		add("grpc", config.GRPC.Address, ":50051")
+	}
+	if config.Metrics != nil {
+		add("metrics", config.Metrics.Address, ":9464")
+	}
+	return addresses
+}
+
+// overlap spune daca doua adrese TCP ar folosi acelasi port: acelasi port pe aceeasi gazda sau pe toate interfetele.
+// Portul 0 este ales de sistem si nu se suprapune niciodata.
+func overlap(a string, b string) bool {
+	hostA, portA, errA := net.SplitHostPort(a)
+	hostB, portB, errB := net.SplitHostPort(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	if portA != portB || portA == "0" {
+		return false
+	}
+	wildcard := func(host string) bool { return host == "" || host == "0.0.0.0" || host == "::" }
+	return hostA == hostB || wildcard(hostA) || wildcard(hostB)
+}
+
+// claim rezerva adresele configuratiei pentru retea, inlocuind rezervarea ei anterioara.
+// Intoarce o eroare, fara sa schimbe nimic, daca o adresa se suprapune cu a altei retele sau a altui server din aceeasi configuratie.
+func (b *addressBook) claim(name string, config utils.System) error {
+	addresses := listenAddresses(config)
+	servers := make([]string, 0, len(addresses))
+	for server := range addresses {
+		servers = append(servers, server)
+	}
+	sort.Strings(servers)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, server := range servers {
+		for _, other := range servers[i+1:] {
+			if overlap(addresses[server], addresses[other]) {
+				return fmt.Errorf("%s address %s overlaps the %s address %s", server, addresses[server], other, addresses[other])
+			}
+		}
+		for network, owned := range b.owners {
+			if network == name {
+				continue
+			}
+			for otherServer, address := range owned {
+				if overlap(addresses[server], address) {
+					return fmt.Errorf("%s address %s is already used by the %s server of network %s", server, addresses[server], otherServer, network)
+				}
+			}
+		}
+	}
+	b.owners[name] = addresses
+	return nil
+}
+
+// release elibereaza adresele retelei.
+func (b *addressBook) release(name string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.owners, name)
+}
+
+// runDirectory ruleaza fiecare configuratie *.json din director ca retea separata, pana cand contextul se incheie.
+// Directorul este citit din nou la fiecare secunda: fisierele noi pornesc o retea, iar cele sterse o opresc pe a lor.
+func runDirectory(ctx context.Context, dir string, logDir string, tick time.Duration) {
+	type running struct {
+		cancel context.CancelFunc
+		done   chan struct{}
+	}
+	networks := map[string]running{}
+	addresses := newAddressBook()
+	var wg sync.WaitGroup
+
+	scan := func() {
+		paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+		if err != nil {
+			log.Printf("Failed to read the configuration directory: %v", err)
+			return
+		}
+		present := map[string]bool{}
+		for _, path := range paths {
+			name := strings.TrimSuffix(filepath.Base(path), ".json")
+			present[name] = true
+			if _, exists := networks[name]; exists {
+				continue
+			}
+
+			log.Printf("Starting network %s from %s", name, path)
+			networkCtx, cancel := context.WithCancel(ctx)
+			done := make(chan struct{})
+			networks[name] = running{cancel: cancel, done: done}
+			n := newNetwork(name, path, filepath.Join(logDir, name), tick)
+			n.addresses = addresses
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer close(done)
+				n.run(networkCtx)
+			}()
+		}
+		for name, r := range networks {
+			if !present[name] {
+				log.Printf("Stopping network %s: its configuration was removed", name)
+				r.cancel()
+				<-r.done
+				delete(networks, name)
+			}
+		}
+	}
+
+	scan()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case <-ticker.C:
+			scan()
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"contor-system/src/utils"
+)
+
+func TestAddressBook(t *testing.T) {
+	book := newAddressBook()
+	if err := book.claim("a", utils.System{Metrics: &utils.Metrics{}, GRPC: &utils.GRPC{Address: "127.0.0.1:7000"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		config   utils.System
+		rejected bool
+	}{
+		{"default metrics address", utils.System{Metrics: &utils.Metrics{}}, true},
+		{"same port on all interfaces", utils.System{Modbus: &utils.Modbus{Address: ":7000"}}, true},
+		{"same port on the same host", utils.System{DLMS: &utils.DLMS{Address: "127.0.0.1:7000"}}, true},
+		{"same port on another host", utils.System{DLMS: &utils.DLMS{Address: "127.0.0.2:7000"}}, false},
+		{"two servers of the network on one port", utils.System{Modbus: &utils.Modbus{Address: ":7100"}, IEC104: &utils.IEC104{Address: ":7100"}}, true},
+		{"ports chosen by the system", utils.System{Modbus: &utils.Modbus{Address: ":0"}, IEC104: &utils.IEC104{Address: ":0"}}, false},
+		{"distinct ports", utils.System{Metrics: &utils.Metrics{Address: ":9465"}, GRPC: &utils.GRPC{}}, false},
+	}
+	for _, c := range cases {
+		err := book.claim("b", c.config)
+		if (err != nil) != c.rejected {
+			t.Fatalf("%s: error %v, rejected %v expected", c.name, err, c.rejected)
+		}
+		book.release("b")
+	}
+
+	// O reincarcare a retelei a isi poate pastra adresele, iar dupa oprirea ei adresele sunt libere
+	if err := book.claim("a", utils.System{Metrics: &utils.Metrics{}}); err != nil {
+		t.Fatalf("reload with the same address: %v", err)
+	}
+	book.release("a")
+	if err := book.claim("b", utils.System{Metrics: &utils.Metrics{}}); err != nil {
+		t.Fatalf("address not released: %v", err)
+	}
+}
+
+// freePort intoarce o adresa locala pe care nu asculta nimeni.
+func freePort(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+// writeNetwork scrie configuratia unei retele cu un singur consumator si metricile pe adresa data.
+func writeNetwork(t *testing.T, path string, consumer string, metrics string) {
+	t.Helper()
+	system := map[string]interface{}{
+		"source":    map[string]interface{}{"id": "source1", "power": 10, "voltage": 20, "connectedTo": consumer},
+		"consumers": []interface{}{map[string]interface{}{"id": consumer, "powerNeeded": 1, "voltage": 20}},
+		"metrics":   map[string]interface{}{"address": metrics},
+		"daemon":    map[string]interface{}{"tick": 0.1},
+	}
+	content, err := json.Marshal(system)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Fisierul se inlocuieste dintr-o data, ca reteaua sa nu citeasca o configuratie scrisa pe jumatate
+	temporary := path + ".tmp"
+	if err := os.WriteFile(temporary, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(temporary, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// scrape intoarce metricile servite pe adresa, sau un sir gol daca nu raspunde nimeni.
+func scrape(address string) string {
+	client := http.Client{Timeout: time.Second}
+	response, err := client.Get("http://" + address + "/metrics")
+	if err != nil {
+		return ""
+	}
+	defer response.Body.Close()
+	body, _ := io.ReadAll(response.Body)
+	return string(body)
+}
+
+// eventually asteapta pana cand conditia este indeplinita.
+func eventually(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestDirectoryRejectsDuplicateAddresses(t *testing.T) {
+	dir := t.TempDir()
+	logDir := t.TempDir()
+	first, second := freePort(t), freePort(t)
+	writeNetwork(t, filepath.Join(dir, "a.json"), "consumerA", first)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		runDirectory(ctx, dir, logDir, time.Second)
+	}()
+	defer func() {
+		cancel()
+		<-stopped
+	}()
+	eventually(t, "network a", func() bool { return strings.Contains(scrape(first), "consumerA") })
+
+	// Reteaua b cere adresa retelei a: nu porneste, iar a ramane neatinsa
+	writeNetwork(t, filepath.Join(dir, "b.json"), "consumerB", first)
+	time.Sleep(1500 * time.Millisecond)
+	if _, err := os.Stat(filepath.Join(logDir, "b")); !os.IsNotExist(err) {
+		t.Fatalf("network b ran with a duplicate address: %v", err)
+	}
+	if metrics := scrape(first); !strings.Contains(metrics, "consumerA") || strings.Contains(metrics, "consumerB") {
+		t.Fatalf("network a disturbed by b:\n%s", metrics)
+	}
+
+	// Cu o adresa proprie, b porneste la urmatoarea incercare
+	writeNetwork(t, filepath.Join(dir, "b.json"), "consumerB", second)
+	eventually(t, "network b", func() bool { return strings.Contains(scrape(second), "consumerB") })
+
+	// O reincarcare a retelei a pe adresa lui b este refuzata: a ramane pe vechea adresa, b pe a ei
+	writeNetwork(t, filepath.Join(dir, "a.json"), "consumerA", second)
+	time.Sleep(500 * time.Millisecond)
+	if !strings.Contains(scrape(first), "consumerA") {
+		t.Fatal("network a stopped serving after a refused reload")
+	}
+	if metrics := scrape(second); !strings.Contains(metrics, "consumerB") || strings.Contains(metrics, "consumerA") {
+		t.Fatalf("network b disturbed by the reload of a:\n%s", metrics)
+	}
+
+	// Dupa oprirea lui b, adresa se elibereaza si a trece pe ea
+	if err := os.Remove(filepath.Join(dir, "b.json")); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "network a on the released address", func() bool { return strings.Contains(scrape(second), "consumerA") })
+	if scrape(first) != "" {
+		t.Fatal("network a still serves on its old address")
+	}
+}
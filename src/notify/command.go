@@ -0,0 +1,55 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"contor-system/src/alarms"
+	"contor-system/src/utils"
+)
+
+const commandTimeout = 30 * time.Second
+
+// commandSender executa un script local; evenimentul este dat ca JSON pe stdin si in variabile de mediu ALARM_*.
+type commandSender struct {
+	command string
+	args    []string
+}
+
+func newCommandSender(config utils.Notifier) (*commandSender, error) {
+	if config.Command == "" {
+		return nil, fmt.Errorf("command notifier needs a command")
+	}
+	return &commandSender{command: config.Command, args: config.Args}, nil
+}
+
+func (c *commandSender) Send(ctx context.Context, event alarms.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, c.command, c.args...)
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Env = append(os.Environ(),
+		"ALARM_EVENT="+string(event.Type),
+		"ALARM_ELEMENT="+event.Alarm.ElementID,
+		"ALARM_KIND="+string(event.Alarm.Kind),
+		"ALARM_SEVERITY="+string(event.Alarm.Severity),
+		fmt.Sprintf("ALARM_VALUE=%g", event.Alarm.Value),
+		fmt.Sprintf("ALARM_LIMIT=%g", event.Alarm.Limit),
+	)
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("command %s failed: %v: %s", c.command, err, bytes.TrimSpace(output))
+	}
+	return nil
+}
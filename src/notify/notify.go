@@ -0,0 +1,245 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+	"sync"
+	"time"
+
+	"contor-system/src/alarms"
+	"contor-system/src/utils"
+)
+
+// Sender trimite un eveniment de alarma catre o destinatie. ctx este anulat la oprirea dispecerului si
+// intrerupe asteptarile dintre reincercari.
+type Sender interface {
+	Send(ctx context.Context, event alarms.Event) error
+}
+
+// queueSize este numarul de evenimente care pot astepta trimiterea pe un notificator.
+const queueSize = 256
+
+// closeTimeout este timpul lasat la oprire pentru trimiterea evenimentelor ramase in cozi.
+const closeTimeout = 10 * time.Second
+
+// route leaga un sender de filtrul de severitate si limitarea de rata din configuratie. Evenimentele unui
+// notificator sunt trimise pe rand, din coada lui, in ordinea in care au fost produse.
+type route struct {
+	config utils.Notifier
+	sender Sender
+	queue  chan alarms.Event
+	done   chan struct{} // inchis cand coada a fost golita
+
+	mu      sync.Mutex
+	sent    []time.Time // momentele trimiterilor din ultimul minut
+	dropped int         // evenimente pierdute pentru ca limita de rata sau coada erau pline
+}
+
+// Dispatcher distribuie evenimentele de alarma catre notificatorii configurati.
+type Dispatcher struct {
+	mu     sync.Mutex
+	routes []*route
+	ctx    context.Context
+	cancel context.CancelFunc
+	logger *log.Logger
+	now    func() time.Time
+
+	closeTimeout time.Duration
+}
+
+func newSender(config utils.Notifier) (Sender, error) {
+	switch config.Type {
+	case utils.NotifierTypeWebhook:
+		return newWebhookSender(config)
+	case utils.NotifierTypeSMTP:
+		return newSMTPSender(config)
+	case utils.NotifierTypeCommand:
+		return newCommandSender(config)
+	default:
+		return nil, fmt.Errorf("unknown notifier type %q", config.Type)
+	}
+}
+
+// NewDispatcher construieste notificatorii din configuratie. Un notificator invalid este ignorat si raportat in eroare.
+func NewDispatcher(configs []utils.Notifier, logger *log.Logger) (*Dispatcher, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	dispatcher := &Dispatcher{ctx: ctx, cancel: cancel, logger: logger, now: time.Now, closeTimeout: closeTimeout}
+	if dispatcher.logger == nil {
+		dispatcher.logger = log.Default()
+	}
+	return dispatcher, dispatcher.Reload(configs)
+}
+
+// Reload inlocuieste notificatorii fara sa astepte trimiterile in curs. Un notificator cu acelasi id isi pastreaza
+// limitarea de rata; daca configuratia lui nu s-a schimbat, isi pastreaza si coada.
+func (d *Dispatcher) Reload(configs []utils.Notifier) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	previous := map[string]*route{}
+	for _, r := range d.routes {
+		previous[r.config.ID] = r
+	}
+
+	var routes []*route
+	var errs []error
+	for _, config := range configs {
+		old, exists := previous[config.ID]
+		if exists && reflect.DeepEqual(old.config, config) {
+			routes = append(routes, old)
+			delete(previous, config.ID)
+			continue
+		}
+
+		sender, err := newSender(config)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("notifier %s: %v", config.ID, err))
+			continue
+		}
+		r := d.start(config, sender)
+		if exists {
+			old.mu.Lock()
+			r.sent = old.sent
+			old.mu.Unlock()
+		}
+		routes = append(routes, r)
+	}
+
+	// Notificatorii inlocuiti sau eliminati isi golesc coada in fundal
+	for _, old := range previous {
+		close(old.queue)
+	}
+	d.routes = routes
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid notifiers: %v", errs)
+	}
+	return nil
+}
+
+// start porneste trimiterea din coada unui notificator.
+func (d *Dispatcher) start(config utils.Notifier, sender Sender) *route {
+	r := &route{config: config, sender: sender, queue: make(chan alarms.Event, queueSize), done: make(chan struct{})}
+	go func() {
+		defer close(r.done)
+		for event := range r.queue {
+			if err := r.sender.Send(d.ctx, event); err != nil {
+				d.logger.Printf("Notifier %s failed: %v", r.config.ID, err)
+			}
+		}
+	}()
+	return r
+}
+
+func (r *route) accepts(severity alarms.Severity) bool {
+	if len(r.config.Severities) == 0 {
+		return true
+	}
+	for _, s := range r.config.Severities {
+		if alarms.Severity(s) == severity {
+			return true
+		}
+	}
+	return false
+}
+
+// allow aplica limitarea de rata: cel mult RateLimit notificari intr-o fereastra de un minut.
+func (r *route) allow(now time.Time) bool {
+	if r.config.RateLimit <= 0 {
+		return true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	window := now.Add(-time.Minute)
+	kept := r.sent[:0]
+	for _, t := range r.sent {
+		if t.After(window) {
+			kept = append(kept, t)
+		}
+	}
+	r.sent = kept
+
+	if len(r.sent) >= r.config.RateLimit {
+		return false
+	}
+	r.sent = append(r.sent, now)
+	return true
+}
+
+func (r *route) drop() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.dropped++
+	return r.dropped
+}
+
+// Dispatch pune evenimentele in coada fiecarui notificator; trimiterea si reincercarile nu blocheaza ciclul de calcul.
+func (d *Dispatcher) Dispatch(events []alarms.Event) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, event := range events {
+		for _, r := range d.routes {
+			if !r.accepts(event.Alarm.Severity) {
+				continue
+			}
+			if !r.allow(d.now()) {
+				d.logger.Printf("Notifier %s rate limited, dropping %s %s alarm for %s (%d dropped)", r.config.ID, event.Type, event.Alarm.Kind, event.Alarm.ElementID, r.drop())
+				continue
+			}
+			select {
+			case r.queue <- event:
+			default:
+				d.logger.Printf("Notifier %s queue full, dropping %s %s alarm for %s (%d dropped)", r.config.ID, event.Type, event.Alarm.Kind, event.Alarm.ElementID, r.drop())
+			}
+		}
+	}
+}
+
+// Dropped intoarce numarul de evenimente pierdute de fiecare notificator, dupa id.
+func (d *Dispatcher) Dropped() map[string]int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	dropped := map[string]int{}
+	for _, r := range d.routes {
+		r.mu.Lock()
+		dropped[r.config.ID] = r.dropped
+		r.mu.Unlock()
+	}
+	return dropped
+}
+
+// Close trimite evenimentele ramase in cozi si asteapta terminarea lor. Trimiterile si reincercarile care depasesc
+// closeTimeout sunt intrerupte.
+func (d *Dispatcher) Close() {
+	d.mu.Lock()
+	routes := d.routes
+	d.routes = nil
+	d.mu.Unlock()
+	defer d.cancel()
+
+	for _, r := range routes {
+		close(r.queue)
+	}
+	deadline := time.NewTimer(d.closeTimeout)
+	defer deadline.Stop()
+	for _, r := range routes {
+		select {
+		case <-r.done:
+		case <-deadline.C:
+			d.logger.Printf("Notifiers did not finish within %s, cancelling the pending notifications", d.closeTimeout)
+			d.cancel()
+			<-r.done
+		}
+	}
+}
+
+// subject intoarce un rezumat de o linie al evenimentului.
+func subject(event alarms.Event) string {
+	return fmt.Sprintf("[%s] alarm %s: %s on %s", event.Alarm.Severity, event.Type, event.Alarm.Kind, event.Alarm.ElementID)
+}
@@ -0,0 +1,380 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"contor-system/src/alarms"
+	"contor-system/src/utils"
+)
+
+// recorder retine evenimentele primite; delay intarzie prima trimitere.
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+	delay  time.Duration
+}
+
+func (r *recorder) Send(ctx context.Context, event alarms.Event) error {
+	r.mu.Lock()
+	delay := r.delay
+	r.delay = 0
+	r.mu.Unlock()
+	time.Sleep(delay)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, string(event.Type)+" "+event.Alarm.ElementID)
+	return nil
+}
+
+func (r *recorder) received() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.events...)
+}
+
+func testDispatcher(t *testing.T, configs []utils.Notifier, senders []Sender) *Dispatcher {
+	t.Helper()
+	d, err := NewDispatcher(nil, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, config := range configs {
+		d.routes = append(d.routes, d.start(config, senders[i]))
+	}
+	return d
+}
+
+func event(eventType alarms.EventType, severity alarms.Severity, element string) alarms.Event {
+	return alarms.Event{Type: eventType, Alarm: alarms.Alarm{ElementID: element, Kind: alarms.KindOverload, Severity: severity}}
+}
+
+func expectReceived(t *testing.T, name string, got []string, expected ...string) {
+	t.Helper()
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Fatalf("%s received %v, expected %v", name, got, expected)
+	}
+}
+
+func TestRoutingBySeverity(t *testing.T) {
+	all, critical := &recorder{}, &recorder{}
+	d := testDispatcher(t, []utils.Notifier{
+		{ID: "all"},
+		{ID: "critical", Severities: []string{string(alarms.SeverityCritical)}},
+	}, []Sender{all, critical})
+
+	d.Dispatch([]alarms.Event{
+		event(alarms.EventRaised, alarms.SeverityWarning, "line1"),
+		event(alarms.EventRaised, alarms.SeverityCritical, "line2"),
+	})
+	d.Close()
+
+	expectReceived(t, "all", all.received(), "raised line1", "raised line2")
+	expectReceived(t, "critical", critical.received(), "raised line2")
+}
+
+func TestEventsKeepTheirOrder(t *testing.T) {
+	sender := &recorder{delay: 50 * time.Millisecond}
+	d := testDispatcher(t, []utils.Notifier{{ID: "ordered"}}, []Sender{sender})
+
+	// Trimiterea ridicarii dureaza mai mult, dar stergerea nu o poate depasi
+	d.Dispatch([]alarms.Event{event(alarms.EventRaised, alarms.SeverityCritical, "line1")})
+	d.Dispatch([]alarms.Event{event(alarms.EventCleared, alarms.SeverityCritical, "line1")})
+	d.Close()
+
+	expectReceived(t, "ordered", sender.received(), "raised line1", "cleared line1")
+}
+
+func TestRateLimit(t *testing.T) {
+	sender := &recorder{}
+	d := testDispatcher(t, []utils.Notifier{{ID: "limited", RateLimit: 2}}, []Sender{sender})
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	d.now = func() time.Time { return now }
+
+	for _, element := range []string{"line1", "line2", "line3"} {
+		d.Dispatch([]alarms.Event{event(alarms.EventRaised, alarms.SeverityCritical, element)})
+	}
+	// Dupa un minut fereastra se elibereaza
+	now = now.Add(61 * time.Second)
+	d.Dispatch([]alarms.Event{event(alarms.EventRaised, alarms.SeverityCritical, "line4")})
+
+	if dropped := d.Dropped()["limited"]; dropped != 1 {
+		t.Fatalf("dropped %d events, expected 1", dropped)
+	}
+	d.Close()
+	expectReceived(t, "limited", sender.received(), "raised line1", "raised line2", "raised line4")
+}
+
+func TestReloadKeepsRateLimit(t *testing.T) {
+	config := utils.Notifier{ID: "hook", Type: utils.NotifierTypeWebhook, URL: "http://127.0.0.1:1", RateLimit: 1}
+	d, err := NewDispatcher([]utils.Notifier{config}, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	d.now = func() time.Time { return now }
+
+	d.routes[0].allow(now)
+	config.Retries = 3
+	if err := d.Reload([]utils.Notifier{config}); err != nil {
+		t.Fatal(err)
+	}
+	if d.routes[0].allow(now) {
+		t.Fatalf("the rate limit was reset by the reload")
+	}
+}
+
+func TestWebhookRetries(t *testing.T) {
+	var mu sync.Mutex
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if attempts <= 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	sender, err := newWebhookSender(utils.Notifier{URL: server.URL, Retries: 2, Backoff: 0.01})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sender.Send(context.Background(), event(alarms.EventRaised, alarms.SeverityCritical, "line1")); err != nil {
+		t.Fatalf("send after two failures: %v", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("%d attempts, expected 3", attempts)
+	}
+
+	attempts = 0
+	sender.retries = 1
+	if err := sender.Send(context.Background(), event(alarms.EventRaised, alarms.SeverityCritical, "line1")); err == nil {
+		t.Fatalf("send succeeded with the retries exhausted")
+	}
+	if attempts != 2 {
+		t.Fatalf("%d attempts, expected 2", attempts)
+	}
+}
+
+func TestCloseCancelsBackoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	d, err := NewDispatcher([]utils.Notifier{{ID: "hook", Type: utils.NotifierTypeWebhook, URL: server.URL, Retries: 5, Backoff: 60}}, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.closeTimeout = 100 * time.Millisecond
+	d.Dispatch([]alarms.Event{event(alarms.EventRaised, alarms.SeverityCritical, "line1")})
+
+	start := time.Now()
+	d.Close()
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Close waited %s for the backoff", elapsed)
+	}
+}
+
+// smtpMessage este ce a primit serverul SMTP de test intr-o conversatie.
+type smtpMessage struct {
+	from string
+	to   []string
+	data string
+}
+
+// smtpServer accepta o singura conversatie SMTP pe loopback si o trimite pe canal.
+func smtpServer(t *testing.T) (string, <-chan smtpMessage) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	messages := make(chan smtpMessage, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { fmt.Fprintf(conn, "%s\r\n", line) }
+		var message smtpMessage
+		reply("220 localhost ready")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			command := strings.ToUpper(line)
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(command, "MAIL FROM:"):
+				message.from = strings.Trim(line[len("MAIL FROM:"):], "<>")
+				reply("250 OK")
+			case strings.HasPrefix(command, "RCPT TO:"):
+				message.to = append(message.to, strings.Trim(line[len("RCPT TO:"):], "<>"))
+				reply("250 OK")
+			case command == "DATA":
+				reply("354 end with .")
+				var data strings.Builder
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				message.data = data.String()
+				reply("250 queued")
+			case command == "QUIT":
+				reply("221 bye")
+				messages <- message
+				return
+			default:
+				reply("502 not implemented")
+			}
+		}
+	}()
+	return listener.Addr().String(), messages
+}
+
+func TestSMTPSend(t *testing.T) {
+	addr, messages := smtpServer(t)
+	host, port, _ := net.SplitHostPort(addr)
+	var portNumber int
+	fmt.Sscan(port, &portNumber)
+
+	sender, err := newSMTPSender(utils.Notifier{
+		Host: host, Port: portNumber, From: "grid@example.com", To: []string{"ops@example.com", "oncall@example.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := event(alarms.EventRaised, alarms.SeverityCritical, "line1")
+	e.Alarm.Value, e.Alarm.Limit = 120, 100
+	if err := sender.Send(context.Background(), e); err != nil {
+		t.Fatal(err)
+	}
+
+	message := <-messages
+	if message.from != "grid@example.com" {
+		t.Fatalf("MAIL FROM %q", message.from)
+	}
+	if strings.Join(message.to, ",") != "ops@example.com,oncall@example.com" {
+		t.Fatalf("RCPT TO %v", message.to)
+	}
+	for _, expected := range []string{"To: ops@example.com, oncall@example.com\r\n", "Element: line1\r\n", "Value: 120.000, limit: 100.000\r\n"} {
+		if !strings.Contains(message.data, expected) {
+			t.Fatalf("message without %q:\n%s", expected, message.data)
+		}
+	}
+}
+
+func TestSMTPSendStopsOnCancel(t *testing.T) {
+	// Serverul accepta conexiunea dar nu raspunde niciodata
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			defer conn.Close()
+			io.Copy(io.Discard, conn)
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	var portNumber int
+	fmt.Sscan(port, &portNumber)
+	sender, err := newSMTPSender(utils.Notifier{Host: host, Port: portNumber, From: "grid@example.com", To: []string{"ops@example.com"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := sender.Send(ctx, event(alarms.EventRaised, alarms.SeverityCritical, "line1")); err == nil {
+		t.Fatal("send to a silent server succeeded")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Send waited %s after the context ended", elapsed)
+	}
+}
+
+// commandNotifier scrie stdin-ul si variabilele ALARM_* ale scriptului in output.
+func commandNotifier(output string) utils.Notifier {
+	return utils.Notifier{
+		ID: "script", Type: utils.NotifierTypeCommand, Command: "sh",
+		Args: []string{"-c", `{ cat; echo; echo "$ALARM_EVENT $ALARM_ELEMENT $ALARM_SEVERITY $ALARM_VALUE"; } > "$0"`, output},
+	}
+}
+
+func TestCommandNotifier(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "event")
+	sender, err := newCommandSender(commandNotifier(output))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := event(alarms.EventRaised, alarms.SeverityCritical, "line1")
+	e.Alarm.Value = 1.5
+	if err := sender.Send(context.Background(), e); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdin, environment, _ := strings.Cut(string(content), "\n")
+	var received alarms.Event
+	if err := json.Unmarshal([]byte(stdin), &received); err != nil || received.Alarm.ElementID != "line1" {
+		t.Fatalf("stdin %q: %v", stdin, err)
+	}
+	if expected := fmt.Sprintf("%s line1 %s 1.5\n", alarms.EventRaised, alarms.SeverityCritical); environment != expected {
+		t.Fatalf("environment %q, expected %q", environment, expected)
+	}
+}
+
+func TestCloseRunsQueuedCommands(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "event")
+	d, err := NewDispatcher([]utils.Notifier{commandNotifier(output)}, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Dispatch([]alarms.Event{event(alarms.EventRaised, alarms.SeverityCritical, "line1")})
+	d.Close()
+
+	if _, err := os.Stat(output); err != nil {
+		t.Fatalf("the queued command did not run before Close returned: %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"contor-system/src/alarms"
+	"contor-system/src/utils"
+)
+
+// smtpTimeout limiteaza durata unei conversatii SMTP, de la conectare pana la QUIT.
+const smtpTimeout = 30 * time.Second
+
+// smtpSender trimite evenimentul prin email.
+type smtpSender struct {
+	host string
+	addr string
+	auth smtp.Auth
+	from string
+	to   []string
+}
+
+func newSMTPSender(config utils.Notifier) (*smtpSender, error) {
+	if config.Host == "" || config.From == "" || len(config.To) == 0 {
+		return nil, fmt.Errorf("smtp notifier needs host, from and to")
+	}
+
+	port := config.Port
+	if port == 0 {
+		port = 25
+	}
+
+	var auth smtp.Auth
+	if config.Username != "" {
+		auth = smtp.PlainAuth("", config.Username, config.Password, config.Host)
+	}
+
+	return &smtpSender{
+		host: config.Host,
+		addr: fmt.Sprintf("%s:%d", config.Host, port),
+		auth: auth,
+		from: config.From,
+		to:   config.To,
+	}, nil
+}
+
+func (s *smtpSender) Send(ctx context.Context, event alarms.Event) error {
+	var body strings.Builder
+	fmt.Fprintf(&body, "From: %s\r\n", s.from)
+	fmt.Fprintf(&body, "To: %s\r\n", strings.Join(s.to, ", "))
+	fmt.Fprintf(&body, "Subject: %s\r\n", subject(event))
+	fmt.Fprintf(&body, "Date: %s\r\n", event.Time.Format(time.RFC1123Z))
+	body.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	fmt.Fprintf(&body, "Element: %s\r\n", event.Alarm.ElementID)
+	fmt.Fprintf(&body, "Alarm: %s (%s)\r\n", event.Alarm.Kind, event.Alarm.Severity)
+	fmt.Fprintf(&body, "Event: %s at %s\r\n", event.Type, event.Time.Format("2006/01/02-15:04:05"))
+	fmt.Fprintf(&body, "Value: %.3f, limit: %.3f\r\n", event.Alarm.Value, event.Alarm.Limit)
+
+	if err := s.deliver(ctx, body.String()); err != nil {
+		return fmt.Errorf("failed to send email via %s: %v", s.addr, err)
+	}
+	return nil
+}
+
+// deliver poarta conversatia SMTP pe o conexiune cu termen limita, inchisa si la anularea contextului.
+func (s *smtpSender) deliver(ctx context.Context, message string) error {
+	dialer := net.Dialer{Timeout: smtpTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(smtpTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return err
+		}
+	}
+	if s.auth != nil {
+		if err := client.Auth(s.auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(s.from); err != nil {
+		return err
+	}
+	for _, to := range s.to {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write([]byte(message)); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
@@ -0,0 +1,76 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"contor-system/src/alarms"
+	"contor-system/src/utils"
+)
+
+// webhookSender trimite evenimentul ca JSON printr-un POST HTTP, cu reincercari si backoff exponential.
+type webhookSender struct {
+	url     string
+	retries int
+	backoff time.Duration
+	client  *http.Client
+}
+
+func newWebhookSender(config utils.Notifier) (*webhookSender, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("webhook notifier needs a url")
+	}
+
+	backoff := time.Duration(config.Backoff * float64(time.Second))
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+
+	return &webhookSender{
+		url:     config.URL,
+		retries: config.Retries,
+		backoff: backoff,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (w *webhookSender) post(body []byte) error {
+	response, err := w.client.Post(w.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", response.StatusCode)
+	}
+	return nil
+}
+
+func (w *webhookSender) Send(ctx context.Context, event alarms.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %v", err)
+	}
+
+	delay := w.backoff
+	for attempt := 0; ; attempt++ {
+		err = w.post(body)
+		if err == nil {
+			return nil
+		}
+		if attempt >= w.retries {
+			return fmt.Errorf("webhook %s failed after %d attempts: %v", w.url, attempt+1, err)
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return fmt.Errorf("webhook %s failed after %d attempts, retries cancelled: %v", w.url, attempt+1, err)
+		}
+		delay *= 2
+	}
+}
@@ -0,0 +1,158 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+// table este o sectiune a raportului, aceeasi pentru iesirea text, Markdown si HTML.
+type table struct {
+	Title  string
+	Header []string
+	Rows   [][]string
+	Status []Status // starea fiecarui rand, pentru evidentierea in HTML
+}
+
+func number(format string, value float64) string {
+	text := fmt.Sprintf(format, value)
+	// Fara -0.00 pentru valorile rotunjite la zero
+	if strings.Trim(text, "-0.") == "" {
+		return strings.TrimPrefix(text, "-")
+	}
+	return text
+}
+
+func limit(format string, value float64) string {
+	if value <= 0 {
+		return "-"
+	}
+	return number(format, value)
+}
+
+// tables intoarce sectiunile raportului, fara rezumat.
+func tables(r Report) []table {
+	buses := table{Title: "Bus voltages", Header: []string{"Bus", "Kind", "U (kV)", "U (pu)", "Deviation (%)", "Min (pu)", "Max (pu)", "Status"}}
+	for _, bus := range r.Buses {
+		voltage, voltagePU, deviation := number("%.2f", bus.Voltage), number("%.3f", bus.VoltagePU), number("%+.2f", bus.Deviation)
+		if bus.Status == StatusDeenergized {
+			voltage, voltagePU, deviation = "-", "-", "-"
+		}
+		buses.Rows = append(buses.Rows, []string{bus.ID, bus.Kind, voltage, voltagePU, deviation, limit("%.3f", bus.MinVoltage), limit("%.3f", bus.MaxVoltage), string(bus.Status)})
+		buses.Status = append(buses.Status, bus.Status)
+	}
+
+	branches := table{Title: "Branch flows", Header: []string{"Branch", "Kind", "To", "P (MW)", "Q (MVAr)", "I (A)", "Loading (%)", "Max (%)", "Status"}}
+	for _, branch := range r.Branches {
+		branches.Rows = append(branches.Rows, []string{branch.ID, branch.Kind, branch.ConnectedTo, number("%.3f", branch.ActivePower), number("%.3f", branch.ReactivePower),
+			number("%.1f", branch.Current), number("%.1f", branch.Loading), limit("%.0f", branch.MaxLoading), string(branch.Status)})
+		branches.Status = append(branches.Status, branch.Status)
+	}
+
+	transformers := table{Title: "Transformer losses", Header: []string{"Transformer", "Loading (%)", "P losses (kW)", "Q losses (kVAr)", "Rated Cu (kW)", "Rated Fe (kW)"}}
+	for _, transformer := range r.Transformers {
+		transformers.Rows = append(transformers.Rows, []string{transformer.ID, number("%.1f", transformer.Loading), number("%.1f", transformer.ActivePowerLosses*1000),
+			number("%.1f", transformer.ReactivePowerLosses*1000), number("%.1f", transformer.CopperLosses), number("%.1f", transformer.SteelLosses)})
+		transformers.Status = append(transformers.Status, StatusOK)
+	}
+
+	consumers := table{Title: "Consumer supply", Header: []string{"Consumer", "Needed (MW)", "Supplied (MW)", "Unserved (MW)", "U (pu)", "Status"}}
+	for _, consumer := range r.Consumers {
+		voltage := "-"
+		if consumer.VoltagePU > 0 {
+			voltage = number("%.3f", consumer.VoltagePU)
+		}
+		consumers.Rows = append(consumers.Rows, []string{consumer.ID, number("%.2f", consumer.PowerNeeded), number("%.2f", consumer.SuppliedPower),
+			number("%.2f", consumer.UnservedPower), voltage, string(consumer.Status)})
+		consumers.Status = append(consumers.Status, consumer.Status)
+	}
+
+	return []table{buses, branches, transformers, consumers}
+}
+
+// summaryLines intoarce totalurile ca perechi nume - valoare.
+func summaryLines(s Summary) [][2]string {
+	engine := s.Engine
+	if s.Iterations > 0 {
+		engine = fmt.Sprintf("%s, %d iterations, converged: %v", s.Engine, s.Iterations, s.Converged)
+	}
+	return [][2]string{
+		{"Demand", number("%.2f", s.Demand) + " MW"},
+		{"Supplied", number("%.2f", s.SuppliedPower) + " MW"},
+		{"Unserved", number("%.2f", s.UnservedPower) + " MW"},
+		{"Active power losses", fmt.Sprintf("%s MW (%s %%)", number("%.4f", s.ActivePowerLosses), number("%.2f", s.LossesShare))},
+		{"Reactive power losses", number("%.4f", s.ReactivePowerLosses) + " MVAr"},
+		{"Overloaded branches", fmt.Sprint(s.Overloads)},
+		{"Voltage violations", fmt.Sprint(s.VoltageViolations)},
+		{"Unsupplied consumers", fmt.Sprint(s.UnsuppliedConsumers)},
+		{"Engine", engine},
+	}
+}
+
+// FormatText scrie raportul ca tabele text aliniate, pentru terminal.
+func FormatText(r Report) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\nGenerated %s\n", r.Title, r.Generated.Format("2006-01-02 15:04:05"))
+	for _, t := range tables(r) {
+		fmt.Fprintf(&b, "\n%s\n", t.Title)
+		if len(t.Rows) == 0 {
+			b.WriteString("  none\n")
+			continue
+		}
+		w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintln(w, strings.Join(t.Header, "\t")+"\t")
+		for _, row := range t.Rows {
+			fmt.Fprintln(w, strings.Join(row, "\t")+"\t")
+		}
+		w.Flush()
+	}
+
+	b.WriteString("\nSummary\n")
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	for _, line := range summaryLines(r.Summary) {
+		fmt.Fprintf(w, "  %s:\t%s\n", line[0], line[1])
+	}
+	w.Flush()
+	return b.String()
+}
+
+// FormatMarkdown scrie raportul ca document Markdown cu tabele.
+func FormatMarkdown(r Report) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\nGenerated %s\n", r.Title, r.Generated.Format("2006-01-02 15:04:05"))
+
+	b.WriteString("\n## Summary\n\n| | |\n|---|---:|\n")
+	for _, line := range summaryLines(r.Summary) {
+		fmt.Fprintf(&b, "| %s | %s |\n", line[0], markdownCell(line[1]))
+	}
+
+	for _, t := range tables(r) {
+		fmt.Fprintf(&b, "\n## %s\n\n", t.Title)
+		if len(t.Rows) == 0 {
+			b.WriteString("None.\n")
+			continue
+		}
+		b.WriteString("| " + strings.Join(t.Header, " | ") + " |\n|")
+		for i := range t.Header {
+			// Primele coloane sunt nume, restul valori aliniate la dreapta
+			if i == 0 || t.Header[i] == "Kind" || t.Header[i] == "To" || t.Header[i] == "Status" {
+				b.WriteString("---|")
+			} else {
+				b.WriteString("---:|")
+			}
+		}
+		b.WriteString("\n")
+		for _, row := range t.Rows {
+			cells := make([]string, len(row))
+			for i, cell := range row {
+				cells[i] = markdownCell(cell)
+			}
+			b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		}
+	}
+	return b.String()
+}
+
+func markdownCell(text string) string {
+	return strings.ReplaceAll(text, "|", "\\|")
+}
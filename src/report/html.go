@@ -0,0 +1,265 @@
+package report
+
+import (
+	"html/template"
+	"io"
+	"math"
+)
+
+// Raportul HTML este un singur fisier, fara resurse externe: graficele sunt SVG in pagina.
+
+const (
+	chartLabelWidth = 140.0 // px, coloana cu numele elementelor
+	chartPlotWidth  = 460.0 // px
+	chartRowHeight  = 22.0  // px
+	chartAxisHeight = 24.0  // px
+)
+
+var statusColors = map[Status]string{
+	StatusOK:          "#2e7d32",
+	StatusSupplied:    "#2e7d32",
+	StatusLow:         "#ef6c00",
+	StatusHigh:        "#ef6c00",
+	StatusPartial:     "#ef6c00",
+	StatusOverloaded:  "#c62828",
+	StatusUnsupplied:  "#c62828",
+	StatusDeenergized: "#9e9e9e",
+}
+
+type segment struct {
+	X, Width float64
+	Color    string
+}
+
+type bar struct {
+	Label    string
+	Value    string
+	Y        float64 // marginea de sus a randului
+	Top      float64 // marginea de sus a barei
+	Bottom   float64
+	Text     float64 // linia de baza a textului
+	Segments []segment
+	Markers  []float64 // pozitiile limitelor
+}
+
+type tick struct {
+	X     float64
+	Label string
+}
+
+type chart struct {
+	Title  string
+	Unit   string
+	Width  float64
+	Height float64
+	Plot   float64 // inaltimea barelor, deasupra axei
+	Axis   float64 // linia de baza a etichetelor axei
+	Left   float64
+	Right  float64
+	Bars   []bar
+	Ticks  []tick
+	Origin float64 // pozitia valorii 0, pentru graficele cu valori negative
+}
+
+func newBar(label string, value string, row int) bar {
+	y := float64(row) * chartRowHeight
+	return bar{Label: label, Value: value, Y: y, Top: y + 3, Bottom: y + chartRowHeight, Text: y + 15}
+}
+
+func newChart(title string, unit string, minimum float64, maximum float64, rows int) chart {
+	c := chart{
+		Title:  title,
+		Unit:   unit,
+		Width:  chartLabelWidth + chartPlotWidth + 80,
+		Height: float64(rows)*chartRowHeight + chartAxisHeight,
+		Plot:   float64(rows) * chartRowHeight,
+		Axis:   float64(rows)*chartRowHeight + chartAxisHeight - 6,
+		Left:   chartLabelWidth,
+		Right:  chartLabelWidth + chartPlotWidth,
+	}
+	for i := 0; i <= 4; i++ {
+		value := minimum + (maximum-minimum)*float64(i)/4
+		c.Ticks = append(c.Ticks, tick{X: c.scale(value, minimum, maximum), Label: number("%.4g", value)})
+	}
+	c.Origin = c.scale(math.Max(minimum, math.Min(0, maximum)), minimum, maximum)
+	return c
+}
+
+// scale intoarce pozitia orizontala a unei valori, limitata la suprafata graficului.
+func (c chart) scale(value float64, minimum float64, maximum float64) float64 {
+	if maximum <= minimum {
+		return c.Left
+	}
+	ratio := math.Max(0, math.Min(1, (value-minimum)/(maximum-minimum)))
+	return round(c.Left + ratio*chartPlotWidth)
+}
+
+// round pastreaza o zecimala din coordonatele SVG.
+func round(value float64) float64 {
+	return math.Round(value*10) / 10
+}
+
+// loadingChart arata incarcarea laturilor cu limita fiecareia.
+func loadingChart(r Report) chart {
+	maximum := 100.0
+	for _, branch := range r.Branches {
+		maximum = math.Max(maximum, math.Max(branch.Loading, branch.MaxLoading))
+	}
+	maximum = math.Ceil(maximum/20) * 20
+	c := newChart("Branch loading", "%", 0, maximum, len(r.Branches))
+	for i, branch := range r.Branches {
+		b := newBar(branch.ID, number("%.1f", branch.Loading)+" %", i)
+		b.Segments = []segment{{X: c.Left, Width: round(c.scale(branch.Loading, 0, maximum) - c.Left), Color: statusColors[branch.Status]}}
+		if branch.MaxLoading > 0 {
+			b.Markers = []float64{c.scale(branch.MaxLoading, 0, maximum)}
+		}
+		c.Bars = append(c.Bars, b)
+	}
+	return c
+}
+
+// voltageChart arata abaterea tensiunii nodurilor fata de valoarea nominala, cu limitele lor.
+func voltageChart(r Report) chart {
+	span := 10.0
+	for _, bus := range r.Buses {
+		span = math.Max(span, math.Abs(bus.Deviation))
+		if bus.MinVoltage > 0 {
+			span = math.Max(span, (1-bus.MinVoltage)*100)
+		}
+		if bus.MaxVoltage > 0 {
+			span = math.Max(span, (bus.MaxVoltage-1)*100)
+		}
+	}
+	span = math.Ceil(span/5) * 5
+	c := newChart("Bus voltage deviation", "%", -span, span, len(r.Buses))
+	for i, bus := range r.Buses {
+		b := newBar(bus.ID, number("%+.2f", bus.Deviation)+" %", i)
+		if bus.Status == StatusDeenergized {
+			b.Value = "off"
+		}
+		x := c.scale(bus.Deviation, -span, span)
+		b.Segments = []segment{{X: math.Min(x, c.Origin), Width: round(math.Abs(x - c.Origin)), Color: statusColors[bus.Status]}}
+		if bus.MinVoltage > 0 {
+			b.Markers = append(b.Markers, c.scale((bus.MinVoltage-1)*100, -span, span))
+		}
+		if bus.MaxVoltage > 0 {
+			b.Markers = append(b.Markers, c.scale((bus.MaxVoltage-1)*100, -span, span))
+		}
+		c.Bars = append(c.Bars, b)
+	}
+	return c
+}
+
+// supplyChart arata puterea livrata si nelivrata fiecarui consumator.
+func supplyChart(r Report) chart {
+	maximum := 0.0
+	for _, consumer := range r.Consumers {
+		maximum = math.Max(maximum, consumer.PowerNeeded)
+	}
+	if maximum <= 0 {
+		maximum = 1
+	}
+	c := newChart("Consumer supply", "MW", 0, maximum, len(r.Consumers))
+	for i, consumer := range r.Consumers {
+		b := newBar(consumer.ID, number("%.2f", consumer.SuppliedPower)+" / "+number("%.2f", consumer.PowerNeeded)+" MW", i)
+		supplied := c.scale(consumer.SuppliedPower, 0, maximum)
+		needed := c.scale(consumer.PowerNeeded, 0, maximum)
+		b.Segments = []segment{
+			{X: c.Left, Width: round(supplied - c.Left), Color: statusColors[StatusSupplied]},
+			{X: supplied, Width: round(needed - supplied), Color: statusColors[StatusUnsupplied]},
+		}
+		c.Bars = append(c.Bars, b)
+	}
+	return c
+}
+
+type htmlReport struct {
+	Report
+	Summary [][2]string
+	Tables  []table
+	Charts  []chart
+	Colors  map[Status]string
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #212121; }
+h1 { margin-bottom: 0; }
+.generated { color: #757575; margin-top: 0.2em; }
+table { border-collapse: collapse; margin: 0.5em 0 1.5em; }
+th, td { padding: 0.25em 0.8em; border-bottom: 1px solid #e0e0e0; text-align: right; }
+th { background: #f5f5f5; }
+td:first-child, th:first-child { text-align: left; }
+.status { font-weight: bold; text-align: left; }
+.charts { display: flex; flex-wrap: wrap; gap: 1.5em; }
+svg text { font-size: 12px; fill: #424242; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="generated">Generated {{.Generated.Format "2006-01-02 15:04:05"}}</p>
+<h2>Summary</h2>
+<table>
+{{- range .Summary}}
+<tr><td>{{index . 0}}</td><td>{{index . 1}}</td></tr>
+{{- end}}
+</table>
+<div class="charts">
+{{- range .Charts}}{{if .Bars}}
+<figure>
+<figcaption>{{.Title}} ({{.Unit}})</figcaption>
+<svg xmlns="http://www.w3.org/2000/svg" width="{{.Width}}" height="{{.Height}}" viewBox="0 0 {{.Width}} {{.Height}}">
+{{- $chart := .}}
+{{- range .Ticks}}
+<line x1="{{.X}}" y1="0" x2="{{.X}}" y2="{{$chart.Plot}}" stroke="#eeeeee"/>
+<text x="{{.X}}" y="{{$chart.Axis}}" text-anchor="middle">{{.Label}}</text>
+{{- end}}
+<line x1="{{.Origin}}" y1="0" x2="{{.Origin}}" y2="{{.Plot}}" stroke="#9e9e9e"/>
+{{- range .Bars}}
+{{- $bar := .}}
+<text x="{{$chart.Left}}" dx="-6" y="{{.Text}}" text-anchor="end">{{.Label}}</text>
+{{- range .Segments}}
+<rect x="{{.X}}" y="{{$bar.Top}}" width="{{.Width}}" height="16" fill="{{.Color}}"/>
+{{- end}}
+{{- range .Markers}}
+<line x1="{{.}}" y1="{{$bar.Y}}" x2="{{.}}" y2="{{$bar.Bottom}}" stroke="#212121" stroke-width="2" stroke-dasharray="3,2"/>
+{{- end}}
+<text x="{{$chart.Right}}" dx="6" y="{{.Text}}">{{.Value}}</text>
+{{- end}}
+</svg>
+</figure>
+{{- end}}{{end}}
+</div>
+{{- $colors := .Colors}}
+{{- range .Tables}}
+<h2>{{.Title}}</h2>
+{{- if .Rows}}
+<table>
+<tr>{{range .Header}}<th>{{.}}</th>{{end}}</tr>
+{{- $table := .}}
+{{- range $i, $row := .Rows}}
+<tr>{{range $j, $cell := $row}}{{if eq (index $table.Header $j) "Status"}}<td class="status" style="color: {{index $colors (index $table.Status $i)}}">{{$cell}}</td>{{else}}<td>{{$cell}}</td>{{end}}{{end}}</tr>
+{{- end}}
+</table>
+{{- else}}
+<p>None.</p>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+// WriteHTML scrie raportul ca pagina HTML de sine statatoare, cu grafice SVG.
+func WriteHTML(w io.Writer, r Report) error {
+	return htmlTemplate.Execute(w, htmlReport{
+		Report:  r,
+		Summary: summaryLines(r.Summary),
+		Tables:  tables(r),
+		Charts:  []chart{loadingChart(r), voltageChart(r), supplyChart(r)},
+		Colors:  statusColors,
+	})
+}
@@ -0,0 +1,236 @@
+package report
+
+import (
+	"math"
+	"time"
+
+	"contor-system/src/computing"
+	"contor-system/src/utils"
+)
+
+// Raportul unui calcul de circulatie de puteri: tensiunile nodurilor, circulatia prin laturi, pierderile in
+// transformatoare, alimentarea consumatorilor si totalurile, redat ca tabele text, Markdown sau HTML.
+
+type Status string
+
+const (
+	StatusOK          Status = "ok"
+	StatusLow         Status = "low"
+	StatusHigh        Status = "high"
+	StatusOverloaded  Status = "overloaded"
+	StatusDeenergized Status = "off"
+	StatusSupplied    Status = "supplied"
+	StatusPartial     Status = "partial"
+	StatusUnsupplied  Status = "unsupplied"
+)
+
+// Bus este tensiunea unui nod; pentru transformatoare nodul este infasurarea de iesire.
+type Bus struct {
+	ID         string  `json:"id"`
+	Kind       string  `json:"kind"`
+	Voltage    float64 `json:"voltage"`              // kV
+	VoltagePU  float64 `json:"voltagePU"`            // u.r.
+	Deviation  float64 `json:"deviation"`            // % fata de tensiunea nominala
+	MinVoltage float64 `json:"minVoltage,omitempty"` // u.r., 0 = fara limita
+	MaxVoltage float64 `json:"maxVoltage,omitempty"` // u.r., 0 = fara limita
+	Status     Status  `json:"status"`
+}
+
+// Branch este circulatia printr-o linie sau un transformator de putere.
+type Branch struct {
+	ID                  string  `json:"id"`
+	Kind                string  `json:"kind"`
+	ConnectedTo         string  `json:"connectedTo"`
+	ActivePower         float64 `json:"activePower"`   // MW
+	ReactivePower       float64 `json:"reactivePower"` // MVAr
+	Current             float64 `json:"current"`       // A
+	Loading             float64 `json:"loading"`       // %
+	MaxLoading          float64 `json:"maxLoading,omitempty"`
+	ActivePowerLosses   float64 `json:"activePowerLosses"`   // MW
+	ReactivePowerLosses float64 `json:"reactivePowerLosses"` // MVAr
+	Status              Status  `json:"status"`
+}
+
+// TransformerLosses compara pierderile calculate ale unui transformator cu pierderile nominale din configuratie.
+type TransformerLosses struct {
+	ID                  string  `json:"id"`
+	Loading             float64 `json:"loading"`             // %
+	ActivePowerLosses   float64 `json:"activePowerLosses"`   // MW
+	ReactivePowerLosses float64 `json:"reactivePowerLosses"` // MVAr
+	CopperLosses        float64 `json:"copperLosses"`        // kW, nominale
+	SteelLosses         float64 `json:"steelLosses"`         // kW, nominale
+}
+
+// ConsumerSupply este starea alimentarii unui consumator.
+type ConsumerSupply struct {
+	ID            string  `json:"id"`
+	PowerNeeded   float64 `json:"powerNeeded"`   // MW
+	SuppliedPower float64 `json:"suppliedPower"` // MW
+	UnservedPower float64 `json:"unservedPower"` // MW
+	VoltagePU     float64 `json:"voltagePU"`     // u.r.
+	Status        Status  `json:"status"`
+}
+
+// Summary contine totalurile sistemului.
+type Summary struct {
+	Demand              float64 `json:"demand"`              // MW, puterea ceruta de consumatori
+	SuppliedPower       float64 `json:"suppliedPower"`       // MW
+	UnservedPower       float64 `json:"unservedPower"`       // MW
+	ActivePowerLosses   float64 `json:"activePowerLosses"`   // MW
+	ReactivePowerLosses float64 `json:"reactivePowerLosses"` // MVAr
+	LossesShare         float64 `json:"lossesShare"`         // % din puterea livrata plus pierderi
+	Overloads           int     `json:"overloads"`
+	VoltageViolations   int     `json:"voltageViolations"`
+	UnsuppliedConsumers int     `json:"unsuppliedConsumers"`
+	Engine              string  `json:"engine"`
+	Iterations          int     `json:"iterations,omitempty"`
+	Converged           bool    `json:"converged"`
+}
+
+type Report struct {
+	Title        string              `json:"title"`
+	Generated    time.Time           `json:"generated"`
+	Buses        []Bus               `json:"buses"`
+	Branches     []Branch            `json:"branches"`
+	Transformers []TransformerLosses `json:"transformers"`
+	Consumers    []ConsumerSupply    `json:"consumers"`
+	Summary      Summary             `json:"summary"`
+}
+
+// busKinds sunt elementele redate ca noduri; liniile si separatoarele leaga noduri.
+var busKinds = map[string]bool{"source": true, "battery": true, "transformer": true, "consumer": true, "shunt": true}
+
+// Build construieste raportul rezultatului unui calcul pe sistemul dat.
+func Build(title string, system utils.System, result utils.SystemResult, generated time.Time) Report {
+	report := Report{
+		Title:        title,
+		Generated:    generated,
+		Buses:        []Bus{},
+		Branches:     []Branch{},
+		Transformers: []TransformerLosses{},
+		Consumers:    []ConsumerSupply{},
+	}
+	limits := utils.ElementLimits(system)
+
+	connectedTo := map[string]string{}
+	transformers := map[string]utils.Transformer{}
+	measure := map[string]bool{}
+	for _, transformer := range system.Transformers {
+		connectedTo[transformer.ID] = transformer.ConnectedTo
+		transformers[transformer.ID] = transformer
+		measure[transformer.ID] = transformer.Type == utils.TransformerTypeMeasure
+	}
+	for _, line := range system.Lines {
+		connectedTo[line.ID] = line.ConnectedTo
+	}
+
+	for _, id := range result.Order {
+		// Transformatoarele de masura nu transporta putere
+		if measure[id] {
+			continue
+		}
+		element := result.Elements[id]
+		elementLimits := limits[id]
+
+		if busKinds[element.Kind] {
+			bus := Bus{
+				ID:         id,
+				Kind:       element.Kind,
+				Voltage:    element.Voltage,
+				VoltagePU:  element.VoltagePU,
+				MinVoltage: elementLimits.MinVoltage,
+				MaxVoltage: elementLimits.MaxVoltage,
+				Status:     StatusOK,
+			}
+			switch {
+			case !element.Energized:
+				bus.Status = StatusDeenergized
+			case elementLimits.MinVoltage > 0 && element.VoltagePU < elementLimits.MinVoltage:
+				bus.Status = StatusLow
+			case elementLimits.MaxVoltage > 0 && element.VoltagePU > elementLimits.MaxVoltage:
+				bus.Status = StatusHigh
+			}
+			if element.Energized {
+				bus.Deviation = (element.VoltagePU - 1) * 100
+			}
+			if bus.Status == StatusLow || bus.Status == StatusHigh {
+				report.Summary.VoltageViolations++
+			}
+			report.Buses = append(report.Buses, bus)
+		}
+
+		if element.Kind == "line" || element.Kind == "transformer" {
+			branch := Branch{
+				ID:                  id,
+				Kind:                element.Kind,
+				ConnectedTo:         connectedTo[id],
+				ActivePower:         element.ActivePower,
+				ReactivePower:       element.ReactivePower,
+				Current:             math.Abs(element.Current),
+				Loading:             element.Loading,
+				MaxLoading:          elementLimits.MaxLoading,
+				ActivePowerLosses:   element.ActivePowerLosses,
+				ReactivePowerLosses: element.ReactivePowerLosses,
+				Status:              StatusOK,
+			}
+			switch {
+			case elementLimits.MaxLoading > 0 && element.Loading > elementLimits.MaxLoading:
+				branch.Status = StatusOverloaded
+				report.Summary.Overloads++
+			case !element.Energized:
+				branch.Status = StatusDeenergized
+			}
+			report.Branches = append(report.Branches, branch)
+		}
+
+		if transformer, exists := transformers[id]; exists && element.Kind == "transformer" {
+			report.Transformers = append(report.Transformers, TransformerLosses{
+				ID:                  id,
+				Loading:             element.Loading,
+				ActivePowerLosses:   element.ActivePowerLosses,
+				ReactivePowerLosses: element.ReactivePowerLosses,
+				CopperLosses:        transformer.CooperLosses,
+				SteelLosses:         transformer.SteelLosses,
+			})
+		}
+	}
+
+	unserved := computing.UnservedPower(system, result)
+	for _, consumer := range system.Consumers {
+		supply := ConsumerSupply{
+			ID:            consumer.ID,
+			PowerNeeded:   consumer.PowerNeeded,
+			UnservedPower: unserved[consumer.ID],
+			SuppliedPower: consumer.PowerNeeded - unserved[consumer.ID],
+			Status:        StatusSupplied,
+		}
+		if element, exists := result.Elements[consumer.ID]; exists && element.Energized {
+			supply.VoltagePU = element.VoltagePU
+		}
+		switch {
+		case supply.SuppliedPower <= 0 && supply.PowerNeeded > 0:
+			supply.Status = StatusUnsupplied
+			report.Summary.UnsuppliedConsumers++
+		case supply.UnservedPower > 1e-6:
+			supply.Status = StatusPartial
+		}
+		report.Consumers = append(report.Consumers, supply)
+
+		report.Summary.Demand += supply.PowerNeeded
+		report.Summary.SuppliedPower += supply.SuppliedPower
+		report.Summary.UnservedPower += supply.UnservedPower
+	}
+
+	report.Summary.ActivePowerLosses = result.ActivePowerLosses
+	report.Summary.ReactivePowerLosses = result.ReactivePowerLosses
+	if delivered := report.Summary.SuppliedPower + result.ActivePowerLosses; delivered > 0 {
+		report.Summary.LossesShare = result.ActivePowerLosses / delivered * 100
+	}
+	report.Summary.Engine = string(result.Engine)
+	if report.Summary.Engine == "" {
+		report.Summary.Engine = string(utils.EngineTraversal)
+	}
+	report.Summary.Iterations = result.Iterations
+	report.Summary.Converged = result.Converged || result.Iterations == 0
+	return report
+}
@@ -0,0 +1,3718 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: contor.proto
+
+// Serviciul gRPC pentru studii "what-if": circulatie de puteri, serii de timp, analiza N-1 si scurtcircuit.
+// Mesajele modelului urmeaza schema config.json (numele JSON ale campurilor sunt aceleasi).
+
+package contorpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type System struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Source            *Source                `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Transformers      []*Transformer         `protobuf:"bytes,2,rep,name=transformers,proto3" json:"transformers,omitempty"`
+	Lines             []*Line                `protobuf:"bytes,3,rep,name=lines,proto3" json:"lines,omitempty"`
+	Consumers         []*Consumer            `protobuf:"bytes,4,rep,name=consumers,proto3" json:"consumers,omitempty"`
+	Separators        []*Separator           `protobuf:"bytes,5,rep,name=separators,proto3" json:"separators,omitempty"`
+	AdditionalSources []*Source              `protobuf:"bytes,6,rep,name=additional_sources,json=additionalSources,proto3" json:"additional_sources,omitempty"`
+	Alarms            *AlarmSettings         `protobuf:"bytes,7,opt,name=alarms,proto3" json:"alarms,omitempty"`
+	Solver            *Solver                `protobuf:"bytes,8,opt,name=solver,proto3" json:"solver,omitempty"`
+	Batteries         []*Battery             `protobuf:"bytes,9,rep,name=batteries,proto3" json:"batteries,omitempty"`
+	Shunts            []*Shunt               `protobuf:"bytes,10,rep,name=shunts,proto3" json:"shunts,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *System) Reset() {
+	*x = System{}
+	mi := &file_contor_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *System) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*System) ProtoMessage() {}
+
+func (x *System) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use System.ProtoReflect.Descriptor instead.
+func (*System) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *System) GetSource() *Source {
+	if x != nil {
+		return x.Source
+	}
+	return nil
+}
+
+func (x *System) GetTransformers() []*Transformer {
+	if x != nil {
+		return x.Transformers
+	}
+	return nil
+}
+
+func (x *System) GetLines() []*Line {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *System) GetConsumers() []*Consumer {
+	if x != nil {
+		return x.Consumers
+	}
+	return nil
+}
+
+func (x *System) GetSeparators() []*Separator {
+	if x != nil {
+		return x.Separators
+	}
+	return nil
+}
+
+func (x *System) GetAdditionalSources() []*Source {
+	if x != nil {
+		return x.AdditionalSources
+	}
+	return nil
+}
+
+func (x *System) GetAlarms() *AlarmSettings {
+	if x != nil {
+		return x.Alarms
+	}
+	return nil
+}
+
+func (x *System) GetSolver() *Solver {
+	if x != nil {
+		return x.Solver
+	}
+	return nil
+}
+
+func (x *System) GetBatteries() []*Battery {
+	if x != nil {
+		return x.Batteries
+	}
+	return nil
+}
+
+func (x *System) GetShunts() []*Shunt {
+	if x != nil {
+		return x.Shunts
+	}
+	return nil
+}
+
+type Source struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Power             float64                `protobuf:"fixed64,2,opt,name=power,proto3" json:"power,omitempty"`     // MW
+	Voltage           float64                `protobuf:"fixed64,3,opt,name=voltage,proto3" json:"voltage,omitempty"` // kV
+	ConnectedTo       string                 `protobuf:"bytes,4,opt,name=connected_to,json=connectedTo,proto3" json:"connected_to,omitempty"`
+	AdditionalPower   float64                `protobuf:"fixed64,5,opt,name=additional_power,json=additionalPower,proto3" json:"additional_power,omitempty"`
+	ReactivePower     float64                `protobuf:"fixed64,6,opt,name=reactive_power,json=reactivePower,proto3" json:"reactive_power,omitempty"` // MVAr
+	Limits            *Limits                `protobuf:"bytes,7,opt,name=limits,proto3" json:"limits,omitempty"`
+	ShortCircuitPower float64                `protobuf:"fixed64,8,opt,name=short_circuit_power,json=shortCircuitPower,proto3" json:"short_circuit_power,omitempty"` // MVA
+	RxRatio           float64                `protobuf:"fixed64,9,opt,name=rx_ratio,json=rxRatio,proto3" json:"rx_ratio,omitempty"`
+	Generator         bool                   `protobuf:"varint,10,opt,name=generator,proto3" json:"generator,omitempty"`
+	Z2Z1Ratio         float64                `protobuf:"fixed64,11,opt,name=z2z1_ratio,json=z2z1Ratio,proto3" json:"z2z1_ratio,omitempty"`
+	Z0Z1Ratio         float64                `protobuf:"fixed64,12,opt,name=z0z1_ratio,json=z0z1Ratio,proto3" json:"z0z1_ratio,omitempty"`
+	Ungrounded        bool                   `protobuf:"varint,13,opt,name=ungrounded,proto3" json:"ungrounded,omitempty"`
+	VoltageSetpoint   float64                `protobuf:"fixed64,14,opt,name=voltage_setpoint,json=voltageSetpoint,proto3" json:"voltage_setpoint,omitempty"`      // u.r.
+	MinReactivePower  float64                `protobuf:"fixed64,15,opt,name=min_reactive_power,json=minReactivePower,proto3" json:"min_reactive_power,omitempty"` // MVAr
+	MaxReactivePower  float64                `protobuf:"fixed64,16,opt,name=max_reactive_power,json=maxReactivePower,proto3" json:"max_reactive_power,omitempty"` // MVAr
+	Capability        []*CapabilityPoint     `protobuf:"bytes,17,rep,name=capability,proto3" json:"capability,omitempty"`
+	Photovoltaic      *Photovoltaic          `protobuf:"bytes,18,opt,name=photovoltaic,proto3" json:"photovoltaic,omitempty"`
+	Wind              *WindFarm              `protobuf:"bytes,19,opt,name=wind,proto3" json:"wind,omitempty"`
+	Inverter          *InverterControl       `protobuf:"bytes,20,opt,name=inverter,proto3" json:"inverter,omitempty"`
+	MinPower          float64                `protobuf:"fixed64,21,opt,name=min_power,json=minPower,proto3" json:"min_power,omitempty"` // MW
+	MaxPower          float64                `protobuf:"fixed64,22,opt,name=max_power,json=maxPower,proto3" json:"max_power,omitempty"` // MW
+	Cost              *CostCurve             `protobuf:"bytes,23,opt,name=cost,proto3" json:"cost,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Source) Reset() {
+	*x = Source{}
+	mi := &file_contor_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Source) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Source) ProtoMessage() {}
+
+func (x *Source) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Source.ProtoReflect.Descriptor instead.
+func (*Source) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Source) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Source) GetPower() float64 {
+	if x != nil {
+		return x.Power
+	}
+	return 0
+}
+
+func (x *Source) GetVoltage() float64 {
+	if x != nil {
+		return x.Voltage
+	}
+	return 0
+}
+
+func (x *Source) GetConnectedTo() string {
+	if x != nil {
+		return x.ConnectedTo
+	}
+	return ""
+}
+
+func (x *Source) GetAdditionalPower() float64 {
+	if x != nil {
+		return x.AdditionalPower
+	}
+	return 0
+}
+
+func (x *Source) GetReactivePower() float64 {
+	if x != nil {
+		return x.ReactivePower
+	}
+	return 0
+}
+
+func (x *Source) GetLimits() *Limits {
+	if x != nil {
+		return x.Limits
+	}
+	return nil
+}
+
+func (x *Source) GetShortCircuitPower() float64 {
+	if x != nil {
+		return x.ShortCircuitPower
+	}
+	return 0
+}
+
+func (x *Source) GetRxRatio() float64 {
+	if x != nil {
+		return x.RxRatio
+	}
+	return 0
+}
+
+func (x *Source) GetGenerator() bool {
+	if x != nil {
+		return x.Generator
+	}
+	return false
+}
+
+func (x *Source) GetZ2Z1Ratio() float64 {
+	if x != nil {
+		return x.Z2Z1Ratio
+	}
+	return 0
+}
+
+func (x *Source) GetZ0Z1Ratio() float64 {
+	if x != nil {
+		return x.Z0Z1Ratio
+	}
+	return 0
+}
+
+func (x *Source) GetUngrounded() bool {
+	if x != nil {
+		return x.Ungrounded
+	}
+	return false
+}
+
+func (x *Source) GetVoltageSetpoint() float64 {
+	if x != nil {
+		return x.VoltageSetpoint
+	}
+	return 0
+}
+
+func (x *Source) GetMinReactivePower() float64 {
+	if x != nil {
+		return x.MinReactivePower
+	}
+	return 0
+}
+
+func (x *Source) GetMaxReactivePower() float64 {
+	if x != nil {
+		return x.MaxReactivePower
+	}
+	return 0
+}
+
+func (x *Source) GetCapability() []*CapabilityPoint {
+	if x != nil {
+		return x.Capability
+	}
+	return nil
+}
+
+func (x *Source) GetPhotovoltaic() *Photovoltaic {
+	if x != nil {
+		return x.Photovoltaic
+	}
+	return nil
+}
+
+func (x *Source) GetWind() *WindFarm {
+	if x != nil {
+		return x.Wind
+	}
+	return nil
+}
+
+func (x *Source) GetInverter() *InverterControl {
+	if x != nil {
+		return x.Inverter
+	}
+	return nil
+}
+
+func (x *Source) GetMinPower() float64 {
+	if x != nil {
+		return x.MinPower
+	}
+	return 0
+}
+
+func (x *Source) GetMaxPower() float64 {
+	if x != nil {
+		return x.MaxPower
+	}
+	return 0
+}
+
+func (x *Source) GetCost() *CostCurve {
+	if x != nil {
+		return x.Cost
+	}
+	return nil
+}
+
+type CostCurve struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	A             float64                `protobuf:"fixed64,1,opt,name=a,proto3" json:"a,omitempty"`
+	B             float64                `protobuf:"fixed64,2,opt,name=b,proto3" json:"b,omitempty"`
+	C             float64                `protobuf:"fixed64,3,opt,name=c,proto3" json:"c,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CostCurve) Reset() {
+	*x = CostCurve{}
+	mi := &file_contor_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CostCurve) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CostCurve) ProtoMessage() {}
+
+func (x *CostCurve) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CostCurve.ProtoReflect.Descriptor instead.
+func (*CostCurve) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CostCurve) GetA() float64 {
+	if x != nil {
+		return x.A
+	}
+	return 0
+}
+
+func (x *CostCurve) GetB() float64 {
+	if x != nil {
+		return x.B
+	}
+	return 0
+}
+
+func (x *CostCurve) GetC() float64 {
+	if x != nil {
+		return x.C
+	}
+	return 0
+}
+
+type Profile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Step          float64                `protobuf:"fixed64,1,opt,name=step,proto3" json:"step,omitempty"` // s
+	Values        []float64              `protobuf:"fixed64,2,rep,packed,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Profile) Reset() {
+	*x = Profile{}
+	mi := &file_contor_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Profile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Profile) ProtoMessage() {}
+
+func (x *Profile) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Profile.ProtoReflect.Descriptor instead.
+func (*Profile) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Profile) GetStep() float64 {
+	if x != nil {
+		return x.Step
+	}
+	return 0
+}
+
+func (x *Profile) GetValues() []float64 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type CurvePoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	X             float64                `protobuf:"fixed64,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y             float64                `protobuf:"fixed64,2,opt,name=y,proto3" json:"y,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CurvePoint) Reset() {
+	*x = CurvePoint{}
+	mi := &file_contor_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CurvePoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CurvePoint) ProtoMessage() {}
+
+func (x *CurvePoint) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CurvePoint.ProtoReflect.Descriptor instead.
+func (*CurvePoint) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CurvePoint) GetX() float64 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *CurvePoint) GetY() float64 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+type Photovoltaic struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	PeakPower        float64                `protobuf:"fixed64,1,opt,name=peak_power,json=peakPower,proto3" json:"peak_power,omitempty"`                // MWp
+	InverterRating   float64                `protobuf:"fixed64,2,opt,name=inverter_rating,json=inverterRating,proto3" json:"inverter_rating,omitempty"` // MVA
+	PerformanceRatio float64                `protobuf:"fixed64,3,opt,name=performance_ratio,json=performanceRatio,proto3" json:"performance_ratio,omitempty"`
+	Irradiance       *Profile               `protobuf:"bytes,4,opt,name=irradiance,proto3" json:"irradiance,omitempty"` // W/m2
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Photovoltaic) Reset() {
+	*x = Photovoltaic{}
+	mi := &file_contor_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Photovoltaic) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Photovoltaic) ProtoMessage() {}
+
+func (x *Photovoltaic) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Photovoltaic.ProtoReflect.Descriptor instead.
+func (*Photovoltaic) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Photovoltaic) GetPeakPower() float64 {
+	if x != nil {
+		return x.PeakPower
+	}
+	return 0
+}
+
+func (x *Photovoltaic) GetInverterRating() float64 {
+	if x != nil {
+		return x.InverterRating
+	}
+	return 0
+}
+
+func (x *Photovoltaic) GetPerformanceRatio() float64 {
+	if x != nil {
+		return x.PerformanceRatio
+	}
+	return 0
+}
+
+func (x *Photovoltaic) GetIrradiance() *Profile {
+	if x != nil {
+		return x.Irradiance
+	}
+	return nil
+}
+
+type WindFarm struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Turbines      int32                  `protobuf:"varint,1,opt,name=turbines,proto3" json:"turbines,omitempty"`
+	PowerCurve    []*CurvePoint          `protobuf:"bytes,2,rep,name=power_curve,json=powerCurve,proto3" json:"power_curve,omitempty"`
+	Rating        float64                `protobuf:"fixed64,3,opt,name=rating,proto3" json:"rating,omitempty"`                      // MVA
+	WindSpeed     *Profile               `protobuf:"bytes,4,opt,name=wind_speed,json=windSpeed,proto3" json:"wind_speed,omitempty"` // m/s
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WindFarm) Reset() {
+	*x = WindFarm{}
+	mi := &file_contor_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WindFarm) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WindFarm) ProtoMessage() {}
+
+func (x *WindFarm) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WindFarm.ProtoReflect.Descriptor instead.
+func (*WindFarm) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *WindFarm) GetTurbines() int32 {
+	if x != nil {
+		return x.Turbines
+	}
+	return 0
+}
+
+func (x *WindFarm) GetPowerCurve() []*CurvePoint {
+	if x != nil {
+		return x.PowerCurve
+	}
+	return nil
+}
+
+func (x *WindFarm) GetRating() float64 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *WindFarm) GetWindSpeed() *Profile {
+	if x != nil {
+		return x.WindSpeed
+	}
+	return nil
+}
+
+type InverterControl struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mode          string                 `protobuf:"bytes,1,opt,name=mode,proto3" json:"mode,omitempty"` // fixedPowerFactor, qu
+	PowerFactor   float64                `protobuf:"fixed64,2,opt,name=power_factor,json=powerFactor,proto3" json:"power_factor,omitempty"`
+	Qu            []*CurvePoint          `protobuf:"bytes,3,rep,name=qu,proto3" json:"qu,omitempty"`
+	Pu            []*CurvePoint          `protobuf:"bytes,4,rep,name=pu,proto3" json:"pu,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InverterControl) Reset() {
+	*x = InverterControl{}
+	mi := &file_contor_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InverterControl) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InverterControl) ProtoMessage() {}
+
+func (x *InverterControl) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InverterControl.ProtoReflect.Descriptor instead.
+func (*InverterControl) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *InverterControl) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *InverterControl) GetPowerFactor() float64 {
+	if x != nil {
+		return x.PowerFactor
+	}
+	return 0
+}
+
+func (x *InverterControl) GetQu() []*CurvePoint {
+	if x != nil {
+		return x.Qu
+	}
+	return nil
+}
+
+func (x *InverterControl) GetPu() []*CurvePoint {
+	if x != nil {
+		return x.Pu
+	}
+	return nil
+}
+
+type CapabilityPoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	P             float64                `protobuf:"fixed64,1,opt,name=p,proto3" json:"p,omitempty"`                   // MW
+	QMin          float64                `protobuf:"fixed64,2,opt,name=q_min,json=qMin,proto3" json:"q_min,omitempty"` // MVAr
+	QMax          float64                `protobuf:"fixed64,3,opt,name=q_max,json=qMax,proto3" json:"q_max,omitempty"` // MVAr
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CapabilityPoint) Reset() {
+	*x = CapabilityPoint{}
+	mi := &file_contor_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CapabilityPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CapabilityPoint) ProtoMessage() {}
+
+func (x *CapabilityPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CapabilityPoint.ProtoReflect.Descriptor instead.
+func (*CapabilityPoint) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CapabilityPoint) GetP() float64 {
+	if x != nil {
+		return x.P
+	}
+	return 0
+}
+
+func (x *CapabilityPoint) GetQMin() float64 {
+	if x != nil {
+		return x.QMin
+	}
+	return 0
+}
+
+func (x *CapabilityPoint) GetQMax() float64 {
+	if x != nil {
+		return x.QMax
+	}
+	return 0
+}
+
+type Transformer struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	Id                      string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	InputVoltage            float64                `protobuf:"fixed64,2,opt,name=input_voltage,json=inputVoltage,proto3" json:"input_voltage,omitempty"`    // kV
+	OutputVoltage           float64                `protobuf:"fixed64,3,opt,name=output_voltage,json=outputVoltage,proto3" json:"output_voltage,omitempty"` // kV
+	ConnectedTo             string                 `protobuf:"bytes,4,opt,name=connected_to,json=connectedTo,proto3" json:"connected_to,omitempty"`
+	Type                    string                 `protobuf:"bytes,5,opt,name=type,proto3" json:"type,omitempty"` // measure, power
+	Efficency               float64                `protobuf:"fixed64,6,opt,name=efficency,proto3" json:"efficency,omitempty"`
+	ApparentPower           float64                `protobuf:"fixed64,7,opt,name=apparent_power,json=apparentPower,proto3" json:"apparent_power,omitempty"`
+	CooperLosses            float64                `protobuf:"fixed64,8,opt,name=cooper_losses,json=cooperLosses,proto3" json:"cooper_losses,omitempty"` // kW
+	SteelLosses             float64                `protobuf:"fixed64,9,opt,name=steel_losses,json=steelLosses,proto3" json:"steel_losses,omitempty"`    // kW
+	PowerTransfered         float64                `protobuf:"fixed64,10,opt,name=power_transfered,json=powerTransfered,proto3" json:"power_transfered,omitempty"`
+	ReactivePowerTransfered float64                `protobuf:"fixed64,11,opt,name=reactive_power_transfered,json=reactivePowerTransfered,proto3" json:"reactive_power_transfered,omitempty"`
+	Limits                  *Limits                `protobuf:"bytes,12,opt,name=limits,proto3" json:"limits,omitempty"`
+	Uk                      float64                `protobuf:"fixed64,13,opt,name=uk,proto3" json:"uk,omitempty"` // %
+	VectorGroup             string                 `protobuf:"bytes,14,opt,name=vector_group,json=vectorGroup,proto3" json:"vector_group,omitempty"`
+	Z0Z1Ratio               float64                `protobuf:"fixed64,15,opt,name=z0z1_ratio,json=z0z1Ratio,proto3" json:"z0z1_ratio,omitempty"`
+	NeutralResistance       float64                `protobuf:"fixed64,16,opt,name=neutral_resistance,json=neutralResistance,proto3" json:"neutral_resistance,omitempty"` // ohm
+	NeutralReactance        float64                `protobuf:"fixed64,17,opt,name=neutral_reactance,json=neutralReactance,proto3" json:"neutral_reactance,omitempty"`    // ohm
+	Tap                     *TapChanger            `protobuf:"bytes,18,opt,name=tap,proto3" json:"tap,omitempty"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *Transformer) Reset() {
+	*x = Transformer{}
+	mi := &file_contor_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Transformer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Transformer) ProtoMessage() {}
+
+func (x *Transformer) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Transformer.ProtoReflect.Descriptor instead.
+func (*Transformer) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Transformer) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Transformer) GetInputVoltage() float64 {
+	if x != nil {
+		return x.InputVoltage
+	}
+	return 0
+}
+
+func (x *Transformer) GetOutputVoltage() float64 {
+	if x != nil {
+		return x.OutputVoltage
+	}
+	return 0
+}
+
+func (x *Transformer) GetConnectedTo() string {
+	if x != nil {
+		return x.ConnectedTo
+	}
+	return ""
+}
+
+func (x *Transformer) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Transformer) GetEfficency() float64 {
+	if x != nil {
+		return x.Efficency
+	}
+	return 0
+}
+
+func (x *Transformer) GetApparentPower() float64 {
+	if x != nil {
+		return x.ApparentPower
+	}
+	return 0
+}
+
+func (x *Transformer) GetCooperLosses() float64 {
+	if x != nil {
+		return x.CooperLosses
+	}
+	return 0
+}
+
+func (x *Transformer) GetSteelLosses() float64 {
+	if x != nil {
+		return x.SteelLosses
+	}
+	return 0
+}
+
+func (x *Transformer) GetPowerTransfered() float64 {
+	if x != nil {
+		return x.PowerTransfered
+	}
+	return 0
+}
+
+func (x *Transformer) GetReactivePowerTransfered() float64 {
+	if x != nil {
+		return x.ReactivePowerTransfered
+	}
+	return 0
+}
+
+func (x *Transformer) GetLimits() *Limits {
+	if x != nil {
+		return x.Limits
+	}
+	return nil
+}
+
+func (x *Transformer) GetUk() float64 {
+	if x != nil {
+		return x.Uk
+	}
+	return 0
+}
+
+func (x *Transformer) GetVectorGroup() string {
+	if x != nil {
+		return x.VectorGroup
+	}
+	return ""
+}
+
+func (x *Transformer) GetZ0Z1Ratio() float64 {
+	if x != nil {
+		return x.Z0Z1Ratio
+	}
+	return 0
+}
+
+func (x *Transformer) GetNeutralResistance() float64 {
+	if x != nil {
+		return x.NeutralResistance
+	}
+	return 0
+}
+
+func (x *Transformer) GetNeutralReactance() float64 {
+	if x != nil {
+		return x.NeutralReactance
+	}
+	return 0
+}
+
+func (x *Transformer) GetTap() *TapChanger {
+	if x != nil {
+		return x.Tap
+	}
+	return nil
+}
+
+type TapChanger struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Position      int32                  `protobuf:"varint,1,opt,name=position,proto3" json:"position,omitempty"`
+	MinPosition   int32                  `protobuf:"varint,2,opt,name=min_position,json=minPosition,proto3" json:"min_position,omitempty"`
+	MaxPosition   int32                  `protobuf:"varint,3,opt,name=max_position,json=maxPosition,proto3" json:"max_position,omitempty"`
+	StepPercent   float64                `protobuf:"fixed64,4,opt,name=step_percent,json=stepPercent,proto3" json:"step_percent,omitempty"` // %
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TapChanger) Reset() {
+	*x = TapChanger{}
+	mi := &file_contor_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TapChanger) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TapChanger) ProtoMessage() {}
+
+func (x *TapChanger) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TapChanger.ProtoReflect.Descriptor instead.
+func (*TapChanger) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *TapChanger) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *TapChanger) GetMinPosition() int32 {
+	if x != nil {
+		return x.MinPosition
+	}
+	return 0
+}
+
+func (x *TapChanger) GetMaxPosition() int32 {
+	if x != nil {
+		return x.MaxPosition
+	}
+	return 0
+}
+
+func (x *TapChanger) GetStepPercent() float64 {
+	if x != nil {
+		return x.StepPercent
+	}
+	return 0
+}
+
+type Line struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	Id                      string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Voltage                 float64                `protobuf:"fixed64,2,opt,name=voltage,proto3" json:"voltage,omitempty"` // kV
+	Length                  int32                  `protobuf:"varint,3,opt,name=length,proto3" json:"length,omitempty"`    // km
+	ConnectedTo             string                 `protobuf:"bytes,4,opt,name=connected_to,json=connectedTo,proto3" json:"connected_to,omitempty"`
+	Area                    float64                `protobuf:"fixed64,5,opt,name=area,proto3" json:"area,omitempty"`
+	Current                 float64                `protobuf:"fixed64,6,opt,name=current,proto3" json:"current,omitempty"`
+	Ro                      float64                `protobuf:"fixed64,7,opt,name=ro,proto3" json:"ro,omitempty"`
+	Drs                     float64                `protobuf:"fixed64,8,opt,name=drs,json=Drs,proto3" json:"drs,omitempty"`
+	Dst                     float64                `protobuf:"fixed64,9,opt,name=dst,json=Dst,proto3" json:"dst,omitempty"`
+	Drt                     float64                `protobuf:"fixed64,10,opt,name=drt,json=Drt,proto3" json:"drt,omitempty"`
+	ConductorDiameter       float64                `protobuf:"fixed64,11,opt,name=conductor_diameter,json=conductorDiameter,proto3" json:"conductor_diameter,omitempty"`
+	R                       float64                `protobuf:"fixed64,12,opt,name=r,proto3" json:"r,omitempty"`
+	PowerTransfered         float64                `protobuf:"fixed64,13,opt,name=power_transfered,json=powerTransfered,proto3" json:"power_transfered,omitempty"`
+	ReactivePowerTransfered float64                `protobuf:"fixed64,14,opt,name=reactive_power_transfered,json=reactivePowerTransfered,proto3" json:"reactive_power_transfered,omitempty"`
+	ReactivePowerLosses     float64                `protobuf:"fixed64,15,opt,name=reactive_power_losses,json=reactivePowerLosses,proto3" json:"reactive_power_losses,omitempty"`
+	ActivePowerLosses       float64                `protobuf:"fixed64,16,opt,name=active_power_losses,json=activePowerLosses,proto3" json:"active_power_losses,omitempty"`
+	RatedCurrent            float64                `protobuf:"fixed64,17,opt,name=rated_current,json=ratedCurrent,proto3" json:"rated_current,omitempty"` // A
+	Limits                  *Limits                `protobuf:"bytes,18,opt,name=limits,proto3" json:"limits,omitempty"`
+	X                       float64                `protobuf:"fixed64,19,opt,name=x,proto3" json:"x,omitempty"`   // ohm/km
+	R0                      float64                `protobuf:"fixed64,20,opt,name=r0,proto3" json:"r0,omitempty"` // ohm/km
+	X0                      float64                `protobuf:"fixed64,21,opt,name=x0,proto3" json:"x0,omitempty"` // ohm/km
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *Line) Reset() {
+	*x = Line{}
+	mi := &file_contor_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Line) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Line) ProtoMessage() {}
+
+func (x *Line) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Line.ProtoReflect.Descriptor instead.
+func (*Line) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Line) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Line) GetVoltage() float64 {
+	if x != nil {
+		return x.Voltage
+	}
+	return 0
+}
+
+func (x *Line) GetLength() int32 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *Line) GetConnectedTo() string {
+	if x != nil {
+		return x.ConnectedTo
+	}
+	return ""
+}
+
+func (x *Line) GetArea() float64 {
+	if x != nil {
+		return x.Area
+	}
+	return 0
+}
+
+func (x *Line) GetCurrent() float64 {
+	if x != nil {
+		return x.Current
+	}
+	return 0
+}
+
+func (x *Line) GetRo() float64 {
+	if x != nil {
+		return x.Ro
+	}
+	return 0
+}
+
+func (x *Line) GetDrs() float64 {
+	if x != nil {
+		return x.Drs
+	}
+	return 0
+}
+
+func (x *Line) GetDst() float64 {
+	if x != nil {
+		return x.Dst
+	}
+	return 0
+}
+
+func (x *Line) GetDrt() float64 {
+	if x != nil {
+		return x.Drt
+	}
+	return 0
+}
+
+func (x *Line) GetConductorDiameter() float64 {
+	if x != nil {
+		return x.ConductorDiameter
+	}
+	return 0
+}
+
+func (x *Line) GetR() float64 {
+	if x != nil {
+		return x.R
+	}
+	return 0
+}
+
+func (x *Line) GetPowerTransfered() float64 {
+	if x != nil {
+		return x.PowerTransfered
+	}
+	return 0
+}
+
+func (x *Line) GetReactivePowerTransfered() float64 {
+	if x != nil {
+		return x.ReactivePowerTransfered
+	}
+	return 0
+}
+
+func (x *Line) GetReactivePowerLosses() float64 {
+	if x != nil {
+		return x.ReactivePowerLosses
+	}
+	return 0
+}
+
+func (x *Line) GetActivePowerLosses() float64 {
+	if x != nil {
+		return x.ActivePowerLosses
+	}
+	return 0
+}
+
+func (x *Line) GetRatedCurrent() float64 {
+	if x != nil {
+		return x.RatedCurrent
+	}
+	return 0
+}
+
+func (x *Line) GetLimits() *Limits {
+	if x != nil {
+		return x.Limits
+	}
+	return nil
+}
+
+func (x *Line) GetX() float64 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *Line) GetR0() float64 {
+	if x != nil {
+		return x.R0
+	}
+	return 0
+}
+
+func (x *Line) GetX0() float64 {
+	if x != nil {
+		return x.X0
+	}
+	return 0
+}
+
+type Consumer struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Id                    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PowerNeeded           float64                `protobuf:"fixed64,2,opt,name=power_needed,json=powerNeeded,proto3" json:"power_needed,omitempty"` // MW
+	Voltage               float64                `protobuf:"fixed64,3,opt,name=voltage,proto3" json:"voltage,omitempty"`                            // kV
+	ConnectedTo           string                 `protobuf:"bytes,4,opt,name=connected_to,json=connectedTo,proto3" json:"connected_to,omitempty"`
+	RemainingPower        float64                `protobuf:"fixed64,5,opt,name=remaining_power,json=remainingPower,proto3" json:"remaining_power,omitempty"`
+	ReactivePowerAbsorbed float64                `protobuf:"fixed64,6,opt,name=reactive_power_absorbed,json=reactivePowerAbsorbed,proto3" json:"reactive_power_absorbed,omitempty"` // MVAr
+	Limits                *Limits                `protobuf:"bytes,7,opt,name=limits,proto3" json:"limits,omitempty"`
+	Phases                string                 `protobuf:"bytes,8,opt,name=phases,proto3" json:"phases,omitempty"`
+	PhaseLoads            map[string]*PhaseLoad  `protobuf:"bytes,9,rep,name=phase_loads,json=phaseLoads,proto3" json:"phase_loads,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	PowerFactor           float64                `protobuf:"fixed64,10,opt,name=power_factor,json=powerFactor,proto3" json:"power_factor,omitempty"`
+	LoadModel             *LoadModel             `protobuf:"bytes,11,opt,name=load_model,json=loadModel,proto3" json:"load_model,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *Consumer) Reset() {
+	*x = Consumer{}
+	mi := &file_contor_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Consumer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Consumer) ProtoMessage() {}
+
+func (x *Consumer) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Consumer.ProtoReflect.Descriptor instead.
+func (*Consumer) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Consumer) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Consumer) GetPowerNeeded() float64 {
+	if x != nil {
+		return x.PowerNeeded
+	}
+	return 0
+}
+
+func (x *Consumer) GetVoltage() float64 {
+	if x != nil {
+		return x.Voltage
+	}
+	return 0
+}
+
+func (x *Consumer) GetConnectedTo() string {
+	if x != nil {
+		return x.ConnectedTo
+	}
+	return ""
+}
+
+func (x *Consumer) GetRemainingPower() float64 {
+	if x != nil {
+		return x.RemainingPower
+	}
+	return 0
+}
+
+func (x *Consumer) GetReactivePowerAbsorbed() float64 {
+	if x != nil {
+		return x.ReactivePowerAbsorbed
+	}
+	return 0
+}
+
+func (x *Consumer) GetLimits() *Limits {
+	if x != nil {
+		return x.Limits
+	}
+	return nil
+}
+
+func (x *Consumer) GetPhases() string {
+	if x != nil {
+		return x.Phases
+	}
+	return ""
+}
+
+func (x *Consumer) GetPhaseLoads() map[string]*PhaseLoad {
+	if x != nil {
+		return x.PhaseLoads
+	}
+	return nil
+}
+
+func (x *Consumer) GetPowerFactor() float64 {
+	if x != nil {
+		return x.PowerFactor
+	}
+	return 0
+}
+
+func (x *Consumer) GetLoadModel() *LoadModel {
+	if x != nil {
+		return x.LoadModel
+	}
+	return nil
+}
+
+type LoadModel struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"` // constantPower, zip, exponential
+	Zp            float64                `protobuf:"fixed64,2,opt,name=zp,proto3" json:"zp,omitempty"`
+	Ip            float64                `protobuf:"fixed64,3,opt,name=ip,proto3" json:"ip,omitempty"`
+	Pp            float64                `protobuf:"fixed64,4,opt,name=pp,proto3" json:"pp,omitempty"`
+	Zq            float64                `protobuf:"fixed64,5,opt,name=zq,proto3" json:"zq,omitempty"`
+	Iq            float64                `protobuf:"fixed64,6,opt,name=iq,proto3" json:"iq,omitempty"`
+	Pq            float64                `protobuf:"fixed64,7,opt,name=pq,proto3" json:"pq,omitempty"`
+	Np            float64                `protobuf:"fixed64,8,opt,name=np,proto3" json:"np,omitempty"`
+	Nq            float64                `protobuf:"fixed64,9,opt,name=nq,proto3" json:"nq,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoadModel) Reset() {
+	*x = LoadModel{}
+	mi := &file_contor_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoadModel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadModel) ProtoMessage() {}
+
+func (x *LoadModel) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadModel.ProtoReflect.Descriptor instead.
+func (*LoadModel) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *LoadModel) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *LoadModel) GetZp() float64 {
+	if x != nil {
+		return x.Zp
+	}
+	return 0
+}
+
+func (x *LoadModel) GetIp() float64 {
+	if x != nil {
+		return x.Ip
+	}
+	return 0
+}
+
+func (x *LoadModel) GetPp() float64 {
+	if x != nil {
+		return x.Pp
+	}
+	return 0
+}
+
+func (x *LoadModel) GetZq() float64 {
+	if x != nil {
+		return x.Zq
+	}
+	return 0
+}
+
+func (x *LoadModel) GetIq() float64 {
+	if x != nil {
+		return x.Iq
+	}
+	return 0
+}
+
+func (x *LoadModel) GetPq() float64 {
+	if x != nil {
+		return x.Pq
+	}
+	return 0
+}
+
+func (x *LoadModel) GetNp() float64 {
+	if x != nil {
+		return x.Np
+	}
+	return 0
+}
+
+func (x *LoadModel) GetNq() float64 {
+	if x != nil {
+		return x.Nq
+	}
+	return 0
+}
+
+type PhaseLoad struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	P             float64                `protobuf:"fixed64,1,opt,name=p,proto3" json:"p,omitempty"` // MW
+	Q             float64                `protobuf:"fixed64,2,opt,name=q,proto3" json:"q,omitempty"` // MVAr
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PhaseLoad) Reset() {
+	*x = PhaseLoad{}
+	mi := &file_contor_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PhaseLoad) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PhaseLoad) ProtoMessage() {}
+
+func (x *PhaseLoad) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PhaseLoad.ProtoReflect.Descriptor instead.
+func (*PhaseLoad) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *PhaseLoad) GetP() float64 {
+	if x != nil {
+		return x.P
+	}
+	return 0
+}
+
+func (x *PhaseLoad) GetQ() float64 {
+	if x != nil {
+		return x.Q
+	}
+	return 0
+}
+
+type Separator struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConnectsFrom  string                 `protobuf:"bytes,1,opt,name=connects_from,json=connectsFrom,proto3" json:"connects_from,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	State         string                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"` // open, close
+	ConnectedTo   string                 `protobuf:"bytes,4,opt,name=connected_to,json=connectedTo,proto3" json:"connected_to,omitempty"`
+	Type          string                 `protobuf:"bytes,5,opt,name=type,proto3" json:"type,omitempty"` // disconnector, loadBreakSwitch, breaker
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Separator) Reset() {
+	*x = Separator{}
+	mi := &file_contor_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Separator) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Separator) ProtoMessage() {}
+
+func (x *Separator) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Separator.ProtoReflect.Descriptor instead.
+func (*Separator) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Separator) GetConnectsFrom() string {
+	if x != nil {
+		return x.ConnectsFrom
+	}
+	return ""
+}
+
+func (x *Separator) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Separator) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Separator) GetConnectedTo() string {
+	if x != nil {
+		return x.ConnectedTo
+	}
+	return ""
+}
+
+func (x *Separator) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+type Shunt struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type             string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"` // capacitor, reactor
+	ConnectedTo      string                 `protobuf:"bytes,3,opt,name=connected_to,json=connectedTo,proto3" json:"connected_to,omitempty"`
+	StepSize         float64                `protobuf:"fixed64,4,opt,name=step_size,json=stepSize,proto3" json:"step_size,omitempty"` // MVAr
+	Steps            int32                  `protobuf:"varint,5,opt,name=steps,proto3" json:"steps,omitempty"`
+	Step             int32                  `protobuf:"varint,6,opt,name=step,proto3" json:"step,omitempty"`
+	Control          string                 `protobuf:"bytes,7,opt,name=control,proto3" json:"control,omitempty"`                           // voltage, powerFactor
+	MinVoltage       float64                `protobuf:"fixed64,8,opt,name=min_voltage,json=minVoltage,proto3" json:"min_voltage,omitempty"` // u.r.
+	MaxVoltage       float64                `protobuf:"fixed64,9,opt,name=max_voltage,json=maxVoltage,proto3" json:"max_voltage,omitempty"` // u.r.
+	PowerFactor      float64                `protobuf:"fixed64,10,opt,name=power_factor,json=powerFactor,proto3" json:"power_factor,omitempty"`
+	MonitoredElement string                 `protobuf:"bytes,11,opt,name=monitored_element,json=monitoredElement,proto3" json:"monitored_element,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Shunt) Reset() {
+	*x = Shunt{}
+	mi := &file_contor_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Shunt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Shunt) ProtoMessage() {}
+
+func (x *Shunt) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Shunt.ProtoReflect.Descriptor instead.
+func (*Shunt) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Shunt) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Shunt) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Shunt) GetConnectedTo() string {
+	if x != nil {
+		return x.ConnectedTo
+	}
+	return ""
+}
+
+func (x *Shunt) GetStepSize() float64 {
+	if x != nil {
+		return x.StepSize
+	}
+	return 0
+}
+
+func (x *Shunt) GetSteps() int32 {
+	if x != nil {
+		return x.Steps
+	}
+	return 0
+}
+
+func (x *Shunt) GetStep() int32 {
+	if x != nil {
+		return x.Step
+	}
+	return 0
+}
+
+func (x *Shunt) GetControl() string {
+	if x != nil {
+		return x.Control
+	}
+	return ""
+}
+
+func (x *Shunt) GetMinVoltage() float64 {
+	if x != nil {
+		return x.MinVoltage
+	}
+	return 0
+}
+
+func (x *Shunt) GetMaxVoltage() float64 {
+	if x != nil {
+		return x.MaxVoltage
+	}
+	return 0
+}
+
+func (x *Shunt) GetPowerFactor() float64 {
+	if x != nil {
+		return x.PowerFactor
+	}
+	return 0
+}
+
+func (x *Shunt) GetMonitoredElement() string {
+	if x != nil {
+		return x.MonitoredElement
+	}
+	return ""
+}
+
+type Battery struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ConnectedTo       string                 `protobuf:"bytes,2,opt,name=connected_to,json=connectedTo,proto3" json:"connected_to,omitempty"`
+	Capacity          float64                `protobuf:"fixed64,3,opt,name=capacity,proto3" json:"capacity,omitempty"`                                              // kWh
+	MaxChargePower    float64                `protobuf:"fixed64,4,opt,name=max_charge_power,json=maxChargePower,proto3" json:"max_charge_power,omitempty"`          // MW
+	MaxDischargePower float64                `protobuf:"fixed64,5,opt,name=max_discharge_power,json=maxDischargePower,proto3" json:"max_discharge_power,omitempty"` // MW
+	Efficiency        float64                `protobuf:"fixed64,6,opt,name=efficiency,proto3" json:"efficiency,omitempty"`
+	MinSoc            float64                `protobuf:"fixed64,7,opt,name=min_soc,json=minSoc,proto3" json:"min_soc,omitempty"`             // %
+	MaxSoc            float64                `protobuf:"fixed64,8,opt,name=max_soc,json=maxSoc,proto3" json:"max_soc,omitempty"`             // %
+	InitialSoc        float64                `protobuf:"fixed64,9,opt,name=initial_soc,json=initialSoc,proto3" json:"initial_soc,omitempty"` // %
+	Strategy          string                 `protobuf:"bytes,10,opt,name=strategy,proto3" json:"strategy,omitempty"`                        // fixed, peakShaving, schedule, selfConsumption
+	Power             float64                `protobuf:"fixed64,11,opt,name=power,proto3" json:"power,omitempty"`                            // MW
+	PeakLimit         float64                `protobuf:"fixed64,12,opt,name=peak_limit,json=peakLimit,proto3" json:"peak_limit,omitempty"`   // MW
+	MonitoredElement  string                 `protobuf:"bytes,13,opt,name=monitored_element,json=monitoredElement,proto3" json:"monitored_element,omitempty"`
+	Schedule          *Profile               `protobuf:"bytes,14,opt,name=schedule,proto3" json:"schedule,omitempty"` // MW
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Battery) Reset() {
+	*x = Battery{}
+	mi := &file_contor_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Battery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Battery) ProtoMessage() {}
+
+func (x *Battery) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Battery.ProtoReflect.Descriptor instead.
+func (*Battery) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Battery) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Battery) GetConnectedTo() string {
+	if x != nil {
+		return x.ConnectedTo
+	}
+	return ""
+}
+
+func (x *Battery) GetCapacity() float64 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *Battery) GetMaxChargePower() float64 {
+	if x != nil {
+		return x.MaxChargePower
+	}
+	return 0
+}
+
+func (x *Battery) GetMaxDischargePower() float64 {
+	if x != nil {
+		return x.MaxDischargePower
+	}
+	return 0
+}
+
+func (x *Battery) GetEfficiency() float64 {
+	if x != nil {
+		return x.Efficiency
+	}
+	return 0
+}
+
+func (x *Battery) GetMinSoc() float64 {
+	if x != nil {
+		return x.MinSoc
+	}
+	return 0
+}
+
+func (x *Battery) GetMaxSoc() float64 {
+	if x != nil {
+		return x.MaxSoc
+	}
+	return 0
+}
+
+func (x *Battery) GetInitialSoc() float64 {
+	if x != nil {
+		return x.InitialSoc
+	}
+	return 0
+}
+
+func (x *Battery) GetStrategy() string {
+	if x != nil {
+		return x.Strategy
+	}
+	return ""
+}
+
+func (x *Battery) GetPower() float64 {
+	if x != nil {
+		return x.Power
+	}
+	return 0
+}
+
+func (x *Battery) GetPeakLimit() float64 {
+	if x != nil {
+		return x.PeakLimit
+	}
+	return 0
+}
+
+func (x *Battery) GetMonitoredElement() string {
+	if x != nil {
+		return x.MonitoredElement
+	}
+	return ""
+}
+
+func (x *Battery) GetSchedule() *Profile {
+	if x != nil {
+		return x.Schedule
+	}
+	return nil
+}
+
+type Solver struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Engine        string                 `protobuf:"bytes,1,opt,name=engine,proto3" json:"engine,omitempty"`         // traversal, sweep
+	Tolerance     float64                `protobuf:"fixed64,2,opt,name=tolerance,proto3" json:"tolerance,omitempty"` // u.r.
+	MaxIterations int32                  `protobuf:"varint,3,opt,name=max_iterations,json=maxIterations,proto3" json:"max_iterations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Solver) Reset() {
+	*x = Solver{}
+	mi := &file_contor_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Solver) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Solver) ProtoMessage() {}
+
+func (x *Solver) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Solver.ProtoReflect.Descriptor instead.
+func (*Solver) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Solver) GetEngine() string {
+	if x != nil {
+		return x.Engine
+	}
+	return ""
+}
+
+func (x *Solver) GetTolerance() float64 {
+	if x != nil {
+		return x.Tolerance
+	}
+	return 0
+}
+
+func (x *Solver) GetMaxIterations() int32 {
+	if x != nil {
+		return x.MaxIterations
+	}
+	return 0
+}
+
+type Limits struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	MaxLoading     float64                `protobuf:"fixed64,1,opt,name=max_loading,json=maxLoading,proto3" json:"max_loading,omitempty"` // %
+	MinVoltage     float64                `protobuf:"fixed64,2,opt,name=min_voltage,json=minVoltage,proto3" json:"min_voltage,omitempty"` // u.r.
+	MaxVoltage     float64                `protobuf:"fixed64,3,opt,name=max_voltage,json=maxVoltage,proto3" json:"max_voltage,omitempty"` // u.r.
+	MinPowerFactor float64                `protobuf:"fixed64,4,opt,name=min_power_factor,json=minPowerFactor,proto3" json:"min_power_factor,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Limits) Reset() {
+	*x = Limits{}
+	mi := &file_contor_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Limits) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Limits) ProtoMessage() {}
+
+func (x *Limits) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Limits.ProtoReflect.Descriptor instead.
+func (*Limits) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Limits) GetMaxLoading() float64 {
+	if x != nil {
+		return x.MaxLoading
+	}
+	return 0
+}
+
+func (x *Limits) GetMinVoltage() float64 {
+	if x != nil {
+		return x.MinVoltage
+	}
+	return 0
+}
+
+func (x *Limits) GetMaxVoltage() float64 {
+	if x != nil {
+		return x.MaxVoltage
+	}
+	return 0
+}
+
+func (x *Limits) GetMinPowerFactor() float64 {
+	if x != nil {
+		return x.MinPowerFactor
+	}
+	return 0
+}
+
+type AlarmSettings struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DefaultLimits *Limits                `protobuf:"bytes,1,opt,name=default_limits,json=defaultLimits,proto3" json:"default_limits,omitempty"`
+	Hysteresis    float64                `protobuf:"fixed64,2,opt,name=hysteresis,proto3" json:"hysteresis,omitempty"`                   // %
+	RaiseDelay    float64                `protobuf:"fixed64,3,opt,name=raise_delay,json=raiseDelay,proto3" json:"raise_delay,omitempty"` // s
+	ClearDelay    float64                `protobuf:"fixed64,4,opt,name=clear_delay,json=clearDelay,proto3" json:"clear_delay,omitempty"` // s
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AlarmSettings) Reset() {
+	*x = AlarmSettings{}
+	mi := &file_contor_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlarmSettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlarmSettings) ProtoMessage() {}
+
+func (x *AlarmSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlarmSettings.ProtoReflect.Descriptor instead.
+func (*AlarmSettings) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *AlarmSettings) GetDefaultLimits() *Limits {
+	if x != nil {
+		return x.DefaultLimits
+	}
+	return nil
+}
+
+func (x *AlarmSettings) GetHysteresis() float64 {
+	if x != nil {
+		return x.Hysteresis
+	}
+	return 0
+}
+
+func (x *AlarmSettings) GetRaiseDelay() float64 {
+	if x != nil {
+		return x.RaiseDelay
+	}
+	return 0
+}
+
+func (x *AlarmSettings) GetClearDelay() float64 {
+	if x != nil {
+		return x.ClearDelay
+	}
+	return 0
+}
+
+// Modification schimba un element al modelului inainte de calcul.
+type Modification struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Element string                 `protobuf:"bytes,1,opt,name=element,proto3" json:"element,omitempty"`
+	// Types that are valid to be assigned to Change:
+	//
+	//	*Modification_State
+	//	*Modification_OutOfService
+	//	*Modification_ActivePower
+	//	*Modification_ReactivePower
+	//	*Modification_TapPosition
+	Change        isModification_Change `protobuf_oneof:"change"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Modification) Reset() {
+	*x = Modification{}
+	mi := &file_contor_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Modification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Modification) ProtoMessage() {}
+
+func (x *Modification) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Modification.ProtoReflect.Descriptor instead.
+func (*Modification) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Modification) GetElement() string {
+	if x != nil {
+		return x.Element
+	}
+	return ""
+}
+
+func (x *Modification) GetChange() isModification_Change {
+	if x != nil {
+		return x.Change
+	}
+	return nil
+}
+
+func (x *Modification) GetState() string {
+	if x != nil {
+		if x, ok := x.Change.(*Modification_State); ok {
+			return x.State
+		}
+	}
+	return ""
+}
+
+func (x *Modification) GetOutOfService() bool {
+	if x != nil {
+		if x, ok := x.Change.(*Modification_OutOfService); ok {
+			return x.OutOfService
+		}
+	}
+	return false
+}
+
+func (x *Modification) GetActivePower() float64 {
+	if x != nil {
+		if x, ok := x.Change.(*Modification_ActivePower); ok {
+			return x.ActivePower
+		}
+	}
+	return 0
+}
+
+func (x *Modification) GetReactivePower() float64 {
+	if x != nil {
+		if x, ok := x.Change.(*Modification_ReactivePower); ok {
+			return x.ReactivePower
+		}
+	}
+	return 0
+}
+
+func (x *Modification) GetTapPosition() int32 {
+	if x != nil {
+		if x, ok := x.Change.(*Modification_TapPosition); ok {
+			return x.TapPosition
+		}
+	}
+	return 0
+}
+
+type isModification_Change interface {
+	isModification_Change()
+}
+
+type Modification_State struct {
+	State string `protobuf:"bytes,2,opt,name=state,proto3,oneof"` // separator: open, close
+}
+
+type Modification_OutOfService struct {
+	OutOfService bool `protobuf:"varint,3,opt,name=out_of_service,json=outOfService,proto3,oneof"` // linie, transformator sau sursa scoasa din retea
+}
+
+type Modification_ActivePower struct {
+	ActivePower float64 `protobuf:"fixed64,4,opt,name=active_power,json=activePower,proto3,oneof"` // MW: puterea ceruta a consumatorului, puterea sursei sau puterea impusa a bateriei
+}
+
+type Modification_ReactivePower struct {
+	ReactivePower float64 `protobuf:"fixed64,5,opt,name=reactive_power,json=reactivePower,proto3,oneof"` // MVAr: consumator sau sursa
+}
+
+type Modification_TapPosition struct {
+	TapPosition int32 `protobuf:"varint,6,opt,name=tap_position,json=tapPosition,proto3,oneof"` // plotul transformatorului
+}
+
+func (*Modification_State) isModification_Change() {}
+
+func (*Modification_OutOfService) isModification_Change() {}
+
+func (*Modification_ActivePower) isModification_Change() {}
+
+func (*Modification_ReactivePower) isModification_Change() {}
+
+func (*Modification_TapPosition) isModification_Change() {}
+
+type SolveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	System        *System                `protobuf:"bytes,1,opt,name=system,proto3" json:"system,omitempty"` // lipsa = modelul in functiune al serviciului
+	Modifications []*Modification        `protobuf:"bytes,2,rep,name=modifications,proto3" json:"modifications,omitempty"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=time,proto3" json:"time,omitempty"` // momentul profilelor generarii distribuite, implicit momentul curent
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SolveRequest) Reset() {
+	*x = SolveRequest{}
+	mi := &file_contor_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SolveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SolveRequest) ProtoMessage() {}
+
+func (x *SolveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SolveRequest.ProtoReflect.Descriptor instead.
+func (*SolveRequest) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SolveRequest) GetSystem() *System {
+	if x != nil {
+		return x.System
+	}
+	return nil
+}
+
+func (x *SolveRequest) GetModifications() []*Modification {
+	if x != nil {
+		return x.Modifications
+	}
+	return nil
+}
+
+func (x *SolveRequest) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+type SolveResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        *SystemResult          `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SolveResponse) Reset() {
+	*x = SolveResponse{}
+	mi := &file_contor_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SolveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SolveResponse) ProtoMessage() {}
+
+func (x *SolveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SolveResponse.ProtoReflect.Descriptor instead.
+func (*SolveResponse) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *SolveResponse) GetResult() *SystemResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type SolveStreamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	System        *System                `protobuf:"bytes,1,opt,name=system,proto3" json:"system,omitempty"` // lipsa = modelul in functiune al serviciului
+	Modifications []*Modification        `protobuf:"bytes,2,rep,name=modifications,proto3" json:"modifications,omitempty"`
+	Start         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start,proto3" json:"start,omitempty"`  // implicit momentul curent
+	Step          float64                `protobuf:"fixed64,4,opt,name=step,proto3" json:"step,omitempty"`  // s, implicit 900
+	Steps         int32                  `protobuf:"varint,5,opt,name=steps,proto3" json:"steps,omitempty"` // implicit 96
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SolveStreamRequest) Reset() {
+	*x = SolveStreamRequest{}
+	mi := &file_contor_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SolveStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SolveStreamRequest) ProtoMessage() {}
+
+func (x *SolveStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SolveStreamRequest.ProtoReflect.Descriptor instead.
+func (*SolveStreamRequest) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SolveStreamRequest) GetSystem() *System {
+	if x != nil {
+		return x.System
+	}
+	return nil
+}
+
+func (x *SolveStreamRequest) GetModifications() []*Modification {
+	if x != nil {
+		return x.Modifications
+	}
+	return nil
+}
+
+func (x *SolveStreamRequest) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *SolveStreamRequest) GetStep() float64 {
+	if x != nil {
+		return x.Step
+	}
+	return 0
+}
+
+func (x *SolveStreamRequest) GetSteps() int32 {
+	if x != nil {
+		return x.Steps
+	}
+	return 0
+}
+
+type TimeStep struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	Result        *SystemResult          `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
+	BatterySoc    map[string]float64     `protobuf:"bytes,3,rep,name=battery_soc,json=batterySoc,proto3" json:"battery_soc,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"` // %, la inceputul pasului
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimeStep) Reset() {
+	*x = TimeStep{}
+	mi := &file_contor_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimeStep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeStep) ProtoMessage() {}
+
+func (x *TimeStep) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeStep.ProtoReflect.Descriptor instead.
+func (*TimeStep) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *TimeStep) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *TimeStep) GetResult() *SystemResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *TimeStep) GetBatterySoc() map[string]float64 {
+	if x != nil {
+		return x.BatterySoc
+	}
+	return nil
+}
+
+type ElementResult struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Id                  string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Kind                string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`                                          // source, transformer, line, separator, consumer
+	ActivePower         float64                `protobuf:"fixed64,3,opt,name=active_power,json=activePower,proto3" json:"active_power,omitempty"`       // MW
+	ReactivePower       float64                `protobuf:"fixed64,4,opt,name=reactive_power,json=reactivePower,proto3" json:"reactive_power,omitempty"` // MVAr
+	Current             float64                `protobuf:"fixed64,5,opt,name=current,proto3" json:"current,omitempty"`                                  // A
+	Voltage             float64                `protobuf:"fixed64,6,opt,name=voltage,proto3" json:"voltage,omitempty"`                                  // kV
+	VoltagePu           float64                `protobuf:"fixed64,7,opt,name=voltage_pu,json=voltagePu,proto3" json:"voltage_pu,omitempty"`             // u.r.
+	Loading             float64                `protobuf:"fixed64,8,opt,name=loading,proto3" json:"loading,omitempty"`                                  // %
+	PowerFactor         float64                `protobuf:"fixed64,9,opt,name=power_factor,json=powerFactor,proto3" json:"power_factor,omitempty"`
+	ActivePowerLosses   float64                `protobuf:"fixed64,10,opt,name=active_power_losses,json=activePowerLosses,proto3" json:"active_power_losses,omitempty"`       // MW
+	ReactivePowerLosses float64                `protobuf:"fixed64,11,opt,name=reactive_power_losses,json=reactivePowerLosses,proto3" json:"reactive_power_losses,omitempty"` // MVAr
+	Energized           bool                   `protobuf:"varint,12,opt,name=energized,proto3" json:"energized,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *ElementResult) Reset() {
+	*x = ElementResult{}
+	mi := &file_contor_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ElementResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ElementResult) ProtoMessage() {}
+
+func (x *ElementResult) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ElementResult.ProtoReflect.Descriptor instead.
+func (*ElementResult) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ElementResult) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ElementResult) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *ElementResult) GetActivePower() float64 {
+	if x != nil {
+		return x.ActivePower
+	}
+	return 0
+}
+
+func (x *ElementResult) GetReactivePower() float64 {
+	if x != nil {
+		return x.ReactivePower
+	}
+	return 0
+}
+
+func (x *ElementResult) GetCurrent() float64 {
+	if x != nil {
+		return x.Current
+	}
+	return 0
+}
+
+func (x *ElementResult) GetVoltage() float64 {
+	if x != nil {
+		return x.Voltage
+	}
+	return 0
+}
+
+func (x *ElementResult) GetVoltagePu() float64 {
+	if x != nil {
+		return x.VoltagePu
+	}
+	return 0
+}
+
+func (x *ElementResult) GetLoading() float64 {
+	if x != nil {
+		return x.Loading
+	}
+	return 0
+}
+
+func (x *ElementResult) GetPowerFactor() float64 {
+	if x != nil {
+		return x.PowerFactor
+	}
+	return 0
+}
+
+func (x *ElementResult) GetActivePowerLosses() float64 {
+	if x != nil {
+		return x.ActivePowerLosses
+	}
+	return 0
+}
+
+func (x *ElementResult) GetReactivePowerLosses() float64 {
+	if x != nil {
+		return x.ReactivePowerLosses
+	}
+	return 0
+}
+
+func (x *ElementResult) GetEnergized() bool {
+	if x != nil {
+		return x.Energized
+	}
+	return false
+}
+
+type SystemResult struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Elements            []*ElementResult       `protobuf:"bytes,1,rep,name=elements,proto3" json:"elements,omitempty"` // in ordinea parcurgerii
+	UnservedConsumers   []*UnservedConsumer    `protobuf:"bytes,2,rep,name=unserved_consumers,json=unservedConsumers,proto3" json:"unserved_consumers,omitempty"`
+	ActivePowerLosses   float64                `protobuf:"fixed64,3,opt,name=active_power_losses,json=activePowerLosses,proto3" json:"active_power_losses,omitempty"`       // MW
+	ReactivePowerLosses float64                `protobuf:"fixed64,4,opt,name=reactive_power_losses,json=reactivePowerLosses,proto3" json:"reactive_power_losses,omitempty"` // MVAr
+	Engine              string                 `protobuf:"bytes,5,opt,name=engine,proto3" json:"engine,omitempty"`
+	Iterations          int32                  `protobuf:"varint,6,opt,name=iterations,proto3" json:"iterations,omitempty"`
+	Converged           bool                   `protobuf:"varint,7,opt,name=converged,proto3" json:"converged,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *SystemResult) Reset() {
+	*x = SystemResult{}
+	mi := &file_contor_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SystemResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SystemResult) ProtoMessage() {}
+
+func (x *SystemResult) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SystemResult.ProtoReflect.Descriptor instead.
+func (*SystemResult) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *SystemResult) GetElements() []*ElementResult {
+	if x != nil {
+		return x.Elements
+	}
+	return nil
+}
+
+func (x *SystemResult) GetUnservedConsumers() []*UnservedConsumer {
+	if x != nil {
+		return x.UnservedConsumers
+	}
+	return nil
+}
+
+func (x *SystemResult) GetActivePowerLosses() float64 {
+	if x != nil {
+		return x.ActivePowerLosses
+	}
+	return 0
+}
+
+func (x *SystemResult) GetReactivePowerLosses() float64 {
+	if x != nil {
+		return x.ReactivePowerLosses
+	}
+	return 0
+}
+
+func (x *SystemResult) GetEngine() string {
+	if x != nil {
+		return x.Engine
+	}
+	return ""
+}
+
+func (x *SystemResult) GetIterations() int32 {
+	if x != nil {
+		return x.Iterations
+	}
+	return 0
+}
+
+func (x *SystemResult) GetConverged() bool {
+	if x != nil {
+		return x.Converged
+	}
+	return false
+}
+
+type ContingencyRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	System         *System                `protobuf:"bytes,1,opt,name=system,proto3" json:"system,omitempty"` // lipsa = modelul in functiune al serviciului
+	Modifications  []*Modification        `protobuf:"bytes,2,rep,name=modifications,proto3" json:"modifications,omitempty"`
+	IncludeSources bool                   `protobuf:"varint,3,opt,name=include_sources,json=includeSources,proto3" json:"include_sources,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ContingencyRequest) Reset() {
+	*x = ContingencyRequest{}
+	mi := &file_contor_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContingencyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContingencyRequest) ProtoMessage() {}
+
+func (x *ContingencyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContingencyRequest.ProtoReflect.Descriptor instead.
+func (*ContingencyRequest) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ContingencyRequest) GetSystem() *System {
+	if x != nil {
+		return x.System
+	}
+	return nil
+}
+
+func (x *ContingencyRequest) GetModifications() []*Modification {
+	if x != nil {
+		return x.Modifications
+	}
+	return nil
+}
+
+func (x *ContingencyRequest) GetIncludeSources() bool {
+	if x != nil {
+		return x.IncludeSources
+	}
+	return false
+}
+
+type Violation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ElementId     string                 `protobuf:"bytes,1,opt,name=element_id,json=elementId,proto3" json:"element_id,omitempty"`
+	Kind          string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"` // overload, undervoltage, overvoltage
+	Value         float64                `protobuf:"fixed64,3,opt,name=value,proto3" json:"value,omitempty"`
+	Limit         float64                `protobuf:"fixed64,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Violation) Reset() {
+	*x = Violation{}
+	mi := &file_contor_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Violation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Violation) ProtoMessage() {}
+
+func (x *Violation) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Violation.ProtoReflect.Descriptor instead.
+func (*Violation) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *Violation) GetElementId() string {
+	if x != nil {
+		return x.ElementId
+	}
+	return ""
+}
+
+func (x *Violation) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Violation) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Violation) GetLimit() float64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type UnservedConsumer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UnservedPower float64                `protobuf:"fixed64,2,opt,name=unserved_power,json=unservedPower,proto3" json:"unserved_power,omitempty"` // MW
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnservedConsumer) Reset() {
+	*x = UnservedConsumer{}
+	mi := &file_contor_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnservedConsumer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnservedConsumer) ProtoMessage() {}
+
+func (x *UnservedConsumer) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnservedConsumer.ProtoReflect.Descriptor instead.
+func (*UnservedConsumer) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *UnservedConsumer) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UnservedConsumer) GetUnservedPower() float64 {
+	if x != nil {
+		return x.UnservedPower
+	}
+	return 0
+}
+
+type ContingencyCase struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Outage            string                 `protobuf:"bytes,1,opt,name=outage,proto3" json:"outage,omitempty"` // gol pentru cazul de baza
+	OutageKind        string                 `protobuf:"bytes,2,opt,name=outage_kind,json=outageKind,proto3" json:"outage_kind,omitempty"`
+	Overloads         []*Violation           `protobuf:"bytes,3,rep,name=overloads,proto3" json:"overloads,omitempty"`
+	VoltageViolations []*Violation           `protobuf:"bytes,4,rep,name=voltage_violations,json=voltageViolations,proto3" json:"voltage_violations,omitempty"`
+	UnservedConsumers []*UnservedConsumer    `protobuf:"bytes,5,rep,name=unserved_consumers,json=unservedConsumers,proto3" json:"unserved_consumers,omitempty"`
+	UnservedPower     float64                `protobuf:"fixed64,6,opt,name=unserved_power,json=unservedPower,proto3" json:"unserved_power,omitempty"`               // MW
+	ActivePowerLosses float64                `protobuf:"fixed64,7,opt,name=active_power_losses,json=activePowerLosses,proto3" json:"active_power_losses,omitempty"` // MW
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ContingencyCase) Reset() {
+	*x = ContingencyCase{}
+	mi := &file_contor_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContingencyCase) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContingencyCase) ProtoMessage() {}
+
+func (x *ContingencyCase) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContingencyCase.ProtoReflect.Descriptor instead.
+func (*ContingencyCase) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ContingencyCase) GetOutage() string {
+	if x != nil {
+		return x.Outage
+	}
+	return ""
+}
+
+func (x *ContingencyCase) GetOutageKind() string {
+	if x != nil {
+		return x.OutageKind
+	}
+	return ""
+}
+
+func (x *ContingencyCase) GetOverloads() []*Violation {
+	if x != nil {
+		return x.Overloads
+	}
+	return nil
+}
+
+func (x *ContingencyCase) GetVoltageViolations() []*Violation {
+	if x != nil {
+		return x.VoltageViolations
+	}
+	return nil
+}
+
+func (x *ContingencyCase) GetUnservedConsumers() []*UnservedConsumer {
+	if x != nil {
+		return x.UnservedConsumers
+	}
+	return nil
+}
+
+func (x *ContingencyCase) GetUnservedPower() float64 {
+	if x != nil {
+		return x.UnservedPower
+	}
+	return 0
+}
+
+func (x *ContingencyCase) GetActivePowerLosses() float64 {
+	if x != nil {
+		return x.ActivePowerLosses
+	}
+	return 0
+}
+
+type ContingencyReport struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Base          *ContingencyCase       `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
+	Cases         []*ContingencyCase     `protobuf:"bytes,2,rep,name=cases,proto3" json:"cases,omitempty"` // de la cel mai sever caz
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ContingencyReport) Reset() {
+	*x = ContingencyReport{}
+	mi := &file_contor_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContingencyReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContingencyReport) ProtoMessage() {}
+
+func (x *ContingencyReport) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContingencyReport.ProtoReflect.Descriptor instead.
+func (*ContingencyReport) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ContingencyReport) GetBase() *ContingencyCase {
+	if x != nil {
+		return x.Base
+	}
+	return nil
+}
+
+func (x *ContingencyReport) GetCases() []*ContingencyCase {
+	if x != nil {
+		return x.Cases
+	}
+	return nil
+}
+
+type ShortCircuitRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	System        *System                `protobuf:"bytes,1,opt,name=system,proto3" json:"system,omitempty"` // lipsa = modelul in functiune al serviciului
+	Modifications []*Modification        `protobuf:"bytes,2,rep,name=modifications,proto3" json:"modifications,omitempty"`
+	Case          string                 `protobuf:"bytes,3,opt,name=case,proto3" json:"case,omitempty"`                                         // max (implicit), min
+	C             float64                `protobuf:"fixed64,4,opt,name=c,proto3" json:"c,omitempty"`                                             // factor de tensiune impus; 0 = dupa nivelul de tensiune
+	MinTimeDelay  float64                `protobuf:"fixed64,5,opt,name=min_time_delay,json=minTimeDelay,proto3" json:"min_time_delay,omitempty"` // s, implicit 0.1
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShortCircuitRequest) Reset() {
+	*x = ShortCircuitRequest{}
+	mi := &file_contor_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShortCircuitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortCircuitRequest) ProtoMessage() {}
+
+func (x *ShortCircuitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortCircuitRequest.ProtoReflect.Descriptor instead.
+func (*ShortCircuitRequest) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ShortCircuitRequest) GetSystem() *System {
+	if x != nil {
+		return x.System
+	}
+	return nil
+}
+
+func (x *ShortCircuitRequest) GetModifications() []*Modification {
+	if x != nil {
+		return x.Modifications
+	}
+	return nil
+}
+
+func (x *ShortCircuitRequest) GetCase() string {
+	if x != nil {
+		return x.Case
+	}
+	return ""
+}
+
+func (x *ShortCircuitRequest) GetC() float64 {
+	if x != nil {
+		return x.C
+	}
+	return 0
+}
+
+func (x *ShortCircuitRequest) GetMinTimeDelay() float64 {
+	if x != nil {
+		return x.MinTimeDelay
+	}
+	return 0
+}
+
+type ShortCircuitNode struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Node          string                 `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	Voltage       float64                `protobuf:"fixed64,2,opt,name=voltage,proto3" json:"voltage,omitempty"` // kV
+	C             float64                `protobuf:"fixed64,3,opt,name=c,proto3" json:"c,omitempty"`
+	R             float64                `protobuf:"fixed64,4,opt,name=r,proto3" json:"r,omitempty"`   // ohm
+	X             float64                `protobuf:"fixed64,5,opt,name=x,proto3" json:"x,omitempty"`   // ohm
+	Ik            float64                `protobuf:"fixed64,6,opt,name=ik,proto3" json:"ik,omitempty"` // kA
+	Ip            float64                `protobuf:"fixed64,7,opt,name=ip,proto3" json:"ip,omitempty"` // kA
+	Ib            float64                `protobuf:"fixed64,8,opt,name=ib,proto3" json:"ib,omitempty"` // kA
+	Sk            float64                `protobuf:"fixed64,9,opt,name=sk,proto3" json:"sk,omitempty"` // MVA
+	Sources       []string               `protobuf:"bytes,10,rep,name=sources,proto3" json:"sources,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShortCircuitNode) Reset() {
+	*x = ShortCircuitNode{}
+	mi := &file_contor_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShortCircuitNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortCircuitNode) ProtoMessage() {}
+
+func (x *ShortCircuitNode) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortCircuitNode.ProtoReflect.Descriptor instead.
+func (*ShortCircuitNode) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ShortCircuitNode) GetNode() string {
+	if x != nil {
+		return x.Node
+	}
+	return ""
+}
+
+func (x *ShortCircuitNode) GetVoltage() float64 {
+	if x != nil {
+		return x.Voltage
+	}
+	return 0
+}
+
+func (x *ShortCircuitNode) GetC() float64 {
+	if x != nil {
+		return x.C
+	}
+	return 0
+}
+
+func (x *ShortCircuitNode) GetR() float64 {
+	if x != nil {
+		return x.R
+	}
+	return 0
+}
+
+func (x *ShortCircuitNode) GetX() float64 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *ShortCircuitNode) GetIk() float64 {
+	if x != nil {
+		return x.Ik
+	}
+	return 0
+}
+
+func (x *ShortCircuitNode) GetIp() float64 {
+	if x != nil {
+		return x.Ip
+	}
+	return 0
+}
+
+func (x *ShortCircuitNode) GetIb() float64 {
+	if x != nil {
+		return x.Ib
+	}
+	return 0
+}
+
+func (x *ShortCircuitNode) GetSk() float64 {
+	if x != nil {
+		return x.Sk
+	}
+	return 0
+}
+
+func (x *ShortCircuitNode) GetSources() []string {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+type ShortCircuitResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Nodes         []*ShortCircuitNode    `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShortCircuitResponse) Reset() {
+	*x = ShortCircuitResponse{}
+	mi := &file_contor_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShortCircuitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortCircuitResponse) ProtoMessage() {}
+
+func (x *ShortCircuitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_contor_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortCircuitResponse.ProtoReflect.Descriptor instead.
+func (*ShortCircuitResponse) Descriptor() ([]byte, []int) {
+	return file_contor_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ShortCircuitResponse) GetNodes() []*ShortCircuitNode {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+var File_contor_proto protoreflect.FileDescriptor
+
+const file_contor_proto_rawDesc = "" +
+	"\n" +
+	"\fcontor.proto\x12\tcontor.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xfa\x03\n" +
+	"\x06System\x12)\n" +
+	"\x06source\x18\x01 \x01(\v2\x11.contor.v1.SourceR\x06source\x12:\n" +
+	"\ftransformers\x18\x02 \x03(\v2\x16.contor.v1.TransformerR\ftransformers\x12%\n" +
+	"\x05lines\x18\x03 \x03(\v2\x0f.contor.v1.LineR\x05lines\x121\n" +
+	"\tconsumers\x18\x04 \x03(\v2\x13.contor.v1.ConsumerR\tconsumers\x124\n" +
+	"\n" +
+	"separators\x18\x05 \x03(\v2\x14.contor.v1.SeparatorR\n" +
+	"separators\x12@\n" +
+	"\x12additional_sources\x18\x06 \x03(\v2\x11.contor.v1.SourceR\x11additionalSources\x120\n" +
+	"\x06alarms\x18\a \x01(\v2\x18.contor.v1.AlarmSettingsR\x06alarms\x12)\n" +
+	"\x06solver\x18\b \x01(\v2\x11.contor.v1.SolverR\x06solver\x120\n" +
+	"\tbatteries\x18\t \x03(\v2\x12.contor.v1.BatteryR\tbatteries\x12(\n" +
+	"\x06shunts\x18\n" +
+	" \x03(\v2\x10.contor.v1.ShuntR\x06shunts\"\xf4\x06\n" +
+	"\x06Source\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05power\x18\x02 \x01(\x01R\x05power\x12\x18\n" +
+	"\avoltage\x18\x03 \x01(\x01R\avoltage\x12!\n" +
+	"\fconnected_to\x18\x04 \x01(\tR\vconnectedTo\x12)\n" +
+	"\x10additional_power\x18\x05 \x01(\x01R\x0fadditionalPower\x12%\n" +
+	"\x0ereactive_power\x18\x06 \x01(\x01R\rreactivePower\x12)\n" +
+	"\x06limits\x18\a \x01(\v2\x11.contor.v1.LimitsR\x06limits\x12.\n" +
+	"\x13short_circuit_power\x18\b \x01(\x01R\x11shortCircuitPower\x12\x19\n" +
+	"\brx_ratio\x18\t \x01(\x01R\arxRatio\x12\x1c\n" +
+	"\tgenerator\x18\n" +
+	" \x01(\bR\tgenerator\x12\x1d\n" +
+	"\n" +
+	"z2z1_ratio\x18\v \x01(\x01R\tz2z1Ratio\x12\x1d\n" +
+	"\n" +
+	"z0z1_ratio\x18\f \x01(\x01R\tz0z1Ratio\x12\x1e\n" +
+	"\n" +
+	"ungrounded\x18\r \x01(\bR\n" +
+	"ungrounded\x12)\n" +
+	"\x10voltage_setpoint\x18\x0e \x01(\x01R\x0fvoltageSetpoint\x12,\n" +
+	"\x12min_reactive_power\x18\x0f \x01(\x01R\x10minReactivePower\x12,\n" +
+	"\x12max_reactive_power\x18\x10 \x01(\x01R\x10maxReactivePower\x12:\n" +
+	"\n" +
+	"capability\x18\x11 \x03(\v2\x1a.contor.v1.CapabilityPointR\n" +
+	"capability\x12;\n" +
+	"\fphotovoltaic\x18\x12 \x01(\v2\x17.contor.v1.PhotovoltaicR\fphotovoltaic\x12'\n" +
+	"\x04wind\x18\x13 \x01(\v2\x13.contor.v1.WindFarmR\x04wind\x126\n" +
+	"\binverter\x18\x14 \x01(\v2\x1a.contor.v1.InverterControlR\binverter\x12\x1b\n" +
+	"\tmin_power\x18\x15 \x01(\x01R\bminPower\x12\x1b\n" +
+	"\tmax_power\x18\x16 \x01(\x01R\bmaxPower\x12(\n" +
+	"\x04cost\x18\x17 \x01(\v2\x14.contor.v1.CostCurveR\x04cost\"5\n" +
+	"\tCostCurve\x12\f\n" +
+	"\x01a\x18\x01 \x01(\x01R\x01a\x12\f\n" +
+	"\x01b\x18\x02 \x01(\x01R\x01b\x12\f\n" +
+	"\x01c\x18\x03 \x01(\x01R\x01c\"5\n" +
+	"\aProfile\x12\x12\n" +
+	"\x04step\x18\x01 \x01(\x01R\x04step\x12\x16\n" +
+	"\x06values\x18\x02 \x03(\x01R\x06values\"(\n" +
+	"\n" +
+	"CurvePoint\x12\f\n" +
+	"\x01x\x18\x01 \x01(\x01R\x01x\x12\f\n" +
+	"\x01y\x18\x02 \x01(\x01R\x01y\"\xb7\x01\n" +
+	"\fPhotovoltaic\x12\x1d\n" +
+	"\n" +
+	"peak_power\x18\x01 \x01(\x01R\tpeakPower\x12'\n" +
+	"\x0finverter_rating\x18\x02 \x01(\x01R\x0einverterRating\x12+\n" +
+	"\x11performance_ratio\x18\x03 \x01(\x01R\x10performanceRatio\x122\n" +
+	"\n" +
+	"irradiance\x18\x04 \x01(\v2\x12.contor.v1.ProfileR\n" +
+	"irradiance\"\xa9\x01\n" +
+	"\bWindFarm\x12\x1a\n" +
+	"\bturbines\x18\x01 \x01(\x05R\bturbines\x126\n" +
+	"\vpower_curve\x18\x02 \x03(\v2\x15.contor.v1.CurvePointR\n" +
+	"powerCurve\x12\x16\n" +
+	"\x06rating\x18\x03 \x01(\x01R\x06rating\x121\n" +
+	"\n" +
+	"wind_speed\x18\x04 \x01(\v2\x12.contor.v1.ProfileR\twindSpeed\"\x96\x01\n" +
+	"\x0fInverterControl\x12\x12\n" +
+	"\x04mode\x18\x01 \x01(\tR\x04mode\x12!\n" +
+	"\fpower_factor\x18\x02 \x01(\x01R\vpowerFactor\x12%\n" +
+	"\x02qu\x18\x03 \x03(\v2\x15.contor.v1.CurvePointR\x02qu\x12%\n" +
+	"\x02pu\x18\x04 \x03(\v2\x15.contor.v1.CurvePointR\x02pu\"I\n" +
+	"\x0fCapabilityPoint\x12\f\n" +
+	"\x01p\x18\x01 \x01(\x01R\x01p\x12\x13\n" +
+	"\x05q_min\x18\x02 \x01(\x01R\x04qMin\x12\x13\n" +
+	"\x05q_max\x18\x03 \x01(\x01R\x04qMax\"\x96\x05\n" +
+	"\vTransformer\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12#\n" +
+	"\rinput_voltage\x18\x02 \x01(\x01R\finputVoltage\x12%\n" +
+	"\x0eoutput_voltage\x18\x03 \x01(\x01R\routputVoltage\x12!\n" +
+	"\fconnected_to\x18\x04 \x01(\tR\vconnectedTo\x12\x12\n" +
+	"\x04type\x18\x05 \x01(\tR\x04type\x12\x1c\n" +
+	"\tefficency\x18\x06 \x01(\x01R\tefficency\x12%\n" +
+	"\x0eapparent_power\x18\a \x01(\x01R\rapparentPower\x12#\n" +
+	"\rcooper_losses\x18\b \x01(\x01R\fcooperLosses\x12!\n" +
+	"\fsteel_losses\x18\t \x01(\x01R\vsteelLosses\x12)\n" +
+	"\x10power_transfered\x18\n" +
+	" \x01(\x01R\x0fpowerTransfered\x12:\n" +
+	"\x19reactive_power_transfered\x18\v \x01(\x01R\x17reactivePowerTransfered\x12)\n" +
+	"\x06limits\x18\f \x01(\v2\x11.contor.v1.LimitsR\x06limits\x12\x0e\n" +
+	"\x02uk\x18\r \x01(\x01R\x02uk\x12!\n" +
+	"\fvector_group\x18\x0e \x01(\tR\vvectorGroup\x12\x1d\n" +
+	"\n" +
+	"z0z1_ratio\x18\x0f \x01(\x01R\tz0z1Ratio\x12-\n" +
+	"\x12neutral_resistance\x18\x10 \x01(\x01R\x11neutralResistance\x12+\n" +
+	"\x11neutral_reactance\x18\x11 \x01(\x01R\x10neutralReactance\x12'\n" +
+	"\x03tap\x18\x12 \x01(\v2\x15.contor.v1.TapChangerR\x03tap\"\x91\x01\n" +
+	"\n" +
+	"TapChanger\x12\x1a\n" +
+	"\bposition\x18\x01 \x01(\x05R\bposition\x12!\n" +
+	"\fmin_position\x18\x02 \x01(\x05R\vminPosition\x12!\n" +
+	"\fmax_position\x18\x03 \x01(\x05R\vmaxPosition\x12!\n" +
+	"\fstep_percent\x18\x04 \x01(\x01R\vstepPercent\"\xe5\x04\n" +
+	"\x04Line\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\avoltage\x18\x02 \x01(\x01R\avoltage\x12\x16\n" +
+	"\x06length\x18\x03 \x01(\x05R\x06length\x12!\n" +
+	"\fconnected_to\x18\x04 \x01(\tR\vconnectedTo\x12\x12\n" +
+	"\x04area\x18\x05 \x01(\x01R\x04area\x12\x18\n" +
+	"\acurrent\x18\x06 \x01(\x01R\acurrent\x12\x0e\n" +
+	"\x02ro\x18\a \x01(\x01R\x02ro\x12\x10\n" +
+	"\x03drs\x18\b \x01(\x01R\x03Drs\x12\x10\n" +
+	"\x03dst\x18\t \x01(\x01R\x03Dst\x12\x10\n" +
+	"\x03drt\x18\n" +
+	" \x01(\x01R\x03Drt\x12-\n" +
+	"\x12conductor_diameter\x18\v \x01(\x01R\x11conductorDiameter\x12\f\n" +
+	"\x01r\x18\f \x01(\x01R\x01r\x12)\n" +
+	"\x10power_transfered\x18\r \x01(\x01R\x0fpowerTransfered\x12:\n" +
+	"\x19reactive_power_transfered\x18\x0e \x01(\x01R\x17reactivePowerTransfered\x122\n" +
+	"\x15reactive_power_losses\x18\x0f \x01(\x01R\x13reactivePowerLosses\x12.\n" +
+	"\x13active_power_losses\x18\x10 \x01(\x01R\x11activePowerLosses\x12#\n" +
+	"\rrated_current\x18\x11 \x01(\x01R\fratedCurrent\x12)\n" +
+	"\x06limits\x18\x12 \x01(\v2\x11.contor.v1.LimitsR\x06limits\x12\f\n" +
+	"\x01x\x18\x13 \x01(\x01R\x01x\x12\x0e\n" +
+	"\x02r0\x18\x14 \x01(\x01R\x02r0\x12\x0e\n" +
+	"\x02x0\x18\x15 \x01(\x01R\x02x0\"\x91\x04\n" +
+	"\bConsumer\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12!\n" +
+	"\fpower_needed\x18\x02 \x01(\x01R\vpowerNeeded\x12\x18\n" +
+	"\avoltage\x18\x03 \x01(\x01R\avoltage\x12!\n" +
+	"\fconnected_to\x18\x04 \x01(\tR\vconnectedTo\x12'\n" +
+	"\x0fremaining_power\x18\x05 \x01(\x01R\x0eremainingPower\x126\n" +
+	"\x17reactive_power_absorbed\x18\x06 \x01(\x01R\x15reactivePowerAbsorbed\x12)\n" +
+	"\x06limits\x18\a \x01(\v2\x11.contor.v1.LimitsR\x06limits\x12\x16\n" +
+	"\x06phases\x18\b \x01(\tR\x06phases\x12D\n" +
+	"\vphase_loads\x18\t \x03(\v2#.contor.v1.Consumer.PhaseLoadsEntryR\n" +
+	"phaseLoads\x12!\n" +
+	"\fpower_factor\x18\n" +
+	" \x01(\x01R\vpowerFactor\x123\n" +
+	"\n" +
+	"load_model\x18\v \x01(\v2\x14.contor.v1.LoadModelR\tloadModel\x1aS\n" +
+	"\x0fPhaseLoadsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12*\n" +
+	"\x05value\x18\x02 \x01(\v2\x14.contor.v1.PhaseLoadR\x05value:\x028\x01\"\x9f\x01\n" +
+	"\tLoadModel\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x0e\n" +
+	"\x02zp\x18\x02 \x01(\x01R\x02zp\x12\x0e\n" +
+	"\x02ip\x18\x03 \x01(\x01R\x02ip\x12\x0e\n" +
+	"\x02pp\x18\x04 \x01(\x01R\x02pp\x12\x0e\n" +
+	"\x02zq\x18\x05 \x01(\x01R\x02zq\x12\x0e\n" +
+	"\x02iq\x18\x06 \x01(\x01R\x02iq\x12\x0e\n" +
+	"\x02pq\x18\a \x01(\x01R\x02pq\x12\x0e\n" +
+	"\x02np\x18\b \x01(\x01R\x02np\x12\x0e\n" +
+	"\x02nq\x18\t \x01(\x01R\x02nq\"'\n" +
+	"\tPhaseLoad\x12\f\n" +
+	"\x01p\x18\x01 \x01(\x01R\x01p\x12\f\n" +
+	"\x01q\x18\x02 \x01(\x01R\x01q\"\x8d\x01\n" +
+	"\tSeparator\x12#\n" +
+	"\rconnects_from\x18\x01 \x01(\tR\fconnectsFrom\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x14\n" +
+	"\x05state\x18\x03 \x01(\tR\x05state\x12!\n" +
+	"\fconnected_to\x18\x04 \x01(\tR\vconnectedTo\x12\x12\n" +
+	"\x04type\x18\x05 \x01(\tR\x04type\"\xc1\x02\n" +
+	"\x05Shunt\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12!\n" +
+	"\fconnected_to\x18\x03 \x01(\tR\vconnectedTo\x12\x1b\n" +
+	"\tstep_size\x18\x04 \x01(\x01R\bstepSize\x12\x14\n" +
+	"\x05steps\x18\x05 \x01(\x05R\x05steps\x12\x12\n" +
+	"\x04step\x18\x06 \x01(\x05R\x04step\x12\x18\n" +
+	"\acontrol\x18\a \x01(\tR\acontrol\x12\x1f\n" +
+	"\vmin_voltage\x18\b \x01(\x01R\n" +
+	"minVoltage\x12\x1f\n" +
+	"\vmax_voltage\x18\t \x01(\x01R\n" +
+	"maxVoltage\x12!\n" +
+	"\fpower_factor\x18\n" +
+	" \x01(\x01R\vpowerFactor\x12+\n" +
+	"\x11monitored_element\x18\v \x01(\tR\x10monitoredElement\"\xd3\x03\n" +
+	"\aBattery\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12!\n" +
+	"\fconnected_to\x18\x02 \x01(\tR\vconnectedTo\x12\x1a\n" +
+	"\bcapacity\x18\x03 \x01(\x01R\bcapacity\x12(\n" +
+	"\x10max_charge_power\x18\x04 \x01(\x01R\x0emaxChargePower\x12.\n" +
+	"\x13max_discharge_power\x18\x05 \x01(\x01R\x11maxDischargePower\x12\x1e\n" +
+	"\n" +
+	"efficiency\x18\x06 \x01(\x01R\n" +
+	"efficiency\x12\x17\n" +
+	"\amin_soc\x18\a \x01(\x01R\x06minSoc\x12\x17\n" +
+	"\amax_soc\x18\b \x01(\x01R\x06maxSoc\x12\x1f\n" +
+	"\vinitial_soc\x18\t \x01(\x01R\n" +
+	"initialSoc\x12\x1a\n" +
+	"\bstrategy\x18\n" +
+	" \x01(\tR\bstrategy\x12\x14\n" +
+	"\x05power\x18\v \x01(\x01R\x05power\x12\x1d\n" +
+	"\n" +
+	"peak_limit\x18\f \x01(\x01R\tpeakLimit\x12+\n" +
+	"\x11monitored_element\x18\r \x01(\tR\x10monitoredElement\x12.\n" +
+	"\bschedule\x18\x0e \x01(\v2\x12.contor.v1.ProfileR\bschedule\"e\n" +
+	"\x06Solver\x12\x16\n" +
+	"\x06engine\x18\x01 \x01(\tR\x06engine\x12\x1c\n" +
+	"\ttolerance\x18\x02 \x01(\x01R\ttolerance\x12%\n" +
+	"\x0emax_iterations\x18\x03 \x01(\x05R\rmaxIterations\"\x95\x01\n" +
+	"\x06Limits\x12\x1f\n" +
+	"\vmax_loading\x18\x01 \x01(\x01R\n" +
+	"maxLoading\x12\x1f\n" +
+	"\vmin_voltage\x18\x02 \x01(\x01R\n" +
+	"minVoltage\x12\x1f\n" +
+	"\vmax_voltage\x18\x03 \x01(\x01R\n" +
+	"maxVoltage\x12(\n" +
+	"\x10min_power_factor\x18\x04 \x01(\x01R\x0eminPowerFactor\"\xab\x01\n" +
+	"\rAlarmSettings\x128\n" +
+	"\x0edefault_limits\x18\x01 \x01(\v2\x11.contor.v1.LimitsR\rdefaultLimits\x12\x1e\n" +
+	"\n" +
+	"hysteresis\x18\x02 \x01(\x01R\n" +
+	"hysteresis\x12\x1f\n" +
+	"\vraise_delay\x18\x03 \x01(\x01R\n" +
+	"raiseDelay\x12\x1f\n" +
+	"\vclear_delay\x18\x04 \x01(\x01R\n" +
+	"clearDelay\"\xe5\x01\n" +
+	"\fModification\x12\x18\n" +
+	"\aelement\x18\x01 \x01(\tR\aelement\x12\x16\n" +
+	"\x05state\x18\x02 \x01(\tH\x00R\x05state\x12&\n" +
+	"\x0eout_of_service\x18\x03 \x01(\bH\x00R\foutOfService\x12#\n" +
+	"\factive_power\x18\x04 \x01(\x01H\x00R\vactivePower\x12'\n" +
+	"\x0ereactive_power\x18\x05 \x01(\x01H\x00R\rreactivePower\x12#\n" +
+	"\ftap_position\x18\x06 \x01(\x05H\x00R\vtapPositionB\b\n" +
+	"\x06change\"\xa8\x01\n" +
+	"\fSolveRequest\x12)\n" +
+	"\x06system\x18\x01 \x01(\v2\x11.contor.v1.SystemR\x06system\x12=\n" +
+	"\rmodifications\x18\x02 \x03(\v2\x17.contor.v1.ModificationR\rmodifications\x12.\n" +
+	"\x04time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\"@\n" +
+	"\rSolveResponse\x12/\n" +
+	"\x06result\x18\x01 \x01(\v2\x17.contor.v1.SystemResultR\x06result\"\xda\x01\n" +
+	"\x12SolveStreamRequest\x12)\n" +
+	"\x06system\x18\x01 \x01(\v2\x11.contor.v1.SystemR\x06system\x12=\n" +
+	"\rmodifications\x18\x02 \x03(\v2\x17.contor.v1.ModificationR\rmodifications\x120\n" +
+	"\x05start\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12\x12\n" +
+	"\x04step\x18\x04 \x01(\x01R\x04step\x12\x14\n" +
+	"\x05steps\x18\x05 \x01(\x05R\x05steps\"\xf0\x01\n" +
+	"\bTimeStep\x12.\n" +
+	"\x04time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12/\n" +
+	"\x06result\x18\x02 \x01(\v2\x17.contor.v1.SystemResultR\x06result\x12D\n" +
+	"\vbattery_soc\x18\x03 \x03(\v2#.contor.v1.TimeStep.BatterySocEntryR\n" +
+	"batterySoc\x1a=\n" +
+	"\x0fBatterySocEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"\x8f\x03\n" +
+	"\rElementResult\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12!\n" +
+	"\factive_power\x18\x03 \x01(\x01R\vactivePower\x12%\n" +
+	"\x0ereactive_power\x18\x04 \x01(\x01R\rreactivePower\x12\x18\n" +
+	"\acurrent\x18\x05 \x01(\x01R\acurrent\x12\x18\n" +
+	"\avoltage\x18\x06 \x01(\x01R\avoltage\x12\x1d\n" +
+	"\n" +
+	"voltage_pu\x18\a \x01(\x01R\tvoltagePu\x12\x18\n" +
+	"\aloading\x18\b \x01(\x01R\aloading\x12!\n" +
+	"\fpower_factor\x18\t \x01(\x01R\vpowerFactor\x12.\n" +
+	"\x13active_power_losses\x18\n" +
+	" \x01(\x01R\x11activePowerLosses\x122\n" +
+	"\x15reactive_power_losses\x18\v \x01(\x01R\x13reactivePowerLosses\x12\x1c\n" +
+	"\tenergized\x18\f \x01(\bR\tenergized\"\xca\x02\n" +
+	"\fSystemResult\x124\n" +
+	"\belements\x18\x01 \x03(\v2\x18.contor.v1.ElementResultR\belements\x12J\n" +
+	"\x12unserved_consumers\x18\x02 \x03(\v2\x1b.contor.v1.UnservedConsumerR\x11unservedConsumers\x12.\n" +
+	"\x13active_power_losses\x18\x03 \x01(\x01R\x11activePowerLosses\x122\n" +
+	"\x15reactive_power_losses\x18\x04 \x01(\x01R\x13reactivePowerLosses\x12\x16\n" +
+	"\x06engine\x18\x05 \x01(\tR\x06engine\x12\x1e\n" +
+	"\n" +
+	"iterations\x18\x06 \x01(\x05R\n" +
+	"iterations\x12\x1c\n" +
+	"\tconverged\x18\a \x01(\bR\tconverged\"\xa7\x01\n" +
+	"\x12ContingencyRequest\x12)\n" +
+	"\x06system\x18\x01 \x01(\v2\x11.contor.v1.SystemR\x06system\x12=\n" +
+	"\rmodifications\x18\x02 \x03(\v2\x17.contor.v1.ModificationR\rmodifications\x12'\n" +
+	"\x0finclude_sources\x18\x03 \x01(\bR\x0eincludeSources\"j\n" +
+	"\tViolation\x12\x1d\n" +
+	"\n" +
+	"element_id\x18\x01 \x01(\tR\telementId\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\x01R\x05value\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x01R\x05limit\"I\n" +
+	"\x10UnservedConsumer\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12%\n" +
+	"\x0eunserved_power\x18\x02 \x01(\x01R\runservedPower\"\xe6\x02\n" +
+	"\x0fContingencyCase\x12\x16\n" +
+	"\x06outage\x18\x01 \x01(\tR\x06outage\x12\x1f\n" +
+	"\voutage_kind\x18\x02 \x01(\tR\n" +
+	"outageKind\x122\n" +
+	"\toverloads\x18\x03 \x03(\v2\x14.contor.v1.ViolationR\toverloads\x12C\n" +
+	"\x12voltage_violations\x18\x04 \x03(\v2\x14.contor.v1.ViolationR\x11voltageViolations\x12J\n" +
+	"\x12unserved_consumers\x18\x05 \x03(\v2\x1b.contor.v1.UnservedConsumerR\x11unservedConsumers\x12%\n" +
+	"\x0eunserved_power\x18\x06 \x01(\x01R\runservedPower\x12.\n" +
+	"\x13active_power_losses\x18\a \x01(\x01R\x11activePowerLosses\"u\n" +
+	"\x11ContingencyReport\x12.\n" +
+	"\x04base\x18\x01 \x01(\v2\x1a.contor.v1.ContingencyCaseR\x04base\x120\n" +
+	"\x05cases\x18\x02 \x03(\v2\x1a.contor.v1.ContingencyCaseR\x05cases\"\xc7\x01\n" +
+	"\x13ShortCircuitRequest\x12)\n" +
+	"\x06system\x18\x01 \x01(\v2\x11.contor.v1.SystemR\x06system\x12=\n" +
+	"\rmodifications\x18\x02 \x03(\v2\x17.contor.v1.ModificationR\rmodifications\x12\x12\n" +
+	"\x04case\x18\x03 \x01(\tR\x04case\x12\f\n" +
+	"\x01c\x18\x04 \x01(\x01R\x01c\x12$\n" +
+	"\x0emin_time_delay\x18\x05 \x01(\x01R\fminTimeDelay\"\xc4\x01\n" +
+	"\x10ShortCircuitNode\x12\x12\n" +
+	"\x04node\x18\x01 \x01(\tR\x04node\x12\x18\n" +
+	"\avoltage\x18\x02 \x01(\x01R\avoltage\x12\f\n" +
+	"\x01c\x18\x03 \x01(\x01R\x01c\x12\f\n" +
+	"\x01r\x18\x04 \x01(\x01R\x01r\x12\f\n" +
+	"\x01x\x18\x05 \x01(\x01R\x01x\x12\x0e\n" +
+	"\x02ik\x18\x06 \x01(\x01R\x02ik\x12\x0e\n" +
+	"\x02ip\x18\a \x01(\x01R\x02ip\x12\x0e\n" +
+	"\x02ib\x18\b \x01(\x01R\x02ib\x12\x0e\n" +
+	"\x02sk\x18\t \x01(\x01R\x02sk\x12\x18\n" +
+	"\asources\x18\n" +
+	" \x03(\tR\asources\"I\n" +
+	"\x14ShortCircuitResponse\x121\n" +
+	"\x05nodes\x18\x01 \x03(\v2\x1b.contor.v1.ShortCircuitNodeR\x05nodes2\xa6\x02\n" +
+	"\x06Contor\x12:\n" +
+	"\x05Solve\x12\x17.contor.v1.SolveRequest\x1a\x18.contor.v1.SolveResponse\x12C\n" +
+	"\vSolveStream\x12\x1d.contor.v1.SolveStreamRequest\x1a\x13.contor.v1.TimeStep0\x01\x12J\n" +
+	"\vContingency\x12\x1d.contor.v1.ContingencyRequest\x1a\x1c.contor.v1.ContingencyReport\x12O\n" +
+	"\fShortCircuit\x12\x1e.contor.v1.ShortCircuitRequest\x1a\x1f.contor.v1.ShortCircuitResponseB Z\x1econtor-system/src/rpc/contorpbb\x06proto3"
+
+var (
+	file_contor_proto_rawDescOnce sync.Once
+	file_contor_proto_rawDescData []byte
+)
+
+func file_contor_proto_rawDescGZIP() []byte {
+	file_contor_proto_rawDescOnce.Do(func() {
+		file_contor_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_contor_proto_rawDesc), len(file_contor_proto_rawDesc)))
+	})
+	return file_contor_proto_rawDescData
+}
+
+var file_contor_proto_msgTypes = make([]protoimpl.MessageInfo, 38)
+var file_contor_proto_goTypes = []any{
+	(*System)(nil),                // 0: contor.v1.System
+	(*Source)(nil),                // 1: contor.v1.Source
+	(*CostCurve)(nil),             // 2: contor.v1.CostCurve
+	(*Profile)(nil),               // 3: contor.v1.Profile
+	(*CurvePoint)(nil),            // 4: contor.v1.CurvePoint
+	(*Photovoltaic)(nil),          // 5: contor.v1.Photovoltaic
+	(*WindFarm)(nil),              // 6: contor.v1.WindFarm
+	(*InverterControl)(nil),       // 7: contor.v1.InverterControl
+	(*CapabilityPoint)(nil),       // 8: contor.v1.CapabilityPoint
+	(*Transformer)(nil),           // 9: contor.v1.Transformer
+	(*TapChanger)(nil),            // 10: contor.v1.TapChanger
+	(*Line)(nil),                  // 11: contor.v1.Line
+	(*Consumer)(nil),              // 12: contor.v1.Consumer
+	(*LoadModel)(nil),             // 13: contor.v1.LoadModel
+	(*PhaseLoad)(nil),             // 14: contor.v1.PhaseLoad
+	(*Separator)(nil),             // 15: contor.v1.Separator
+	(*Shunt)(nil),                 // 16: contor.v1.Shunt
+	(*Battery)(nil),               // 17: contor.v1.Battery
+	(*Solver)(nil),                // 18: contor.v1.Solver
+	(*Limits)(nil),                // 19: contor.v1.Limits
+	(*AlarmSettings)(nil),         // 20: contor.v1.AlarmSettings
+	(*Modification)(nil),          // 21: contor.v1.Modification
+	(*SolveRequest)(nil),          // 22: contor.v1.SolveRequest
+	(*SolveResponse)(nil),         // 23: contor.v1.SolveResponse
+	(*SolveStreamRequest)(nil),    // 24: contor.v1.SolveStreamRequest
+	(*TimeStep)(nil),              // 25: contor.v1.TimeStep
+	(*ElementResult)(nil),         // 26: contor.v1.ElementResult
+	(*SystemResult)(nil),          // 27: contor.v1.SystemResult
+	(*ContingencyRequest)(nil),    // 28: contor.v1.ContingencyRequest
+	(*Violation)(nil),             // 29: contor.v1.Violation
+	(*UnservedConsumer)(nil),      // 30: contor.v1.UnservedConsumer
+	(*ContingencyCase)(nil),       // 31: contor.v1.ContingencyCase
+	(*ContingencyReport)(nil),     // 32: contor.v1.ContingencyReport
+	(*ShortCircuitRequest)(nil),   // 33: contor.v1.ShortCircuitRequest
+	(*ShortCircuitNode)(nil),      // 34: contor.v1.ShortCircuitNode
+	(*ShortCircuitResponse)(nil),  // 35: contor.v1.ShortCircuitResponse
+	nil,                           // 36: contor.v1.Consumer.PhaseLoadsEntry
+	nil,                           // 37: contor.v1.TimeStep.BatterySocEntry
+	(*timestamppb.Timestamp)(nil), // 38: google.protobuf.Timestamp
+}
+var file_contor_proto_depIdxs = []int32{
+	1,  // 0: contor.v1.System.source:type_name -> contor.v1.Source
+	9,  // 1: contor.v1.System.transformers:type_name -> contor.v1.Transformer
+	11, // 2: contor.v1.System.lines:type_name -> contor.v1.Line
+	12, // 3: contor.v1.System.consumers:type_name -> contor.v1.Consumer
+	15, // 4: contor.v1.System.separators:type_name -> contor.v1.Separator
+	1,  // 5: contor.v1.System.additional_sources:type_name -> contor.v1.Source
+	20, // 6: contor.v1.System.alarms:type_name -> contor.v1.AlarmSettings
+	18, // 7: contor.v1.System.solver:type_name -> contor.v1.Solver
+	17, // 8: contor.v1.System.batteries:type_name -> contor.v1.Battery
+	16, // 9: contor.v1.System.shunts:type_name -> contor.v1.Shunt
+	19, // 10: contor.v1.Source.limits:type_name -> contor.v1.Limits
+	8,  // 11: contor.v1.Source.capability:type_name -> contor.v1.CapabilityPoint
+	5,  // 12: contor.v1.Source.photovoltaic:type_name -> contor.v1.Photovoltaic
+	6,  // 13: contor.v1.Source.wind:type_name -> contor.v1.WindFarm
+	7,  // 14: contor.v1.Source.inverter:type_name -> contor.v1.InverterControl
+	2,  // 15: contor.v1.Source.cost:type_name -> contor.v1.CostCurve
+	3,  // 16: contor.v1.Photovoltaic.irradiance:type_name -> contor.v1.Profile
+	4,  // 17: contor.v1.WindFarm.power_curve:type_name -> contor.v1.CurvePoint
+	3,  // 18: contor.v1.WindFarm.wind_speed:type_name -> contor.v1.Profile
+	4,  // 19: contor.v1.InverterControl.qu:type_name -> contor.v1.CurvePoint
+	4,  // 20: contor.v1.InverterControl.pu:type_name -> contor.v1.CurvePoint
+	19, // 21: contor.v1.Transformer.limits:type_name -> contor.v1.Limits
+	10, // 22: contor.v1.Transformer.tap:type_name -> contor.v1.TapChanger
+	19, // 23: contor.v1.Line.limits:type_name -> contor.v1.Limits
+	19, // 24: contor.v1.Consumer.limits:type_name -> contor.v1.Limits
+	36, // 25: contor.v1.Consumer.phase_loads:type_name -> contor.v1.Consumer.PhaseLoadsEntry
+	13, // 26: contor.v1.Consumer.load_model:type_name -> contor.v1.LoadModel
+	3,  // 27: contor.v1.Battery.schedule:type_name -> contor.v1.Profile
+	19, // 28: contor.v1.AlarmSettings.default_limits:type_name -> contor.v1.Limits
+	0,  // 29: contor.v1.SolveRequest.system:type_name -> contor.v1.System
+	21, // 30: contor.v1.SolveRequest.modifications:type_name -> contor.v1.Modification
+	38, // 31: contor.v1.SolveRequest.time:type_name -> google.protobuf.Timestamp
+	27, // 32: contor.v1.SolveResponse.result:type_name -> contor.v1.SystemResult
+	0,  // 33: contor.v1.SolveStreamRequest.system:type_name -> contor.v1.System
+	21, // 34: contor.v1.SolveStreamRequest.modifications:type_name -> contor.v1.Modification
+	38, // 35: contor.v1.SolveStreamRequest.start:type_name -> google.protobuf.Timestamp
+	38, // 36: contor.v1.TimeStep.time:type_name -> google.protobuf.Timestamp
+	27, // 37: contor.v1.TimeStep.result:type_name -> contor.v1.SystemResult
+	37, // 38: contor.v1.TimeStep.battery_soc:type_name -> contor.v1.TimeStep.BatterySocEntry
+	26, // 39: contor.v1.SystemResult.elements:type_name -> contor.v1.ElementResult
+	30, // 40: contor.v1.SystemResult.unserved_consumers:type_name -> contor.v1.UnservedConsumer
+	0,  // 41: contor.v1.ContingencyRequest.system:type_name -> contor.v1.System
+	21, // 42: contor.v1.ContingencyRequest.modifications:type_name -> contor.v1.Modification
+	29, // 43: contor.v1.ContingencyCase.overloads:type_name -> contor.v1.Violation
+	29, // 44: contor.v1.ContingencyCase.voltage_violations:type_name -> contor.v1.Violation
+	30, // 45: contor.v1.ContingencyCase.unserved_consumers:type_name -> contor.v1.UnservedConsumer
+	31, // 46: contor.v1.ContingencyReport.base:type_name -> contor.v1.ContingencyCase
+	31, // 47: contor.v1.ContingencyReport.cases:type_name -> contor.v1.ContingencyCase
+	0,  // 48: contor.v1.ShortCircuitRequest.system:type_name -> contor.v1.System
+	21, // 49: contor.v1.ShortCircuitRequest.modifications:type_name -> contor.v1.Modification
+	34, // 50: contor.v1.ShortCircuitResponse.nodes:type_name -> contor.v1.ShortCircuitNode
+	14, // 51: contor.v1.Consumer.PhaseLoadsEntry.value:type_name -> contor.v1.PhaseLoad
+	22, // 52: contor.v1.Contor.Solve:input_type -> contor.v1.SolveRequest
+	24, // 53: contor.v1.Contor.SolveStream:input_type -> contor.v1.SolveStreamRequest
+	28, // 54: contor.v1.Contor.Contingency:input_type -> contor.v1.ContingencyRequest
+	33, // 55: contor.v1.Contor.ShortCircuit:input_type -> contor.v1.ShortCircuitRequest
+	23, // 56: contor.v1.Contor.Solve:output_type -> contor.v1.SolveResponse
+	25, // 57: contor.v1.Contor.SolveStream:output_type -> contor.v1.TimeStep
+	32, // 58: contor.v1.Contor.Contingency:output_type -> contor.v1.ContingencyReport
+	35, // 59: contor.v1.Contor.ShortCircuit:output_type -> contor.v1.ShortCircuitResponse
+	56, // [56:60] is the sub-list for method output_type
+	52, // [52:56] is the sub-list for method input_type
+	52, // [52:52] is the sub-list for extension type_name
+	52, // [52:52] is the sub-list for extension extendee
+	0,  // [0:52] is the sub-list for field type_name
+}
+
+func init() { file_contor_proto_init() }
+func file_contor_proto_init() {
+	if File_contor_proto != nil {
+		return
+	}
+	file_contor_proto_msgTypes[21].OneofWrappers = []any{
+		(*Modification_State)(nil),
+		(*Modification_OutOfService)(nil),
+		(*Modification_ActivePower)(nil),
+		(*Modification_ReactivePower)(nil),
+		(*Modification_TapPosition)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_contor_proto_rawDesc), len(file_contor_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   38,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_contor_proto_goTypes,
+		DependencyIndexes: file_contor_proto_depIdxs,
+		MessageInfos:      file_contor_proto_msgTypes,
+	}.Build()
+	File_contor_proto = out.File
+	file_contor_proto_goTypes = nil
+	file_contor_proto_depIdxs = nil
+}
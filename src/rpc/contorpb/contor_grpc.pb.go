@@ -0,0 +1,250 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: contor.proto
+
+// Serviciul gRPC pentru studii "what-if": circulatie de puteri, serii de timp, analiza N-1 si scurtcircuit.
+// Mesajele modelului urmeaza schema config.json (numele JSON ale campurilor sunt aceleasi).
+
+package contorpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Contor_Solve_FullMethodName        = "/contor.v1.Contor/Solve"
+	Contor_SolveStream_FullMethodName  = "/contor.v1.Contor/SolveStream"
+	Contor_Contingency_FullMethodName  = "/contor.v1.Contor/Contingency"
+	Contor_ShortCircuit_FullMethodName = "/contor.v1.Contor/ShortCircuit"
+)
+
+// ContorClient is the client API for Contor service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ContorClient interface {
+	// Solve calculeaza circulatia de puteri pentru un model.
+	Solve(ctx context.Context, in *SolveRequest, opts ...grpc.CallOption) (*SolveResponse, error)
+	// SolveStream calculeaza o serie de timp; bateriile isi pastreaza starea de incarcare intre pasi.
+	SolveStream(ctx context.Context, in *SolveStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TimeStep], error)
+	// Contingency ruleaza analiza N-1.
+	Contingency(ctx context.Context, in *ContingencyRequest, opts ...grpc.CallOption) (*ContingencyReport, error)
+	// ShortCircuit calculeaza curentii de scurtcircuit trifazat dupa IEC 60909.
+	ShortCircuit(ctx context.Context, in *ShortCircuitRequest, opts ...grpc.CallOption) (*ShortCircuitResponse, error)
+}
+
+type contorClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewContorClient(cc grpc.ClientConnInterface) ContorClient {
+	return &contorClient{cc}
+}
+
+func (c *contorClient) Solve(ctx context.Context, in *SolveRequest, opts ...grpc.CallOption) (*SolveResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SolveResponse)
+	err := c.cc.Invoke(ctx, Contor_Solve_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contorClient) SolveStream(ctx context.Context, in *SolveStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TimeStep], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Contor_ServiceDesc.Streams[0], Contor_SolveStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SolveStreamRequest, TimeStep]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Contor_SolveStreamClient = grpc.ServerStreamingClient[TimeStep]
+
+func (c *contorClient) Contingency(ctx context.Context, in *ContingencyRequest, opts ...grpc.CallOption) (*ContingencyReport, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ContingencyReport)
+	err := c.cc.Invoke(ctx, Contor_Contingency_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contorClient) ShortCircuit(ctx context.Context, in *ShortCircuitRequest, opts ...grpc.CallOption) (*ShortCircuitResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ShortCircuitResponse)
+	err := c.cc.Invoke(ctx, Contor_ShortCircuit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ContorServer is the server API for Contor service.
+// All implementations must embed UnimplementedContorServer
+// for forward compatibility.
+type ContorServer interface {
+	// Solve calculeaza circulatia de puteri pentru un model.
+	Solve(context.Context, *SolveRequest) (*SolveResponse, error)
+	// SolveStream calculeaza o serie de timp; bateriile isi pastreaza starea de incarcare intre pasi.
+	SolveStream(*SolveStreamRequest, grpc.ServerStreamingServer[TimeStep]) error
+	// Contingency ruleaza analiza N-1.
+	Contingency(context.Context, *ContingencyRequest) (*ContingencyReport, error)
+	// ShortCircuit calculeaza curentii de scurtcircuit trifazat dupa IEC 60909.
+	ShortCircuit(context.Context, *ShortCircuitRequest) (*ShortCircuitResponse, error)
+	mustEmbedUnimplementedContorServer()
+}
+
+// UnimplementedContorServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedContorServer struct{}
+
+func (UnimplementedContorServer) Solve(context.Context, *SolveRequest) (*SolveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Solve not implemented")
+}
+func (UnimplementedContorServer) SolveStream(*SolveStreamRequest, grpc.ServerStreamingServer[TimeStep]) error {
+	return status.Errorf(codes.Unimplemented, "method SolveStream not implemented")
+}
+func (UnimplementedContorServer) Contingency(context.Context, *ContingencyRequest) (*ContingencyReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Contingency not implemented")
+}
+func (UnimplementedContorServer) ShortCircuit(context.Context, *ShortCircuitRequest) (*ShortCircuitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ShortCircuit not implemented")
+}
+func (UnimplementedContorServer) mustEmbedUnimplementedContorServer() {}
+func (UnimplementedContorServer) testEmbeddedByValue()                {}
+
+// UnsafeContorServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ContorServer will
+// result in compilation errors.
+type UnsafeContorServer interface {
+	mustEmbedUnimplementedContorServer()
+}
+
+func RegisterContorServer(s grpc.ServiceRegistrar, srv ContorServer) {
+	// If the following call pancis, it indicates UnimplementedContorServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Contor_ServiceDesc, srv)
+}
+
+func _Contor_Solve_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SolveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContorServer).Solve(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Contor_Solve_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContorServer).Solve(ctx, req.(*SolveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Contor_SolveStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SolveStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ContorServer).SolveStream(m, &grpc.GenericServerStream[SolveStreamRequest, TimeStep]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Contor_SolveStreamServer = grpc.ServerStreamingServer[TimeStep]
+
+func _Contor_Contingency_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ContingencyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContorServer).Contingency(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Contor_Contingency_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContorServer).Contingency(ctx, req.(*ContingencyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Contor_ShortCircuit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShortCircuitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContorServer).ShortCircuit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Contor_ShortCircuit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContorServer).ShortCircuit(ctx, req.(*ShortCircuitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Contor_ServiceDesc is the grpc.ServiceDesc for Contor service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Contor_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "contor.v1.Contor",
+	HandlerType: (*ContorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Solve",
+			Handler:    _Contor_Solve_Handler,
+		},
+		{
+			MethodName: "Contingency",
+			Handler:    _Contor_Contingency_Handler,
+		},
+		{
+			MethodName: "ShortCircuit",
+			Handler:    _Contor_ShortCircuit_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SolveStream",
+			Handler:       _Contor_SolveStream_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "contor.proto",
+}
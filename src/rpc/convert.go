@@ -0,0 +1,265 @@
+package rpc
+
+import (
+	"encoding/json"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"contor-system/src/computing"
+	"contor-system/src/rpc/contorpb"
+	"contor-system/src/utils"
+)
+
+// Conversia intre mesajele protobuf si tipurile din utils. Modelul retelei trece prin JSON, numele campurilor
+// din contor.proto fiind cele din config.json; rezultatele se copiaza camp cu camp.
+
+// toSystem converteste modelul primit in utils.System.
+func toSystem(system *contorpb.System) (utils.System, error) {
+	content, err := protojson.Marshal(system)
+	if err != nil {
+		return utils.System{}, status.Errorf(codes.InvalidArgument, "invalid system: %v", err)
+	}
+	var converted utils.System
+	if err := json.Unmarshal(content, &converted); err != nil {
+		return utils.System{}, status.Errorf(codes.InvalidArgument, "invalid system: %v", err)
+	}
+	return converted, nil
+}
+
+// modify aplica modificarile cererii asupra modelului.
+func modify(system utils.System, modifications []*contorpb.Modification) (utils.System, error) {
+	for _, modification := range modifications {
+		var err error
+		system, err = apply(system, modification)
+		if err != nil {
+			return utils.System{}, err
+		}
+	}
+	return system, nil
+}
+
+func apply(system utils.System, modification *contorpb.Modification) (utils.System, error) {
+	id := modification.GetElement()
+	notFound := status.Errorf(codes.NotFound, "modification %s: no matching element", id)
+
+	switch change := modification.GetChange().(type) {
+	case *contorpb.Modification_State:
+		state := utils.StateType(change.State)
+		if state != utils.StateOpen && state != utils.StateClose {
+			return system, status.Errorf(codes.InvalidArgument, "modification %s: state must be open or close", id)
+		}
+		separators := append([]utils.Separator(nil), system.Separators...)
+		for i := range separators {
+			if separators[i].ID == id {
+				separators[i].State = state
+				system.Separators = separators
+				return system, nil
+			}
+		}
+		return system, notFound
+
+	case *contorpb.Modification_OutOfService:
+		if !change.OutOfService {
+			return system, nil
+		}
+		if system.Source.ID != id && !hasBranch(system, id) {
+			return system, notFound
+		}
+		return computing.WithOutage(system, id), nil
+
+	case *contorpb.Modification_ActivePower:
+		if system.Source.ID == id {
+			system.Source.Power = change.ActivePower
+			return system, nil
+		}
+		consumers := append([]utils.Consumer(nil), system.Consumers...)
+		for i := range consumers {
+			if consumers[i].ID == id {
+				consumers[i].PowerNeeded = change.ActivePower
+				system.Consumers = consumers
+				return system, nil
+			}
+		}
+		sources := append([]utils.Source(nil), system.AdditionalSources...)
+		for i := range sources {
+			if sources[i].ID == id {
+				sources[i].Power = change.ActivePower
+				system.AdditionalSources = sources
+				return system, nil
+			}
+		}
+		batteries := append([]utils.Battery(nil), system.Batteries...)
+		for i := range batteries {
+			if batteries[i].ID == id {
+				batteries[i].Strategy = utils.BatteryStrategyFixed
+				batteries[i].Power = change.ActivePower
+				system.Batteries = batteries
+				return system, nil
+			}
+		}
+		return system, notFound
+
+	case *contorpb.Modification_ReactivePower:
+		if system.Source.ID == id {
+			system.Source.ReactivePower = change.ReactivePower
+			return system, nil
+		}
+		consumers := append([]utils.Consumer(nil), system.Consumers...)
+		for i := range consumers {
+			if consumers[i].ID == id {
+				consumers[i].ReactivePowerAbsorbed = change.ReactivePower
+				system.Consumers = consumers
+				return system, nil
+			}
+		}
+		sources := append([]utils.Source(nil), system.AdditionalSources...)
+		for i := range sources {
+			if sources[i].ID == id {
+				sources[i].ReactivePower = change.ReactivePower
+				system.AdditionalSources = sources
+				return system, nil
+			}
+		}
+		return system, notFound
+
+	case *contorpb.Modification_TapPosition:
+		transformers := append([]utils.Transformer(nil), system.Transformers...)
+		for i := range transformers {
+			if transformers[i].ID != id {
+				continue
+			}
+			if transformers[i].Tap == nil {
+				return system, status.Errorf(codes.FailedPrecondition, "modification %s: transformer has no tap changer", id)
+			}
+			tap := *transformers[i].Tap
+			position := int(change.TapPosition)
+			if position < tap.MinPosition || position > tap.MaxPosition {
+				return system, status.Errorf(codes.OutOfRange, "modification %s: tap position %d outside %d..%d", id, position, tap.MinPosition, tap.MaxPosition)
+			}
+			tap.Position = position
+			transformers[i].Tap = &tap
+			system.Transformers = transformers
+			return system, nil
+		}
+		return system, notFound
+	}
+
+	return system, status.Errorf(codes.InvalidArgument, "modification %s: no change given", id)
+}
+
+// hasBranch verifica daca id este o linie, un transformator sau o sursa aditionala.
+func hasBranch(system utils.System, id string) bool {
+	for _, line := range system.Lines {
+		if line.ID == id {
+			return true
+		}
+	}
+	for _, transformer := range system.Transformers {
+		if transformer.ID == id {
+			return true
+		}
+	}
+	for _, source := range system.AdditionalSources {
+		if source.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func fromResult(system utils.System, result utils.SystemResult) *contorpb.SystemResult {
+	converted := &contorpb.SystemResult{
+		ActivePowerLosses:   result.ActivePowerLosses,
+		ReactivePowerLosses: result.ReactivePowerLosses,
+		Engine:              string(result.Engine),
+		Iterations:          int32(result.Iterations),
+		Converged:           result.Converged,
+	}
+	if converted.Engine == "" {
+		converted.Engine = string(utils.EngineTraversal)
+	}
+	for _, id := range result.Order {
+		element := result.Elements[id]
+		converted.Elements = append(converted.Elements, &contorpb.ElementResult{
+			Id:                  element.ID,
+			Kind:                element.Kind,
+			ActivePower:         element.ActivePower,
+			ReactivePower:       element.ReactivePower,
+			Current:             element.Current,
+			Voltage:             element.Voltage,
+			VoltagePu:           element.VoltagePU,
+			Loading:             element.Loading,
+			PowerFactor:         element.PowerFactor,
+			ActivePowerLosses:   element.ActivePowerLosses,
+			ReactivePowerLosses: element.ReactivePowerLosses,
+			Energized:           element.Energized,
+		})
+	}
+	unserved := computing.UnservedPower(system, result)
+	for _, consumer := range system.Consumers {
+		if unserved[consumer.ID] > 0 {
+			converted.UnservedConsumers = append(converted.UnservedConsumers, &contorpb.UnservedConsumer{Id: consumer.ID, UnservedPower: unserved[consumer.ID]})
+		}
+	}
+	return converted
+}
+
+func fromViolations(violations []computing.Violation) []*contorpb.Violation {
+	converted := make([]*contorpb.Violation, 0, len(violations))
+	for _, violation := range violations {
+		converted = append(converted, &contorpb.Violation{
+			ElementId: violation.ElementID,
+			Kind:      string(violation.Kind),
+			Value:     violation.Value,
+			Limit:     violation.Limit,
+		})
+	}
+	return converted
+}
+
+func fromContingencyCase(c computing.ContingencyCase) *contorpb.ContingencyCase {
+	converted := &contorpb.ContingencyCase{
+		Outage:            c.Outage,
+		OutageKind:        c.OutageKind,
+		Overloads:         fromViolations(c.Overloads),
+		VoltageViolations: fromViolations(c.VoltageViolations),
+		UnservedPower:     c.UnservedPower,
+		ActivePowerLosses: c.ActivePowerLosses,
+	}
+	for _, consumer := range c.UnservedConsumers {
+		converted.UnservedConsumers = append(converted.UnservedConsumers, &contorpb.UnservedConsumer{Id: consumer.ID, UnservedPower: consumer.UnservedPower})
+	}
+	return converted
+}
+
+func fromShortCircuit(nodes []computing.ShortCircuitNode) *contorpb.ShortCircuitResponse {
+	converted := &contorpb.ShortCircuitResponse{}
+	for _, node := range nodes {
+		converted.Nodes = append(converted.Nodes, &contorpb.ShortCircuitNode{
+			Node:    node.Node,
+			Voltage: node.Voltage,
+			C:       node.C,
+			R:       node.R,
+			X:       node.X,
+			Ik:      node.Ik,
+			Ip:      node.Ip,
+			Ib:      node.Ib,
+			Sk:      node.Sk,
+			Sources: node.Sources,
+		})
+	}
+	return converted
+}
+
+// shortCircuitCase valideaza cazul de calcul cerut.
+func shortCircuitCase(value string) (computing.ShortCircuitCase, error) {
+	switch computing.ShortCircuitCase(value) {
+	case "", computing.ShortCircuitMax:
+		return computing.ShortCircuitMax, nil
+	case computing.ShortCircuitMin:
+		return computing.ShortCircuitMin, nil
+	}
+	return "", status.Errorf(codes.InvalidArgument, "unknown short-circuit case %q: use max or min", value)
+}
@@ -0,0 +1,221 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"contor-system/src/computing"
+	"contor-system/src/model"
+	"contor-system/src/rpc/contorpb"
+	"contor-system/src/storage"
+	"contor-system/src/utils"
+)
+
+// Serviciu gRPC pentru studii "what-if": alte aplicatii trimit un model (sau folosesc modelul in functiune),
+// optional cu modificari, si primesc rezultatele fara sa modifice config.json.
+
+const maxStreamSteps = 10000
+
+type Server struct {
+	contorpb.UnimplementedContorServer
+
+	settings utils.GRPC
+	listener net.Listener
+	server   *grpc.Server
+
+	mu   sync.Mutex
+	live []byte // modelul in functiune, serializat pentru a fi copiat la fiecare cerere
+}
+
+func NewServer(settings utils.GRPC) *Server {
+	if settings.Address == "" {
+		settings.Address = ":50051"
+	}
+	return &Server{settings: settings}
+}
+
+// Start deschide portul si serveste cererile in fundal.
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.settings.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %v", s.settings.Address, err)
+	}
+	s.serve(listener)
+	return nil
+}
+
+// serve serveste cererile pe listener in fundal.
+func (s *Server) serve(listener net.Listener) {
+	s.listener = listener
+	s.server = grpc.NewServer()
+	contorpb.RegisterContorServer(s.server, s)
+	go s.server.Serve(listener)
+}
+
+// Addr intoarce adresa pe care asculta serverul.
+func (s *Server) Addr() net.Addr {
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+// Close opreste serverul si intrerupe cererile in curs.
+func (s *Server) Close() error {
+	if s.server == nil {
+		return nil
+	}
+	s.server.Stop()
+	return nil
+}
+
+// Update retine modelul in functiune, folosit de cererile care nu trimit un model.
+func (s *Server) Update(system utils.System) error {
+	content, err := json.Marshal(system)
+	if err != nil {
+		return fmt.Errorf("failed to store the live model for gRPC: %v", err)
+	}
+	s.mu.Lock()
+	s.live = content
+	s.mu.Unlock()
+	return nil
+}
+
+// model intoarce modelul cererii cu modificarile aplicate; un model cu erori de validare este respins.
+func (s *Server) model(system *contorpb.System, modifications []*contorpb.Modification) (utils.System, error) {
+	var config utils.System
+	if system != nil {
+		var err error
+		if config, err = toSystem(system); err != nil {
+			return utils.System{}, err
+		}
+	} else {
+		s.mu.Lock()
+		live := s.live
+		s.mu.Unlock()
+		if live == nil {
+			return utils.System{}, status.Error(codes.FailedPrecondition, "no system given and no live model computed yet")
+		}
+		if err := json.Unmarshal(live, &config); err != nil {
+			return utils.System{}, status.Errorf(codes.Internal, "failed to read the live model: %v", err)
+		}
+	}
+	config, err := modify(config, modifications)
+	if err != nil {
+		return utils.System{}, err
+	}
+
+	var errors []string
+	for _, issue := range model.Validate(config) {
+		if issue.Severity != model.SeverityError {
+			continue
+		}
+		element := issue.Element
+		if element == "" {
+			element = "system"
+		}
+		errors = append(errors, element+": "+issue.Message)
+	}
+	if len(errors) > 0 {
+		return utils.System{}, status.Errorf(codes.InvalidArgument, "invalid system: %s", strings.Join(errors, "; "))
+	}
+	return config, nil
+}
+
+func (s *Server) Solve(ctx context.Context, request *contorpb.SolveRequest) (*contorpb.SolveResponse, error) {
+	system, err := s.model(request.GetSystem(), request.GetModifications())
+	if err != nil {
+		return nil, err
+	}
+	at := time.Now()
+	if request.GetTime() != nil {
+		at = request.GetTime().AsTime()
+	}
+	result := computing.SolveAt(system, at)
+	return &contorpb.SolveResponse{Result: fromResult(system, result)}, nil
+}
+
+// SolveStream calculeaza pasii seriei de timp si trimite fiecare rezultat cand este gata.
+func (s *Server) SolveStream(request *contorpb.SolveStreamRequest, stream grpc.ServerStreamingServer[contorpb.TimeStep]) error {
+	system, err := s.model(request.GetSystem(), request.GetModifications())
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	if request.GetStart() != nil {
+		start = request.GetStart().AsTime()
+	}
+	step := request.GetStep()
+	if step == 0 {
+		step = 900
+	}
+	steps := int(request.GetSteps())
+	if steps == 0 {
+		steps = 96
+	}
+	if !(step > 0) || steps < 0 || steps > maxStreamSteps {
+		return status.Errorf(codes.InvalidArgument, "step must be positive and steps between 1 and %d", maxStreamSteps)
+	}
+
+	batteries := storage.NewManager()
+	var previous *utils.SystemResult
+	for i := 0; i < steps; i++ {
+		if err := stream.Context().Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		at := start.Add(time.Duration(float64(i) * step * float64(time.Second)))
+		dispatched, _ := batteries.Dispatch(system, previous, at)
+		result := computing.SolveAt(dispatched, at)
+		previous = &result
+
+		timeStep := &contorpb.TimeStep{Time: timestamppb.New(at), Result: fromResult(dispatched, result)}
+		if len(dispatched.Batteries) > 0 {
+			timeStep.BatterySoc = map[string]float64{}
+			for _, battery := range dispatched.Batteries {
+				if soc, exists := batteries.SOC(battery.ID); exists {
+					timeStep.BatterySoc[battery.ID] = soc
+				}
+			}
+		}
+		if err := stream.Send(timeStep); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) Contingency(ctx context.Context, request *contorpb.ContingencyRequest) (*contorpb.ContingencyReport, error) {
+	system, err := s.model(request.GetSystem(), request.GetModifications())
+	if err != nil {
+		return nil, err
+	}
+	report := computing.RunContingencies(system, computing.ContingencyOptions{IncludeSources: request.GetIncludeSources()})
+	converted := &contorpb.ContingencyReport{Base: fromContingencyCase(report.Base)}
+	for _, c := range report.Cases {
+		converted.Cases = append(converted.Cases, fromContingencyCase(c))
+	}
+	return converted, nil
+}
+
+func (s *Server) ShortCircuit(ctx context.Context, request *contorpb.ShortCircuitRequest) (*contorpb.ShortCircuitResponse, error) {
+	system, err := s.model(request.GetSystem(), request.GetModifications())
+	if err != nil {
+		return nil, err
+	}
+	scCase, err := shortCircuitCase(request.GetCase())
+	if err != nil {
+		return nil, err
+	}
+	options := computing.ShortCircuitOptions{Case: scCase, C: request.GetC(), MinTimeDelay: request.GetMinTimeDelay()}
+	return fromShortCircuit(computing.ComputeShortCircuit(system, options)), nil
+}
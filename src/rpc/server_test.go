@@ -0,0 +1,158 @@
+package rpc
+
+import (
+	"context"
+	"math"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"contor-system/src/rpc/contorpb"
+	"contor-system/src/utils"
+)
+
+// testClient porneste serviciul pe o conexiune in memorie, cu modelul in functiune source1 → line1 → consumer1.
+func testClient(t *testing.T) contorpb.ContorClient {
+	t.Helper()
+	s := NewServer(utils.GRPC{})
+	err := s.Update(utils.System{
+		Source: utils.Source{ID: "source1", Power: 10, Voltage: 20, ConnectedTo: "line1"},
+		Lines: []utils.Line{{
+			ID: "line1", Voltage: 20, Length: 5, Area: 95, Ro: 0.0295, X: 0.4, ConnectedTo: "consumer1",
+		}},
+		Consumers: []utils.Consumer{{ID: "consumer1", PowerNeeded: 4, Voltage: 20}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener := bufconn.Listen(1 << 20)
+	s.serve(listener)
+	t.Cleanup(func() { s.Close() })
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return contorpb.NewContorClient(conn)
+}
+
+func element(result *contorpb.SystemResult, id string) *contorpb.ElementResult {
+	for _, e := range result.GetElements() {
+		if e.GetId() == id {
+			return e
+		}
+	}
+	return nil
+}
+
+func TestSolveLiveModel(t *testing.T) {
+	client := testClient(t)
+	response, err := client.Solve(context.Background(), &contorpb.SolveRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if consumer := element(response.GetResult(), "consumer1"); consumer == nil || !consumer.GetEnergized() {
+		t.Fatalf("consumer1 %+v", consumer)
+	}
+}
+
+func TestSolveModifications(t *testing.T) {
+	client := testClient(t)
+	response, err := client.Solve(context.Background(), &contorpb.SolveRequest{Modifications: []*contorpb.Modification{
+		{Element: "consumer1", Change: &contorpb.Modification_ActivePower{ActivePower: 6}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if consumer := element(response.GetResult(), "consumer1"); math.Abs(consumer.GetActivePower()-6) > 1e-9 {
+		t.Fatalf("consumer1 draws %.4f MW, expected 6", consumer.GetActivePower())
+	}
+
+	// Modificarea nu ramane in modelul in functiune
+	response, err = client.Solve(context.Background(), &contorpb.SolveRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if consumer := element(response.GetResult(), "consumer1"); math.Abs(consumer.GetActivePower()-4) > 1e-9 {
+		t.Fatalf("consumer1 draws %.4f MW after the study, expected 4", consumer.GetActivePower())
+	}
+
+	_, err = client.Solve(context.Background(), &contorpb.SolveRequest{Modifications: []*contorpb.Modification{
+		{Element: "consumer9", Change: &contorpb.Modification_ActivePower{ActivePower: 6}},
+	}})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("unknown element: %v", err)
+	}
+}
+
+func TestInvalidSystemRejected(t *testing.T) {
+	client := testClient(t)
+	system := &contorpb.System{Source: &contorpb.Source{Id: "source1", Power: 10, Voltage: 20, ConnectedTo: "missing"}}
+
+	_, err := client.Solve(context.Background(), &contorpb.SolveRequest{System: system})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Solve: %v", err)
+	}
+	_, err = client.Contingency(context.Background(), &contorpb.ContingencyRequest{System: system})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Contingency: %v", err)
+	}
+	_, err = client.ShortCircuit(context.Background(), &contorpb.ShortCircuitRequest{System: system})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("ShortCircuit: %v", err)
+	}
+	stream, err := client.SolveStream(context.Background(), &contorpb.SolveStreamRequest{System: system})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("SolveStream: %v", err)
+	}
+}
+
+func TestSolveStreamRejectsInvalidStep(t *testing.T) {
+	client := testClient(t)
+	for _, step := range []float64{-900, math.NaN()} {
+		stream, err := client.SolveStream(context.Background(), &contorpb.SolveStreamRequest{Step: step, Steps: 2})
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("step %v: %v", step, err)
+		}
+	}
+}
+
+func TestSolveStreamCancel(t *testing.T) {
+	client := testClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.SolveStream(ctx, &contorpb.SolveStreamRequest{Step: 60, Steps: maxStreamSteps})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+
+	received := 1
+	for {
+		if _, err = stream.Recv(); err != nil {
+			break
+		}
+		received++
+	}
+	if status.Code(err) != codes.Canceled || received >= maxStreamSteps {
+		t.Fatalf("stream ended with %v after %d steps", err, received)
+	}
+}
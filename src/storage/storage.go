@@ -0,0 +1,174 @@
+package storage
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"contor-system/src/utils"
+)
+
+// Sisteme de stocare cu baterii: starea de incarcare (SOC) pastrata intre pasii de calcul si strategiile de dispecerizare
+
+// Manager pastreaza starea de incarcare a bateriilor intre pasii de calcul.
+type Manager struct {
+	soc   map[string]float64 // %
+	power map[string]float64 // MW, puterea impusa la pasul anterior
+	last  time.Time
+}
+
+func NewManager() *Manager {
+	return &Manager{soc: map[string]float64{}, power: map[string]float64{}}
+}
+
+// SOC intoarce starea de incarcare a bateriei dupa ultimul pas de calcul: %.
+func (m *Manager) SOC(id string) (float64, bool) {
+	soc, exists := m.soc[id]
+	return soc, exists
+}
+
+// parameters intoarce randamentul si limitele SOC ale bateriei, cu valorile implicite.
+func parameters(battery utils.Battery) (float64, float64, float64) {
+	efficiency, minSOC, maxSOC := battery.Efficiency, battery.MinSOC, battery.MaxSOC
+	if efficiency <= 0 || efficiency > 1 {
+		efficiency = 0.9
+	}
+	if minSOC <= 0 {
+		minSOC = 10
+	}
+	if maxSOC <= 0 {
+		maxSOC = 90
+	}
+	return efficiency, minSOC, maxSOC
+}
+
+/*
+Variatia starii de incarcare pentru puterea p pe durata dt, randamentul ciclului fiind impartit egal intre incarcare si descarcare:
+- descarcare: dSOC = -P * dt / (sqrt(eta) * E)
+- incarcare: dSOC = -P * dt * sqrt(eta) / E
+- p: MW, dt: h, E: MWh, dSOC: %
+*/
+func socChange(p float64, hours float64, capacity float64, efficiency float64) float64 {
+	if capacity <= 0 {
+		return 0
+	}
+	energy := p * hours
+	if p > 0 {
+		energy /= math.Sqrt(efficiency)
+	} else {
+		energy *= math.Sqrt(efficiency)
+	}
+	return -energy / capacity * 100
+}
+
+// request intoarce puterea ceruta de strategia bateriei, inainte de aplicarea limitelor: MW.
+func request(battery utils.Battery, system utils.System, previous *utils.SystemResult, previousPower float64, now time.Time) float64 {
+	switch battery.Strategy {
+	case utils.BatteryStrategySchedule:
+		return battery.Schedule.ValueAt(now)
+
+	case utils.BatteryStrategyPeakShaving:
+		if previous == nil {
+			return 0
+		}
+		monitored := battery.MonitoredElement
+		if monitored == "" {
+			monitored = system.Source.ID
+		}
+		element, exists := previous.Elements[monitored]
+		if !exists {
+			return 0
+		}
+		// Puterea masurata include aportul bateriei de la pasul anterior
+		return previousPower + element.ActivePower - battery.PeakLimit
+
+	case utils.BatteryStrategySelfConsumption:
+		if previous == nil {
+			return 0
+		}
+		var load, generation float64
+		if element, exists := previous.Elements[battery.ConnectedTo]; exists && element.Kind == "consumer" {
+			load = element.ActivePower
+		}
+		for _, source := range system.AdditionalSources {
+			if element, exists := previous.Elements[source.ID]; exists && source.ConnectedTo == battery.ConnectedTo {
+				generation += element.ActivePower
+			}
+		}
+		return load - generation
+
+	default:
+		return battery.Power
+	}
+}
+
+// Dispatch integreaza puterea pasului anterior in starea de incarcare, calculeaza puterea fiecarei baterii pentru pasul curent
+// si intoarce o copie a sistemului cu puterile impuse. previous este rezultatul pasului anterior, nil la primul pas.
+func (m *Manager) Dispatch(system utils.System, previous *utils.SystemResult, now time.Time) (utils.System, []utils.LogEntry) {
+	interval := time.Second
+	if !m.last.IsZero() && now.After(m.last) {
+		interval = now.Sub(m.last)
+	}
+	elapsed := !m.last.IsZero()
+	m.last = now
+
+	batteries := append([]utils.Battery(nil), system.Batteries...)
+	var logs []utils.LogEntry
+
+	for i, battery := range batteries {
+		efficiency, minSOC, maxSOC := parameters(battery)
+		capacity := battery.Capacity / 1000 // MWh
+		hours := interval.Hours()
+
+		soc, exists := m.soc[battery.ID]
+		if !exists {
+			soc = battery.InitialSOC
+			if soc <= 0 {
+				soc = 50
+			}
+		} else if elapsed {
+			// Incarcarea se opreste la limita superioara, iar descarcarea la cea inferioara, chiar daca pasul a fost mai lung
+			// decat cel folosit la limitarea puterii
+			previousSOC := soc
+			soc += socChange(m.power[battery.ID], hours, capacity, efficiency)
+			if m.power[battery.ID] < 0 {
+				soc = math.Min(soc, math.Max(maxSOC, previousSOC))
+			} else if m.power[battery.ID] > 0 {
+				soc = math.Max(soc, math.Min(minSOC, previousSOC))
+			}
+		}
+		soc = math.Max(0, math.Min(100, soc))
+
+		// Limitele de putere si de energie disponibila pentru pasul urmator
+		maxDischarge := math.Max(0, (soc-minSOC)/100*capacity*math.Sqrt(efficiency)/hours)
+		maxCharge := math.Max(0, (maxSOC-soc)/100*capacity/math.Sqrt(efficiency)/hours)
+		if battery.MaxDischargePower > 0 {
+			maxDischarge = math.Min(maxDischarge, battery.MaxDischargePower)
+		}
+		if battery.MaxChargePower > 0 {
+			maxCharge = math.Min(maxCharge, battery.MaxChargePower)
+		}
+
+		power := request(battery, system, previous, m.power[battery.ID], now)
+		power = math.Max(-maxCharge, math.Min(maxDischarge, power))
+
+		m.soc[battery.ID] = soc
+		m.power[battery.ID] = power
+		batteries[i].Power = power
+
+		mode := "idle"
+		if power > 0 {
+			mode = "discharging"
+		} else if power < 0 {
+			mode = "charging"
+		}
+		logs = append(logs, utils.LogEntry{
+			Timestamp:   now.Format("2006/01/02-15:04:05"),
+			ComponentID: battery.ID,
+			Message:     fmt.Sprintf("Battery %s SOC %.2f %% (%.1f kWh), %s %.2f MW\n", battery.ID, soc, soc/100*battery.Capacity, mode, math.Abs(power)),
+		})
+	}
+
+	system.Batteries = batteries
+	return system, logs
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"contor-system/src/utils"
+)
+
+// battery este o baterie de 1 MWh cu puterea impusa power, fara limite de putere.
+func battery(power float64, initialSOC float64) utils.System {
+	return utils.System{Batteries: []utils.Battery{{
+		ID: "battery1", Capacity: 1000, Efficiency: 1, MinSOC: 10, MaxSOC: 90, InitialSOC: initialSOC, Power: power,
+	}}}
+}
+
+func TestSOCStopsAtMaxSOC(t *testing.T) {
+	m := NewManager()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Primul pas limiteaza puterea pentru o secunda; ora care urmeaza ar incarca 2 MWh intr-o baterie de 1 MWh
+	system, _ := m.Dispatch(battery(-2, 80), nil, start)
+	if power := system.Batteries[0].Power; power != -2 {
+		t.Fatalf("first step %.4f MW, expected -2", power)
+	}
+	system, _ = m.Dispatch(battery(-2, 80), nil, start.Add(time.Hour))
+	if soc, _ := m.SOC("battery1"); soc != 90 {
+		t.Fatalf("SOC %.4f %%, expected the upper bound 90", soc)
+	}
+	if power := system.Batteries[0].Power; power != 0 {
+		t.Fatalf("charging %.4f MW at the upper bound", power)
+	}
+}
+
+func TestSOCStopsAtMinSOC(t *testing.T) {
+	m := NewManager()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	m.Dispatch(battery(3, 30), nil, start)
+	system, _ := m.Dispatch(battery(3, 30), nil, start.Add(time.Hour))
+	if soc, _ := m.SOC("battery1"); soc != 10 {
+		t.Fatalf("SOC %.4f %%, expected the lower bound 10", soc)
+	}
+	if power := system.Batteries[0].Power; power != 0 {
+		t.Fatalf("discharging %.4f MW at the lower bound", power)
+	}
+}
+
+func TestPowerLimitedByEnergyLeft(t *testing.T) {
+	m := NewManager()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	m.Dispatch(battery(-0.1, 50), nil, start)
+	m.Dispatch(battery(-0.1, 50), nil, start.Add(time.Hour))
+
+	// Dupa doua ore la 0.1 MW SOC = 70 %; pentru pasul urmator de o ora raman 0.2 MWh pana la 90 %
+	system, _ := m.Dispatch(battery(-2, 50), nil, start.Add(2*time.Hour))
+	if soc, _ := m.SOC("battery1"); math.Abs(soc-70) > 1e-9 {
+		t.Fatalf("SOC %.4f %%, expected 70", soc)
+	}
+	if power := system.Batteries[0].Power; math.Abs(power+0.2) > 1e-9 {
+		t.Fatalf("charging %.4f MW, expected 0.2", -power)
+	}
+}
@@ -0,0 +1,381 @@
+package switching
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"contor-system/src/utils"
+)
+
+// Secvente de manevre: operatii temporizate de inchidere / deschidere a separatoarelor, verificate cu regulile de interblocare
+// si executate pe modelul in functiune, cu circulatia de puteri inainte si dupa fiecare pas.
+
+// Operation este o manevra a unui separator, executata la Delay secunde dupa manevra anterioara (sau dupa pornirea secventei).
+type Operation struct {
+	Separator string          `json:"separator"`
+	State     utils.StateType `json:"state"`
+	Delay     float64         `json:"delay"` // s
+}
+
+type Sequence struct {
+	Name             string      `json:"name"`
+	Operations       []Operation `json:"operations"`
+	Faults           []string    `json:"faults,omitempty"`           // elementele aflate in defect
+	VoltageTolerance float64     `json:"voltageTolerance,omitempty"` // %, diferenta admisa la punerea in paralel a surselor, implicit 5
+	LoadThreshold    float64     `json:"loadThreshold,omitempty"`    // A, curentul peste care un separator este considerat in sarcina, implicit 1
+}
+
+// Flow este circulatia printr-un element inainte si dupa o manevra.
+type Flow struct {
+	ID           string  `json:"id"`
+	PowerBefore  float64 `json:"powerBefore"` // MW
+	PowerAfter   float64 `json:"powerAfter"`  // MW
+	CurrentAfter float64 `json:"currentAfter"`
+	Energized    bool    `json:"energized"`
+}
+
+type StepResult struct {
+	Index     int       `json:"index"`
+	Operation Operation `json:"operation"`
+	Time      time.Time `json:"time"`
+	Executed  bool      `json:"executed"`
+	Reason    string    `json:"reason,omitempty"` // motivul blocarii
+	Flows     []Flow    `json:"flows"`            // elementele a caror circulatie s-a modificat
+}
+
+// Command este o manevra comandata de la distanta (IEC 104, MQTT). Bucla de calcul apeleaza Confirm cu rezultatul
+// verificarii de interblocare si, daca manevra a fost executata, Terminate dupa recalcularea circulatiei de puteri.
+type Command struct {
+	Separator string
+	State     utils.StateType
+	Confirm   func(err error)
+	Terminate func()
+}
+
+// Solver calculeaza circulatia de puteri pentru o stare a retelei.
+type Solver func(utils.System) utils.SystemResult
+
+// Load citeste o secventa de manevre dintr-un fisier JSON.
+func Load(path string) (Sequence, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Sequence{}, fmt.Errorf("failed to read switching sequence: %v", err)
+	}
+	var sequence Sequence
+	if err := json.Unmarshal(data, &sequence); err != nil {
+		return Sequence{}, fmt.Errorf("failed to decode switching sequence: %v", err)
+	}
+	for i, op := range sequence.Operations {
+		if op.State != utils.StateOpen && op.State != utils.StateClose {
+			return Sequence{}, fmt.Errorf("operation %d: state must be %q or %q", i+1, utils.StateOpen, utils.StateClose)
+		}
+	}
+	if sequence.VoltageTolerance <= 0 {
+		sequence.VoltageTolerance = 5
+	}
+	if sequence.LoadThreshold <= 0 {
+		sequence.LoadThreshold = 1
+	}
+	return sequence, nil
+}
+
+// withState intoarce o copie a sistemului cu separatorul in starea data.
+func withState(system utils.System, id string, state utils.StateType) utils.System {
+	separators := append([]utils.Separator(nil), system.Separators...)
+	for i := range separators {
+		if separators[i].ID == id {
+			separators[i].State = state
+		}
+	}
+	system.Separators = separators
+	return system
+}
+
+// WithStates intoarce o copie a sistemului cu starile date ale separatoarelor.
+func WithStates(system utils.System, states map[string]utils.StateType) utils.System {
+	for id, state := range states {
+		system = withState(system, id, state)
+	}
+	return system
+}
+
+// Interlock verifica o manevra izolata (de exemplu o telecomanda) cu pragurile implicite.
+func Interlock(system utils.System, op Operation, solve Solver) error {
+	sequence := Sequence{VoltageTolerance: 5, LoadThreshold: 1}
+	return Check(system, sequence, op, solve(system), solve(withState(system, op.Separator, op.State)))
+}
+
+// Check verifica regulile de interblocare pentru o manevra; before si after sunt circulatiile inainte si dupa manevra.
+func Check(system utils.System, sequence Sequence, op Operation, before utils.SystemResult, after utils.SystemResult) error {
+	var separator *utils.Separator
+	for i := range system.Separators {
+		if system.Separators[i].ID == op.Separator {
+			separator = &system.Separators[i]
+		}
+	}
+	if separator == nil {
+		return fmt.Errorf("separator %s does not exist", op.Separator)
+	}
+	if separator.State == op.State {
+		return fmt.Errorf("separator %s is already %s", op.Separator, op.State)
+	}
+
+	energized := func(result utils.SystemResult, id string) bool {
+		element, exists := result.Elements[id]
+		return exists && element.Energized
+	}
+
+	if op.State == utils.StateClose {
+		// Inchiderea pe defect: un element in defect devine alimentat sau se afla pe portiunea legata de separator
+		// (de o parte sau de alta a lui), chiar daca modelul nu calculeaza insula
+		fed := section(system, *separator, func(id string) bool { return energized(before, id) })
+		for _, fault := range sequence.Faults {
+			if fed[fault] || (!energized(before, fault) && energized(after, fault)) {
+				return fmt.Errorf("closing %s would energize faulted element %s", op.Separator, fault)
+			}
+		}
+
+		// Punerea in paralel a unei surse cu tensiune diferita de a nodului in care se conecteaza
+		for _, source := range system.AdditionalSources {
+			if source.ID != separator.ConnectedTo {
+				continue
+			}
+			node, exists := before.Elements[source.ConnectedTo]
+			if !exists || !node.Energized || node.Voltage == 0 {
+				continue
+			}
+			if difference := math.Abs(source.Voltage-node.Voltage) / node.Voltage * 100; difference > sequence.VoltageTolerance {
+				return fmt.Errorf("closing %s would parallel source %s (%.2f kV) with %s at %.2f kV (%.1f %% difference)", op.Separator, source.ID, source.Voltage, node.ID, node.Voltage, difference)
+			}
+		}
+		return nil
+	}
+
+	// Deschiderea sub sarcina a unui separator fara putere de rupere
+	if separator.Type == utils.SeparatorTypeBreaker || separator.Type == utils.SeparatorTypeLoadBreakSwitch {
+		return nil
+	}
+	loaded := func(id string) bool {
+		element, exists := before.Elements[id]
+		return exists && (math.Abs(element.Current) > sequence.LoadThreshold || math.Abs(element.ActivePower) > 1e-3)
+	}
+	if loaded(separator.ID) {
+		return fmt.Errorf("disconnector %s cannot be opened under load", op.Separator)
+	}
+	for _, source := range system.AdditionalSources {
+		if source.ID == separator.ConnectedTo && loaded(source.ID) {
+			return fmt.Errorf("disconnector %s cannot be opened while source %s is supplying power", op.Separator, source.ID)
+		}
+	}
+	return nil
+}
+
+// section intoarce elementele legate direct de separator (connectsFrom si elementele conectate la el) si lantul connectedTo
+// din aval, pana la un separator deschis sau primul element deja alimentat.
+func section(system utils.System, separator utils.Separator, energized func(string) bool) map[string]bool {
+	next := map[string]string{}
+	open := map[string]bool{}
+	for _, t := range system.Transformers {
+		next[t.ID] = t.ConnectedTo
+	}
+	for _, l := range system.Lines {
+		next[l.ID] = l.ConnectedTo
+	}
+	for _, c := range system.Consumers {
+		next[c.ID] = c.ConnectedTo
+	}
+	for _, s := range system.Separators {
+		next[s.ID] = s.ConnectedTo
+		open[s.ID] = s.State == utils.StateOpen
+	}
+	for _, s := range system.AdditionalSources {
+		next[s.ID] = s.ConnectedTo
+	}
+
+	ids := map[string]bool{}
+	if separator.ConnectsFrom != "" && separator.ConnectsFrom != "_" {
+		ids[separator.ConnectsFrom] = true
+	}
+	for id, to := range next {
+		if to == separator.ID {
+			ids[id] = true
+		}
+	}
+	for id := separator.ConnectedTo; id != "" && id != separator.ID && !ids[id]; id = next[id] {
+		ids[id] = true
+		if open[id] || (id != separator.ConnectedTo && energized(id)) {
+			break
+		}
+	}
+	return ids
+}
+
+// changedFlows intoarce elementele a caror putere sau stare s-a modificat.
+func changedFlows(before utils.SystemResult, after utils.SystemResult) []Flow {
+	var flows []Flow
+	for _, id := range after.Order {
+		a := after.Elements[id]
+		var powerBefore float64
+		var energizedBefore bool
+		if b, exists := before.Elements[id]; exists {
+			powerBefore, energizedBefore = b.ActivePower, b.Energized
+		}
+		if math.Abs(a.ActivePower-powerBefore) < 1e-3 && a.Energized == energizedBefore {
+			continue
+		}
+		flows = append(flows, Flow{ID: id, PowerBefore: powerBefore, PowerAfter: a.ActivePower, CurrentAfter: a.Current, Energized: a.Energized})
+	}
+	return flows
+}
+
+// Runner executa o secventa pe modelul in functiune; manevrele executate raman aplicate si dupa reincarcarea configuratiei.
+type Runner struct {
+	sequence Sequence
+	due      time.Time
+	next     int
+	aborted  bool
+	states   map[string]utils.StateType
+	Steps    []StepResult
+}
+
+func NewRunner(sequence Sequence, start time.Time) *Runner {
+	r := &Runner{sequence: sequence, states: map[string]utils.StateType{}}
+	if len(sequence.Operations) > 0 {
+		r.due = start.Add(time.Duration(sequence.Operations[0].Delay * float64(time.Second)))
+	}
+	return r
+}
+
+// Done verifica daca secventa s-a terminat sau a fost oprita.
+func (r *Runner) Done() bool {
+	return r.aborted || r.next >= len(r.sequence.Operations)
+}
+
+// Pending verifica daca secventa mai are de manevrat separatorul dat.
+func (r *Runner) Pending(separator string) bool {
+	if r.Done() {
+		return false
+	}
+	for _, op := range r.sequence.Operations[r.next:] {
+		if op.Separator == separator {
+			return true
+		}
+	}
+	return false
+}
+
+// Release renunta la starea impusa de secventa pentru separatorul dat, de exemplu dupa o telecomanda pe acelasi separator.
+func (r *Runner) Release(separator string) {
+	delete(r.states, separator)
+}
+
+// Name intoarce numele secventei.
+func (r *Runner) Name() string {
+	return r.sequence.Name
+}
+
+// Apply intoarce sistemul cu manevrele deja executate.
+func (r *Runner) Apply(system utils.System) utils.System {
+	return WithStates(system, r.states)
+}
+
+// Step executa manevrele scadente la momentul now. O manevra blocata de interblocare opreste secventa.
+func (r *Runner) Step(system utils.System, now time.Time, solve Solver) (utils.System, []utils.LogEntry) {
+	var logs []utils.LogEntry
+	entry := func(id string, message string) {
+		logs = append(logs, utils.LogEntry{Timestamp: now.Format("2006/01/02-15:04:05"), ComponentID: id, Message: message})
+	}
+
+	system = r.Apply(system)
+	for !r.Done() && !now.Before(r.due) {
+		op := r.sequence.Operations[r.next]
+		step := StepResult{Index: r.next + 1, Operation: op, Time: now}
+
+		before := solve(system)
+		candidate := withState(system, op.Separator, op.State)
+		after := solve(candidate)
+
+		if err := Check(system, r.sequence, op, before, after); err != nil {
+			step.Reason = err.Error()
+			r.aborted = true
+			r.Steps = append(r.Steps, step)
+			entry(op.Separator, fmt.Sprintf("Switching step %d (%s %s) blocked by interlocking: %s; sequence %s aborted\n", step.Index, op.State, op.Separator, step.Reason, r.sequence.Name))
+			break
+		}
+
+		system = candidate
+		r.states[op.Separator] = op.State
+		step.Executed = true
+		step.Flows = changedFlows(before, after)
+		r.Steps = append(r.Steps, step)
+
+		entry(op.Separator, fmt.Sprintf("Switching step %d: separator %s set to %s\n", step.Index, op.Separator, op.State))
+		for _, flow := range step.Flows {
+			entry(flow.ID, fmt.Sprintf("Switching step %d: %s %.2f MW -> %.2f MW (%.2f A, energized %t)\n", step.Index, flow.ID, flow.PowerBefore, flow.PowerAfter, flow.CurrentAfter, flow.Energized))
+		}
+
+		r.next++
+		if r.next < len(r.sequence.Operations) {
+			r.due = r.due.Add(time.Duration(r.sequence.Operations[r.next].Delay * float64(time.Second)))
+		}
+	}
+
+	return system, logs
+}
+
+// Simulate executa toata secventa pe o copie a sistemului, fara a astepta intarzierile.
+func Simulate(system utils.System, sequence Sequence, start time.Time, solve Solver) ([]StepResult, utils.System, []utils.LogEntry) {
+	runner := NewRunner(sequence, start)
+	var logs []utils.LogEntry
+	now := start
+	for !runner.Done() {
+		now = runner.due
+		var entries []utils.LogEntry
+		system, entries = runner.Step(system, now, solve)
+		logs = append(logs, entries...)
+	}
+	return runner.Steps, system, logs
+}
+
+// FormatReport scrie pasii secventei cu circulatiile modificate.
+func FormatReport(sequence Sequence, steps []StepResult) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Switching sequence %s (%d operations)\n", sequence.Name, len(sequence.Operations))
+	for _, step := range steps {
+		op := step.Operation
+		if !step.Executed {
+			fmt.Fprintf(&b, "%2d. %s %s: BLOCKED - %s\n", step.Index, op.State, op.Separator, step.Reason)
+			continue
+		}
+		fmt.Fprintf(&b, "%2d. %s %s at %s\n", step.Index, op.State, op.Separator, step.Time.Format("15:04:05"))
+		for _, flow := range step.Flows {
+			state := "energized"
+			if !flow.Energized {
+				state = "de-energized"
+			}
+			fmt.Fprintf(&b, "      %-20s %9.3f MW -> %9.3f MW  %8.2f A  %s\n", flow.ID, flow.PowerBefore, flow.PowerAfter, flow.CurrentAfter, state)
+		}
+	}
+	if executed := countExecuted(steps); executed < len(sequence.Operations) {
+		fmt.Fprintf(&b, "Sequence aborted after %d of %d operations\n", executed, len(sequence.Operations))
+	} else {
+		b.WriteString("Sequence completed\n")
+	}
+
+	return b.String()
+}
+
+func countExecuted(steps []StepResult) int {
+	var n int
+	for _, step := range steps {
+		if step.Executed {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,104 @@
+package switching
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"contor-system/src/utils"
+)
+
+// testSystem: source1 → line1 → separator1 (deschis) → line2 → consumer2, plus sursa aditionala source2 legata prin
+// separator2 (deschis) la consumer2.
+func testSystem() utils.System {
+	return utils.System{
+		Source: utils.Source{ID: "source1", Voltage: 20, Power: 10, ConnectedTo: "line1"},
+		Lines: []utils.Line{
+			{ID: "line1", Voltage: 20, ConnectedTo: "separator1"},
+			{ID: "line2", Voltage: 20, ConnectedTo: "consumer2"},
+			{ID: "line3", Voltage: 20},
+		},
+		Consumers: []utils.Consumer{{ID: "consumer2", PowerNeeded: 1}},
+		Separators: []utils.Separator{
+			{ID: "separator1", ConnectsFrom: "line1", State: utils.StateOpen, ConnectedTo: "line2"},
+			{ID: "separator2", ConnectsFrom: "_", State: utils.StateOpen, ConnectedTo: "source2"},
+			{ID: "separator3", ConnectsFrom: "line1", State: utils.StateClose, ConnectedTo: "line2", Type: utils.SeparatorTypeBreaker},
+		},
+		AdditionalSources: []utils.Source{{ID: "source2", Voltage: 20, Power: 1, ConnectedTo: "consumer2"}},
+	}
+}
+
+// result intoarce un rezultat cu elementele date alimentate.
+func result(elements ...utils.ElementResult) utils.SystemResult {
+	r := utils.SystemResult{Elements: map[string]*utils.ElementResult{}}
+	for i := range elements {
+		elements[i].Energized = true
+		r.Order = append(r.Order, elements[i].ID)
+		r.Elements[elements[i].ID] = &elements[i]
+	}
+	return r
+}
+
+func TestCheck(t *testing.T) {
+	closeSeparator := func(id string) Operation { return Operation{Separator: id, State: utils.StateClose} }
+	openSeparator := func(id string) Operation { return Operation{Separator: id, State: utils.StateOpen} }
+	node := utils.ElementResult{ID: "consumer2", Voltage: 20}
+
+	for _, c := range []struct {
+		name     string
+		op       Operation
+		faults   []string
+		before   utils.SystemResult
+		after    utils.SystemResult
+		rejected string
+	}{
+		{name: "close into a downstream fault", op: closeSeparator("separator1"), faults: []string{"consumer2"}, rejected: "faulted element consumer2"},
+		{name: "close into an upstream fault", op: closeSeparator("separator1"), faults: []string{"line1"}, before: result(utils.ElementResult{ID: "line1"}), rejected: "faulted element line1"},
+		{name: "close into a fault energized by the flow", op: closeSeparator("separator1"), faults: []string{"line3"}, after: result(utils.ElementResult{ID: "line3"}), rejected: "faulted element line3"},
+		{name: "close away from the fault", op: closeSeparator("separator1"), faults: []string{"line3"}},
+		{name: "parallel at a voltage difference", op: closeSeparator("separator2"), before: result(utils.ElementResult{ID: "consumer2", Voltage: 18}), rejected: "would parallel source source2"},
+		{name: "parallel within the tolerance", op: closeSeparator("separator2"), before: result(node)},
+		{name: "disconnector under load", op: openSeparator("separator1"), before: result(utils.ElementResult{ID: "separator1", Current: 50, ActivePower: 1.7}), rejected: "cannot be opened under load"},
+		{name: "disconnector of a supplying source", op: openSeparator("separator2"), before: result(utils.ElementResult{ID: "source2", ActivePower: 1}), rejected: "while source source2 is supplying power"},
+		{name: "breaker under load", op: openSeparator("separator3"), before: result(utils.ElementResult{ID: "separator3", Current: 50, ActivePower: 1.7})},
+		{name: "disconnector without load", op: openSeparator("separator1"), before: result(utils.ElementResult{ID: "separator1", Current: 0.5})},
+	} {
+		system := testSystem()
+		// Separatoarele deschise in testSystem se deschid aici din starea inchisa
+		if c.op.State == utils.StateOpen {
+			for i := range system.Separators {
+				system.Separators[i].State = utils.StateClose
+			}
+		}
+		sequence := Sequence{Faults: c.faults, VoltageTolerance: 5, LoadThreshold: 1}
+		err := Check(system, sequence, c.op, c.before, c.after)
+		switch {
+		case c.rejected == "" && err != nil:
+			t.Fatalf("%s: rejected: %v", c.name, err)
+		case c.rejected != "" && (err == nil || !strings.Contains(err.Error(), c.rejected)):
+			t.Fatalf("%s: error %v, expected %q", c.name, err, c.rejected)
+		}
+	}
+}
+
+func TestRunnerReleasesRemoteSeparators(t *testing.T) {
+	sequence := Sequence{Name: "restore", Operations: []Operation{{Separator: "separator1", State: utils.StateClose}}, VoltageTolerance: 5, LoadThreshold: 1}
+	start := time.Now()
+	runner := NewRunner(sequence, start)
+	if !runner.Pending("separator1") || runner.Pending("separator2") {
+		t.Fatal("pending separators do not follow the sequence")
+	}
+
+	solve := func(utils.System) utils.SystemResult { return result() }
+	runner.Step(testSystem(), start, solve)
+	if runner.Pending("separator1") || !runner.Done() {
+		t.Fatal("separator1 is still pending after its operation")
+	}
+
+	// O telecomanda ulterioara pe separator1 nu mai este suprascrisa de secventa
+	runner.Release("separator1")
+	system := runner.Apply(WithStates(testSystem(), map[string]utils.StateType{"separator1": utils.StateOpen}))
+	if system.Separators[0].State != utils.StateOpen {
+		t.Fatalf("the sequence overrode the remote command: separator1 %s", system.Separators[0].State)
+	}
+}
@@ -0,0 +1,44 @@
+package utils
+
+// ResolveLimits combina limitele elementului cu cele implicite; campurile nule din element sunt luate din cele implicite.
+func ResolveLimits(own *Limits, defaults Limits) Limits {
+	limits := defaults
+	if own == nil {
+		return limits
+	}
+	if own.MaxLoading != 0 {
+		limits.MaxLoading = own.MaxLoading
+	}
+	if own.MinVoltage != 0 {
+		limits.MinVoltage = own.MinVoltage
+	}
+	if own.MaxVoltage != 0 {
+		limits.MaxVoltage = own.MaxVoltage
+	}
+	if own.MinPowerFactor != 0 {
+		limits.MinPowerFactor = own.MinPowerFactor
+	}
+	return limits
+}
+
+// ElementLimits intoarce limitele fiecarui element din sistem.
+func ElementLimits(system System) map[string]Limits {
+	defaults := system.Alarms.DefaultLimits
+	limits := map[string]Limits{}
+
+	limits[system.Source.ID] = ResolveLimits(system.Source.Limits, defaults)
+	for _, source := range system.AdditionalSources {
+		limits[source.ID] = ResolveLimits(source.Limits, defaults)
+	}
+	for _, transformer := range system.Transformers {
+		limits[transformer.ID] = ResolveLimits(transformer.Limits, defaults)
+	}
+	for _, line := range system.Lines {
+		limits[line.ID] = ResolveLimits(line.Limits, defaults)
+	}
+	for _, consumer := range system.Consumers {
+		limits[consumer.ID] = ResolveLimits(consumer.Limits, defaults)
+	}
+
+	return limits
+}
@@ -0,0 +1,22 @@
+package utils
+
+import "time"
+
+// ValueAt intoarce valoarea profilului la momentul t, interpoland intre esantioane.
+func (profile Profile) ValueAt(t time.Time) float64 {
+	n := len(profile.Values)
+	if n == 0 {
+		return 0
+	}
+	step := profile.Step
+	if step <= 0 {
+		step = 3600
+	}
+
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	position := t.Sub(midnight).Seconds() / step
+	i := int(position)
+	fraction := position - float64(i)
+	a, b := profile.Values[i%n], profile.Values[(i+1)%n]
+	return a + fraction*(b-a)
+}
@@ -24,6 +24,85 @@ type Source struct {
 	ConnectedTo     string  `json:"connectedTo"`
 	AdditionalPower float64 `json:"additionalPower"`
 	ReactivePower   float64 `json:"reactivePower"`
+	Limits          *Limits `json:"limits,omitempty"`
+	// Scurtcircuit (IEC 60909)
+	ShortCircuitPower float64 `json:"shortCircuitPower,omitempty"` // MVA, S''k al retelei; 0 = retea de putere infinita
+	RXRatio           float64 `json:"rxRatio,omitempty"`           // R/X al retelei, implicit 0.1
+	Generator         bool    `json:"generator,omitempty"`         // sursa apropiata de generator, Power este puterea nominala
+	// Componente simetrice
+	Z2Z1Ratio  float64 `json:"z2z1Ratio,omitempty"`  // Z2/Z1, implicit 1
+	Z0Z1Ratio  float64 `json:"z0z1Ratio,omitempty"`  // Z0/Z1, implicit 1
+	Ungrounded bool    `json:"ungrounded,omitempty"` // neutrul sursei izolat, fara cale pentru secventa zero
+	// Reglajul tensiunii (nod PV)
+	VoltageSetpoint  float64           `json:"voltageSetpoint,omitempty"`  // u.r.; 0 = nod PQ cu reactivePower fix; pentru sursa principala tensiunea nodului de echilibru
+	MinReactivePower float64           `json:"minReactivePower,omitempty"` // MVAr
+	MaxReactivePower float64           `json:"maxReactivePower,omitempty"` // MVAr; daca ambele limite lipsesc, Q nu este limitat
+	Capability       []CapabilityPoint `json:"capability,omitempty"`       // diagrama de capabilitate, are prioritate fata de limitele fixe
+	// Generare distribuita: puterea disponibila se calculeaza din profil in locul lui power
+	Photovoltaic *Photovoltaic    `json:"photovoltaic,omitempty"`
+	Wind         *WindFarm        `json:"wind,omitempty"`
+	Inverter     *InverterControl `json:"inverter,omitempty"` // reglajul Q si limitarea P in functie de tensiune
+	// Dispecerizare (OPF)
+	MinPower float64    `json:"minPower,omitempty"` // MW
+	MaxPower float64    `json:"maxPower,omitempty"` // MW; 0 = puterea sursei aditionale nu este dispecerizabila
+	Cost     *CostCurve `json:"cost,omitempty"`
+}
+
+// CostCurve este costul de productie: C = A + B * P + C * P^2, pe ora, P in MW.
+type CostCurve struct {
+	A float64 `json:"a"`
+	B float64 `json:"b"`
+	C float64 `json:"c"`
+}
+
+// Profile este o serie de valori esantionate la Step secunde incepand de la miezul noptii, repetata zilnic; intre esantioane se interpoleaza liniar.
+type Profile struct {
+	Step   float64   `json:"step,omitempty"` // s, implicit 3600
+	Values []float64 `json:"values"`
+}
+
+// CurvePoint este un punct al unei curbe definite pe segmente.
+type CurvePoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Photovoltaic descrie o centrala fotovoltaica: P = PeakPower * G / 1000 * PerformanceRatio, limitata la InverterRating.
+type Photovoltaic struct {
+	PeakPower        float64 `json:"peakPower"`                  // MWp, la 1000 W/m2
+	InverterRating   float64 `json:"inverterRating"`             // MVA
+	PerformanceRatio float64 `json:"performanceRatio,omitempty"` // implicit 0.85
+	Irradiance       Profile `json:"irradiance"`                 // W/m2
+}
+
+// WindFarm descrie un parc eolian cu turbine identice.
+type WindFarm struct {
+	Turbines   int          `json:"turbines,omitempty"` // implicit 1
+	PowerCurve []CurvePoint `json:"powerCurve"`         // x: viteza vantului m/s, y: puterea unei turbine MW
+	Rating     float64      `json:"rating"`             // MVA, puterea aparenta nominala a parcului
+	WindSpeed  Profile      `json:"windSpeed"`          // m/s
+}
+
+type ReactiveControlMode string
+
+const (
+	ReactiveControlFixedPowerFactor ReactiveControlMode = "fixedPowerFactor"
+	ReactiveControlQU               ReactiveControlMode = "qu"
+)
+
+// InverterControl descrie reglajele invertorului. Q pozitiv este injectat (regim capacitiv), negativ absorbit.
+type InverterControl struct {
+	Mode        ReactiveControlMode `json:"mode,omitempty"`        // gol = reactivePower fix
+	PowerFactor float64             `json:"powerFactor,omitempty"` // fixedPowerFactor; negativ = absoarbe Q
+	QU          []CurvePoint        `json:"qu,omitempty"`          // x: tensiunea u.r., y: Q in u.r. din puterea nominala
+	PU          []CurvePoint        `json:"pu,omitempty"`          // x: tensiunea u.r., y: fractiunea din puterea disponibila; gol = fara limitare
+}
+
+// CapabilityPoint este un punct al diagramei P-Q a generatorului; intre puncte limitele se interpoleaza liniar.
+type CapabilityPoint struct {
+	P    float64 `json:"p"`    // MW
+	QMin float64 `json:"qMin"` // MVAr
+	QMax float64 `json:"qMax"` // MVAr
 }
 
 type Transformer struct {
@@ -38,6 +117,22 @@ type Transformer struct {
 	SteelLosses             float64         `json:"steelLosses"`   // KW
 	PowerTransfered         float64         `json:"powerTransfered"`
 	ReactivePowerTransfered float64         `json:"reactivePowerTransfered"`
+	Limits                  *Limits         `json:"limits,omitempty"`
+	Uk                      float64         `json:"uk,omitempty"` // %, tensiunea de scurtcircuit
+	// Componente simetrice
+	VectorGroup       string      `json:"vectorGroup,omitempty"`       // ex. Dyn5, YNd11, YNyn0; implicit YNyn0
+	Z0Z1Ratio         float64     `json:"z0z1Ratio,omitempty"`         // Z0/Z1, implicit 1
+	NeutralResistance float64     `json:"neutralResistance,omitempty"` // ohm, impedanta de tratare a neutrului infasurarii de iesire
+	NeutralReactance  float64     `json:"neutralReactance,omitempty"`  // ohm
+	Tap               *TapChanger `json:"tap,omitempty"`
+}
+
+// TapChanger descrie comutatorul de ploturi: raportul de transformare este (1 + Position * StepPercent / 100) * OutputVoltage / InputVoltage.
+type TapChanger struct {
+	Position    int     `json:"position"`
+	MinPosition int     `json:"minPosition"`
+	MaxPosition int     `json:"maxPosition"`
+	StepPercent float64 `json:"stepPercent"` // %, variatia tensiunii de iesire pe plot
 }
 
 type Line struct {
@@ -57,6 +152,11 @@ type Line struct {
 	ReactivePowerTransfered float64 `json:"reactivePowerTransfered"`
 	ReactivePowerLosses     float64 `json:"reactivePowerLosses"`
 	ActivePowerLosses       float64 `json:"activePowerLosses"`
+	RatedCurrent            float64 `json:"ratedCurrent,omitempty"` // A
+	Limits                  *Limits `json:"limits,omitempty"`
+	X                       float64 `json:"x,omitempty"`  // ohm/km, daca lipseste se calculeaza din geometrie
+	R0                      float64 `json:"r0,omitempty"` // ohm/km, secventa zero, implicit R0 = R1
+	X0                      float64 `json:"x0,omitempty"` // ohm/km, secventa zero, implicit X0 = 3 * X1
 }
 
 type Consumer struct {
@@ -66,13 +166,59 @@ type Consumer struct {
 	ConnectedTo           string  `json:"connectedTo,omitempty"`
 	RemainingPower        float64 `json:"remainingPower"`
 	ReactivePowerAbsorbed float64 `json:"reactivePowerAbsorbed"`
+	Limits                *Limits `json:"limits,omitempty"`
+	// Sarcina pe faze, conectata in stea intre faza si neutru
+	Phases     string               `json:"phases,omitempty"`     // fazele la care este conectat consumatorul, ex. "abc", "a", "bc"; implicit "abc"
+	PhaseLoads map[string]PhaseLoad `json:"phaseLoads,omitempty"` // chei "a", "b", "c"; daca lipseste, powerNeeded se imparte egal pe faze
+	// Dependenta de tensiune a sarcinii
+	PowerFactor float64    `json:"powerFactor,omitempty"` // cosfi inductiv, folosit cand reactivePowerAbsorbed lipseste
+	LoadModel   *LoadModel `json:"loadModel,omitempty"`   // implicit putere constanta
 }
 
+type LoadModelType string
+
+const (
+	LoadModelConstantPower LoadModelType = "constantPower"
+	LoadModelZIP           LoadModelType = "zip"
+	LoadModelExponential   LoadModelType = "exponential"
+)
+
+/*
+LoadModel descrie variatia puterii consumate cu tensiunea (u.r.):
+- zip: P = P0 * (Zp*V^2 + Ip*V + Pp), Q = Q0 * (Zq*V^2 + Iq*V + Pq); coeficientii se normeaza la suma lor
+- exponential: P = P0 * V^Np, Q = Q0 * V^Nq
+*/
+type LoadModel struct {
+	Type LoadModelType `json:"type"`
+	Zp   float64       `json:"zp,omitempty"` // impedanta constanta
+	Ip   float64       `json:"ip,omitempty"` // curent constant
+	Pp   float64       `json:"pp,omitempty"` // putere constanta
+	Zq   float64       `json:"zq,omitempty"`
+	Iq   float64       `json:"iq,omitempty"`
+	Pq   float64       `json:"pq,omitempty"`
+	Np   float64       `json:"np,omitempty"`
+	Nq   float64       `json:"nq,omitempty"`
+}
+
+type PhaseLoad struct {
+	P float64 `json:"p"` // MW
+	Q float64 `json:"q"` // MVAr
+}
+
+type SeparatorType string
+
+const (
+	SeparatorTypeDisconnector    SeparatorType = "disconnector"    // nu poate fi deschis sub sarcina
+	SeparatorTypeLoadBreakSwitch SeparatorType = "loadBreakSwitch" // separator de sarcina
+	SeparatorTypeBreaker         SeparatorType = "breaker"         // intreruptor
+)
+
 type Separator struct {
-	ConnectsFrom string    `json:"connectsFrom"`
-	ID           string    `json:"id"`
-	State        StateType `json:"state"`
-	ConnectedTo  string    `json:"connectedTo"`
+	ConnectsFrom string        `json:"connectsFrom"`
+	ID           string        `json:"id"`
+	State        StateType     `json:"state"`
+	ConnectedTo  string        `json:"connectedTo"`
+	Type         SeparatorType `json:"type,omitempty"` // implicit disconnector
 }
 
 type System struct {
@@ -82,6 +228,104 @@ type System struct {
 	Consumers         []Consumer    `json:"consumers"`
 	Separators        []Separator   `json:"separators"`
 	AdditionalSources []Source      `json:"additionalSources"`
+	Alarms            AlarmSettings `json:"alarms"`
+	Notifiers         []Notifier    `json:"notifiers"`
+	Solver            Solver        `json:"solver"`
+	Batteries         []Battery     `json:"batteries"`
+	Shunts            []Shunt       `json:"shunts"`
+	Modbus            *Modbus       `json:"modbus,omitempty"`
+	IEC104            *IEC104       `json:"iec104,omitempty"`
+	DLMS              *DLMS         `json:"dlms,omitempty"`
+	MQTT              *MQTT         `json:"mqtt,omitempty"`
+	Metrics           *Metrics      `json:"metrics,omitempty"`
+	GRPC              *GRPC         `json:"grpc,omitempty"`
+	Daemon            *Daemon       `json:"daemon,omitempty"`
+}
+
+type ShuntType string
+
+const (
+	ShuntTypeCapacitor ShuntType = "capacitor" // produce putere reactiva
+	ShuntTypeReactor   ShuntType = "reactor"   // absoarbe putere reactiva
+)
+
+type ShuntControl string
+
+const (
+	ShuntControlVoltage     ShuntControl = "voltage"     // mentine tensiunea nodului intre minVoltage si maxVoltage
+	ShuntControlPowerFactor ShuntControl = "powerFactor" // mentine cosfi elementului monitorizat peste powerFactor
+)
+
+// Shunt descrie o baterie de condensatoare sau o bobina de compensare in trepte, racordata in nodul unui element.
+type Shunt struct {
+	ID               string       `json:"id"`
+	Type             ShuntType    `json:"type"`
+	ConnectedTo      string       `json:"connectedTo"`
+	StepSize         float64      `json:"stepSize"`                   // MVAr pe treapta la tensiunea nominala
+	Steps            int          `json:"steps"`                      // numarul de trepte
+	Step             int          `json:"step"`                       // treapta conectata; punctul de plecare al regulatorului
+	Control          ShuntControl `json:"control,omitempty"`          // gol = treapta fixa
+	MinVoltage       float64      `json:"minVoltage,omitempty"`       // u.r., control voltage
+	MaxVoltage       float64      `json:"maxVoltage,omitempty"`       // u.r., control voltage
+	PowerFactor      float64      `json:"powerFactor,omitempty"`      // control powerFactor
+	MonitoredElement string       `json:"monitoredElement,omitempty"` // control powerFactor, implicit connectedTo
+}
+
+type BatteryStrategy string
+
+const (
+	BatteryStrategyFixed           BatteryStrategy = "fixed"           // puterea din campul power
+	BatteryStrategyPeakShaving     BatteryStrategy = "peakShaving"     // limiteaza puterea elementului monitorizat la peakLimit
+	BatteryStrategySchedule        BatteryStrategy = "schedule"        // puterea din profilul schedule
+	BatteryStrategySelfConsumption BatteryStrategy = "selfConsumption" // acopera diferenta dintre consumul si generarea din nodul de racord
+)
+
+// Battery descrie un sistem de stocare racordat in nodul unui element. Puterea pozitiva este descarcare (injectata in retea).
+type Battery struct {
+	ID                string          `json:"id"`
+	ConnectedTo       string          `json:"connectedTo"`                // elementul in al carui nod este racordata bateria
+	Capacity          float64         `json:"capacity"`                   // kWh
+	MaxChargePower    float64         `json:"maxChargePower"`             // MW
+	MaxDischargePower float64         `json:"maxDischargePower"`          // MW
+	Efficiency        float64         `json:"efficiency,omitempty"`       // randamentul ciclului incarcare-descarcare, implicit 0.9
+	MinSOC            float64         `json:"minSoc,omitempty"`           // %, implicit 10
+	MaxSOC            float64         `json:"maxSoc,omitempty"`           // %, implicit 90
+	InitialSOC        float64         `json:"initialSoc,omitempty"`       // %, implicit 50
+	Strategy          BatteryStrategy `json:"strategy,omitempty"`         // implicit fixed
+	Power             float64         `json:"power,omitempty"`            // MW, puterea impusa; calculata la fiecare pas pentru celelalte strategii
+	PeakLimit         float64         `json:"peakLimit,omitempty"`        // MW, peakShaving
+	MonitoredElement  string          `json:"monitoredElement,omitempty"` // peakShaving, implicit sursa principala
+	Schedule          Profile         `json:"schedule,omitempty"`         // MW, schedule
+}
+
+type EngineType string
+
+const (
+	EngineTraversal EngineType = "traversal" // parcurgerea lantului connectedTo cu bilant de puteri
+	EngineSweep     EngineType = "sweep"     // backward/forward sweep pentru retele radiale
+)
+
+// Solver alege motorul de calcul si parametrii de convergenta.
+type Solver struct {
+	Engine        EngineType `json:"engine,omitempty"`    // implicit traversal
+	Tolerance     float64    `json:"tolerance,omitempty"` // u.r.
+	MaxIterations int        `json:"maxIterations,omitempty"`
+}
+
+// Limits descrie pragurile de alarmare ale unui element. Valorile 0 sunt ignorate.
+type Limits struct {
+	MaxLoading     float64 `json:"maxLoading,omitempty"`     // % din curentul / puterea nominala
+	MinVoltage     float64 `json:"minVoltage,omitempty"`     // u.r.
+	MaxVoltage     float64 `json:"maxVoltage,omitempty"`     // u.r.
+	MinPowerFactor float64 `json:"minPowerFactor,omitempty"` // cosfi
+}
+
+// AlarmSettings contine pragurile implicite si parametrii masinii de stari pentru alarme.
+type AlarmSettings struct {
+	DefaultLimits Limits  `json:"defaultLimits"`
+	Hysteresis    float64 `json:"hysteresis"` // % din prag, banda in care alarma nu se sterge
+	RaiseDelay    float64 `json:"raiseDelay"` // s, cat timp trebuie depasit pragul pana la ridicarea alarmei
+	ClearDelay    float64 `json:"clearDelay"` // s, cat timp trebuie revenit in banda pana la stergerea alarmei
 }
 
 // * This type struct also represents the parquet schema which is pretty cool
@@ -97,6 +341,158 @@ type LogEntry struct {
 	Message     string
 }
 
+// ElementResult retine marimile calculate pentru un element la un pas de calcul.
+type ElementResult struct {
+	ID                  string
+	Kind                string  // source, transformer, line, separator, consumer
+	ActivePower         float64 // MW
+	ReactivePower       float64 // MVAr
+	Current             float64 // A
+	Voltage             float64 // kV
+	VoltagePU           float64 // u.r.
+	Loading             float64 // %, 0 daca elementul nu are valoare nominala
+	PowerFactor         float64
+	ActivePowerLosses   float64 // MW
+	ReactivePowerLosses float64 // MVAr
+	Energized           bool
+}
+
+// SystemResult este rezultatul unui calcul de circulatie de puteri.
+type SystemResult struct {
+	Elements              map[string]*ElementResult
+	Order                 []string // ordinea in care au fost parcurse elementele
+	ConsumersWithoutPower []ConsumerPowerDetails
+	ActivePowerLosses     float64 // MW
+	ReactivePowerLosses   float64 // MVAr
+	Engine                EngineType
+	Iterations            int // 0 pentru motorul fara iteratii
+	Converged             bool
+}
+
+type NotifierType string
+
+const (
+	NotifierTypeWebhook NotifierType = "webhook"
+	NotifierTypeSMTP    NotifierType = "smtp"
+	NotifierTypeCommand NotifierType = "command"
+)
+
+// Notifier descrie o destinatie pentru evenimentele de alarma.
+type Notifier struct {
+	ID         string       `json:"id"`
+	Type       NotifierType `json:"type"`
+	Severities []string     `json:"severities,omitempty"` // gol = toate severitatile
+	RateLimit  int          `json:"rateLimit,omitempty"`  // notificari pe minut, 0 = nelimitat
+
+	// webhook
+	URL     string  `json:"url,omitempty"`
+	Retries int     `json:"retries,omitempty"`
+	Backoff float64 `json:"backoff,omitempty"` // s, se dubleaza la fiecare reincercare
+
+	// smtp
+	Host     string   `json:"host,omitempty"`
+	Port     int      `json:"port,omitempty"`
+	Username string   `json:"username,omitempty"`
+	Password string   `json:"password,omitempty"`
+	From     string   `json:"from,omitempty"`
+	To       []string `json:"to,omitempty"`
+
+	// command
+	Command string   `json:"command,omitempty"`
+	Args    []string `json:"args,omitempty"`
+}
+
+type ModbusTable string
+
+const (
+	ModbusTableHolding ModbusTable = "holding" // citite cu functia 3
+	ModbusTableInput   ModbusTable = "input"   // citite cu functia 4
+)
+
+type ModbusDataType string
+
+const (
+	ModbusFloat32 ModbusDataType = "float32" // doua registre, big-endian
+	ModbusInt16   ModbusDataType = "int16"
+	ModbusUint16  ModbusDataType = "uint16"
+	ModbusInt32   ModbusDataType = "int32"  // doua registre, cuvantul superior primul
+	ModbusUint32  ModbusDataType = "uint32" // doua registre, cuvantul superior primul
+)
+
+// Modbus descrie serverul Modbus TCP care expune valorile calculate.
+type Modbus struct {
+	Address   string           `json:"address,omitempty"`   // implicit :5020
+	UnitID    uint8            `json:"unitId,omitempty"`    // 0 = raspunde oricarui unit id
+	Registers []ModbusRegister `json:"registers,omitempty"` // gol = harta implicita
+}
+
+// ModbusRegister leaga o marime a unui element de o adresa din tabelul de registre.
+type ModbusRegister struct {
+	Element  string         `json:"element"`
+	Quantity string         `json:"quantity"`        // voltage, current, activePower, reactivePower, powerFactor, loading, energized, activeEnergyImport, ...
+	Table    ModbusTable    `json:"table,omitempty"` // implicit input
+	Address  uint16         `json:"address"`
+	Type     ModbusDataType `json:"type,omitempty"`  // implicit float32
+	Scale    float64        `json:"scale,omitempty"` // valoarea se inmulteste cu scale inainte de codificare, implicit 1
+}
+
+// IEC104 descrie statia controlata IEC 60870-5-104.
+type IEC104 struct {
+	Address       string        `json:"address,omitempty"`       // implicit :2404
+	CommonAddress uint16        `json:"commonAddress,omitempty"` // adresa comuna ASDU, implicit 1
+	Deadband      float64       `json:"deadband,omitempty"`      // %, variatia minima pentru transmiterea spontana, implicit 1
+	Points        []IEC104Point `json:"points,omitempty"`        // gol = harta implicita
+}
+
+// IEC104Point leaga o marime a unui element de o adresa de obiect informational (IOA).
+type IEC104Point struct {
+	Element  string `json:"element"`
+	Quantity string `json:"quantity"` // activePower, reactivePower, current, voltage, loading; state pentru separatoare (stare si comanda)
+	IOA      uint32 `json:"ioa"`
+}
+
+// DLMS descrie interfata de citire a contoarelor dupa modelul DLMS/COSEM.
+type DLMS struct {
+	Address       string  `json:"address,omitempty"`       // implicit :4059
+	CapturePeriod float64 `json:"capturePeriod,omitempty"` // s, perioada curbei de sarcina, implicit 900
+	ProfileDepth  int     `json:"profileDepth,omitempty"`  // inregistrari pastrate in curba de sarcina, implicit 2880
+}
+
+// MQTT descrie publicarea rezultatelor si primirea comenzilor printr-un broker MQTT (3.1.1).
+type MQTT struct {
+	Broker       string   `json:"broker"` // host:port sau tcp://host:port
+	ClientID     string   `json:"clientId,omitempty"`
+	Username     string   `json:"username,omitempty"`
+	Password     string   `json:"password,omitempty"`
+	Topic        string   `json:"topic,omitempty"`        // radacina topicurilor, implicit contor
+	SystemID     string   `json:"systemId,omitempty"`     // implicit id-ul sursei principale
+	QoS          byte     `json:"qos,omitempty"`          // 0 sau 1
+	Retain       bool     `json:"retain,omitempty"`       // brokerul pastreaza ultima valoare a fiecarui topic
+	Quantities   []string `json:"quantities,omitempty"`   // implicit activePower, reactivePower, current, voltage, loading, energized
+	KeepAlive    float64  `json:"keepAlive,omitempty"`    // s, implicit 30
+	ReconnectMin float64  `json:"reconnectMin,omitempty"` // s, prima pauza inainte de reconectare, implicit 1
+	ReconnectMax float64  `json:"reconnectMax,omitempty"` // s, pauza maxima, implicit 60
+}
+
+// Metrics descrie serverul HTTP care expune metricile pentru Prometheus.
+type Metrics struct {
+	Address string `json:"address,omitempty"` // implicit :9464
+	Path    string `json:"path,omitempty"`    // implicit /metrics
+}
+
+// GRPC descrie serverul gRPC pentru studiile "what-if".
+type GRPC struct {
+	Address string `json:"address,omitempty"` // implicit :50051
+}
+
+// Daemon descrie rularea continua a retelei: intervalul de calcul si directorul jurnalelor.
+type Daemon struct {
+	Tick    float64 `json:"tick,omitempty"`    // s, implicit 1
+	LogDir  string  `json:"logDir,omitempty"`  // implicit directorul dat prin -log-dir
+	Report  string  `json:"report,omitempty"`  // text, markdown sau html: raportul ultimului calcul, scris in directorul de loguri; gol = fara raport
+	Diagram string  `json:"diagram,omitempty"` // svg sau dot: schema monofilara cu rezultatele ultimului calcul; gol = fara schema
+}
+
 type ConnectedElement struct {
 	ID      string
 	Details interface{} // Store the full details of the element